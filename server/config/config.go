@@ -3,8 +3,10 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -60,6 +62,73 @@ type RateLimitConfig struct {
 	RequestTimeout    time.Duration `json:"request_timeout"`
 }
 
+type SchedulerConfig struct {
+	Enabled         bool          `json:"scheduler_enabled"`
+	PublishInterval time.Duration `json:"publish_interval"`
+	JobTimeout      time.Duration `json:"job_timeout"`
+	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
+}
+
+type ViewTrackingConfig struct {
+	Enabled       bool          `json:"view_tracking_enabled"`
+	DedupWindow   time.Duration `json:"dedup_window"`
+	FlushInterval time.Duration `json:"flush_interval"`
+	MaxBufferSize int           `json:"max_buffer_size"`
+}
+
+type TrendingConfig struct {
+	Enabled           bool          `json:"trending_enabled"`
+	RecomputeInterval time.Duration `json:"recompute_interval"`
+	Gravity           float64       `json:"gravity"`
+}
+
+type TrashConfig struct {
+	Enabled       bool          `json:"trash_enabled"`
+	RetentionDays int           `json:"retention_days"`
+	PurgeInterval time.Duration `json:"purge_interval"`
+}
+
+type CommentConfig struct {
+	MaxDepth             int           `json:"max_depth"`
+	ModerationPolicy     string        `json:"moderation_policy"`
+	TrustedApprovedCount int           `json:"trusted_approved_count"`
+	EditGracePeriod      time.Duration `json:"edit_grace_period"`
+	PageSize             int           `json:"page_size"`
+	MaxRepliesPerComment int           `json:"max_replies_per_comment"`
+}
+
+type SpamConfig struct {
+	Enabled            bool          `json:"spam_enabled"`
+	SpamThreshold      float64       `json:"spam_threshold"`
+	HoldThreshold      float64       `json:"hold_threshold"`
+	MaxLinks           int           `json:"max_links"`
+	DuplicateWindow    time.Duration `json:"duplicate_window"`
+	DuplicateMinLength int           `json:"duplicate_min_length"`
+	VelocityLimit      int           `json:"velocity_limit"`
+	VelocityWindow     time.Duration `json:"velocity_window"`
+	BlockedWords       []string      `json:"blocked_words"`
+	BlockedDomains     []string      `json:"blocked_domains"`
+	TrainingLimit      int           `json:"training_limit"`
+}
+
+type CacheConfig struct {
+	Enabled       bool          `json:"cache_enabled"`
+	Driver        string        `json:"driver"`
+	TTL           time.Duration `json:"ttl"`
+	MaxEntries    int           `json:"max_entries"`
+	RedisAddr     string        `json:"redis_addr"`
+	RedisPassword string        `json:"redis_password"`
+	RedisDB       int           `json:"redis_db"`
+	KeyPrefix     string        `json:"key_prefix"`
+}
+
+type SiteConfig struct {
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	BaseURL       string `json:"base_url"`
+	FeedItemLimit int    `json:"feed_item_limit"`
+}
+
 type Config struct {
 	DbConfig
 	AppConfig
@@ -68,6 +137,14 @@ type Config struct {
 	ContextConfig
 	LoggingConfig
 	RateLimitConfig
+	SchedulerConfig
+	ViewTrackingConfig
+	TrendingConfig
+	TrashConfig
+	CommentConfig
+	SpamConfig
+	CacheConfig
+	SiteConfig
 }
 
 func (c *Config) readConfig() error {
@@ -113,6 +190,30 @@ func (c *Config) readConfig() error {
 	// Load rate limiting configuration with defaults
 	c.RateLimitConfig = c.loadRateLimitConfig()
 
+	// Load background scheduler configuration with defaults
+	c.SchedulerConfig = c.loadSchedulerConfig()
+
+	// Load article view tracking configuration with defaults
+	c.ViewTrackingConfig = c.loadViewTrackingConfig()
+
+	// Load trending article ranking configuration with defaults
+	c.TrendingConfig = c.loadTrendingConfig()
+
+	// Load trash retention configuration with defaults
+	c.TrashConfig = c.loadTrashConfig()
+
+	// Load comment thread configuration with defaults
+	c.CommentConfig = c.loadCommentConfig()
+
+	// Load comment spam filter configuration with defaults
+	c.SpamConfig = c.loadSpamConfig()
+
+	// Load read cache configuration with defaults
+	c.CacheConfig = c.loadCacheConfig()
+
+	// Load public site configuration (feeds, sitemap) with defaults
+	c.SiteConfig = c.loadSiteConfig()
+
 	// Validate required configuration fields
 	if err := c.validateConfig(); err != nil {
 		return err
@@ -308,6 +409,317 @@ func (c *Config) loadRateLimitConfig() RateLimitConfig {
 	return rateLimitConfig
 }
 
+func (c *Config) loadSchedulerConfig() SchedulerConfig {
+	// Start with default configuration
+	schedulerConfig := DefaultSchedulerConfig()
+
+	// Override with environment variables if present
+	if enabled := os.Getenv("SCHEDULER_ENABLED"); enabled != "" {
+		if val, err := strconv.ParseBool(enabled); err == nil {
+			schedulerConfig.Enabled = val
+		}
+	}
+
+	if publishInterval := os.Getenv("SCHEDULER_PUBLISH_INTERVAL"); publishInterval != "" {
+		if val, err := time.ParseDuration(publishInterval); err == nil && val > 0 {
+			schedulerConfig.PublishInterval = val
+		}
+	}
+
+	if jobTimeout := os.Getenv("SCHEDULER_JOB_TIMEOUT"); jobTimeout != "" {
+		if val, err := time.ParseDuration(jobTimeout); err == nil && val > 0 {
+			schedulerConfig.JobTimeout = val
+		}
+	}
+
+	if shutdownTimeout := os.Getenv("SHUTDOWN_TIMEOUT"); shutdownTimeout != "" {
+		if val, err := time.ParseDuration(shutdownTimeout); err == nil && val > 0 {
+			schedulerConfig.ShutdownTimeout = val
+		}
+	}
+
+	return schedulerConfig
+}
+
+func (c *Config) loadViewTrackingConfig() ViewTrackingConfig {
+	// Start with default configuration
+	viewTrackingConfig := DefaultViewTrackingConfig()
+
+	// Override with environment variables if present
+	if enabled := os.Getenv("VIEW_TRACKING_ENABLED"); enabled != "" {
+		if val, err := strconv.ParseBool(enabled); err == nil {
+			viewTrackingConfig.Enabled = val
+		}
+	}
+
+	if dedupWindow := os.Getenv("VIEW_DEDUP_WINDOW"); dedupWindow != "" {
+		if val, err := time.ParseDuration(dedupWindow); err == nil && val > 0 {
+			viewTrackingConfig.DedupWindow = val
+		}
+	}
+
+	if flushInterval := os.Getenv("VIEW_FLUSH_INTERVAL"); flushInterval != "" {
+		if val, err := time.ParseDuration(flushInterval); err == nil && val > 0 {
+			viewTrackingConfig.FlushInterval = val
+		}
+	}
+
+	if maxBufferSize := os.Getenv("VIEW_MAX_BUFFER_SIZE"); maxBufferSize != "" {
+		if val, err := strconv.Atoi(maxBufferSize); err == nil && val > 0 {
+			viewTrackingConfig.MaxBufferSize = val
+		}
+	}
+
+	return viewTrackingConfig
+}
+
+func (c *Config) loadTrendingConfig() TrendingConfig {
+	// Start with default configuration
+	trendingConfig := DefaultTrendingConfig()
+
+	// Override with environment variables if present
+	if enabled := os.Getenv("TRENDING_ENABLED"); enabled != "" {
+		if val, err := strconv.ParseBool(enabled); err == nil {
+			trendingConfig.Enabled = val
+		}
+	}
+
+	if interval := os.Getenv("TRENDING_RECOMPUTE_INTERVAL"); interval != "" {
+		if val, err := time.ParseDuration(interval); err == nil && val > 0 {
+			trendingConfig.RecomputeInterval = val
+		}
+	}
+
+	if gravity := os.Getenv("TRENDING_GRAVITY"); gravity != "" {
+		if val, err := strconv.ParseFloat(gravity, 64); err == nil && val > 0 {
+			trendingConfig.Gravity = val
+		}
+	}
+
+	return trendingConfig
+}
+
+func (c *Config) loadTrashConfig() TrashConfig {
+	// Start with default configuration
+	trashConfig := DefaultTrashConfig()
+
+	// Override with environment variables if present
+	if enabled := os.Getenv("TRASH_ENABLED"); enabled != "" {
+		if val, err := strconv.ParseBool(enabled); err == nil {
+			trashConfig.Enabled = val
+		}
+	}
+
+	if days := os.Getenv("TRASH_RETENTION_DAYS"); days != "" {
+		if val, err := strconv.Atoi(days); err == nil && val > 0 {
+			trashConfig.RetentionDays = val
+		}
+	}
+
+	if interval := os.Getenv("TRASH_PURGE_INTERVAL"); interval != "" {
+		if val, err := time.ParseDuration(interval); err == nil && val > 0 {
+			trashConfig.PurgeInterval = val
+		}
+	}
+
+	return trashConfig
+}
+
+func (c *Config) loadCommentConfig() CommentConfig {
+	// Start with default configuration
+	commentConfig := DefaultCommentConfig()
+
+	// Override with environment variables if present
+	if depth := os.Getenv("COMMENT_MAX_DEPTH"); depth != "" {
+		if val, err := strconv.Atoi(depth); err == nil && val >= 0 {
+			commentConfig.MaxDepth = val
+		}
+	}
+
+	if policy := os.Getenv("COMMENT_MODERATION_POLICY"); policy != "" {
+		commentConfig.ModerationPolicy = strings.ToLower(policy)
+	}
+
+	if count := os.Getenv("COMMENT_TRUSTED_APPROVED_COUNT"); count != "" {
+		if val, err := strconv.Atoi(count); err == nil && val > 0 {
+			commentConfig.TrustedApprovedCount = val
+		}
+	}
+
+	if period := os.Getenv("COMMENT_EDIT_GRACE_PERIOD"); period != "" {
+		if val, err := time.ParseDuration(period); err == nil && val >= 0 {
+			commentConfig.EditGracePeriod = val
+		}
+	}
+
+	if size := os.Getenv("COMMENT_PAGE_SIZE"); size != "" {
+		if val, err := strconv.Atoi(size); err == nil && val > 0 {
+			commentConfig.PageSize = val
+		}
+	}
+
+	if replies := os.Getenv("COMMENT_MAX_REPLIES_PER_COMMENT"); replies != "" {
+		if val, err := strconv.Atoi(replies); err == nil && val > 0 {
+			commentConfig.MaxRepliesPerComment = val
+		}
+	}
+
+	return commentConfig
+}
+
+func (c *Config) loadSpamConfig() SpamConfig {
+	// Start with default configuration
+	spamConfig := DefaultSpamConfig()
+
+	// Override with environment variables if present
+	if enabled := os.Getenv("SPAM_FILTER_ENABLED"); enabled != "" {
+		if val, err := strconv.ParseBool(enabled); err == nil {
+			spamConfig.Enabled = val
+		}
+	}
+
+	if threshold := os.Getenv("SPAM_THRESHOLD"); threshold != "" {
+		if val, err := strconv.ParseFloat(threshold, 64); err == nil && val > 0 && val <= 1 {
+			spamConfig.SpamThreshold = val
+		}
+	}
+
+	if threshold := os.Getenv("SPAM_HOLD_THRESHOLD"); threshold != "" {
+		if val, err := strconv.ParseFloat(threshold, 64); err == nil && val > 0 && val <= 1 {
+			spamConfig.HoldThreshold = val
+		}
+	}
+
+	if links := os.Getenv("SPAM_MAX_LINKS"); links != "" {
+		if val, err := strconv.Atoi(links); err == nil && val >= 0 {
+			spamConfig.MaxLinks = val
+		}
+	}
+
+	if window := os.Getenv("SPAM_DUPLICATE_WINDOW"); window != "" {
+		if val, err := time.ParseDuration(window); err == nil && val > 0 {
+			spamConfig.DuplicateWindow = val
+		}
+	}
+
+	if length := os.Getenv("SPAM_DUPLICATE_MIN_LENGTH"); length != "" {
+		if val, err := strconv.Atoi(length); err == nil && val >= 0 {
+			spamConfig.DuplicateMinLength = val
+		}
+	}
+
+	if limit := os.Getenv("SPAM_VELOCITY_LIMIT"); limit != "" {
+		if val, err := strconv.Atoi(limit); err == nil && val > 0 {
+			spamConfig.VelocityLimit = val
+		}
+	}
+
+	if window := os.Getenv("SPAM_VELOCITY_WINDOW"); window != "" {
+		if val, err := time.ParseDuration(window); err == nil && val > 0 {
+			spamConfig.VelocityWindow = val
+		}
+	}
+
+	if words := os.Getenv("SPAM_BLOCKED_WORDS"); words != "" {
+		spamConfig.BlockedWords = splitList(words)
+	}
+
+	if domains := os.Getenv("SPAM_BLOCKED_DOMAINS"); domains != "" {
+		spamConfig.BlockedDomains = splitList(domains)
+	}
+
+	if limit := os.Getenv("SPAM_TRAINING_LIMIT"); limit != "" {
+		if val, err := strconv.Atoi(limit); err == nil && val >= 0 {
+			spamConfig.TrainingLimit = val
+		}
+	}
+
+	return spamConfig
+}
+
+// splitList splits a comma separated environment variable into its lowercase, non-empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (c *Config) loadCacheConfig() CacheConfig {
+	// Start with default configuration
+	cacheConfig := DefaultCacheConfig()
+
+	// Override with environment variables if present
+	if enabled := os.Getenv("CACHE_ENABLED"); enabled != "" {
+		if val, err := strconv.ParseBool(enabled); err == nil {
+			cacheConfig.Enabled = val
+		}
+	}
+
+	if driver := os.Getenv("CACHE_DRIVER"); driver != "" {
+		cacheConfig.Driver = strings.ToLower(driver)
+	}
+
+	if ttl := os.Getenv("CACHE_TTL"); ttl != "" {
+		if val, err := time.ParseDuration(ttl); err == nil && val > 0 {
+			cacheConfig.TTL = val
+		}
+	}
+
+	if maxEntries := os.Getenv("CACHE_MAX_ENTRIES"); maxEntries != "" {
+		if val, err := strconv.Atoi(maxEntries); err == nil && val > 0 {
+			cacheConfig.MaxEntries = val
+		}
+	}
+
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		cacheConfig.RedisAddr = addr
+	}
+
+	cacheConfig.RedisPassword = os.Getenv("REDIS_PASSWORD")
+
+	if db := os.Getenv("REDIS_DB"); db != "" {
+		if val, err := strconv.Atoi(db); err == nil && val >= 0 {
+			cacheConfig.RedisDB = val
+		}
+	}
+
+	if prefix := os.Getenv("CACHE_KEY_PREFIX"); prefix != "" {
+		cacheConfig.KeyPrefix = prefix
+	}
+
+	return cacheConfig
+}
+
+func (c *Config) loadSiteConfig() SiteConfig {
+	// Start with default configuration
+	siteConfig := DefaultSiteConfig()
+
+	// Override with environment variables if present
+	if title := os.Getenv("SITE_TITLE"); title != "" {
+		siteConfig.Title = title
+	}
+
+	if description := os.Getenv("SITE_DESCRIPTION"); description != "" {
+		siteConfig.Description = description
+	}
+
+	if baseURL := os.Getenv("SITE_BASE_URL"); baseURL != "" {
+		siteConfig.BaseURL = strings.TrimRight(baseURL, "/")
+	}
+
+	if feedItemLimit := os.Getenv("FEED_ITEM_LIMIT"); feedItemLimit != "" {
+		if val, err := strconv.Atoi(feedItemLimit); err == nil && val > 0 {
+			siteConfig.FeedItemLimit = val
+		}
+	}
+
+	return siteConfig
+}
+
 // DefaultContextConfig returns a default context configuration
 func DefaultContextConfig() ContextConfig {
 	return ContextConfig{
@@ -347,6 +759,133 @@ func DefaultRateLimitConfig() RateLimitConfig {
 	}
 }
 
+// DefaultSchedulerConfig returns a default background scheduler configuration
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		Enabled:         true,
+		PublishInterval: 30 * time.Second, // Check publish/unpublish schedules every 30 seconds
+		JobTimeout:      1 * time.Minute,  // Maximum duration of a single job run
+		ShutdownTimeout: 15 * time.Second, // Grace period for in-flight requests and jobs
+	}
+}
+
+// LoadSchedulerConfig loads background scheduler configuration from environment variables (public for testing)
+func (c *Config) LoadSchedulerConfig() SchedulerConfig {
+	return c.loadSchedulerConfig()
+}
+
+// DefaultViewTrackingConfig returns a default article view tracking configuration
+func DefaultViewTrackingConfig() ViewTrackingConfig {
+	return ViewTrackingConfig{
+		Enabled:       true,
+		DedupWindow:   30 * time.Minute, // Repeated reads by the same visitor within this window count once
+		FlushInterval: 10 * time.Second, // Write buffered views to the database every 10 seconds
+		MaxBufferSize: 1000,             // Flush early once this many views are buffered
+	}
+}
+
+// DefaultTrendingConfig returns a default trending article ranking configuration
+func DefaultTrendingConfig() TrendingConfig {
+	return TrendingConfig{
+		Enabled:           true,
+		RecomputeInterval: 5 * time.Minute, // Recompute the stored trending scores every 5 minutes
+		Gravity:           1.8,             // Higher gravity makes older articles drop out of the ranking faster
+	}
+}
+
+// LoadTrendingConfig loads trending article ranking configuration from environment variables (public for testing)
+func (c *Config) LoadTrendingConfig() TrendingConfig {
+	return c.loadTrendingConfig()
+}
+
+// DefaultTrashConfig returns a default trash retention configuration
+func DefaultTrashConfig() TrashConfig {
+	return TrashConfig{
+		Enabled:       true,
+		RetentionDays: 30,        // Deleted articles, comments and products can be restored for 30 days
+		PurgeInterval: time.Hour, // Purge expired trash every hour
+	}
+}
+
+// LoadTrashConfig loads trash retention configuration from environment variables (public for testing)
+func (c *Config) LoadTrashConfig() TrashConfig {
+	return c.loadTrashConfig()
+}
+
+// DefaultCommentConfig returns a default comment thread configuration
+func DefaultCommentConfig() CommentConfig {
+	return CommentConfig{
+		MaxDepth:             5,                 // Replies nest at most 5 levels below a top level comment, 0 disables replies
+		ModerationPolicy:     "hold_first_time", // Used until an admin stores a global policy
+		TrustedApprovedCount: 3,                 // Approved comments a user needs to skip the hold_untrusted queue
+		EditGracePeriod:      15 * time.Minute,  // Authors can edit their comments this long after posting, 0 allows edits any time
+		PageSize:             100,               // Top level comments per page when the client sets no limit, at most 100
+		MaxRepliesPerComment: 200,               // Replies listed with each top level comment, the rest are flagged with has_more_replies
+	}
+}
+
+// LoadCommentConfig loads comment thread configuration from environment variables (public for testing)
+func (c *Config) LoadCommentConfig() CommentConfig {
+	return c.loadCommentConfig()
+}
+
+// DefaultSpamConfig returns a default comment spam filter configuration
+func DefaultSpamConfig() SpamConfig {
+	return SpamConfig{
+		Enabled:            true,
+		SpamThreshold:      0.9,              // Comments scoring at least this are marked as spam right away
+		HoldThreshold:      0.5,              // Comments scoring at least this wait in the moderation queue
+		MaxLinks:           2,                // More links than this make a comment suspicious
+		DuplicateWindow:    24 * time.Hour,   // The same content posted again within this window is suspicious
+		DuplicateMinLength: 20,               // Shorter content ("Thanks!") is repeated innocently and never counts as a duplicate
+		VelocityLimit:      5,                // More comments than this by one user within the velocity window are suspicious
+		VelocityWindow:     10 * time.Minute,
+		TrainingLimit:      5000,             // Most recent moderator decisions the classifier learns from at startup
+	}
+}
+
+// LoadSpamConfig loads comment spam filter configuration from environment variables (public for testing)
+func (c *Config) LoadSpamConfig() SpamConfig {
+	return c.loadSpamConfig()
+}
+
+// DefaultCacheConfig returns a default read cache configuration
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Enabled:    true,
+		Driver:     "memory",         // "memory" for an in-process LRU, "redis" to share entries between instances
+		TTL:        5 * time.Minute,  // Upper bound for stale counters like article views and comment counts, writes invalidate right away
+		MaxEntries: 10000,            // Capacity of the in-process LRU
+		RedisAddr:  "localhost:6379",
+		KeyPrefix:  "develapar:cache:",
+	}
+}
+
+// LoadCacheConfig loads read cache configuration from environment variables (public for testing)
+func (c *Config) LoadCacheConfig() CacheConfig {
+	return c.loadCacheConfig()
+}
+
+// DefaultSiteConfig returns a default public site configuration
+func DefaultSiteConfig() SiteConfig {
+	return SiteConfig{
+		Title:         "Develapar",
+		Description:   "Latest articles from Develapar",
+		BaseURL:       "http://localhost:8080", // Public URL used for links in feeds and sitemaps
+		FeedItemLimit: 20,                      // Number of latest articles in each feed
+	}
+}
+
+// LoadSiteConfig loads public site configuration from environment variables (public for testing)
+func (c *Config) LoadSiteConfig() SiteConfig {
+	return c.loadSiteConfig()
+}
+
+// LoadViewTrackingConfig loads article view tracking configuration from environment variables (public for testing)
+func (c *Config) LoadViewTrackingConfig() ViewTrackingConfig {
+	return c.loadViewTrackingConfig()
+}
+
 // LoadContextConfig loads context configuration from environment variables (public for testing)
 func (c *Config) LoadContextConfig() ContextConfig {
 	return c.loadContextConfig()
@@ -457,6 +996,88 @@ func (c *Config) validateConfig() error {
 		return errors.New("rate limit request timeout must be positive")
 	}
 
+	// Validate scheduler configuration
+	if c.SchedulerConfig.PublishInterval <= 0 {
+		return errors.New("scheduler publish interval must be positive")
+	}
+	if c.SchedulerConfig.JobTimeout <= 0 {
+		return errors.New("scheduler job timeout must be positive")
+	}
+	if c.SchedulerConfig.ShutdownTimeout <= 0 {
+		return errors.New("shutdown timeout must be positive")
+	}
+
+	// Validate site configuration
+	if _, err := url.ParseRequestURI(c.SiteConfig.BaseURL); err != nil {
+		return errors.New("site base URL must be an absolute URL")
+	}
+	if c.SiteConfig.FeedItemLimit <= 0 {
+		return errors.New("feed item limit must be positive")
+	}
+
+	// Validate view tracking configuration
+	if c.ViewTrackingConfig.DedupWindow <= 0 {
+		return errors.New("view dedup window must be positive")
+	}
+	if c.ViewTrackingConfig.FlushInterval <= 0 {
+		return errors.New("view flush interval must be positive")
+	}
+	if c.ViewTrackingConfig.MaxBufferSize <= 0 {
+		return errors.New("view max buffer size must be positive")
+	}
+
+	// Validate trending configuration
+	if c.TrendingConfig.RecomputeInterval <= 0 {
+		return errors.New("trending recompute interval must be positive")
+	}
+	if c.TrendingConfig.Gravity <= 0 {
+		return errors.New("trending gravity must be positive")
+	}
+
+	// Validate read cache configuration
+	if c.CacheConfig.Driver != "memory" && c.CacheConfig.Driver != "redis" {
+		return errors.New("cache driver must be memory or redis")
+	}
+	if c.CacheConfig.TTL <= 0 {
+		return errors.New("cache ttl must be positive")
+	}
+	if c.CacheConfig.MaxEntries <= 0 {
+		return errors.New("cache max entries must be positive")
+	}
+
+	// Validate trash configuration
+	if c.TrashConfig.RetentionDays <= 0 {
+		return errors.New("trash retention days must be positive")
+	}
+	if c.TrashConfig.PurgeInterval <= 0 {
+		return errors.New("trash purge interval must be positive")
+	}
+
+	// Validate comment configuration
+	if c.CommentConfig.MaxDepth < 0 {
+		return errors.New("comment max depth must be non-negative")
+	}
+	if policy := c.CommentConfig.ModerationPolicy; policy != "auto_approve" && policy != "hold_first_time" && policy != "hold_untrusted" {
+		return errors.New("comment moderation policy must be auto_approve, hold_first_time or hold_untrusted")
+	}
+	if c.CommentConfig.TrustedApprovedCount <= 0 {
+		return errors.New("comment trusted approved count must be positive")
+	}
+	if c.CommentConfig.EditGracePeriod < 0 {
+		return errors.New("comment edit grace period must be non-negative")
+	}
+	if c.CommentConfig.PageSize <= 0 || c.CommentConfig.PageSize > 100 {
+		return errors.New("comment page size must be between 1 and 100")
+	}
+	if c.CommentConfig.MaxRepliesPerComment <= 0 {
+		return errors.New("comment max replies per comment must be positive")
+	}
+
+	// Validate spam filter configuration
+	if c.SpamConfig.HoldThreshold > c.SpamConfig.SpamThreshold {
+		return errors.New("spam hold threshold must not exceed the spam threshold")
+	}
+
 	// Validate pool configuration
 	if c.PoolConfig.MaxOpenConns <= 0 {
 		return errors.New("database max open connections must be positive")
@@ -3,23 +3,38 @@ package controller
 import (
 	"context"
 	"develapar-server/middleware"
+	"develapar-server/model"
 	"develapar-server/model/dto"
 	"develapar-server/service"
 	"develapar-server/utils"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// Limits of the password guesses on password protected articles
+const (
+	articleUnlockLimit        = 5
+	articleUnlockAddressLimit = 20
+	articleUnlockWindow       = 15 * time.Minute
+)
+
 type ArticleController struct {
-	service        service.ArticleService
-	md             middleware.AuthMiddleware
-	rg             *gin.RouterGroup
-	errorHandler   middleware.ErrorHandler
-	responseHelper *utils.ResponseHelper
+	service         service.ArticleService
+	relatedService  service.RelatedArticleService
+	trendingService service.ArticleTrendingService
+	seriesService   service.SeriesService
+	previewService  service.ArticlePreviewService
+	viewTracker     service.ArticleViewTracker
+	md              middleware.AuthMiddleware
+	rateLimiter     *middleware.RateLimitMiddleware
+	rg              *gin.RouterGroup
+	errorHandler    middleware.ErrorHandler
+	responseHelper  *utils.ResponseHelper
 }
 
 // Helper function to extract user ID from context
@@ -38,6 +53,33 @@ func (c *ArticleController) getUserID(ctx *gin.Context) (uuid.UUID, error) {
 	return parsedUUID, nil
 }
 
+// articleViewer describes who reads an article: the user of an optional token and the
+// access token of an unlocked password protected article, sent as the X-Article-Token
+// header. Like the preview token it is not read from the URL.
+func articleViewer(ctx *gin.Context) service.ArticleViewer {
+	viewer := service.ArticleViewer{
+		AccessToken: ctx.GetHeader("X-Article-Token"),
+	}
+	if userId, err := utils.GetUserIDFromGinContext(ctx); err == nil {
+		viewer.UserId = userId
+		viewer.Role = ctx.GetString("role")
+	}
+	return viewer
+}
+
+// previewToken returns the preview link token sent as the X-Preview-Token header. It is
+// not read from the URL, where it would end up in access logs and Referer headers.
+func (c *ArticleController) previewToken(ctx *gin.Context) string {
+	return ctx.GetHeader("X-Preview-Token")
+}
+
+// isViewDenied reports whether err denies the view of an article, as opposed to a
+// failure to check the access. Only denied views may be lifted by a preview link.
+func isViewDenied(err error) bool {
+	appErr, ok := err.(*utils.AppError)
+	return ok && (appErr.Code == utils.ErrNotFound || appErr.Code == utils.ErrForbidden)
+}
+
 // Helper function to parse article ID from URL parameter
 func (c *ArticleController) parseArticleID(ctx *gin.Context) (uuid.UUID, error) {
 	idStr := ctx.Param("article_id")
@@ -251,17 +293,20 @@ func (c *ArticleController) GetAllArticleWithPaginationHandler(ginCtx *gin.Conte
 }
 
 // @Summary Update an article
-// @Description Update an existing article by ID
+// @Description Update an existing article by ID. A title change derives a new slug unless a custom slug is pinned; the previous slug keeps redirecting to the article. Only editors and admins may set the slug field.
 // @Tags Articles
 // @Accept json
 // @Produce json
 // @Param article_id path int true "ID of the article to update"
+// @Param If-Match header string true "ETag of the article being updated"
 // @Param payload body dto.UpdateArticleRequest true "Article update details"
 // @Success 200 {object} dto.APIResponse{data=object{message=string,article=model.Article}} "Article updated successfully"
 // @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID or payload"
 // @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
 // @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden (user does not own the article)"
 // @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
+// @Failure 412 {object} dto.APIResponse{error=dto.ErrorResponse} "The article was modified since it was read"
+// @Failure 428 {object} dto.APIResponse{error=dto.ErrorResponse} "If-Match header missing"
 // @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
 // @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
 // @Security BearerAuth
@@ -319,10 +364,14 @@ func (c *ArticleController) UpdateArticleHandler(ginCtx *gin.Context) {
 		return
 	}
 
-	if article.User.Id != userId {
-		appErr := c.errorHandler.WrapError(requestCtx, fmt.Errorf("user does not own article"), utils.ErrForbidden, "You do not own this article")
-		appErr.StatusCode = 403
-		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+	// Any listed author with edit rights may change the article
+	if !c.requireEditRights(requestCtx, ginCtx, article.Id, userId) {
+		return
+	}
+
+	// The update must be made from the current version of the article
+	version, ok := ifMatchVersion(requestCtx, ginCtx, c.errorHandler, "Article")
+	if !ok {
 		return
 	}
 
@@ -334,8 +383,19 @@ func (c *ArticleController) UpdateArticleHandler(ginCtx *gin.Context) {
 		return
 	}
 
+	// Only editors and admins pin custom slugs
+	if req.Slug != nil {
+		role, _ := utils.GetUserRoleFromContext(ginCtx)
+		if role != "editor" && !utils.ValidateAdminRole(role) {
+			appErr := c.errorHandler.WrapError(requestCtx, fmt.Errorf("role %q cannot set a custom slug", role), utils.ErrForbidden, "Only editors can set a custom slug")
+			appErr.StatusCode = 403
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+	}
+
 	// Update article with context
-	updatedArticle, err := c.service.UpdateArticle(requestCtx, id, req)
+	updatedArticle, err := c.service.UpdateArticle(requestCtx, id, version, req, userId)
 	if err != nil {
 		// Check for context-specific errors
 		if requestCtx.Err() == context.DeadlineExceeded {
@@ -362,6 +422,7 @@ func (c *ArticleController) UpdateArticleHandler(ginCtx *gin.Context) {
 	}
 
 	// Create success response with context
+	setVersionETag(ginCtx, updatedArticle.Version)
 	responseData := gin.H{
 		"message": "Article updated successfully",
 		"article": updatedArticle,
@@ -370,12 +431,18 @@ func (c *ArticleController) UpdateArticleHandler(ginCtx *gin.Context) {
 }
 
 // @Summary Get article by slug
-// @Description Get article details by its slug
+// @Description Get article details by its slug. A slug the article used before a title change answers with a permanent redirect to the current slug; with redirect=json the new location is returned as a 200 hint instead.
+// @Description Unpublished and private articles are only visible to their authors, editors and admins, or through a preview link. Password protected articles need the token returned by the unlock endpoint.
 // @Tags Articles
 // @Produce json
 // @Param slug path string true "Slug of the article to retrieve"
+// @Param redirect query string false "Set to json to receive a redirect hint instead of a 301" Enums(json)
+// @Param X-Article-Token header string false "Access token of an unlocked password protected article"
+// @Param X-Preview-Token header string false "Token of a preview link"
 // @Success 200 {object} dto.APIResponse{data=object{message=string,article=model.Article}} "Article details"
+// @Success 301 {object} dto.APIResponse{data=object{message=string,slug=string,location=string}} "Article moved to a new slug"
 // @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid slug"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Article is password protected or the preview link is invalid"
 // @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
 // @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
 // @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
@@ -409,6 +476,10 @@ func (c *ArticleController) GetBySlugHandler(ginCtx *gin.Context) {
 
 		// Check if it's already an AppError
 		if appErr, ok := err.(*utils.AppError); ok {
+			// Slugs changed by a title edit redirect to the current slug
+			if appErr.Code == utils.ErrNotFound && c.redirectOldSlug(requestCtx, ginCtx, slug) {
+				return
+			}
 			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
 			return
 		}
@@ -428,11 +499,171 @@ func (c *ArticleController) GetBySlugHandler(ginCtx *gin.Context) {
 		return
 	}
 
-	// Create success response with context
+	// Unpublished, private and password protected articles are only shown to those allowed
+	// to read them. Anyone else may hold a preview link, which counts a view of the link.
+	var preview *model.ArticlePreview
+	if err := c.service.AuthorizeView(requestCtx, article, articleViewer(ginCtx)); err != nil {
+		if token := c.previewToken(ginCtx); token != "" && isViewDenied(err) {
+			opened, previewErr := c.previewService.OpenPreview(requestCtx, article.Id, token)
+			if previewErr == nil {
+				preview = &opened
+			}
+			err = previewErr
+		}
+		if err != nil {
+			if appErr, ok := err.(*utils.AppError); ok {
+				c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+				return
+			}
+			appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to check article access")
+			appErr.StatusCode = 500
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+	}
+
+	// Articles of a series link to the previous and next part
+	article.Series, err = c.seriesService.FindNavigation(requestCtx, article.Id)
+	if err != nil {
+		if appErr, ok := err.(*utils.AppError); ok {
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to retrieve article series")
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Reads through a preview link are not article views
+	if preview == nil {
+		c.recordView(requestCtx, ginCtx, article)
+	}
+
+	// Articles opened through a preview link or an unlock token must not land in shared caches
+	if preview != nil || articleViewer(ginCtx).AccessToken != "" {
+		middleware.MarkPrivate(ginCtx)
+	}
+
+	// Create success response with context. No Last-Modified is sent: updated_at does not
+	// move when tags, series or comments of the article change, the ETag of the payload does.
+	setVersionETag(ginCtx, article.Version)
 	responseData := gin.H{
 		"message": "Article retrieved successfully",
 		"article": article,
 	}
+	if preview != nil {
+		responseData["preview"] = preview
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// redirectOldSlug answers a request for a slug the article no longer uses with a
+// permanent redirect to the current slug, or with a 200 redirect hint when the client
+// asks for ?redirect=json. It returns false when the slug never belonged to an article,
+// or when the requester may not read the article: its current slug would reveal the
+// new title of an article that was hidden after the old link was shared.
+func (c *ArticleController) redirectOldSlug(requestCtx context.Context, ginCtx *gin.Context, slug string) bool {
+	current, err := c.service.FindSlugRedirect(requestCtx, slug)
+	if err != nil || current == "" {
+		return false
+	}
+	article, err := c.service.FindBySlug(requestCtx, current)
+	if err != nil {
+		return false
+	}
+	if err := c.service.AuthorizeView(requestCtx, article, articleViewer(ginCtx)); err != nil {
+		return false
+	}
+
+	// Replace only the slug segment so the API prefix and sub paths stay intact
+	location := ginCtx.Request.URL.Path
+	location = strings.TrimSuffix(location, slug) + current
+	if query := ginCtx.Request.URL.RawQuery; query != "" && ginCtx.Query("redirect") != "json" {
+		location += "?" + query
+	}
+
+	responseData := gin.H{
+		"message":  "Article has moved",
+		"slug":     current,
+		"location": location,
+	}
+	if ginCtx.Query("redirect") == "json" {
+		c.responseHelper.SendSuccess(ginCtx, gin.H{"redirect": responseData})
+		return true
+	}
+	c.responseHelper.SendMovedPermanently(ginCtx, location, responseData)
+	return true
+}
+
+// @Summary Get related articles
+// @Description Get the published articles most related to an article, scored by shared tags, category, text similarity and recency
+// @Tags Articles
+// @Produce json
+// @Param slug path string true "Slug of the article"
+// @Param limit query int false "Number of related articles (default: 5, max: 20)"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,articles=[]dto.RelatedArticle}} "Related articles"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid slug or limit"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
+// @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /articles/{slug}/related [get]
+func (c *ArticleController) GetRelatedArticlesHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	slug := ginCtx.Param("slug")
+	if slug == "" {
+		appErr := c.errorHandler.ValidationError(requestCtx, "slug", "Article slug is required")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	limit := 5
+	if limitStr := ginCtx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err != nil || l <= 0 || l > service.MaxRelatedArticles {
+			appErr := c.errorHandler.ValidationError(requestCtx, "limit", fmt.Sprintf("Limit must be a positive integer between 1 and %d", service.MaxRelatedArticles))
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		} else {
+			limit = l
+		}
+	}
+
+	// Call service with context
+	articles, err := c.relatedService.FindRelated(requestCtx, slug, limit)
+	if err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, "get related articles")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, "get related articles")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Check if it's already an AppError
+		if appErr, ok := err.(*utils.AppError); ok {
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Wrap as internal error
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to retrieve related articles")
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Create success response with context
+	responseData := gin.H{
+		"message":  "Related articles retrieved successfully",
+		"articles": articles,
+	}
 	c.responseHelper.SendSuccess(ginCtx, responseData)
 }
 
@@ -460,7 +691,7 @@ func (ac *ArticleController) GetByUserIdHandler(ginCtx *gin.Context) {
 	}
 
 	// Call service with context
-	articles, err := ac.service.FindByUserId(requestCtx, userId)
+	articles, err := ac.service.FindByUserId(requestCtx, userId, articleViewer(ginCtx))
 	if err != nil {
 		// Check for context-specific errors
 		if requestCtx.Err() == context.DeadlineExceeded {
@@ -496,7 +727,7 @@ func (ac *ArticleController) GetByUserIdHandler(ginCtx *gin.Context) {
 }
 
 // @Summary Get articles by user ID with pagination
-// @Description Get a paginated list of articles by a specific user ID
+// @Description Get a paginated list of articles by a specific user ID. Only public articles are listed, except for the user themselves, editors and admins who also see unlisted, private and password protected ones.
 // @Tags Articles
 // @Produce json
 // @Param user_id path int true "ID of the user whose articles to retrieve"
@@ -545,7 +776,8 @@ func (ac *ArticleController) GetByUserIdWithPaginationHandler(ginCtx *gin.Contex
 	}
 
 	// Call service with pagination and context
-	result, err := ac.service.FindByUserIdWithPagination(requestCtx, userId, page, limit)
+	// The author, editors and admins also see the unlisted, private and protected articles
+	result, err := ac.service.FindByUserIdWithPagination(requestCtx, userId, articleViewer(ginCtx), page, limit)
 	if err != nil {
 		// Check for context-specific errors
 		if requestCtx.Err() == context.DeadlineExceeded {
@@ -789,10 +1021,8 @@ func (ac *ArticleController) DeleteArticleHandler(ginCtx *gin.Context) {
 		return
 	}
 
-	if article.User.Id != userId {
-		appErr := ac.errorHandler.WrapError(requestCtx, fmt.Errorf("user does not own article"), utils.ErrForbidden, "You do not own this article")
-		appErr.StatusCode = 403
-		ac.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+	// Any listed author with edit rights may change the article
+	if !ac.requireEditRights(requestCtx, ginCtx, article.Id, userId) {
 		return
 	}
 
@@ -830,33 +1060,803 @@ func (ac *ArticleController) DeleteArticleHandler(ginCtx *gin.Context) {
 	ac.responseHelper.SendSuccess(ginCtx, responseData)
 }
 
-func (c *ArticleController) Route() {
-	// Definisikan group HANYA SEKALI
-	articleRoutes := c.rg.Group("/articles")
+// @Summary Get trending articles
+// @Description Published articles ranked by recent views, likes, comments and bookmarks with a time decay. Scores are recomputed periodically.
+// @Tags Articles
+// @Produce json
+// @Param window query string false "Activity window: 24h, 7d or 30d (default: 24h)"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 10, max: 100)"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,window=string,articles=[]dto.TrendingArticle},pagination=dto.PaginationMetadata} "Trending articles"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid window or pagination parameters"
+// @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /articles/trending [get]
+func (c *ArticleController) GetTrendingArticlesHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
 
-	// --- Public Routes ---
-	// Endpoint ini tidak memerlukan middleware
-	articleRoutes.GET("", c.GetAllArticleWithPaginationHandler)
-	articleRoutes.GET("/:slug", c.GetBySlugHandler)
-	// articleRoutes.GET("/author/:user_id", c.GetByUserIdHandler)
-	articleRoutes.GET("/author/:user_id", c.GetByUserIdWithPaginationHandler)
-	// articleRoutes.GET("/category/:category_name", c.GetByCategory)
-	articleRoutes.GET("/category/:category_name", c.GetByCategoryWithPaginationHandler)
+	window := ginCtx.DefaultQuery("window", model.TrendingWindowDay)
 
-	// --- Protected Routes ---
-	// Terapkan middleware HANYA pada endpoint yang membutuhkannya
-	checkTokenMiddleware := c.md.CheckToken("user", "admin")
-	articleRoutes.POST("/", checkTokenMiddleware, c.CreateArticleHandler)
-	articleRoutes.PUT("/:article_id", checkTokenMiddleware, c.UpdateArticleHandler)
-	articleRoutes.DELETE("/:article_id", checkTokenMiddleware, c.DeleteArticleHandler)
+	// Get pagination parameters from query string
+	page := 1
+	limit := 10
+
+	if pageStr := ginCtx.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err != nil || p <= 0 {
+			appErr := c.errorHandler.ValidationError(requestCtx, "page", "Page must be a positive integer")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		} else {
+			page = p
+		}
+	}
+
+	if limitStr := ginCtx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err != nil || l <= 0 || l > 100 {
+			appErr := c.errorHandler.ValidationError(requestCtx, "limit", "Limit must be a positive integer between 1 and 100")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		} else {
+			limit = l
+		}
+	}
+
+	// Call service with pagination and context
+	result, err := c.trendingService.FindTrending(requestCtx, window, page, limit)
+	if err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, "get trending articles")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, "get trending articles")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Check if it's already an AppError
+		if appErr, ok := err.(*utils.AppError); ok {
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Wrap as internal error
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to retrieve trending articles")
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Create success response with context and pagination
+	responseData := gin.H{
+		"message":  "Trending articles retrieved successfully",
+		"window":   window,
+		"articles": result.Data,
+	}
+	c.responseHelper.SendSuccessWithServicePagination(ginCtx, responseData, result.Metadata)
+}
+
+// @Summary Search articles
+// @Description Full-text search over published articles ranked by relevance, with highlighted snippets
+// @Tags Articles
+// @Produce json
+// @Param q query string true "Search query (supports quoted phrases, OR and -exclusion)"
+// @Param category query string false "Filter by category name"
+// @Param tag query string false "Filter by tag name"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 10, max: 100)"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,articles=[]dto.ArticleSearchResult},pagination=dto.PaginationMetadata} "Ranked search results"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Missing query or invalid pagination parameters"
+// @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /articles/search [get]
+func (c *ArticleController) SearchArticlesHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	// Validate search query
+	q := strings.TrimSpace(ginCtx.Query("q"))
+	if q == "" {
+		appErr := c.errorHandler.ValidationError(requestCtx, "q", "Search query is required")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Get pagination parameters from query string
+	page := 1
+	limit := 10
+
+	if pageStr := ginCtx.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err != nil || p <= 0 {
+			appErr := c.errorHandler.ValidationError(requestCtx, "page", "Page must be a positive integer")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		} else {
+			page = p
+		}
+	}
+
+	if limitStr := ginCtx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err != nil || l <= 0 || l > 100 {
+			appErr := c.errorHandler.ValidationError(requestCtx, "limit", "Limit must be a positive integer between 1 and 100")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		} else {
+			limit = l
+		}
+	}
+
+	filter := dto.ArticleSearchFilter{
+		Query:    q,
+		Category: strings.TrimSpace(ginCtx.Query("category")),
+		Tag:      strings.TrimSpace(ginCtx.Query("tag")),
+	}
+
+	// Call service with pagination and context
+	result, err := c.service.Search(requestCtx, filter, page, limit)
+	if err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, "search articles")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, "search articles")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Check if it's already an AppError
+		if appErr, ok := err.(*utils.AppError); ok {
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Wrap as internal error
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to search articles")
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Create success response with context and pagination
+	responseData := gin.H{
+		"message":  "Articles retrieved successfully",
+		"articles": result.Data,
+	}
+	c.responseHelper.SendSuccessWithServicePagination(ginCtx, responseData, result.Metadata)
+}
+
+// recordView counts a read of a published article. Counting is buffered by the
+// tracker and never fails the request.
+func (c *ArticleController) recordView(requestCtx context.Context, ginCtx *gin.Context, article model.Article) {
+	if c.viewTracker == nil || article.Status != model.ArticleStatusPublished {
+		return
+	}
+
+	// Anonymous readers have no user ID, they are deduplicated by IP and User-Agent
+	userId, _ := utils.GetUserIDFromGinContext(ginCtx)
+	c.viewTracker.RecordView(requestCtx, model.ArticleView{
+		ArticleId: article.Id,
+		UserId:    userId,
+		IPAddress: ginCtx.ClientIP(),
+		UserAgent: ginCtx.Request.UserAgent(),
+		Referrer:  ginCtx.Request.Referer(),
+	})
+}
+
+// checkArticleAccess loads the article and checks that the user is one of its authors with
+// edit rights or has one of the privileged roles. It writes the error response itself and returns false when access is denied.
+func (c *ArticleController) checkArticleAccess(requestCtx context.Context, ginCtx *gin.Context, id uuid.UUID, userId uuid.UUID, privilegedRoles ...string) bool {
+	article, err := c.service.FindById(requestCtx, id)
+	if err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, "find article")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return false
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, "find article")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return false
+		}
+
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrNotFound, "Article not found")
+		appErr.StatusCode = 404
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return false
+	}
+
+	role, _ := utils.GetUserRoleFromContext(ginCtx)
+	for _, privileged := range privilegedRoles {
+		if role == privileged {
+			return true
+		}
+	}
+
+	return c.requireEditRights(requestCtx, ginCtx, article.Id, userId)
+}
+
+// requireEditRights checks that the user is listed on the article with a role that may
+// edit it. It writes the error response itself and returns false otherwise.
+func (c *ArticleController) requireEditRights(requestCtx context.Context, ginCtx *gin.Context, articleId uuid.UUID, userId uuid.UUID) bool {
+	canEdit, err := c.service.CanEdit(requestCtx, articleId, userId)
+	if err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, "check article authors")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return false
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, "check article authors")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return false
+		}
+
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to check article authors")
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return false
+	}
+
+	if !canEdit {
+		appErr := c.errorHandler.WrapError(requestCtx, fmt.Errorf("user is not an author of the article"), utils.ErrForbidden, "You are not an author of this article")
+		appErr.StatusCode = 403
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return false
+	}
+
+	return true
+}
+
+// authorizeAuthorManagement checks that the user created the article or is an editor
+// or admin, co-authors cannot change the credits. It writes the error response itself
+// and returns false when access is denied.
+func (c *ArticleController) authorizeAuthorManagement(requestCtx context.Context, ginCtx *gin.Context) (uuid.UUID, bool) {
+	userId, err := c.getUserID(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrUnauthorized, "Authentication required")
+		appErr.StatusCode = 401
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return uuid.Nil, false
+	}
+
+	id, err := c.parseArticleID(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "article_id", "Invalid article ID: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return uuid.Nil, false
+	}
+
+	article, err := c.service.FindById(requestCtx, id)
+	if err != nil {
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, "find article")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return uuid.Nil, false
+		}
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrNotFound, "Article not found")
+		appErr.StatusCode = 404
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return uuid.Nil, false
+	}
+
+	role, _ := utils.GetUserRoleFromContext(ginCtx)
+	if article.UserId != userId && role != "editor" && !utils.ValidateAdminRole(role) {
+		appErr := c.errorHandler.WrapError(requestCtx, fmt.Errorf("user is not the article author"), utils.ErrForbidden, "Only the article author can manage its contributors")
+		appErr.StatusCode = 403
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+// @Summary Add article contributor
+// @Description Credit a user on an article as co-author, editor or reviewer, or change the role of a listed contributor. Co-authors and editors can edit the article. Only the article author, an editor or an admin can manage contributors.
+// @Tags Articles
+// @Accept json
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Param payload body dto.ArticleAuthorRequest true "Contributor"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,author=model.ArticleAuthor}} "Contributor added"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid request payload"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article or user not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /articles/{article_id}/authors [post]
+func (c *ArticleController) AddAuthorHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	id, ok := c.authorizeAuthorManagement(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	var req dto.ArticleAuthorRequest
+	if err := ginCtx.ShouldBindJSON(&req); err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	author, err := c.service.AddAuthor(requestCtx, id, req.UserId, req.Role)
+	if err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, "add article author")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, "add article author")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Check if it's already an AppError
+		if appErr, ok := err.(*utils.AppError); ok {
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to add article author")
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Article author added successfully",
+		"author":  author,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Remove article contributor
+// @Description Remove a contributor from an article. The article author cannot be removed.
+// @Tags Articles
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Param user_id path string true "User ID of the contributor"
+// @Success 200 {object} dto.APIResponse{data=object{message=string}} "Contributor removed"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article or contributor not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /articles/{article_id}/authors/{user_id} [delete]
+func (c *ArticleController) RemoveAuthorHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	id, ok := c.authorizeAuthorManagement(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	authorId, err := uuid.Parse(ginCtx.Param("user_id"))
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "user_id", "Invalid user ID format")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	if err := c.service.RemoveAuthor(requestCtx, id, authorId); err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, "remove article author")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, "remove article author")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Check if it's already an AppError
+		if appErr, ok := err.(*utils.AppError); ok {
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to remove article author")
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Article author removed successfully",
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Unlock a password protected article
+// @Description Check the password of a password protected article and return a short lived access token. Send the token as the X-Article-Token header when reading the article.
+// @Tags Articles
+// @Accept json
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Param payload body dto.UnlockArticleRequest true "Article password"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,access=dto.ArticleAccessResponse}} "Article unlocked"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid request payload"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Incorrect password"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
+// @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
+// @Failure 429 {object} dto.APIResponse{error=dto.ErrorResponse} "Too many password attempts"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /articles/{article_id}/unlock [post]
+func (c *ArticleController) UnlockArticleHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	id, err := c.parseArticleID(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "article_id", err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	var req dto.UnlockArticleRequest
+	if err := ginCtx.ShouldBindJSON(&req); err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	access, err := c.service.UnlockArticle(requestCtx, id, req.Password)
+	if err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, "unlock article")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, "unlock article")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Check if it's already an AppError
+		if appErr, ok := err.(*utils.AppError); ok {
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to unlock article")
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Article unlocked successfully",
+		"access":  access,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// runWorkflowAction handles the shared parts of the editorial workflow endpoints:
+// authentication, optional ownership check, binding the reason and mapping errors.
+// Authors with edit rights may always run the action when ownerAllowed is true,
+// otherwise only the route's role middleware decides.
+func (c *ArticleController) runWorkflowAction(ginCtx *gin.Context, action string, ownerAllowed bool, message string) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	userId, err := c.getUserID(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrUnauthorized, "Authentication required")
+		appErr.StatusCode = 401
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	id, err := c.parseArticleID(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "article_id", "Invalid article ID: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	if ownerAllowed && !c.checkArticleAccess(requestCtx, ginCtx, id, userId, "editor", "admin") {
+		return
+	}
+
+	// Reason is optional for most actions, an empty body is fine
+	var req dto.ArticleTransitionRequest
+	if ginCtx.Request.ContentLength > 0 {
+		if err := ginCtx.ShouldBindJSON(&req); err != nil {
+			appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+	}
+
+	var article model.Article
+	switch action {
+	case service.ArticleActionSubmit:
+		article, err = c.service.SubmitArticle(requestCtx, id, userId)
+	case service.ArticleActionApprove:
+		article, err = c.service.ApproveArticle(requestCtx, id, userId, req.Reason)
+	case service.ArticleActionReject:
+		article, err = c.service.RejectArticle(requestCtx, id, userId, req.Reason)
+	case service.ArticleActionPublish:
+		article, err = c.service.PublishArticle(requestCtx, id, userId)
+	case service.ArticleActionArchive:
+		article, err = c.service.ArchiveArticle(requestCtx, id, userId)
+	}
+	if err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, action+" article")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, action+" article")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Check if it's already an AppError
+		if appErr, ok := err.(*utils.AppError); ok {
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to "+action+" article")
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	responseData := gin.H{
+		"message": message,
+		"article": article,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Submit an article for review
+// @Description Move a draft article to in_review. Only an author of the article, an editor or an admin can submit.
+// @Tags Articles
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,article=model.Article}} "Article submitted"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
+// @Failure 409 {object} dto.APIResponse{error=dto.ErrorResponse} "Illegal status transition"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /articles/{article_id}/submit [post]
+func (c *ArticleController) SubmitArticleHandler(ginCtx *gin.Context) {
+	c.runWorkflowAction(ginCtx, service.ArticleActionSubmit, true, "Article submitted for review")
+}
+
+// @Summary Approve an article
+// @Description Approve an article that is in review. Editor or admin only.
+// @Tags Articles
+// @Accept json
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Param payload body dto.ArticleTransitionRequest false "Optional approval note"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,article=model.Article}} "Article approved"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID or payload"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
+// @Failure 409 {object} dto.APIResponse{error=dto.ErrorResponse} "Illegal status transition"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /articles/{article_id}/approve [post]
+func (c *ArticleController) ApproveArticleHandler(ginCtx *gin.Context) {
+	c.runWorkflowAction(ginCtx, service.ArticleActionApprove, false, "Article approved")
+}
+
+// @Summary Reject an article
+// @Description Send an article in review (or approved) back to draft with a reason. Editor or admin only.
+// @Tags Articles
+// @Accept json
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Param payload body dto.ArticleTransitionRequest true "Rejection reason"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,article=model.Article}} "Article rejected"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID or missing reason"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
+// @Failure 409 {object} dto.APIResponse{error=dto.ErrorResponse} "Illegal status transition"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /articles/{article_id}/reject [post]
+func (c *ArticleController) RejectArticleHandler(ginCtx *gin.Context) {
+	c.runWorkflowAction(ginCtx, service.ArticleActionReject, false, "Article rejected")
+}
+
+// @Summary Publish an article
+// @Description Publish an approved article. If its publish_at lies in the future the article is scheduled instead. Editor or admin only.
+// @Tags Articles
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,article=model.Article}} "Article published or scheduled"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
+// @Failure 409 {object} dto.APIResponse{error=dto.ErrorResponse} "Illegal status transition"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /articles/{article_id}/publish [post]
+func (c *ArticleController) PublishArticleHandler(ginCtx *gin.Context) {
+	c.runWorkflowAction(ginCtx, service.ArticleActionPublish, false, "Article published")
+}
+
+// @Summary Archive an article
+// @Description Archive an article. An author of the article, an editor or an admin can archive.
+// @Tags Articles
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,article=model.Article}} "Article archived"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
+// @Failure 409 {object} dto.APIResponse{error=dto.ErrorResponse} "Illegal status transition"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /articles/{article_id}/archive [post]
+func (c *ArticleController) ArchiveArticleHandler(ginCtx *gin.Context) {
+	c.runWorkflowAction(ginCtx, service.ArticleActionArchive, true, "Article archived")
+}
+
+// @Summary Get article status history
+// @Description List the editorial workflow transitions of an article, oldest first. An author of the article, an editor or an admin can view it.
+// @Tags Articles
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,transitions=[]model.ArticleStatusTransition}} "Status history"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /article-transitions/{article_id} [get]
+func (c *ArticleController) GetStatusTransitionsHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	userId, err := c.getUserID(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrUnauthorized, "Authentication required")
+		appErr.StatusCode = 401
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	id, err := c.parseArticleID(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "article_id", "Invalid article ID: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	if !c.checkArticleAccess(requestCtx, ginCtx, id, userId, "editor", "admin") {
+		return
+	}
+
+	transitions, err := c.service.FindStatusTransitions(requestCtx, id)
+	if err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, "get article status history")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, "get article status history")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to retrieve article status history")
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	responseData := gin.H{
+		"message":     "Article status history retrieved successfully",
+		"transitions": transitions,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+func (c *ArticleController) Route() {
+	// Definisikan group HANYA SEKALI
+	articleRoutes := c.rg.Group("/articles")
+
+	// --- Public Routes ---
+	// Endpoint ini tidak memerlukan middleware
+	articleRoutes.GET("", c.GetAllArticleWithPaginationHandler)
+	articleRoutes.GET("/search", c.SearchArticlesHandler)
+	articleRoutes.GET("/trending", c.GetTrendingArticlesHandler)
+	articleRoutes.GET("/:slug", middleware.HTTPCacheMiddleware(articleCachePolicy), c.md.OptionalToken(), c.GetBySlugHandler)
+	articleRoutes.GET("/:slug/related", c.GetRelatedArticlesHandler)
+	// articleRoutes.GET("/author/:user_id", c.GetByUserIdHandler)
+	articleRoutes.GET("/author/:user_id", c.md.OptionalToken(), c.GetByUserIdWithPaginationHandler)
+	// articleRoutes.GET("/category/:category_name", c.GetByCategory)
+	articleRoutes.GET("/category/:category_name", c.GetByCategoryWithPaginationHandler)
+
+	// --- Protected Routes ---
+	// Terapkan middleware HANYA pada endpoint yang membutuhkannya
+	checkTokenMiddleware := c.md.CheckToken("user", "editor", "admin")
+	articleRoutes.POST("/", checkTokenMiddleware, c.CreateArticleHandler)
+	articleRoutes.PUT("/:article_id", checkTokenMiddleware, c.UpdateArticleHandler)
+	articleRoutes.DELETE("/:article_id", checkTokenMiddleware, c.DeleteArticleHandler)
+
+	// Password protected articles are unlocked without an account. Password guesses are
+	// limited per article and address, and per address across articles.
+	unlockPerArticle := c.rateLimiter.RouteLimit("article_unlock", articleUnlockLimit, articleUnlockWindow, func(ginCtx *gin.Context) string {
+		return ginCtx.Param("article_id") + ":" + ginCtx.ClientIP()
+	})
+	unlockPerAddress := c.rateLimiter.RouteLimit("article_unlock_ip", articleUnlockAddressLimit, articleUnlockWindow, func(ginCtx *gin.Context) string {
+		return ginCtx.ClientIP()
+	})
+	articleRoutes.POST("/:article_id/unlock", unlockPerAddress, unlockPerArticle, c.UnlockArticleHandler)
+
+	// --- Editorial Workflow ---
+	// Submit dan archive boleh oleh pemilik artikel, sisanya hanya editor/admin
+	editorMiddleware := c.md.CheckToken("editor", "admin")
+	articleRoutes.POST("/:article_id/submit", checkTokenMiddleware, c.SubmitArticleHandler)
+	articleRoutes.POST("/:article_id/approve", editorMiddleware, c.ApproveArticleHandler)
+	articleRoutes.POST("/:article_id/reject", editorMiddleware, c.RejectArticleHandler)
+	articleRoutes.POST("/:article_id/publish", editorMiddleware, c.PublishArticleHandler)
+	articleRoutes.POST("/:article_id/archive", checkTokenMiddleware, c.ArchiveArticleHandler)
+
+	// --- Contributors ---
+	articleRoutes.POST("/:article_id/authors", checkTokenMiddleware, c.AddAuthorHandler)
+	articleRoutes.DELETE("/:article_id/authors/:user_id", checkTokenMiddleware, c.RemoveAuthorHandler)
+
+	transitionRoutes := c.rg.Group("/article-transitions/:article_id")
+	transitionRoutes.GET("/", checkTokenMiddleware, c.GetStatusTransitionsHandler) // GET /article-transitions/:article_id
 }
 
-func NewArticleController(aS service.ArticleService, md middleware.AuthMiddleware, rg *gin.RouterGroup, errorHandler middleware.ErrorHandler) *ArticleController {
+func NewArticleController(aS service.ArticleService, relatedService service.RelatedArticleService, trendingService service.ArticleTrendingService, seriesService service.SeriesService, previewService service.ArticlePreviewService, viewTracker service.ArticleViewTracker, md middleware.AuthMiddleware, rateLimiter *middleware.RateLimitMiddleware, rg *gin.RouterGroup, errorHandler middleware.ErrorHandler) *ArticleController {
 	return &ArticleController{
-		service:        aS,
-		md:             md,
-		rg:             rg,
-		errorHandler:   errorHandler,
-		responseHelper: utils.NewResponseHelper(),
+		service:         aS,
+		relatedService:  relatedService,
+		trendingService: trendingService,
+		seriesService:   seriesService,
+		previewService:  previewService,
+		viewTracker:     viewTracker,
+		md:              md,
+		rateLimiter:     rateLimiter,
+		rg:              rg,
+		errorHandler:    errorHandler,
+		responseHelper:  utils.NewResponseHelper(),
 	}
 }
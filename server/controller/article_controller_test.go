@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"context"
+	"develapar-server/middleware"
+	"develapar-server/model"
+	"develapar-server/service"
+	"develapar-server/utils"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeArticleService serves one article, answers its view check with viewErr and keeps
+// the viewer it was asked about
+type fakeArticleService struct {
+	service.ArticleService
+	article model.Article
+	viewErr error
+	viewer  service.ArticleViewer
+}
+
+func (s *fakeArticleService) FindBySlug(ctx context.Context, slug string) (model.Article, error) {
+	return s.article, nil
+}
+
+func (s *fakeArticleService) AuthorizeView(ctx context.Context, article model.Article, viewer service.ArticleViewer) error {
+	s.viewer = viewer
+	return s.viewErr
+}
+
+// fakePreviewService opens the previews of the token "valid" and counts the opened links
+type fakePreviewService struct {
+	service.ArticlePreviewService
+	opened int
+}
+
+func (s *fakePreviewService) OpenPreview(ctx context.Context, articleId uuid.UUID, token string) (model.ArticlePreview, error) {
+	if token != "valid" {
+		return model.ArticlePreview{}, utils.NewErrorWrapper().ForbiddenError(ctx, "Preview link is no longer valid")
+	}
+	s.opened++
+	return model.ArticlePreview{Id: uuid.New(), ArticleId: articleId}, nil
+}
+
+type fakeSeriesService struct {
+	service.SeriesService
+}
+
+func (s *fakeSeriesService) FindNavigation(ctx context.Context, articleId uuid.UUID) (*model.SeriesNavigation, error) {
+	return nil, nil
+}
+
+func TestArticleController_GetBySlugPreview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	errorWrapper := utils.NewErrorWrapper()
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		viewErr    error
+		header     string
+		query      string
+		wantStatus int
+		wantOpened int
+	}{
+		{name: "readable article needs no preview", header: "valid", wantStatus: http.StatusOK},
+		{name: "preview header lifts a hidden article", viewErr: errorWrapper.NotFoundError(ctx, "Article"), header: "valid", wantStatus: http.StatusOK, wantOpened: 1},
+		{name: "preview header lifts a password", viewErr: errorWrapper.ForbiddenError(ctx, "This article is password protected"), header: "valid", wantStatus: http.StatusOK, wantOpened: 1},
+		{name: "invalid preview stays forbidden", viewErr: errorWrapper.NotFoundError(ctx, "Article"), header: "expired", wantStatus: http.StatusForbidden},
+		{name: "preview in the query is ignored", viewErr: errorWrapper.NotFoundError(ctx, "Article"), query: "?preview=valid", wantStatus: http.StatusNotFound},
+		{name: "failing access check is not hidden by a preview", viewErr: errors.New("connection refused"), header: "valid", wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles := &fakeArticleService{
+				article: model.Article{Id: uuid.New(), Slug: "draft", Status: model.ArticleStatusDraft},
+				viewErr: tt.viewErr,
+			}
+			previews := &fakePreviewService{}
+			router := gin.New()
+			controller := NewArticleController(articles, nil, nil, &fakeSeriesService{}, previews, nil, nil, nil, router.Group(""), middleware.NewErrorHandler(nil))
+			router.GET("/articles/:slug", controller.GetBySlugHandler)
+
+			req := httptest.NewRequest(http.MethodGet, "/articles/draft"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Preview-Token", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			assert.Equal(t, tt.wantOpened, previews.opened)
+			assert.Empty(t, w.Header().Get("Last-Modified"), "updated_at misses tag, series and comment changes")
+		})
+	}
+}
+
+func TestArticleController_AccessTokenOnlyFromHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		header    string
+		query     string
+		wantToken string
+	}{
+		{name: "token in the header", header: "unlocked", wantToken: "unlocked"},
+		{name: "token in the query is ignored", query: "?access_token=unlocked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles := &fakeArticleService{article: model.Article{Id: uuid.New(), Slug: "locked", Status: model.ArticleStatusPublished}}
+			router := gin.New()
+			controller := NewArticleController(articles, nil, nil, &fakeSeriesService{}, &fakePreviewService{}, nil, nil, nil, router.Group(""), middleware.NewErrorHandler(nil))
+			router.GET("/articles/:slug", controller.GetBySlugHandler)
+
+			req := httptest.NewRequest(http.MethodGet, "/articles/locked"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Article-Token", tt.header)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.wantToken, articles.viewer.AccessToken)
+		})
+	}
+}
+
+// fakeRenamedArticleService redirects the old slugs in redirects to the articles in
+// current and lets nobody read the hidden ones
+type fakeRenamedArticleService struct {
+	service.ArticleService
+	redirects map[string]string
+	current   map[string]model.Article
+	hidden    map[string]bool
+}
+
+func (s *fakeRenamedArticleService) FindBySlug(ctx context.Context, slug string) (model.Article, error) {
+	article, ok := s.current[slug]
+	if !ok {
+		return model.Article{}, utils.NewErrorWrapper().NotFoundError(ctx, "Article")
+	}
+	return article, nil
+}
+
+func (s *fakeRenamedArticleService) FindSlugRedirect(ctx context.Context, slug string) (string, error) {
+	return s.redirects[slug], nil
+}
+
+func (s *fakeRenamedArticleService) AuthorizeView(ctx context.Context, article model.Article, viewer service.ArticleViewer) error {
+	if s.hidden[article.Slug] {
+		return utils.NewErrorWrapper().NotFoundError(ctx, "Article")
+	}
+	return nil
+}
+
+func TestArticleController_GetBySlugRedirect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		path         string
+		wantStatus   int
+		wantLocation string
+		wantBody     string
+	}{
+		{name: "old slug redirects permanently", path: "/api/v1/articles/old-title", wantStatus: http.StatusMovedPermanently, wantLocation: "/api/v1/articles/new-title"},
+		{name: "query is kept", path: "/api/v1/articles/old-title?utm_source=feed", wantStatus: http.StatusMovedPermanently, wantLocation: "/api/v1/articles/new-title?utm_source=feed"},
+		{name: "json redirect hint", path: "/api/v1/articles/old-title?redirect=json", wantStatus: http.StatusOK, wantBody: `"location":"/api/v1/articles/new-title"`},
+		{name: "unknown slug", path: "/api/v1/articles/never-used", wantStatus: http.StatusNotFound},
+		{name: "hidden article keeps its new slug", path: "/api/v1/articles/old-secret", wantStatus: http.StatusNotFound},
+		{name: "hidden article keeps its new slug in the json hint", path: "/api/v1/articles/old-secret?redirect=json", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles := &fakeRenamedArticleService{
+				redirects: map[string]string{"old-title": "new-title", "old-secret": "new-secret"},
+				current: map[string]model.Article{
+					"new-title":  {Id: uuid.New(), Slug: "new-title"},
+					"new-secret": {Id: uuid.New(), Slug: "new-secret"},
+				},
+				hidden: map[string]bool{"new-secret": true},
+			}
+			router := gin.New()
+			controller := NewArticleController(articles, nil, nil, &fakeSeriesService{}, &fakePreviewService{}, nil, nil, nil, router.Group(""), middleware.NewErrorHandler(nil))
+			router.GET("/api/v1/articles/:slug", controller.GetBySlugHandler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			assert.Equal(t, tt.wantLocation, w.Header().Get("Location"))
+			if tt.wantBody != "" {
+				assert.Contains(t, w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
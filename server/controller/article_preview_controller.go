@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"context"
+	"develapar-server/middleware"
+	"develapar-server/model/dto"
+	"develapar-server/service"
+	"develapar-server/utils"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ArticlePreviewController struct {
+	service        service.ArticlePreviewService
+	articleService service.ArticleService
+	md             middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+	errorHandler   middleware.ErrorHandler
+	responseHelper *utils.ResponseHelper
+}
+
+// handleServiceError maps service errors to error responses
+func (c *ArticlePreviewController) handleServiceError(requestCtx context.Context, ginCtx *gin.Context, err error, operation, message string) {
+	// Check for context-specific errors
+	if requestCtx.Err() == context.DeadlineExceeded {
+		appErr := c.errorHandler.TimeoutError(requestCtx, operation)
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+	if requestCtx.Err() == context.Canceled {
+		appErr := c.errorHandler.CancellationError(requestCtx, operation)
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Check if it's already an AppError
+	if appErr, ok := err.(*utils.AppError); ok {
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Wrap as internal error
+	appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, message)
+	appErr.StatusCode = 500
+	c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+}
+
+// authorizeArticle checks that the current user is an author of the article with edit
+// rights or is an editor or admin.
+// It writes the error response itself and returns false when access is denied.
+func (c *ArticlePreviewController) authorizeArticle(requestCtx context.Context, ginCtx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userId, err := utils.GetUserIDFromGinContext(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrUnauthorized, "Authentication required")
+		appErr.StatusCode = 401
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	articleId, err := uuid.Parse(ginCtx.Param("article_id"))
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "article_id", "Invalid article ID: invalid article ID format")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	role, _ := utils.GetUserRoleFromContext(ginCtx)
+	if role != "editor" && !utils.ValidateAdminRole(role) {
+		canEdit, err := c.articleService.CanEdit(requestCtx, articleId, userId)
+		if err != nil {
+			c.handleServiceError(requestCtx, ginCtx, err, "check article authors", "Failed to check article authors")
+			return uuid.Nil, uuid.Nil, false
+		}
+		if !canEdit {
+			appErr := c.errorHandler.WrapError(requestCtx, fmt.Errorf("user is not an author of the article"), utils.ErrForbidden, "You are not an author of this article")
+			appErr.StatusCode = 403
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return uuid.Nil, uuid.Nil, false
+		}
+	}
+
+	return userId, articleId, true
+}
+
+// @Summary Create a preview link
+// @Description Create a signed preview link showing an unpublished article to readers without an account. The link expires after expires_in_hours (default 72, max 720) and can be limited to max_views views. The token is only returned once.
+// @Tags Article Previews
+// @Accept json
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Param payload body dto.CreateArticlePreviewRequest false "Preview link options"
+// @Success 201 {object} dto.APIResponse{data=object{message=string,preview=dto.ArticlePreviewResponse}} "Preview link created"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid request payload or article already published"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /article-previews/{article_id} [post]
+func (c *ArticlePreviewController) CreatePreviewHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	userId, articleId, ok := c.authorizeArticle(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	// Every option has a default, an empty body is accepted
+	var req dto.CreateArticlePreviewRequest
+	if ginCtx.Request.ContentLength != 0 {
+		if err := ginCtx.ShouldBindJSON(&req); err != nil {
+			appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+	}
+
+	preview, err := c.service.CreatePreview(requestCtx, articleId, userId, req)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "create article preview", "Failed to create article preview")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Article preview created successfully",
+		"preview": preview,
+	}
+	c.responseHelper.SendCreated(ginCtx, responseData)
+}
+
+// @Summary List preview links
+// @Description List the preview links of an article with their expiry, views and revocation, newest first. Tokens are not listed.
+// @Tags Article Previews
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,previews=[]model.ArticlePreview}} "List of preview links"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /article-previews/{article_id} [get]
+func (c *ArticlePreviewController) GetPreviewsHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	_, articleId, ok := c.authorizeArticle(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	previews, err := c.service.FindPreviews(requestCtx, articleId)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "get article previews", "Failed to retrieve article previews")
+		return
+	}
+
+	responseData := gin.H{
+		"message":  "Article previews retrieved successfully",
+		"previews": previews,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Revoke a preview link
+// @Description Revoke a preview link of an article, the link stops working immediately
+// @Tags Article Previews
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Param preview_id path string true "Preview ID"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,preview=model.ArticlePreview}} "Preview link revoked"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article or preview ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Preview not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /article-previews/{article_id}/{preview_id} [delete]
+func (c *ArticlePreviewController) RevokePreviewHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	_, articleId, ok := c.authorizeArticle(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	previewId, err := uuid.Parse(ginCtx.Param("preview_id"))
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "preview_id", "Invalid preview ID format")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	preview, err := c.service.RevokePreview(requestCtx, articleId, previewId)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "revoke article preview", "Failed to revoke article preview")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Article preview revoked successfully",
+		"preview": preview,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+func (c *ArticlePreviewController) Route() {
+	// Tautan pratinjau hanya dikelola oleh penulis artikel, editor atau admin
+	previewRoutes := c.rg.Group("/article-previews/:article_id")
+	previewRoutes.Use(c.md.CheckToken("user", "editor", "admin"))
+	previewRoutes.GET("/", c.GetPreviewsHandler)                 // GET /article-previews/:article_id
+	previewRoutes.POST("/", c.CreatePreviewHandler)              // POST /article-previews/:article_id
+	previewRoutes.DELETE("/:preview_id", c.RevokePreviewHandler) // DELETE /article-previews/:article_id/:preview_id
+}
+
+func NewArticlePreviewController(pS service.ArticlePreviewService, aS service.ArticleService, md middleware.AuthMiddleware, rg *gin.RouterGroup, errorHandler middleware.ErrorHandler) *ArticlePreviewController {
+	return &ArticlePreviewController{
+		service:        pS,
+		articleService: aS,
+		md:             md,
+		rg:             rg,
+		errorHandler:   errorHandler,
+		responseHelper: utils.NewResponseHelper(),
+	}
+}
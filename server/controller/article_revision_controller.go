@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"context"
+	"develapar-server/middleware"
+	"develapar-server/service"
+	"develapar-server/utils"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ArticleRevisionController struct {
+	service        service.ArticleRevisionService
+	articleService service.ArticleService
+	md             middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+	errorHandler   middleware.ErrorHandler
+	responseHelper *utils.ResponseHelper
+}
+
+// handleServiceError maps service errors to error responses
+func (c *ArticleRevisionController) handleServiceError(requestCtx context.Context, ginCtx *gin.Context, err error, operation, message string) {
+	// Check for context-specific errors
+	if requestCtx.Err() == context.DeadlineExceeded {
+		appErr := c.errorHandler.TimeoutError(requestCtx, operation)
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+	if requestCtx.Err() == context.Canceled {
+		appErr := c.errorHandler.CancellationError(requestCtx, operation)
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Check if it's already an AppError
+	if appErr, ok := err.(*utils.AppError); ok {
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Wrap as internal error
+	appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, message)
+	appErr.StatusCode = 500
+	c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+}
+
+// authorizeArticle checks that the current user is an author of the article with edit
+// rights or is an editor or admin.
+// It writes the error response itself and returns false when access is denied.
+func (c *ArticleRevisionController) authorizeArticle(requestCtx context.Context, ginCtx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userId, err := utils.GetUserIDFromGinContext(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrUnauthorized, "Authentication required")
+		appErr.StatusCode = 401
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	articleId, err := uuid.Parse(ginCtx.Param("article_id"))
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "article_id", "Invalid article ID: invalid article ID format")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	article, err := c.articleService.FindById(requestCtx, articleId)
+	if err != nil {
+		if requestCtx.Err() != nil {
+			c.handleServiceError(requestCtx, ginCtx, err, "find article", "Failed to find article")
+			return uuid.Nil, uuid.Nil, false
+		}
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrNotFound, "Article not found")
+		appErr.StatusCode = 404
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	role, _ := utils.GetUserRoleFromContext(ginCtx)
+	if role != "editor" && !utils.ValidateAdminRole(role) {
+		canEdit, err := c.articleService.CanEdit(requestCtx, article.Id, userId)
+		if err != nil {
+			c.handleServiceError(requestCtx, ginCtx, err, "check article authors", "Failed to check article authors")
+			return uuid.Nil, uuid.Nil, false
+		}
+		if !canEdit {
+			appErr := c.errorHandler.WrapError(requestCtx, fmt.Errorf("user is not an author of the article"), utils.ErrForbidden, "You are not an author of this article")
+			appErr.StatusCode = 403
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return uuid.Nil, uuid.Nil, false
+		}
+	}
+
+	return userId, articleId, true
+}
+
+// parseRevisionNumber parses a positive revision number from a raw string
+func (c *ArticleRevisionController) parseRevisionNumber(requestCtx context.Context, ginCtx *gin.Context, field, raw string) (int, bool) {
+	number, err := strconv.Atoi(raw)
+	if err != nil || number <= 0 {
+		appErr := c.errorHandler.ValidationError(requestCtx, field, "Revision must be a positive integer")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return 0, false
+	}
+	return number, true
+}
+
+// @Summary List article revisions
+// @Description List all revisions of an article, newest first. Only an author of the article, an editor or an admin can view revisions.
+// @Tags Article Revisions
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,revisions=[]model.ArticleRevision}} "List of revisions"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /article-revisions/{article_id} [get]
+func (c *ArticleRevisionController) GetRevisionsHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	_, articleId, ok := c.authorizeArticle(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	revisions, err := c.service.FindRevisions(requestCtx, articleId)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "get article revisions", "Failed to retrieve article revisions")
+		return
+	}
+
+	responseData := gin.H{
+		"message":   "Article revisions retrieved successfully",
+		"revisions": revisions,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Get an article revision
+// @Description Get a single revision snapshot of an article
+// @Tags Article Revisions
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Param revision path int true "Revision number"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,revision=model.ArticleRevision}} "Revision details"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID or revision number"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article or revision not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /article-revisions/{article_id}/{revision} [get]
+func (c *ArticleRevisionController) GetRevisionHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	_, articleId, ok := c.authorizeArticle(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	revisionNumber, ok := c.parseRevisionNumber(requestCtx, ginCtx, "revision", ginCtx.Param("revision"))
+	if !ok {
+		return
+	}
+
+	revision, err := c.service.FindRevision(requestCtx, articleId, revisionNumber)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "get article revision", "Failed to retrieve article revision")
+		return
+	}
+
+	responseData := gin.H{
+		"message":  "Article revision retrieved successfully",
+		"revision": revision,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Diff two article revisions
+// @Description Show a line-level diff of the content between two revisions of an article
+// @Tags Article Revisions
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Param from query int true "Base revision number"
+// @Param to query int true "Target revision number"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,diff=dto.ArticleRevisionDiff}} "Line-level diff"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID or revision numbers"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article or revision not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /article-revisions/{article_id}/diff [get]
+func (c *ArticleRevisionController) DiffRevisionsHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	_, articleId, ok := c.authorizeArticle(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	from, ok := c.parseRevisionNumber(requestCtx, ginCtx, "from", ginCtx.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := c.parseRevisionNumber(requestCtx, ginCtx, "to", ginCtx.Query("to"))
+	if !ok {
+		return
+	}
+
+	diff, err := c.service.DiffRevisions(requestCtx, articleId, from, to)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "diff article revisions", "Failed to diff article revisions")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Article revision diff retrieved successfully",
+		"diff":    diff,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Restore an article revision
+// @Description Make an old revision the current version of the article. The restore is recorded as a new revision.
+// @Tags Article Revisions
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Param revision path int true "Revision number to restore"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,article=model.Article}} "Article restored"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID or revision number"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article or revision not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /article-revisions/{article_id}/{revision}/restore [post]
+func (c *ArticleRevisionController) RestoreRevisionHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	userId, articleId, ok := c.authorizeArticle(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	revisionNumber, ok := c.parseRevisionNumber(requestCtx, ginCtx, "revision", ginCtx.Param("revision"))
+	if !ok {
+		return
+	}
+
+	article, err := c.service.RestoreRevision(requestCtx, articleId, revisionNumber, userId)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "restore article revision", "Failed to restore article revision")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Article revision restored successfully",
+		"article": article,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+func (c *ArticleRevisionController) Route() {
+	// Semua endpoint revisi membutuhkan login, revisi draft tidak boleh publik
+	revisionRoutes := c.rg.Group("/article-revisions/:article_id")
+	revisionRoutes.Use(c.md.CheckToken("user", "editor", "admin"))
+	revisionRoutes.GET("/", c.GetRevisionsHandler)                      // GET /article-revisions/:article_id
+	revisionRoutes.GET("/diff", c.DiffRevisionsHandler)                 // GET /article-revisions/:article_id/diff?from=1&to=2
+	revisionRoutes.GET("/:revision", c.GetRevisionHandler)              // GET /article-revisions/:article_id/:revision
+	revisionRoutes.POST("/:revision/restore", c.RestoreRevisionHandler) // POST /article-revisions/:article_id/:revision/restore
+}
+
+func NewArticleRevisionController(rS service.ArticleRevisionService, aS service.ArticleService, md middleware.AuthMiddleware, rg *gin.RouterGroup, errorHandler middleware.ErrorHandler) *ArticleRevisionController {
+	return &ArticleRevisionController{
+		service:        rS,
+		articleService: aS,
+		md:             md,
+		rg:             rg,
+		errorHandler:   errorHandler,
+		responseHelper: utils.NewResponseHelper(),
+	}
+}
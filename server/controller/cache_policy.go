@@ -0,0 +1,14 @@
+package controller
+
+import (
+	"develapar-server/middleware"
+	"time"
+)
+
+// Cache-Control policies of the public read routes. Articles change most often and are
+// kept short with a window to revalidate in the background, categories and tags rarely change.
+var (
+	articleCachePolicy  = middleware.CachePolicy{MaxAge: time.Minute, StaleWhileRevalidate: 5 * time.Minute}
+	taxonomyCachePolicy = middleware.CachePolicy{MaxAge: 5 * time.Minute, StaleWhileRevalidate: 10 * time.Minute}
+	productCachePolicy  = middleware.CachePolicy{MaxAge: 2 * time.Minute, StaleWhileRevalidate: 5 * time.Minute}
+)
@@ -183,6 +183,8 @@ func (c *CategoryController) GetCategoryByIdHandler(ginCtx *gin.Context) {
 	}
 
 	// Create success response with context
+	setVersionETag(ginCtx, category.Version)
+	setLastModified(ginCtx, category.UpdatedAt)
 	responseData := gin.H{
 		"message":  "Category retrieved successfully",
 		"category": category,
@@ -196,9 +198,12 @@ func (c *CategoryController) GetCategoryByIdHandler(ginCtx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param category_id path int true "ID of the category to update"
+// @Param If-Match header string true "ETag of the category being updated"
 // @Param payload body dto.UpdateCategoryRequest true "Category update details"
 // @Success 200 {object} dto.APIResponse{data=object{message=string,category=model.Category}} "Category updated successfully"
 // @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid category ID or payload"
+// @Failure 412 {object} dto.APIResponse{error=dto.ErrorResponse} "The category was modified since it was read"
+// @Failure 428 {object} dto.APIResponse{error=dto.ErrorResponse} "If-Match header missing"
 // @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
 // @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
 // @Security BearerAuth
@@ -216,6 +221,11 @@ func (c *CategoryController) UpdateCategoryHandler(ginCtx *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(requestCtx, ginCtx, c.errorHandler, "Category")
+	if !ok {
+		return
+	}
+
 	var req dto.UpdateCategoryRequest
 	if err := ginCtx.ShouldBindJSON(&req); err != nil {
 		appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
@@ -224,7 +234,7 @@ func (c *CategoryController) UpdateCategoryHandler(ginCtx *gin.Context) {
 	}
 
 	// Call service with context
-	cat, err := c.service.UpdateCategory(requestCtx, id, req)
+	cat, err := c.service.UpdateCategory(requestCtx, id, version, req)
 	if err != nil {
 		// Check for context-specific errors
 		if requestCtx.Err() == context.DeadlineExceeded {
@@ -252,6 +262,7 @@ func (c *CategoryController) UpdateCategoryHandler(ginCtx *gin.Context) {
 	}
 
 	// Create success response with context
+	setVersionETag(ginCtx, cat.Version)
 	responseData := gin.H{
 		"message":  "Category updated successfully",
 		"category": cat,
@@ -320,8 +331,8 @@ func (c *CategoryController) DeleteCategoryHandler(ginCtx *gin.Context) {
 
 func (c *CategoryController) Route() {
 	router := c.rg.Group("/categories") // Changed from singular to plural
-	router.GET("/", c.GetAllCategoryHandler)
-	router.GET("/:category_id", c.GetCategoryByIdHandler) // Added missing endpoint
+	router.GET("/", middleware.HTTPCacheMiddleware(taxonomyCachePolicy), c.GetAllCategoryHandler)
+	router.GET("/:category_id", middleware.HTTPCacheMiddleware(taxonomyCachePolicy), c.GetCategoryByIdHandler) // Added missing endpoint
 
 	routerAuth := router.Group("/", c.md.CheckToken())
 	routerAuth.POST("/", c.CreateCategoryHandler)
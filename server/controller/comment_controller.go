@@ -4,6 +4,7 @@ import (
 	"context"
 	"develapar-server/middleware"
 	"develapar-server/model"
+	"develapar-server/model/dto"
 	"develapar-server/service"
 	"develapar-server/utils"
 	"errors"
@@ -16,6 +17,7 @@ import (
 
 type CommentController struct {
 	service        service.CommentService
+	articleService service.ArticleService
 	rg             *gin.RouterGroup
 	md             middleware.AuthMiddleware
 	errorHandler   middleware.ErrorHandler
@@ -23,14 +25,17 @@ type CommentController struct {
 }
 
 // @Summary Create a new comment
-// @Description Create a new comment on an article
+// @Description Create a new comment on an article, or a reply to one of its comments when parent_comment_id is set. Depending on the moderation policy of the article the comment is approved right away or waits in the moderation queue with status pending. Comments the spam filter scores as likely spam are held as pending, near certain spam gets status spam.
 // @Tags Comments
 // @Accept json
 // @Produce json
-// @Param payload body model.Comment true "Comment creation details"
+// @Param payload body dto.CreateCommentRequest true "Comment creation details"
+// @Param X-Article-Token header string false "Access token of an unlocked password protected article"
 // @Success 201 {object} dto.APIResponse{data=object{message=string,comment=model.Comment}} "Comment successfully created"
 // @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid payload"
 // @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Article is password protected"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
 // @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
 // @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
 // @Security BearerAuth
@@ -40,41 +45,35 @@ func (c *CommentController) CreateCommentHandler(ginCtx *gin.Context) {
 	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
 	defer cancel()
 
-	var payload model.Comment
-
-	userIdRaw, exists := ginCtx.Get("userId")
-	if !exists {
-		appErr := c.errorHandler.WrapError(requestCtx, nil, utils.ErrUnauthorized, "Authentication required")
+	userId, err := utils.GetUserIDFromGinContext(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrUnauthorized, "Authentication required")
 		appErr.StatusCode = 401
 		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
 		return
 	}
-	userIdFloat, ok := userIdRaw.(float64)
-	if !ok {
-		appErr := c.errorHandler.WrapError(requestCtx, nil, utils.ErrInternal, "Invalid user ID type")
-		appErr.StatusCode = 500
-		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
-		return
-	}
-	userId := int(userIdFloat)
 
-	userUUID, err := uuid.Parse(strconv.Itoa(userId))
-	if err != nil {
-		appErr := c.errorHandler.ValidationError(requestCtx, "userId", "Invalid user ID format: "+err.Error())
+	var req dto.CreateCommentRequest
+	if err := ginCtx.ShouldBindJSON(&req); err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
 		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
 		return
 	}
 
-	payload.User.Id = userUUID
-
-	if err := ginCtx.ShouldBindJSON(&payload); err != nil {
-		appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
-		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+	// Only readers of the article may comment on it
+	if !c.authorizeArticle(requestCtx, ginCtx, req.ArticleId) {
 		return
 	}
 
+	role, _ := utils.GetUserRoleFromContext(ginCtx)
+
 	// Call service with context
-	data, err := c.service.CreateComment(requestCtx, payload)
+	data, err := c.service.CreateComment(requestCtx, model.Comment{
+		ArticleId:       req.ArticleId,
+		UserId:          userId,
+		ParentCommentId: req.ParentCommentId,
+		Content:         req.Content,
+	}, role)
 	if err != nil {
 		// Check for context-specific errors
 		if requestCtx.Err() == context.DeadlineExceeded {
@@ -110,12 +109,19 @@ func (c *CommentController) CreateCommentHandler(ginCtx *gin.Context) {
 }
 
 // @Summary Get comments by article ID
-// @Description Get a list of comments for a specific article ID
+// @Description Get a page of the approved comments of a specific article ID, either as nested replies or as a list in thread order with depth and path. Pages hold up to limit top level comments in the chosen order, each with its first replies up to the configured maximum, has_more_replies marks a top level comment with more. While has_more is true pass next_cursor as cursor to get the next page. A page can hold fewer top level comments when some are hidden. most_liked orders by the like counts when each page is read, so a comment liked between two pages can be skipped or repeated. Signed in users also get their own pending comments. Deleted or hidden comments that still have replies are shown as "[deleted]".
 // @Tags Comments
 // @Produce json
-// @Param article_id path int true "ID of the article to retrieve comments for"
-// @Success 200 {object} dto.APIResponse{data=object{message=string,comments=[]model.Comment}} "List of comments for the article"
-// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID"
+// @Param article_id path string true "ID of the article to retrieve comments for"
+// @Param format query string false "Thread format: flat or tree" default(flat)
+// @Param sort query string false "Order of the top level comments: newest, oldest or most_liked" default(newest)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Top level comments per page (max 100), the configured page size when not set"
+// @Param X-Article-Token header string false "Access token of an unlocked password protected article"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,comments=[]model.Comment,next_cursor=string,has_more=bool}} "Page of comments for the article"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID, format, sort, cursor or limit"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Article is password protected"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
 // @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
 // @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
 // @Router /comments/article/{article_id} [get]
@@ -131,8 +137,31 @@ func (c *CommentController) FindCommentByArticleIdHandler(ginCtx *gin.Context) {
 		return
 	}
 
+	query := dto.CommentPageQuery{
+		Format: ginCtx.DefaultQuery("format", model.CommentFormatFlat),
+		Sort:   ginCtx.DefaultQuery("sort", model.CommentSortNewest),
+		Cursor: ginCtx.Query("cursor"),
+	}
+	if limitStr := ginCtx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err != nil || l <= 0 || l > 100 {
+			appErr := c.errorHandler.ValidationError(requestCtx, "limit", "Limit must be a positive integer between 1 and 100")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		} else {
+			query.Limit = l
+		}
+	}
+
+	// The thread is only shown to readers of the article
+	if !c.authorizeArticle(requestCtx, ginCtx, articleId) {
+		return
+	}
+
+	// Signed in users also see their own pending comments
+	viewerId, _ := utils.GetUserIDFromGinContext(ginCtx)
+
 	// Call service with context
-	comments, err := c.service.FindCommentByArticleId(requestCtx, articleId)
+	page, err := c.service.FindCommentByArticleId(requestCtx, articleId, query, viewerId)
 	if err != nil {
 		// Check for context-specific errors
 		if requestCtx.Err() == context.DeadlineExceeded {
@@ -161,14 +190,16 @@ func (c *CommentController) FindCommentByArticleIdHandler(ginCtx *gin.Context) {
 
 	// Create success response with context
 	responseData := gin.H{
-		"message":  "Comments retrieved successfully",
-		"comments": comments,
+		"message":     "Comments retrieved successfully",
+		"comments":    page.Comments,
+		"next_cursor": page.NextCursor,
+		"has_more":    page.HasMore,
 	}
 	c.responseHelper.SendSuccess(ginCtx, responseData)
 }
 
 // @Summary Get comments by user ID
-// @Description Get a list of comments by a specific user ID
+// @Description Get the approved comments of a specific user ID on publicly listed articles, users viewing their own comments also get the pending ones. Signed in users also get the comments on articles they may edit.
 // @Tags Comments
 // @Produce json
 // @Param user_id path int true "ID of the user whose comments to retrieve"
@@ -189,8 +220,11 @@ func (c *CommentController) FindCommentByUserIdHandler(ginCtx *gin.Context) {
 		return
 	}
 
+	// Signed in users also see their own pending comments
+	viewerId, _ := utils.GetUserIDFromGinContext(ginCtx)
+
 	// Call service with context
-	comments, err := c.service.FindCommentByUserId(requestCtx, user_id)
+	comments, err := c.service.FindCommentByUserId(requestCtx, user_id, viewerId)
 	if err != nil {
 		// Check for context-specific errors
 		if requestCtx.Err() == context.DeadlineExceeded {
@@ -226,7 +260,7 @@ func (c *CommentController) FindCommentByUserIdHandler(ginCtx *gin.Context) {
 }
 
 // @Summary Update a comment
-// @Description Update an existing comment by ID
+// @Description Update an existing comment by ID. The previous content is kept in the edit history and the comment is marked as edited. Authors can only edit within the configured grace period after posting, admins any time.
 // @Tags Comments
 // @Accept json
 // @Produce json
@@ -235,7 +269,7 @@ func (c *CommentController) FindCommentByUserIdHandler(ginCtx *gin.Context) {
 // @Success 200 {object} dto.APIResponse{data=object{message=string}} "Comment updated successfully"
 // @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid payload"
 // @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
-// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden (user does not own the comment)"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden (user does not own the comment or the grace period has passed)"
 // @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
 // @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
 // @Security BearerAuth
@@ -283,8 +317,10 @@ func (c *CommentController) UpdateCommentHandler(ginCtx *gin.Context) {
 		return
 	}
 
+	role, _ := utils.GetUserRoleFromContext(ginCtx)
+
 	// Call service with context
-	err = c.service.EditComment(requestCtx, commentId, req.Content, userId)
+	err = c.service.EditComment(requestCtx, commentId, req.Content, userId, role)
 	if err != nil {
 		// Check for context-specific errors
 		if requestCtx.Err() == context.DeadlineExceeded {
@@ -406,21 +442,221 @@ func (c *CommentController) DeleteCommentHandler(ginCtx *gin.Context) {
 	c.responseHelper.SendSuccess(ginCtx, responseData)
 }
 
+// authorizeArticle checks that the requesting user may read the article under its
+// visibility and status, and answers the request when not. Hidden articles answer as
+// not found, password protected ones as forbidden unless unlocked.
+func (c *CommentController) authorizeArticle(requestCtx context.Context, ginCtx *gin.Context, articleId uuid.UUID) bool {
+	article, err := c.articleService.FindById(requestCtx, articleId)
+	if err == nil {
+		err = c.articleService.AuthorizeView(requestCtx, article, articleViewer(ginCtx))
+	} else if requestCtx.Err() == nil {
+		if _, ok := err.(*utils.AppError); !ok {
+			appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrNotFound, "Article not found")
+			appErr.StatusCode = 404
+			err = appErr
+		}
+	}
+	if err == nil {
+		return true
+	}
+
+	// Check for context-specific errors
+	if requestCtx.Err() == context.DeadlineExceeded {
+		appErr := c.errorHandler.TimeoutError(requestCtx, "check article access")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return false
+	}
+	if requestCtx.Err() == context.Canceled {
+		appErr := c.errorHandler.CancellationError(requestCtx, "check article access")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return false
+	}
+
+	// Check if it's already an AppError
+	if appErr, ok := err.(*utils.AppError); ok {
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return false
+	}
+
+	// Wrap as internal error
+	appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to check article access")
+	appErr.StatusCode = 500
+	c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+	return false
+}
+
+// @Summary Like a comment
+// @Description Like an approved comment. Liking a comment twice keeps one like.
+// @Tags Comments
+// @Produce json
+// @Param comment_id path string true "ID of the comment to like"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,like_count=int}} "Comment liked"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid comment ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Comment not found"
+// @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /comments/{comment_id}/like [post]
+func (c *CommentController) LikeCommentHandler(ginCtx *gin.Context) {
+	c.handleCommentLike(ginCtx, "like comment", "Comment liked successfully", c.service.LikeComment)
+}
+
+// @Summary Unlike a comment
+// @Description Remove the like of the signed in user from a comment
+// @Tags Comments
+// @Produce json
+// @Param comment_id path string true "ID of the comment to unlike"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,like_count=int}} "Comment unliked"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid comment ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Comment not found"
+// @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /comments/{comment_id}/like [delete]
+func (c *CommentController) UnlikeCommentHandler(ginCtx *gin.Context) {
+	c.handleCommentLike(ginCtx, "unlike comment", "Comment unliked successfully", c.service.UnlikeComment)
+}
+
+// handleCommentLike runs a like or unlike of the comment in the path for the signed in user
+func (c *CommentController) handleCommentLike(ginCtx *gin.Context, operation, message string, apply func(ctx context.Context, commentId, userId uuid.UUID) (int, error)) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	userId, err := utils.GetUserIDFromGinContext(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrUnauthorized, "Authentication required")
+		appErr.StatusCode = 401
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	commentId, err := uuid.Parse(ginCtx.Param("comment_id"))
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "comment_id", "Invalid comment ID: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Call service with context
+	likes, err := apply(requestCtx, commentId, userId)
+	if err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, operation)
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, operation)
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Check if it's already an AppError
+		if appErr, ok := err.(*utils.AppError); ok {
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Wrap as internal error
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to "+operation)
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Create success response with context
+	responseData := gin.H{
+		"message":    message,
+		"like_count": likes,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Get the edit history of a comment
+// @Description List every edit of a comment oldest first, with the content before and after the edit and who made it. Editor or admin only.
+// @Tags Comments
+// @Produce json
+// @Param comment_id path string true "ID of the comment"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,edits=[]model.CommentEdit}} "Edit history of the comment"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid comment ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Comment not found"
+// @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /comments/{comment_id}/edits [get]
+func (c *CommentController) FindCommentEditsHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	commentId, err := uuid.Parse(ginCtx.Param("comment_id"))
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "comment_id", "Invalid comment ID: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Call service with context
+	edits, err := c.service.FindCommentEdits(requestCtx, commentId)
+	if err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, "get comment edits")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, "get comment edits")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Check if it's already an AppError
+		if appErr, ok := err.(*utils.AppError); ok {
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Wrap as internal error
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to retrieve comment edits")
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Create success response with context
+	responseData := gin.H{
+		"message": "Comment edits retrieved successfully",
+		"edits":   edits,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
 func (c *CommentController) Route() {
-	router := c.rg.Group("/comments")                                   // Changed from singular to plural
-	router.GET("/article/:article_id", c.FindCommentByArticleIdHandler) // Fixed typo: c:article_id to :article_id
-	router.GET("/user/:user_id", c.FindCommentByUserIdHandler)
+	router := c.rg.Group("/comments")                                                         // Changed from singular to plural
+	router.GET("/article/:article_id", c.md.OptionalToken(), c.FindCommentByArticleIdHandler) // Fixed typo: c:article_id to :article_id
+	router.GET("/user/:user_id", c.md.OptionalToken(), c.FindCommentByUserIdHandler)
+	router.GET("/:comment_id/edits", c.md.CheckToken("editor", "admin"), c.FindCommentEditsHandler)
 
 	routerAuth := router.Group("/", c.md.CheckToken())
 
 	routerAuth.POST("/", c.CreateCommentHandler)
 	routerAuth.PUT("/:comment_id", c.UpdateCommentHandler)    // Changed from :id to :comment_id for consistency
 	routerAuth.DELETE("/:comment_id", c.DeleteCommentHandler) // Changed from :id to :comment_id for consistency
+	routerAuth.POST("/:comment_id/like", c.LikeCommentHandler)
+	routerAuth.DELETE("/:comment_id/like", c.UnlikeCommentHandler)
 }
 
-func NewCommentController(cS service.CommentService, rg *gin.RouterGroup, md middleware.AuthMiddleware, errorHandler middleware.ErrorHandler) *CommentController {
+func NewCommentController(cS service.CommentService, aS service.ArticleService, rg *gin.RouterGroup, md middleware.AuthMiddleware, errorHandler middleware.ErrorHandler) *CommentController {
 	return &CommentController{
 		service:        cS,
+		articleService: aS,
 		rg:             rg,
 		md:             md,
 		errorHandler:   errorHandler,
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"develapar-server/middleware"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/service"
+	"develapar-server/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCommentService returns empty threads and counts the calls that got through
+type fakeCommentService struct {
+	service.CommentService
+	calls int
+}
+
+func (s *fakeCommentService) FindCommentByArticleId(ctx context.Context, articleId uuid.UUID, query dto.CommentPageQuery, viewerId uuid.UUID) (service.CommentPage, error) {
+	s.calls++
+	return service.CommentPage{}, nil
+}
+
+func (s *fakeCommentService) CreateComment(ctx context.Context, payload model.Comment, role string) (model.Comment, error) {
+	s.calls++
+	return payload, nil
+}
+
+// fakeArticleAccessService finds the articles in articles and denies their view with viewErr
+type fakeArticleAccessService struct {
+	service.ArticleService
+	articles map[uuid.UUID]model.Article
+	viewErr  error
+}
+
+func (s *fakeArticleAccessService) FindById(ctx context.Context, id uuid.UUID) (model.Article, error) {
+	article, ok := s.articles[id]
+	if !ok {
+		return model.Article{}, utils.NewErrorWrapper().NotFoundError(ctx, "Article")
+	}
+	return article, nil
+}
+
+func (s *fakeArticleAccessService) AuthorizeView(ctx context.Context, article model.Article, viewer service.ArticleViewer) error {
+	return s.viewErr
+}
+
+func TestCommentController_ArticleVisibility(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	errorWrapper := utils.NewErrorWrapper()
+	ctx := context.Background()
+	article := model.Article{Id: uuid.New(), Status: model.ArticleStatusPublished}
+
+	tests := []struct {
+		name       string
+		articleId  uuid.UUID
+		viewErr    error
+		wantStatus int
+	}{
+		{name: "readable article", articleId: article.Id},
+		{name: "hidden article", articleId: article.Id, viewErr: errorWrapper.NotFoundError(ctx, "Article"), wantStatus: http.StatusNotFound},
+		{name: "password protected article", articleId: article.Id, viewErr: errorWrapper.ForbiddenError(ctx, "This article is password protected"), wantStatus: http.StatusForbidden},
+		{name: "missing article", articleId: uuid.New(), wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		for _, method := range []string{http.MethodGet, http.MethodPost} {
+			t.Run(method+" "+tt.name, func(t *testing.T) {
+				comments := &fakeCommentService{}
+				articles := &fakeArticleAccessService{articles: map[uuid.UUID]model.Article{article.Id: article}, viewErr: tt.viewErr}
+				router := gin.New()
+				router.Use(func(c *gin.Context) {
+					c.Set("userId", uuid.NewString())
+					c.Set("role", "user")
+				})
+				controller := NewCommentController(comments, articles, router.Group(""), nil, middleware.NewErrorHandler(nil))
+				router.GET("/comments/article/:article_id", controller.FindCommentByArticleIdHandler)
+				router.POST("/comments", controller.CreateCommentHandler)
+
+				req := httptest.NewRequest(http.MethodGet, "/comments/article/"+tt.articleId.String(), nil)
+				wantStatus := http.StatusOK
+				if method == http.MethodPost {
+					body := `{"article_id":"` + tt.articleId.String() + `","content":"Nice write-up"}`
+					req = httptest.NewRequest(http.MethodPost, "/comments", strings.NewReader(body))
+					req.Header.Set("Content-Type", "application/json")
+					wantStatus = http.StatusCreated
+				}
+				if tt.wantStatus != 0 {
+					wantStatus = tt.wantStatus
+				}
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, wantStatus, w.Code, w.Body.String())
+				if tt.wantStatus != 0 {
+					assert.Zero(t, comments.calls)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package controller
+
+import (
+	"context"
+	"develapar-server/middleware"
+	"develapar-server/model/dto"
+	"develapar-server/service"
+	"develapar-server/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CommentModerationController struct {
+	service        service.CommentModerationService
+	md             middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+	errorHandler   middleware.ErrorHandler
+	responseHelper *utils.ResponseHelper
+}
+
+// handleServiceError maps service errors to error responses
+func (c *CommentModerationController) handleServiceError(requestCtx context.Context, ginCtx *gin.Context, err error, operation, message string) {
+	// Check for context-specific errors
+	if requestCtx.Err() == context.DeadlineExceeded {
+		appErr := c.errorHandler.TimeoutError(requestCtx, operation)
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+	if requestCtx.Err() == context.Canceled {
+		appErr := c.errorHandler.CancellationError(requestCtx, operation)
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Check if it's already an AppError
+	if appErr, ok := err.(*utils.AppError); ok {
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Wrap as internal error
+	appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, message)
+	appErr.StatusCode = 500
+	c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+}
+
+// moderatorId returns the signed in editor or admin, or answers 401 and returns false
+func (c *CommentModerationController) moderatorId(requestCtx context.Context, ginCtx *gin.Context) (uuid.UUID, bool) {
+	userId, err := utils.GetUserIDFromGinContext(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrUnauthorized, "Authentication required")
+		appErr.StatusCode = 401
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return uuid.Nil, false
+	}
+	return userId, true
+}
+
+// articleIdQuery parses the optional article_id query parameter, answering 400 and
+// returning false when it is not a valid ID
+func (c *CommentModerationController) articleIdQuery(requestCtx context.Context, ginCtx *gin.Context) (*uuid.UUID, bool) {
+	articleIdStr := ginCtx.Query("article_id")
+	if articleIdStr == "" {
+		return nil, true
+	}
+	articleId, err := uuid.Parse(articleIdStr)
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "article_id", "Invalid article ID format")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return nil, false
+	}
+	return &articleId, true
+}
+
+// @Summary Get the comment moderation queue
+// @Description List the comments with a moderation status, pending by default, oldest first, with the spam score and signals of each comment. Editor or admin only.
+// @Tags Comment Moderation
+// @Produce json
+// @Param status query string false "Moderation status" Enums(pending, approved, rejected, spam)
+// @Param article_id query string false "Only list the comments of one article"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of comments per page (default: 10, max: 100)"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,comments=[]model.Comment},pagination=dto.PaginationMetadata} "Paginated moderation queue"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid status, article ID or pagination parameters"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /comments/moderation [get]
+func (c *CommentModerationController) GetQueueHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	articleId, ok := c.articleIdQuery(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	// Get pagination parameters from query string
+	page := 1
+	limit := 10
+
+	if pageStr := ginCtx.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err != nil || p <= 0 {
+			appErr := c.errorHandler.ValidationError(requestCtx, "page", "Page must be a positive integer")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		} else {
+			page = p
+		}
+	}
+
+	if limitStr := ginCtx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err != nil || l <= 0 || l > 100 {
+			appErr := c.errorHandler.ValidationError(requestCtx, "limit", "Limit must be a positive integer between 1 and 100")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		} else {
+			limit = l
+		}
+	}
+
+	result, err := c.service.FindQueue(requestCtx, ginCtx.Query("status"), articleId, page, limit)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "get moderation queue", "Failed to retrieve moderation queue")
+		return
+	}
+
+	responseData := gin.H{
+		"message":  "Moderation queue retrieved successfully",
+		"comments": result.Data,
+	}
+	c.responseHelper.SendSuccessWithServicePagination(ginCtx, responseData, result.Metadata)
+}
+
+// @Summary Moderate comments in bulk
+// @Description Approve, reject or mark as spam up to 100 comments at once. Deleted or unknown comments are skipped. Approved and spam decisions train the spam classifier. Editor or admin only.
+// @Tags Comment Moderation
+// @Accept json
+// @Produce json
+// @Param payload body dto.ModerateCommentsRequest true "Comments and moderation action"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,moderated=int}} "Number of moderated comments"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid payload"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /comments/moderation [post]
+func (c *CommentModerationController) ModerateHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	moderatorId, ok := c.moderatorId(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	var req dto.ModerateCommentsRequest
+	if err := ginCtx.ShouldBindJSON(&req); err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	moderated, err := c.service.Moderate(requestCtx, req.CommentIds, req.Action, moderatorId)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "moderate comments", "Failed to moderate comments")
+		return
+	}
+
+	responseData := gin.H{
+		"message":   "Comments moderated successfully",
+		"moderated": moderated,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Get a comment moderation policy
+// @Description Get the moderation policy applied to the comments of an article, or the global policy without article_id. Editor or admin only.
+// @Tags Comment Moderation
+// @Produce json
+// @Param article_id query string false "Article ID"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,policy=model.CommentModerationPolicy}} "Policy in effect"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /comments/moderation/policy [get]
+func (c *CommentModerationController) GetPolicyHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	articleId, ok := c.articleIdQuery(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	policy, err := c.service.GetPolicy(requestCtx, articleId)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "get comment moderation policy", "Failed to retrieve comment moderation policy")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Comment moderation policy retrieved successfully",
+		"policy":  policy,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Set a comment moderation policy
+// @Description Set the global moderation policy, or the policy of one article when article_id is set. auto_approve shows every comment right away, hold_first_time holds the comments of users without an approved comment, hold_untrusted holds them until the user has enough approved comments. Comments of editors and admins are always approved. Editor or admin only.
+// @Tags Comment Moderation
+// @Accept json
+// @Produce json
+// @Param payload body dto.SetCommentPolicyRequest true "Policy"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,policy=model.CommentModerationPolicy}} "Stored policy"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid payload"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Article not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /comments/moderation/policy [put]
+func (c *CommentModerationController) SetPolicyHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	moderatorId, ok := c.moderatorId(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	var req dto.SetCommentPolicyRequest
+	if err := ginCtx.ShouldBindJSON(&req); err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	policy, err := c.service.SetPolicy(requestCtx, req.ArticleId, req.Mode, moderatorId)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "set comment moderation policy", "Failed to set comment moderation policy")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Comment moderation policy saved successfully",
+		"policy":  policy,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Remove the moderation policy of an article
+// @Description Remove the policy of an article so the global policy applies to its comments again. Editor or admin only.
+// @Tags Comment Moderation
+// @Produce json
+// @Param article_id path string true "Article ID"
+// @Success 200 {object} dto.APIResponse{data=object{message=string}} "Policy removed"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid article ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "The article has no policy"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /comments/moderation/policy/{article_id} [delete]
+func (c *CommentModerationController) DeletePolicyHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	articleId, err := uuid.Parse(ginCtx.Param("article_id"))
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "article_id", "Invalid article ID format")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	if err := c.service.DeletePolicy(requestCtx, articleId); err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "delete comment moderation policy", "Failed to delete comment moderation policy")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Comment moderation policy removed successfully",
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+func (c *CommentModerationController) Route() {
+	// Moderasi komentar hanya untuk editor dan admin
+	moderationRoutes := c.rg.Group("/comments/moderation")
+	moderationRoutes.Use(c.md.CheckToken("editor", "admin"))
+	moderationRoutes.GET("", c.GetQueueHandler)                           // GET /comments/moderation?status=pending
+	moderationRoutes.POST("", c.ModerateHandler)                          // POST /comments/moderation (bulk approve/reject/spam)
+	moderationRoutes.GET("/policy", c.GetPolicyHandler)                   // GET /comments/moderation/policy?article_id=
+	moderationRoutes.PUT("/policy", c.SetPolicyHandler)                   // PUT /comments/moderation/policy
+	moderationRoutes.DELETE("/policy/:article_id", c.DeletePolicyHandler) // DELETE /comments/moderation/policy/:article_id
+}
+
+func NewCommentModerationController(cmS service.CommentModerationService, md middleware.AuthMiddleware, rg *gin.RouterGroup, errorHandler middleware.ErrorHandler) *CommentModerationController {
+	return &CommentModerationController{
+		service:        cmS,
+		md:             md,
+		rg:             rg,
+		errorHandler:   errorHandler,
+		responseHelper: utils.NewResponseHelper(),
+	}
+}
@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"context"
+	"develapar-server/middleware"
+	"develapar-server/model/dto"
+	"develapar-server/service"
+	"develapar-server/utils"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// feedCacheControl lets clients and proxies reuse a feed for a few minutes before revalidating
+const feedCacheControl = "public, max-age=300"
+
+// feedFiles maps the file name of every feed route to the format served under it
+var feedFiles = map[string]string{
+	"feed.xml":  service.FeedFormatRSS,
+	"atom.xml":  service.FeedFormatAtom,
+	"feed.json": service.FeedFormatJSON,
+}
+
+type FeedController struct {
+	service      service.FeedService
+	rg           *gin.RouterGroup
+	errorHandler middleware.ErrorHandler
+}
+
+// serveFeed serves one feed in the given format. filterFn builds the article filter
+// from the request and reports false after writing an error response.
+func (c *FeedController) serveFeed(ginCtx *gin.Context, format string, filterFn func(requestCtx context.Context, ginCtx *gin.Context) (dto.ArticleFeedFilter, bool)) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	filter := dto.ArticleFeedFilter{}
+	if filterFn != nil {
+		var ok bool
+		if filter, ok = filterFn(requestCtx, ginCtx); !ok {
+			return
+		}
+	}
+
+	document, err := c.service.GetFeed(requestCtx, ginCtx.Request.URL.Path, filter, format)
+	if err != nil {
+		// Check for context-specific errors
+		if requestCtx.Err() == context.DeadlineExceeded {
+			appErr := c.errorHandler.TimeoutError(requestCtx, "get feed")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+		if requestCtx.Err() == context.Canceled {
+			appErr := c.errorHandler.CancellationError(requestCtx, "get feed")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		// Check if it's already an AppError
+		if appErr, ok := err.(*utils.AppError); ok {
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to generate feed")
+		appErr.StatusCode = 500
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	ginCtx.Header("ETag", document.ETag)
+	ginCtx.Header("Last-Modified", document.LastModified.UTC().Format(http.TimeFormat))
+	ginCtx.Header("Cache-Control", feedCacheControl)
+
+	if utils.IsNotModified(ginCtx.Request, document.ETag, document.LastModified) {
+		ginCtx.Status(http.StatusNotModified)
+		return
+	}
+
+	ginCtx.Data(http.StatusOK, document.ContentType, document.Body)
+}
+
+// routeFeedFormat returns the format of the feed file the matched route ends in
+func routeFeedFormat(ginCtx *gin.Context) string {
+	return feedFiles[path.Base(ginCtx.FullPath())]
+}
+
+func categoryFeedFilter(requestCtx context.Context, ginCtx *gin.Context) (dto.ArticleFeedFilter, bool) {
+	return dto.ArticleFeedFilter{Category: ginCtx.Param("category_name")}, true
+}
+
+func tagFeedFilter(requestCtx context.Context, ginCtx *gin.Context) (dto.ArticleFeedFilter, bool) {
+	return dto.ArticleFeedFilter{Tag: ginCtx.Param("tag_name")}, true
+}
+
+func (c *FeedController) authorFeedFilter(requestCtx context.Context, ginCtx *gin.Context) (dto.ArticleFeedFilter, bool) {
+	userId, err := uuid.Parse(ginCtx.Param("user_id"))
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "user_id", "Invalid user ID format")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return dto.ArticleFeedFilter{}, false
+	}
+	return dto.ArticleFeedFilter{UserId: userId}, true
+}
+
+// @Summary RSS feed
+// @Description RSS 2.0 feed of the latest published articles. Supports conditional GET with ETag and Last-Modified.
+// @Tags Feeds
+// @Produce xml
+// @Success 200 {string} string "RSS feed"
+// @Success 304 "Not modified"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /feed.xml [get]
+func (c *FeedController) RSSFeedHandler(ginCtx *gin.Context) {
+	c.serveFeed(ginCtx, service.FeedFormatRSS, nil)
+}
+
+// @Summary Atom feed
+// @Description Atom 1.0 feed of the latest published articles. Supports conditional GET with ETag and Last-Modified.
+// @Tags Feeds
+// @Produce xml
+// @Success 200 {string} string "Atom feed"
+// @Success 304 "Not modified"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /atom.xml [get]
+func (c *FeedController) AtomFeedHandler(ginCtx *gin.Context) {
+	c.serveFeed(ginCtx, service.FeedFormatAtom, nil)
+}
+
+// @Summary JSON feed
+// @Description JSON Feed 1.1 of the latest published articles. Supports conditional GET with ETag and Last-Modified.
+// @Tags Feeds
+// @Produce json
+// @Success 200 {string} string "JSON feed"
+// @Success 304 "Not modified"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /feed.json [get]
+func (c *FeedController) JSONFeedHandler(ginCtx *gin.Context) {
+	c.serveFeed(ginCtx, service.FeedFormatJSON, nil)
+}
+
+// @Summary Category feeds
+// @Description RSS, Atom and JSON feeds of the latest published articles in a category.
+// @Tags Feeds
+// @Produce xml,json
+// @Param category_name path string true "Category name"
+// @Success 200 {string} string "Feed"
+// @Success 304 "Not modified"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Category not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /feeds/category/{category_name}/feed.xml [get]
+// @Router /feeds/category/{category_name}/atom.xml [get]
+// @Router /feeds/category/{category_name}/feed.json [get]
+func (c *FeedController) CategoryFeedHandler(ginCtx *gin.Context) {
+	c.serveFeed(ginCtx, routeFeedFormat(ginCtx), categoryFeedFilter)
+}
+
+// @Summary Tag feeds
+// @Description RSS, Atom and JSON feeds of the latest published articles with a tag.
+// @Tags Feeds
+// @Produce xml,json
+// @Param tag_name path string true "Tag name"
+// @Success 200 {string} string "Feed"
+// @Success 304 "Not modified"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Tag not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /feeds/tag/{tag_name}/feed.xml [get]
+// @Router /feeds/tag/{tag_name}/atom.xml [get]
+// @Router /feeds/tag/{tag_name}/feed.json [get]
+func (c *FeedController) TagFeedHandler(ginCtx *gin.Context) {
+	c.serveFeed(ginCtx, routeFeedFormat(ginCtx), tagFeedFilter)
+}
+
+// @Summary Author feeds
+// @Description RSS, Atom and JSON feeds of the latest published articles by an author.
+// @Tags Feeds
+// @Produce xml,json
+// @Param user_id path string true "Author user ID"
+// @Success 200 {string} string "Feed"
+// @Success 304 "Not modified"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid user ID"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Author not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /feeds/author/{user_id}/feed.xml [get]
+// @Router /feeds/author/{user_id}/atom.xml [get]
+// @Router /feeds/author/{user_id}/feed.json [get]
+func (c *FeedController) AuthorFeedHandler(ginCtx *gin.Context) {
+	c.serveFeed(ginCtx, routeFeedFormat(ginCtx), c.authorFeedFilter)
+}
+
+func (c *FeedController) Route() {
+	c.rg.GET("/feed.xml", c.RSSFeedHandler)
+	c.rg.GET("/atom.xml", c.AtomFeedHandler)
+	c.rg.GET("/feed.json", c.JSONFeedHandler)
+
+	feedRoutes := c.rg.Group("/feeds")
+	categoryRoutes := feedRoutes.Group("/category/:category_name")
+	tagRoutes := feedRoutes.Group("/tag/:tag_name")
+	authorRoutes := feedRoutes.Group("/author/:user_id")
+	for file := range feedFiles {
+		categoryRoutes.GET("/"+file, c.CategoryFeedHandler)
+		tagRoutes.GET("/"+file, c.TagFeedHandler)
+		authorRoutes.GET("/"+file, c.AuthorFeedHandler)
+	}
+}
+
+func NewFeedController(feedService service.FeedService, rg *gin.RouterGroup, errorHandler middleware.ErrorHandler) *FeedController {
+	return &FeedController{
+		service:      feedService,
+		rg:           rg,
+		errorHandler: errorHandler,
+	}
+}
@@ -198,6 +198,39 @@ func (mc *MetricsController) GetErrorMetrics(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetCacheMetrics godoc
+// @Summary Get cache metrics
+// @Description Get read cache metrics including hits, misses and hit rate per cached service
+// @Tags metrics
+// @Accept json
+// @Produce json
+// @Success 200 {object} MetricsResponse
+// @Failure 500 {object} MetricsResponse
+// @Router /metrics/cache [get]
+func (mc *MetricsController) GetCacheMetrics(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID := getRequestID(ctx)
+	
+	// Create timeout context for metrics collection
+	metricsCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	mc.logger.Info(metricsCtx, "Retrieving cache metrics", 
+		utils.Field{Key: "request_id", Value: requestID})
+
+	// Get cache metrics with context
+	metrics := mc.metricsService.GetCacheMetrics(metricsCtx)
+
+	response := MetricsResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		RequestID: requestID,
+		Data:      metrics,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // ResetMetrics godoc
 // @Summary Reset all metrics
 // @Description Reset all collected metrics to zero (useful for testing or periodic resets)
@@ -280,6 +313,11 @@ func (mc *MetricsController) GetMetricsSummary(c *gin.Context) {
 			"total_errors": allMetrics.Error.TotalErrors,
 			"error_rate":   allMetrics.Error.ErrorRate,
 		},
+		"cache": map[string]interface{}{
+			"hits":     allMetrics.Cache.Hits,
+			"misses":   allMetrics.Cache.Misses,
+			"hit_rate": allMetrics.Cache.HitRate,
+		},
 		"health": map[string]interface{}{
 			"status": mc.getHealthStatus(allMetrics),
 		},
@@ -336,6 +374,7 @@ func (mc *MetricsController) Route(rg *gin.RouterGroup) {
 		metricsGroup.GET("/database", mc.GetDatabaseMetrics)
 		metricsGroup.GET("/application", mc.GetApplicationMetrics)
 		metricsGroup.GET("/errors", mc.GetErrorMetrics)
+		metricsGroup.GET("/cache", mc.GetCacheMetrics)
 		metricsGroup.POST("/reset", mc.ResetMetrics)
 	}
 }
\ No newline at end of file
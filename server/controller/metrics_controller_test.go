@@ -44,6 +44,10 @@ func (m *MockMetricsService) RecordGoroutineCount(ctx context.Context, count int
 	m.Called(ctx, count)
 }
 
+func (m *MockMetricsService) RecordCacheLookup(ctx context.Context, cacheName string, hit bool) {
+	m.Called(ctx, cacheName, hit)
+}
+
 func (m *MockMetricsService) GetRequestMetrics(ctx context.Context) service.RequestMetrics {
 	args := m.Called(ctx)
 	return args.Get(0).(service.RequestMetrics)
@@ -64,6 +68,11 @@ func (m *MockMetricsService) GetErrorMetrics(ctx context.Context) service.ErrorM
 	return args.Get(0).(service.ErrorMetrics)
 }
 
+func (m *MockMetricsService) GetCacheMetrics(ctx context.Context) service.CacheMetrics {
+	args := m.Called(ctx)
+	return args.Get(0).(service.CacheMetrics)
+}
+
 func (m *MockMetricsService) GetAllMetrics(ctx context.Context) service.AllMetrics {
 	args := m.Called(ctx)
 	return args.Get(0).(service.AllMetrics)
@@ -178,6 +187,48 @@ func TestMetricsController_GetRequestMetrics(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestMetricsController_GetCacheMetrics(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockMetricsService)
+	controller := &MetricsController{
+		metricsService: mockService,
+		logger:         utils.NewDefaultLogger("test"),
+	}
+
+	// Mock data
+	expectedMetrics := service.CacheMetrics{
+		Hits:          30,
+		Misses:        10,
+		HitRate:       75.0,
+		HitsByCache:   map[string]int64{"article": 20, "category": 10},
+		MissesByCache: map[string]int64{"article": 8, "category": 2},
+		LastUpdated:   time.Now(),
+	}
+
+	mockService.On("GetCacheMetrics", mock.AnythingOfType("*context.timerCtx")).Return(expectedMetrics)
+
+	// Create request
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest("GET", "/metrics/cache", nil)
+	c.Request = req
+
+	// Execute
+	controller.GetCacheMetrics(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	
+	var response MetricsResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "success", response.Status)
+	assert.NotNil(t, response.Data)
+
+	mockService.AssertExpectations(t)
+}
+
 func TestMetricsController_ResetMetrics(t *testing.T) {
 	// Setup
 	gin.SetMode(gin.TestMode)
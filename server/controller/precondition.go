@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"context"
+	"develapar-server/middleware"
+	"develapar-server/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ifMatchVersion reads the version an update was made from out of the If-Match header.
+// A missing header answers 428 and a header without a version tag answers 412, in both
+// cases the error response is already sent and ok is false.
+func ifMatchVersion(requestCtx context.Context, ginCtx *gin.Context, errorHandler middleware.ErrorHandler, resource string) (int, bool) {
+	header := ginCtx.GetHeader("If-Match")
+	if header == "" {
+		errorHandler.HandleError(requestCtx, ginCtx, errorHandler.PreconditionRequiredError(requestCtx, resource))
+		return 0, false
+	}
+
+	version, ok := utils.IfMatchVersion(header)
+	if !ok {
+		errorHandler.HandleError(requestCtx, ginCtx, errorHandler.PreconditionFailedError(requestCtx, resource))
+		return 0, false
+	}
+	return version, true
+}
+
+// setVersionETag sends the version of a resource as its ETag, the value clients echo in If-Match
+func setVersionETag(ginCtx *gin.Context, version int) {
+	ginCtx.Header("ETag", utils.VersionETag(version))
+}
+
+// setLastModified sends when a resource last changed, the value clients echo in
+// If-Modified-Since on cached routes
+func setLastModified(ginCtx *gin.Context, modifiedAt time.Time) {
+	ginCtx.Header("Last-Modified", modifiedAt.UTC().Format(http.TimeFormat))
+}
@@ -40,9 +40,9 @@ func (c *ProductController) Route() {
 	// Product Categories routes
 	{
 		routerProductCat := c.rg.Group("/product-categories")
-		routerProductCat.GET("/", c.GetAllProductCategories)
-		routerProductCat.GET("/:id", c.GetProductCategoryById)
-		routerProductCat.GET("/s/:slug", c.GetProductCategoryBySlug)
+		routerProductCat.GET("/", middleware.HTTPCacheMiddleware(taxonomyCachePolicy), c.GetAllProductCategories)
+		routerProductCat.GET("/:id", middleware.HTTPCacheMiddleware(taxonomyCachePolicy), c.GetProductCategoryById)
+		routerProductCat.GET("/s/:slug", middleware.HTTPCacheMiddleware(taxonomyCachePolicy), c.GetProductCategoryBySlug)
 
 		routerPCAuth := routerProductCat.Group("/", c.mD.CheckToken("admin"))
 		routerPCAuth.POST("/", c.CreateProductCategory)
@@ -53,8 +53,8 @@ func (c *ProductController) Route() {
 	// Products routes
 	{
 		routerProduct := c.rg.Group("/products")
-		routerProduct.GET("/", c.GetAllProducts)
-		routerProduct.GET("/:id", c.GetProductById)
+		routerProduct.GET("/", middleware.HTTPCacheMiddleware(productCachePolicy), c.GetAllProducts)
+		routerProduct.GET("/:id", middleware.HTTPCacheMiddleware(productCachePolicy), c.GetProductById)
 		routerProduct.GET("/c/:id", c.GetProductsByCategory)
 		routerProduct.GET("/a/:id", c.GetProductsByArticleId)
 
@@ -245,6 +245,8 @@ func (c *ProductController) GetProductCategoryById(ginCtx *gin.Context) {
 		return
 	}
 
+	setVersionETag(ginCtx, data.Version)
+	setLastModified(ginCtx, data.UpdatedAt)
 	responseData := gin.H{
 		"message":          "Product Category retrieved successfully",
 		"product_category": data,
@@ -298,6 +300,7 @@ func (c *ProductController) GetProductCategoryBySlug(ginCtx *gin.Context) {
 		return
 	}
 
+	setVersionETag(ginCtx, data.Version)
 	responseData := gin.H{
 		"message":          "Product Category retrieved successfully",
 		"product_category": data,
@@ -314,10 +317,13 @@ func (c *ProductController) GetProductCategoryBySlug(ginCtx *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "Product Category ID (UUID)"
 // @Param payload body dto.UpdateProductCategoryRequest true "Product category update details"
+// @Param If-Match header string true "ETag of the product category being updated"
 // @Success 200 {object} dto.APIResponse{data=object{message=string,product_category=dto.ProductCategoryResponse}}
 // @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid payload or ID"
 // @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
 // @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Product category not found"
+// @Failure 412 {object} dto.APIResponse{error=dto.ErrorResponse} "The product category was modified since it was read"
+// @Failure 428 {object} dto.APIResponse{error=dto.ErrorResponse} "If-Match header missing"
 // @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
 // @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
 // @Router /product-categories/{id} [put]
@@ -339,6 +345,11 @@ func (c *ProductController) UpdateProductCategory(ginCtx *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(reqCtx, ginCtx, c.errorHandler, "Product category")
+	if !ok {
+		return
+	}
+
 	var req dto.UpdateProductCategoryRequest
 	if err := ginCtx.ShouldBindJSON(&req); err != nil {
 		appErr := c.errorHandler.ValidationError(reqCtx, "payload", "Invalid request payload: "+err.Error())
@@ -346,7 +357,7 @@ func (c *ProductController) UpdateProductCategory(ginCtx *gin.Context) {
 		return
 	}
 
-	data, err := c.s.UpdateProductCategory(reqCtx, uuId, req)
+	data, err := c.s.UpdateProductCategory(reqCtx, uuId, version, req)
 	if err != nil {
 		if reqCtx.Err() == context.DeadlineExceeded {
 			appErr := c.errorHandler.TimeoutError(reqCtx, "Update Product Category")
@@ -369,6 +380,7 @@ func (c *ProductController) UpdateProductCategory(ginCtx *gin.Context) {
 		return
 	}
 
+	setVersionETag(ginCtx, data.Version)
 	responseData := gin.H{
 		"message":          "Product Category updated successfully",
 		"product_category": data,
@@ -604,6 +616,8 @@ func (c *ProductController) GetProductById(ginCtx *gin.Context) {
 		return
 	}
 
+	setVersionETag(ginCtx, data.Version)
+	setLastModified(ginCtx, data.UpdatedAt)
 	responseData := gin.H{
 		"message": "Product retrieved successfully",
 		"product": data,
@@ -697,10 +711,13 @@ func (c *ProductController) GetProductsByCategory(ginCtx *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "Product ID (UUID)"
 // @Param payload body dto.UpdateProductRequest true "Product update details"
+// @Param If-Match header string true "ETag of the product being updated"
 // @Success 200 {object} dto.APIResponse{data=object{message=string,product=dto.ProductResponse}}
 // @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid payload or ID"
 // @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
 // @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Product not found"
+// @Failure 412 {object} dto.APIResponse{error=dto.ErrorResponse} "The product was modified since it was read"
+// @Failure 428 {object} dto.APIResponse{error=dto.ErrorResponse} "If-Match header missing"
 // @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
 // @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
 // @Router /products/{id} [put]
@@ -722,6 +739,11 @@ func (c *ProductController) UpdateProduct(ginCtx *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(reqCtx, ginCtx, c.errorHandler, "Product")
+	if !ok {
+		return
+	}
+
 	var req dto.UpdateProductRequest
 	if err := ginCtx.ShouldBindJSON(&req); err != nil {
 		appErr := c.errorHandler.ValidationError(reqCtx, "payload", "Invalid request payload: "+err.Error())
@@ -729,7 +751,7 @@ func (c *ProductController) UpdateProduct(ginCtx *gin.Context) {
 		return
 	}
 
-	data, err := c.s.UpdateProduct(reqCtx, uuId, req)
+	data, err := c.s.UpdateProduct(reqCtx, uuId, version, req)
 	if err != nil {
 		if reqCtx.Err() == context.DeadlineExceeded {
 			appErr := c.errorHandler.TimeoutError(reqCtx, "Update Product")
@@ -752,6 +774,7 @@ func (c *ProductController) UpdateProduct(ginCtx *gin.Context) {
 		return
 	}
 
+	setVersionETag(ginCtx, data.Version)
 	responseData := gin.H{
 		"message": "Product updated successfully",
 		"product": data,
@@ -965,10 +988,13 @@ func (c *ProductController) GetAffiliateLinksbyProductId(ginCtx *gin.Context) {
 // @Param id path string true "Product ID (UUID)"
 // @Param affiliateId path string true "Affiliate Link ID (UUID)"
 // @Param payload body dto.UpdateProductAffiliateLinkRequest true "Affiliate link update details"
+// @Param If-Match header string true "ETag of the affiliate link being updated"
 // @Success 200 {object} dto.APIResponse{data=object{message=string,product_affiliate_link=dto.ProductAffiliateLinkResponse}}
 // @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid payload, product ID, or affiliate ID"
 // @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
 // @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Product or affiliate link not found"
+// @Failure 412 {object} dto.APIResponse{error=dto.ErrorResponse} "The affiliate link was modified since it was read"
+// @Failure 428 {object} dto.APIResponse{error=dto.ErrorResponse} "If-Match header missing"
 // @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
 // @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
 // @Router /products/{id}/affiliate/{affiliateId} [put]
@@ -1004,6 +1030,11 @@ func (c *ProductController) UpdateProductAffiliateLink(ginCtx *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(reqCtx, ginCtx, c.errorHandler, "Affiliate link")
+	if !ok {
+		return
+	}
+
 	var req dto.UpdateProductAffiliateLinkRequest
 	if err := ginCtx.ShouldBindJSON(&req); err != nil {
 		appErr := c.errorHandler.ValidationError(reqCtx, "payload", "Invalid request payload: "+err.Error())
@@ -1011,7 +1042,7 @@ func (c *ProductController) UpdateProductAffiliateLink(ginCtx *gin.Context) {
 		return
 	}
 
-	data, err := c.s.UpdateProductAffiliateLink(reqCtx, uuId, affiliateUuId, req)
+	data, err := c.s.UpdateProductAffiliateLink(reqCtx, uuId, affiliateUuId, version, req)
 	if err != nil {
 		if reqCtx.Err() == context.DeadlineExceeded {
 			appErr := c.errorHandler.TimeoutError(reqCtx, "Update Product Affiliate Link")
@@ -1034,6 +1065,7 @@ func (c *ProductController) UpdateProductAffiliateLink(ginCtx *gin.Context) {
 		return
 	}
 
+	setVersionETag(ginCtx, data.Version)
 	responseData := gin.H{
 		"message":                "Product affiliate link updated successfully",
 		"product_affiliate_link": data,
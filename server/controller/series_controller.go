@@ -0,0 +1,498 @@
+package controller
+
+import (
+	"context"
+	"develapar-server/middleware"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/service"
+	"develapar-server/utils"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SeriesController struct {
+	service        service.SeriesService
+	articleService service.ArticleService
+	md             middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+	errorHandler   middleware.ErrorHandler
+	responseHelper *utils.ResponseHelper
+}
+
+// handleServiceError maps service errors to error responses
+func (c *SeriesController) handleServiceError(requestCtx context.Context, ginCtx *gin.Context, err error, operation, message string) {
+	// Check for context-specific errors
+	if requestCtx.Err() == context.DeadlineExceeded {
+		appErr := c.errorHandler.TimeoutError(requestCtx, operation)
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+	if requestCtx.Err() == context.Canceled {
+		appErr := c.errorHandler.CancellationError(requestCtx, operation)
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Check if it's already an AppError
+	if appErr, ok := err.(*utils.AppError); ok {
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Wrap as internal error
+	appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, message)
+	appErr.StatusCode = 500
+	c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+}
+
+// canManage reports whether the current user owns the series or is an editor or admin
+func (c *SeriesController) canManage(ginCtx *gin.Context, series model.Series) bool {
+	userId, err := utils.GetUserIDFromGinContext(ginCtx)
+	if err != nil {
+		return false
+	}
+	role, _ := utils.GetUserRoleFromContext(ginCtx)
+	return series.UserId == userId || role == "editor" || utils.ValidateAdminRole(role)
+}
+
+// forbidden writes a 403 response for a series the user may not manage
+func (c *SeriesController) forbidden(requestCtx context.Context, ginCtx *gin.Context) {
+	appErr := c.errorHandler.WrapError(requestCtx, fmt.Errorf("user does not own series"), utils.ErrForbidden, "You do not own this series")
+	appErr.StatusCode = 403
+	c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+}
+
+// authorizeSeries loads the series of the series_id path parameter and checks that the
+// current user may manage it. It writes the error response itself and returns false
+// when access is denied.
+func (c *SeriesController) authorizeSeries(requestCtx context.Context, ginCtx *gin.Context) (uuid.UUID, bool) {
+	seriesId, err := uuid.Parse(ginCtx.Param("series_id"))
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "series_id", "Invalid series ID format")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return uuid.Nil, false
+	}
+
+	series, err := c.service.FindById(requestCtx, seriesId)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "find series", "Failed to find series")
+		return uuid.Nil, false
+	}
+
+	if !c.canManage(ginCtx, series) {
+		c.forbidden(requestCtx, ginCtx)
+		return uuid.Nil, false
+	}
+
+	return seriesId, true
+}
+
+// @Summary List series
+// @Description List all article series, newest first
+// @Tags Series
+// @Produce json
+// @Success 200 {object} dto.APIResponse{data=object{message=string,series=[]model.Series}} "List of series"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /series [get]
+func (c *SeriesController) GetAllSeriesHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	listSeries, err := c.service.FindAll(requestCtx)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "get series", "Failed to retrieve series")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Series retrieved successfully",
+		"series":  listSeries,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Get series by slug
+// @Description Get a series with its published articles in reading order
+// @Tags Series
+// @Produce json
+// @Param slug path string true "Series slug"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,series=model.Series}} "Series details"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Series not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /series/{slug} [get]
+func (c *SeriesController) GetSeriesBySlugHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	series, err := c.service.FindBySlug(requestCtx, ginCtx.Param("slug"), false)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "get series", "Failed to retrieve series")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Series retrieved successfully",
+		"series":  series,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Get series for management
+// @Description Get a series with all of its articles including unpublished ones. Only the series owner, an editor or an admin can view it.
+// @Tags Series
+// @Produce json
+// @Param slug path string true "Series slug"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,series=model.Series}} "Series details"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Series not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /series/{slug}/manage [get]
+func (c *SeriesController) GetManagedSeriesHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	series, err := c.service.FindBySlug(requestCtx, ginCtx.Param("slug"), true)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "get series", "Failed to retrieve series")
+		return
+	}
+
+	if !c.canManage(ginCtx, series) {
+		c.forbidden(requestCtx, ginCtx)
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Series retrieved successfully",
+		"series":  series,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Create series
+// @Description Create a new, empty article series
+// @Tags Series
+// @Accept json
+// @Produce json
+// @Param payload body dto.CreateSeriesRequest true "Series data"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,series=model.Series}} "Series created"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid request payload"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 409 {object} dto.APIResponse{error=dto.ErrorResponse} "Series already exists"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /series [post]
+func (c *SeriesController) CreateSeriesHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	userId, err := utils.GetUserIDFromGinContext(ginCtx)
+	if err != nil {
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrUnauthorized, "Authentication required")
+		appErr.StatusCode = 401
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	var req dto.CreateSeriesRequest
+	if err := ginCtx.ShouldBindJSON(&req); err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	series, err := c.service.CreateSeries(requestCtx, req, userId)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "create series", "Failed to create series")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Series created successfully",
+		"series":  series,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Update series
+// @Description Update the title or description of a series
+// @Tags Series
+// @Accept json
+// @Produce json
+// @Param series_id path string true "Series ID"
+// @Param payload body dto.UpdateSeriesRequest true "Series data"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,series=model.Series}} "Series updated"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid request payload"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Series not found"
+// @Failure 409 {object} dto.APIResponse{error=dto.ErrorResponse} "Series already exists"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /series/{series_id} [put]
+func (c *SeriesController) UpdateSeriesHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	seriesId, ok := c.authorizeSeries(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateSeriesRequest
+	if err := ginCtx.ShouldBindJSON(&req); err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	series, err := c.service.UpdateSeries(requestCtx, seriesId, req)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "update series", "Failed to update series")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Series updated successfully",
+		"series":  series,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Delete series
+// @Description Delete a series. Its articles are kept.
+// @Tags Series
+// @Produce json
+// @Param series_id path string true "Series ID"
+// @Success 200 {object} dto.APIResponse{data=object{message=string}} "Series deleted"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid series ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Series not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /series/{series_id} [delete]
+func (c *SeriesController) DeleteSeriesHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	seriesId, ok := c.authorizeSeries(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteSeries(requestCtx, seriesId); err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "delete series", "Failed to delete series")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Series deleted successfully",
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Add article to series
+// @Description Append an article as the last part of a series. An article can be part of one series only, and only its authors, an editor or an admin can add it.
+// @Tags Series
+// @Accept json
+// @Produce json
+// @Param series_id path string true "Series ID"
+// @Param payload body dto.AddSeriesArticleRequest true "Article to add"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,series=model.Series}} "Series with all articles"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid request payload"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Series or article not found"
+// @Failure 409 {object} dto.APIResponse{error=dto.ErrorResponse} "Article already in a series"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /series/{series_id}/articles [post]
+func (c *SeriesController) AddArticleHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	seriesId, ok := c.authorizeSeries(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	var req dto.AddSeriesArticleRequest
+	if err := ginCtx.ShouldBindJSON(&req); err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// The user must be able to edit the article as well, editors and admins may add any article
+	article, err := c.articleService.FindById(requestCtx, req.ArticleId)
+	if err != nil {
+		if requestCtx.Err() != nil {
+			c.handleServiceError(requestCtx, ginCtx, err, "find article", "Failed to find article")
+			return
+		}
+		appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrNotFound, "Article not found")
+		appErr.StatusCode = 404
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+	userId, _ := utils.GetUserIDFromGinContext(ginCtx)
+	role, _ := utils.GetUserRoleFromContext(ginCtx)
+	if role != "editor" && !utils.ValidateAdminRole(role) {
+		canEdit, err := c.articleService.CanEdit(requestCtx, article.Id, userId)
+		if err != nil {
+			c.handleServiceError(requestCtx, ginCtx, err, "check article authors", "Failed to check article authors")
+			return
+		}
+		if !canEdit {
+			appErr := c.errorHandler.WrapError(requestCtx, fmt.Errorf("user is not an author of the article"), utils.ErrForbidden, "You are not an author of this article")
+			appErr.StatusCode = 403
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		}
+	}
+
+	series, err := c.service.AddArticle(requestCtx, seriesId, req.ArticleId)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "add article to series", "Failed to add article to series")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Article added to series successfully",
+		"series":  series,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Remove article from series
+// @Description Remove an article from a series, the following parts move up
+// @Tags Series
+// @Produce json
+// @Param series_id path string true "Series ID"
+// @Param article_id path string true "Article ID"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,series=model.Series}} "Series with all articles"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Series or article not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /series/{series_id}/articles/{article_id} [delete]
+func (c *SeriesController) RemoveArticleHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	seriesId, ok := c.authorizeSeries(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	articleId, err := uuid.Parse(ginCtx.Param("article_id"))
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "article_id", "Invalid article ID format")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	series, err := c.service.RemoveArticle(requestCtx, seriesId, articleId)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "remove article from series", "Failed to remove article from series")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Article removed from series successfully",
+		"series":  series,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+// @Summary Reorder series
+// @Description Replace the order of a series in one atomic operation. The list must contain every article of the series exactly once.
+// @Tags Series
+// @Accept json
+// @Produce json
+// @Param series_id path string true "Series ID"
+// @Param payload body dto.ReorderSeriesRequest true "Article IDs in the new order"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,series=model.Series}} "Series with all articles"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid order"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Series not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /series/{series_id}/order [put]
+func (c *SeriesController) ReorderArticlesHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	seriesId, ok := c.authorizeSeries(requestCtx, ginCtx)
+	if !ok {
+		return
+	}
+
+	var req dto.ReorderSeriesRequest
+	if err := ginCtx.ShouldBindJSON(&req); err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	series, err := c.service.ReorderArticles(requestCtx, seriesId, req.ArticleIds)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "reorder series", "Failed to reorder series")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Series reordered successfully",
+		"series":  series,
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+func (c *SeriesController) Route() {
+	seriesRoutes := c.rg.Group("/series")
+
+	// --- Public Routes ---
+	seriesRoutes.GET("", c.GetAllSeriesHandler)
+	seriesRoutes.GET("/:slug", c.GetSeriesBySlugHandler)
+
+	// --- Protected Routes ---
+	// Hanya pemilik series, editor, atau admin yang boleh mengelola series
+	checkTokenMiddleware := c.md.CheckToken("user", "editor", "admin")
+	seriesRoutes.GET("/:slug/manage", checkTokenMiddleware, c.GetManagedSeriesHandler)
+	seriesRoutes.POST("", checkTokenMiddleware, c.CreateSeriesHandler)
+	seriesRoutes.PUT("/:series_id", checkTokenMiddleware, c.UpdateSeriesHandler)
+	seriesRoutes.DELETE("/:series_id", checkTokenMiddleware, c.DeleteSeriesHandler)
+	seriesRoutes.POST("/:series_id/articles", checkTokenMiddleware, c.AddArticleHandler)
+	seriesRoutes.DELETE("/:series_id/articles/:article_id", checkTokenMiddleware, c.RemoveArticleHandler)
+	seriesRoutes.PUT("/:series_id/order", checkTokenMiddleware, c.ReorderArticlesHandler)
+}
+
+func NewSeriesController(sS service.SeriesService, aS service.ArticleService, md middleware.AuthMiddleware, rg *gin.RouterGroup, errorHandler middleware.ErrorHandler) *SeriesController {
+	return &SeriesController{
+		service:        sS,
+		articleService: aS,
+		md:             md,
+		rg:             rg,
+		errorHandler:   errorHandler,
+		responseHelper: utils.NewResponseHelper(),
+	}
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"context"
+	"develapar-server/middleware"
+	"develapar-server/service"
+	"develapar-server/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	sitemapContentType  = "application/xml; charset=utf-8"
+	sitemapCacheControl = "public, max-age=3600"
+)
+
+type SitemapController struct {
+	service      service.SitemapService
+	rg           *gin.RouterGroup
+	errorHandler middleware.ErrorHandler
+}
+
+// streamSitemap sets the response headers and lets write stream the document.
+// Errors before the first byte is sent become a normal, uncached error response; later errors
+// can only abort the response, so they are recorded on the gin context.
+func (c *SitemapController) streamSitemap(ginCtx *gin.Context, operation string, write func(requestCtx context.Context) error) {
+	// Sitemaps can be large, allow more time than regular API requests
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	ginCtx.Header("Content-Type", sitemapContentType)
+	ginCtx.Header("Cache-Control", sitemapCacheControl)
+
+	err := write(requestCtx)
+	if err == nil {
+		return
+	}
+
+	if ginCtx.Writer.Written() {
+		_ = ginCtx.Error(err)
+		ginCtx.Abort()
+		return
+	}
+
+	// The error response is JSON and must not be cached like a sitemap
+	header := ginCtx.Writer.Header()
+	header.Del("Content-Type")
+	header.Set("Cache-Control", "no-store")
+
+	// Check for context-specific errors
+	if requestCtx.Err() == context.DeadlineExceeded {
+		appErr := c.errorHandler.TimeoutError(requestCtx, operation)
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+	if requestCtx.Err() == context.Canceled {
+		appErr := c.errorHandler.CancellationError(requestCtx, operation)
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Check if it's already an AppError
+	if appErr, ok := err.(*utils.AppError); ok {
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, "Failed to generate sitemap")
+	appErr.StatusCode = 500
+	c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+}
+
+// @Summary Sitemap
+// @Description Sitemap of published articles, categories, tags and product categories. With more than 50,000 URLs a sitemap index pointing to /sitemaps/sitemap-{n}.xml is returned instead.
+// @Tags SEO
+// @Produce xml
+// @Success 200 {string} string "Sitemap or sitemap index"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /sitemap.xml [get]
+func (c *SitemapController) SitemapHandler(ginCtx *gin.Context) {
+	c.streamSitemap(ginCtx, "generate sitemap", func(requestCtx context.Context) error {
+		return c.service.WriteSitemap(requestCtx, ginCtx.Writer)
+	})
+}
+
+// @Summary Child sitemap
+// @Description One child sitemap of the sitemap index. Only exists while the sitemap is split.
+// @Tags SEO
+// @Produce xml
+// @Param file path string true "Sitemap file name, e.g. sitemap-1.xml"
+// @Success 200 {string} string "Sitemap"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Sitemap not found"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Router /sitemaps/{file} [get]
+func (c *SitemapController) SitemapPageHandler(ginCtx *gin.Context) {
+	file := ginCtx.Param("file")
+	page, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(file, "sitemap-"), ".xml"))
+	if err != nil || !strings.HasPrefix(file, "sitemap-") || !strings.HasSuffix(file, ".xml") {
+		appErr := c.errorHandler.WrapError(ginCtx.Request.Context(), fmt.Errorf("invalid sitemap file %q", file), utils.ErrNotFound, "Sitemap not found")
+		appErr.StatusCode = 404
+		c.errorHandler.HandleError(ginCtx.Request.Context(), ginCtx, appErr)
+		return
+	}
+
+	c.streamSitemap(ginCtx, "generate sitemap page", func(requestCtx context.Context) error {
+		return c.service.WriteSitemapPage(requestCtx, ginCtx.Writer, page)
+	})
+}
+
+// @Summary robots.txt
+// @Description Crawler rules pointing to the sitemap
+// @Tags SEO
+// @Produce plain
+// @Success 200 {string} string "robots.txt"
+// @Router /robots.txt [get]
+func (c *SitemapController) RobotsHandler(ginCtx *gin.Context) {
+	ginCtx.Header("Cache-Control", sitemapCacheControl)
+	ginCtx.String(http.StatusOK, c.service.RobotsTxt())
+}
+
+func (c *SitemapController) Route() {
+	c.rg.GET("/sitemap.xml", c.SitemapHandler)
+	c.rg.GET("/sitemaps/:file", c.SitemapPageHandler)
+	c.rg.GET("/robots.txt", c.RobotsHandler)
+}
+
+func NewSitemapController(sitemapService service.SitemapService, rg *gin.RouterGroup, errorHandler middleware.ErrorHandler) *SitemapController {
+	return &SitemapController{
+		service:      sitemapService,
+		rg:           rg,
+		errorHandler: errorHandler,
+	}
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+	"develapar-server/middleware"
+	"develapar-server/service"
+	"develapar-server/utils"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSitemapService writes one page of sitemap and fails every other one with err
+type fakeSitemapService struct {
+	service.SitemapService
+	err error
+}
+
+func (s *fakeSitemapService) WriteSitemapPage(ctx context.Context, w io.Writer, page int) error {
+	if page != 1 {
+		return s.err
+	}
+	_, err := io.WriteString(w, "<urlset></urlset>")
+	return err
+}
+
+func TestSitemapController_ErrorsAreNotCachedAsXML(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		file       string
+		err        error
+		wantStatus int
+	}{
+		{name: "sitemap", file: "sitemap-1.xml", wantStatus: http.StatusOK},
+		{name: "missing page", file: "sitemap-2.xml", err: utils.NewErrorWrapper().NotFoundError(context.Background(), "Sitemap"), wantStatus: http.StatusNotFound},
+		{name: "failing page", file: "sitemap-2.xml", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			NewSitemapController(&fakeSitemapService{err: tt.err}, router.Group(""), middleware.NewErrorHandler(nil)).Route()
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sitemaps/"+tt.file, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, sitemapContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, sitemapCacheControl, w.Header().Get("Cache-Control"))
+				return
+			}
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+			assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		})
+	}
+}
@@ -181,6 +181,8 @@ func (t *TagController) GetByTagIdHandler(ginCtx *gin.Context) {
 	}
 
 	// Create success response with context
+	setVersionETag(ginCtx, tags.Version)
+	setLastModified(ginCtx, tags.UpdatedAt)
 	responseData := gin.H{
 		"message": "Tag retrieved successfully",
 		"tag":     tags,
@@ -194,11 +196,14 @@ func (t *TagController) GetByTagIdHandler(ginCtx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param tag_id path int true "ID of the tag to update"
+// @Param If-Match header string true "ETag of the tag being updated"
 // @Param payload body model.Tags true "Tag update details"
 // @Success 200 {object} dto.APIResponse{data=object{message=string,tag=model.Tags}} "Tag updated successfully"
 // @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid tag ID or payload"
 // @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
 // @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Tag not found"
+// @Failure 412 {object} dto.APIResponse{error=dto.ErrorResponse} "The tag was modified since it was read"
+// @Failure 428 {object} dto.APIResponse{error=dto.ErrorResponse} "If-Match header missing"
 // @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
 // @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
 // @Security BearerAuth
@@ -215,6 +220,11 @@ func (t *TagController) UpdateTagHandler(ginCtx *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(requestCtx, ginCtx, t.errorHandler, "Tag")
+	if !ok {
+		return
+	}
+
 	var payload model.Tags
 	if err := ginCtx.ShouldBindJSON(&payload); err != nil {
 		appErr := t.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
@@ -223,7 +233,7 @@ func (t *TagController) UpdateTagHandler(ginCtx *gin.Context) {
 	}
 
 	// Call service with context
-	updatedTag, err := t.service.UpdateTag(requestCtx, tagId, payload)
+	updatedTag, err := t.service.UpdateTag(requestCtx, tagId, version, payload)
 	if err != nil {
 		// Check for context-specific errors
 		if requestCtx.Err() == context.DeadlineExceeded {
@@ -251,6 +261,7 @@ func (t *TagController) UpdateTagHandler(ginCtx *gin.Context) {
 	}
 
 	// Create success response with context
+	setVersionETag(ginCtx, updatedTag.Version)
 	responseData := gin.H{
 		"message": "Tag updated successfully",
 		"tag":     updatedTag,
@@ -320,8 +331,8 @@ func (t *TagController) DeleteTagHandler(ginCtx *gin.Context) {
 
 func (t *TagController) Route() {
 	router := t.rg.Group("/tags")
-	router.GET("/:tag_id", t.GetByTagIdHandler) // Changed from tags_id to tag_id
-	router.GET("/", t.GetAllTagHandler)
+	router.GET("/:tag_id", middleware.HTTPCacheMiddleware(taxonomyCachePolicy), t.GetByTagIdHandler) // Changed from tags_id to tag_id
+	router.GET("/", middleware.HTTPCacheMiddleware(taxonomyCachePolicy), t.GetAllTagHandler)
 
 	routerAuth := router.Group("/", t.md.CheckToken())
 	routerAuth.POST("/", t.CreateTagHandler)
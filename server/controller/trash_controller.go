@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"context"
+	"develapar-server/middleware"
+	"develapar-server/service"
+	"develapar-server/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TrashController struct {
+	service        service.TrashService
+	md             middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+	errorHandler   middleware.ErrorHandler
+	responseHelper *utils.ResponseHelper
+}
+
+// handleServiceError maps service errors to error responses
+func (c *TrashController) handleServiceError(requestCtx context.Context, ginCtx *gin.Context, err error, operation, message string) {
+	// Check for context-specific errors
+	if requestCtx.Err() == context.DeadlineExceeded {
+		appErr := c.errorHandler.TimeoutError(requestCtx, operation)
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+	if requestCtx.Err() == context.Canceled {
+		appErr := c.errorHandler.CancellationError(requestCtx, operation)
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Check if it's already an AppError
+	if appErr, ok := err.(*utils.AppError); ok {
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	// Wrap as internal error
+	appErr := c.errorHandler.WrapError(requestCtx, err, utils.ErrInternal, message)
+	appErr.StatusCode = 500
+	c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+}
+
+// @Summary List the trash
+// @Description List the deleted articles, comments and products, most recently deleted first, with the time each one is purged permanently. Admin only.
+// @Tags Trash
+// @Produce json
+// @Param type query string false "Only list one item type" Enums(article, comment, product)
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 10, max: 100)"
+// @Success 200 {object} dto.APIResponse{data=object{message=string,items=[]model.TrashItem},pagination=dto.PaginationMetadata} "Paginated trash"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid type or pagination parameters"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /trash [get]
+func (c *TrashController) GetTrashHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	// Get pagination parameters from query string
+	page := 1
+	limit := 10
+
+	if pageStr := ginCtx.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err != nil || p <= 0 {
+			appErr := c.errorHandler.ValidationError(requestCtx, "page", "Page must be a positive integer")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		} else {
+			page = p
+		}
+	}
+
+	if limitStr := ginCtx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err != nil || l <= 0 || l > 100 {
+			appErr := c.errorHandler.ValidationError(requestCtx, "limit", "Limit must be a positive integer between 1 and 100")
+			c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+			return
+		} else {
+			limit = l
+		}
+	}
+
+	result, err := c.service.FindTrash(requestCtx, ginCtx.Query("type"), page, limit)
+	if err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "get trash", "Failed to retrieve trash")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Trash retrieved successfully",
+		"items":   result.Data,
+	}
+	c.responseHelper.SendSuccessWithServicePagination(ginCtx, responseData, result.Metadata)
+}
+
+// @Summary Restore a deleted item
+// @Description Take an article, comment or product out of the trash. Its tags, likes, bookmarks, comments and affiliate links come back with it. Admin only.
+// @Tags Trash
+// @Produce json
+// @Param type path string true "Item type" Enums(article, comment, product)
+// @Param id path string true "Item ID"
+// @Success 200 {object} dto.APIResponse{data=object{message=string}} "Item restored"
+// @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid type or ID"
+// @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
+// @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "Item not in the trash"
+// @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
+// @Security BearerAuth
+// @Router /trash/{type}/{id}/restore [post]
+func (c *TrashController) RestoreHandler(ginCtx *gin.Context) {
+	// Get request context with timeout
+	requestCtx, cancel := context.WithTimeout(ginCtx.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	id, err := uuid.Parse(ginCtx.Param("id"))
+	if err != nil {
+		appErr := c.errorHandler.ValidationError(requestCtx, "id", "Invalid ID format")
+		c.errorHandler.HandleError(requestCtx, ginCtx, appErr)
+		return
+	}
+
+	itemType := ginCtx.Param("type")
+	if err := c.service.Restore(requestCtx, itemType, id); err != nil {
+		c.handleServiceError(requestCtx, ginCtx, err, "restore "+itemType, "Failed to restore item")
+		return
+	}
+
+	responseData := gin.H{
+		"message": "Item restored successfully",
+	}
+	c.responseHelper.SendSuccess(ginCtx, responseData)
+}
+
+func (c *TrashController) Route() {
+	// Tempat sampah hanya untuk admin
+	trashRoutes := c.rg.Group("/trash")
+	trashRoutes.Use(c.md.CheckToken("admin"))
+	trashRoutes.GET("", c.GetTrashHandler)                   // GET /trash?type=article
+	trashRoutes.POST("/:type/:id/restore", c.RestoreHandler) // POST /trash/:type/:id/restore
+}
+
+func NewTrashController(tS service.TrashService, md middleware.AuthMiddleware, rg *gin.RouterGroup, errorHandler middleware.ErrorHandler) *TrashController {
+	return &TrashController{
+		service:        tS,
+		md:             md,
+		rg:             rg,
+		errorHandler:   errorHandler,
+		responseHelper: utils.NewResponseHelper(),
+	}
+}
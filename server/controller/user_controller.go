@@ -211,6 +211,7 @@ func (u *UserController) findUserByIdHandler(c *gin.Context) {
 	}
 
 	// Create success response with context
+	setVersionETag(c, user.Version)
 	responseData := gin.H{
 		"message": "User retrieved successfully",
 		"user":    user,
@@ -412,12 +413,15 @@ func (u *UserController) refreshTokenHandler(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param user_id path string true "ID of the user to update"
+// @Param If-Match header string true "ETag of the user being updated"
 // @Param payload body dto.UpdateUserRequest true "User update details"
 // @Success 200 {object} dto.APIResponse{data=object{message=string,user=model.User}} "User updated successfully"
 // @Failure 400 {object} dto.APIResponse{error=dto.ErrorResponse} "Invalid user ID or payload"
 // @Failure 401 {object} dto.APIResponse{error=dto.ErrorResponse} "Unauthorized"
 // @Failure 403 {object} dto.APIResponse{error=dto.ErrorResponse} "Forbidden"
 // @Failure 404 {object} dto.APIResponse{error=dto.ErrorResponse} "User not found"
+// @Failure 412 {object} dto.APIResponse{error=dto.ErrorResponse} "The user was modified since it was read"
+// @Failure 428 {object} dto.APIResponse{error=dto.ErrorResponse} "If-Match header missing"
 // @Failure 408 {object} dto.APIResponse{error=dto.ErrorResponse} "Request timeout"
 // @Failure 500 {object} dto.APIResponse{error=dto.ErrorResponse} "Internal server error"
 // @Security BearerAuth
@@ -482,6 +486,11 @@ func (u *UserController) updateUserHandler(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(requestCtx, c, u.errorHandler, "User")
+	if !ok {
+		return
+	}
+
 	var payload dto.UpdateUserRequest
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		appErr := u.errorHandler.ValidationError(requestCtx, "payload", "Invalid request payload: "+err.Error())
@@ -490,7 +499,7 @@ func (u *UserController) updateUserHandler(c *gin.Context) {
 	}
 
 	// Call service with context
-	updatedUser, err := u.service.UpdateUser(requestCtx, requestingUserID, requestingUserRole, userId, payload)
+	updatedUser, err := u.service.UpdateUser(requestCtx, requestingUserID, requestingUserRole, userId, version, payload)
 	if err != nil {
 		// Check for context-specific errors
 		if requestCtx.Err() == context.DeadlineExceeded {
@@ -518,6 +527,7 @@ func (u *UserController) updateUserHandler(c *gin.Context) {
 	}
 
 	// Create success response with context
+	setVersionETag(c, updatedUser.Version)
 	responseData := gin.H{
 		"message": "User updated successfully",
 		"user":    updatedUser,
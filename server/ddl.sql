@@ -4,9 +4,19 @@
 CREATE EXTENSION IF NOT EXISTS "pgcrypto";
 
 -- Membuat tipe ENUM untuk status artikel, lebih efisien dan aman
-CREATE TYPE article_status AS ENUM ('draft', 'published');
+-- Alur editorial: draft -> in_review -> approved -> (scheduled ->) published -> archived
+CREATE TYPE article_status AS ENUM ('draft', 'in_review', 'approved', 'scheduled', 'published', 'archived');
 
-CREATE TYPE user_role AS ENUM ('user', 'admin');
+-- Role 'editor' boleh menyetujui, menolak, dan menerbitkan artikel
+CREATE TYPE user_role AS ENUM ('user', 'editor', 'admin');
+
+-- Visibilitas artikel: public tampil di daftar, unlisted hanya lewat tautan,
+-- private hanya untuk penulis/editor/admin, password_protected perlu kata sandi
+CREATE TYPE article_visibility AS ENUM ('public', 'unlisted', 'private', 'password_protected');
+
+-- Status moderasi komentar: hanya 'approved' yang tampil untuk publik,
+-- 'pending' juga tampil untuk penulis komentarnya sendiri
+CREATE TYPE comment_status AS ENUM ('pending', 'approved', 'rejected', 'spam');
 
 
 -- ========================================
@@ -20,6 +30,7 @@ CREATE TABLE users (
   email VARCHAR(100) UNIQUE NOT NULL,
   password VARCHAR(255) NOT NULL,
   role user_role NOT NULL DEFAULT 'user',
+  version INT NOT NULL DEFAULT 1, -- Versi baris untuk optimistic locking, dikirim sebagai ETag dan dicek lewat If-Match
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
@@ -28,6 +39,8 @@ CREATE TABLE users (
 CREATE TABLE categories (
   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
   name VARCHAR(50) UNIQUE NOT NULL,
+  slug VARCHAR(100) UNIQUE NOT NULL, -- Dibuat dari nama, diberi akhiran angka jika sudah dipakai
+  version INT NOT NULL DEFAULT 1, -- Versi baris, lihat users.version
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
@@ -36,6 +49,7 @@ CREATE TABLE categories (
 CREATE TABLE tags (
   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
   name VARCHAR(50) UNIQUE NOT NULL,
+  version INT NOT NULL DEFAULT 1, -- Versi baris, lihat users.version
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
@@ -45,13 +59,33 @@ CREATE TABLE articles (
   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
   title VARCHAR(255) NOT NULL,
   slug VARCHAR(255) UNIQUE NOT NULL,
+  slug_pinned BOOLEAN NOT NULL DEFAULT FALSE, -- Slug kustom dari editor, tidak ikut berubah saat judul diganti
   content TEXT NOT NULL,
+  -- Hasil render Markdown, dihitung ulang hanya jika content_hash berubah
+  content_html TEXT NOT NULL DEFAULT '',
+  toc JSONB NOT NULL DEFAULT '[]', -- Daftar isi: [{level, text, anchor}]
+  excerpt TEXT NOT NULL DEFAULT '',
+  reading_time_minutes INT NOT NULL DEFAULT 0,
+  content_hash VARCHAR(64) NOT NULL DEFAULT '', -- SHA-256 dari content saat dirender
   user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
   category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
   views INT NOT NULL DEFAULT 0,
+  comment_count INT NOT NULL DEFAULT 0, -- Jumlah komentar approved yang belum dihapus, dijaga trigger trg_comments_article_count
   status article_status NOT NULL DEFAULT 'draft', -- Kolom status (isPublished/draft)
+  visibility article_visibility NOT NULL DEFAULT 'public',
+  password_hash VARCHAR(255) NOT NULL DEFAULT '', -- Hash kata sandi untuk artikel password_protected
+  publish_at TIMESTAMPTZ NULL, -- Jadwal terbit, artikel 'scheduled' diterbitkan otomatis oleh scheduler
+  unpublish_at TIMESTAMPTZ NULL, -- Jadwal tarik, artikel 'published' diarsipkan oleh scheduler
+  deleted_at TIMESTAMPTZ NULL, -- Soft delete, artikel di tempat sampah dihapus permanen setelah masa retensi
+  version INT NOT NULL DEFAULT 1, -- Versi baris, lihat users.version
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
-  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  -- Kolom pencarian full-text, judul diberi bobot lebih tinggi dari konten.
+  -- Memakai konfigurasi 'simple' karena konten campuran Indonesia/Inggris.
+  search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(content, '')), 'B')
+  ) STORED
 );
 
 -- Tabel pivot article_tags (many-to-many)
@@ -68,11 +102,39 @@ CREATE TABLE comments (
   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
   article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
   user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  parent_comment_id UUID NULL REFERENCES comments(id) ON DELETE CASCADE, -- Komentar yang dibalas, NULL untuk komentar utama
   content TEXT NOT NULL,
+  status comment_status NOT NULL DEFAULT 'approved', -- Ditentukan kebijakan moderasi saat komentar dibuat
+  moderated_by UUID NULL REFERENCES users(id) ON DELETE SET NULL, -- Editor/admin yang terakhir memoderasi
+  moderated_at TIMESTAMPTZ NULL,
+  spam_score DOUBLE PRECISION NULL, -- Skor filter spam 0..1 saat komentar dibuat, NULL jika filter nonaktif
+  spam_signals JSONB NULL, -- Rincian skor per sinyal (bayes, links, duplicate, velocity, blocklist) untuk audit
+  like_count INT NOT NULL DEFAULT 0, -- Dijaga trigger trg_comment_likes_count, dipakai untuk urutan most_liked
+  edited_at TIMESTAMPTZ NULL, -- Waktu edit terakhir, NULL jika belum pernah diedit (riwayat di comment_edits)
+  deleted_at TIMESTAMPTZ NULL, -- Soft delete, lihat articles.deleted_at
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
+-- Tabel comment_edits (riwayat edit komentar, hanya untuk editor/admin)
+-- Setiap edit menyimpan isi sebelum dan sesudah edit.
+CREATE TABLE comment_edits (
+  id UUID PRIMARY KEY,
+  comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+  previous_content TEXT NOT NULL,
+  content TEXT NOT NULL,
+  edited_by UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+  edited_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Tabel comment_likes (like per komentar, satu per user)
+CREATE TABLE comment_likes (
+  comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (comment_id, user_id)
+);
+
 -- Tabel likes
 CREATE TABLE likes (
   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -109,6 +171,7 @@ CREATE TABLE product_categories (
     name VARCHAR(255) NOT NULL,
     slug VARCHAR(255) NOT NULL UNIQUE,
     description TEXT NULL,
+    version INT NOT NULL DEFAULT 1, -- Versi baris, lihat users.version
     created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
     updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
@@ -121,6 +184,8 @@ CREATE TABLE products(
   description TEXT NULL,
   image_url VARCHAR(255) NULL,
   is_active BOOLEAN NOT NULL DEFAULT TRUE,
+  deleted_at TIMESTAMPTZ NULL, -- Soft delete, lihat articles.deleted_at
+  version INT NOT NULL DEFAULT 1, -- Versi baris, lihat users.version
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
@@ -131,6 +196,7 @@ CREATE TABLE product_affiliate_links(
   product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
   platform_name VARCHAR(50) NOT NULL,
   url TEXT NOT NULL,
+  version INT NOT NULL DEFAULT 1, -- Versi baris, lihat users.version
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
@@ -142,4 +208,231 @@ CREATE TABLE article_product(
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   PRIMARY KEY(article_id, product_id)
-);
\ No newline at end of file
+);
+
+-- Tabel article_revisions (snapshot artikel setiap kali diubah)
+CREATE TABLE article_revisions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  revision_number INT NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  slug VARCHAR(255) NOT NULL,
+  content TEXT NOT NULL,
+  category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+  status article_status NOT NULL,
+  editor_id UUID REFERENCES users(id) ON DELETE SET NULL, -- User yang membuat revisi
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (article_id, revision_number)
+);
+
+-- Tabel article_status_transitions (riwayat perpindahan status alur editorial)
+CREATE TABLE article_status_transitions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  from_status article_status NOT NULL,
+  to_status article_status NOT NULL,
+  actor_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL jika dilakukan oleh scheduler
+  reason TEXT NULL, -- Alasan penolakan atau catatan editor
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Tabel article_views (data mentah setiap view yang dihitung, untuk analitik)
+-- Kolom articles.views diperbarui bersamaan secara batch, bukan per request
+CREATE TABLE article_views (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  user_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL untuk pengunjung anonim
+  ip_address VARCHAR(45) NOT NULL,
+  user_agent TEXT NOT NULL,
+  referrer TEXT NULL,
+  viewed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Tabel article_trending_scores (skor trending per jendela waktu, dihitung ulang oleh background job)
+-- Skor = poin interaksi dalam jendela / (umur artikel dalam jam + 2) ^ gravity
+CREATE TABLE article_trending_scores (
+  article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  time_window VARCHAR(8) NOT NULL, -- '24h', '7d' atau '30d'
+  score DOUBLE PRECISION NOT NULL,
+  views INT NOT NULL DEFAULT 0,
+  likes INT NOT NULL DEFAULT 0,
+  comments INT NOT NULL DEFAULT 0,
+  bookmarks INT NOT NULL DEFAULT 0,
+  computed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (article_id, time_window)
+);
+
+-- Tabel series (kumpulan artikel berurutan, misalnya tutorial beberapa bagian)
+CREATE TABLE series (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  title VARCHAR(200) NOT NULL,
+  slug VARCHAR(255) UNIQUE NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Tabel series_articles (urutan artikel dalam series, satu artikel hanya boleh di satu series)
+-- Constraint posisi ditunda sampai commit agar urutan bisa diubah sekaligus dalam satu transaksi
+CREATE TABLE series_articles (
+  series_id UUID NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+  article_id UUID NOT NULL UNIQUE REFERENCES articles(id) ON DELETE CASCADE,
+  position INT NOT NULL CHECK (position > 0),
+  PRIMARY KEY (series_id, article_id),
+  CONSTRAINT series_articles_position_key UNIQUE (series_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+
+-- Tabel article_authors (kontributor artikel beserta perannya)
+-- Pembuat artikel tercatat dengan peran 'author', author/co-author/editor boleh mengubah artikel
+CREATE TABLE article_authors (
+  article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role VARCHAR(20) NOT NULL CHECK (role IN ('author', 'co-author', 'editor', 'reviewer')),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (article_id, user_id)
+);
+
+
+-- Tabel article_slug_history (slug lama artikel, diarahkan permanen ke slug terbaru)
+CREATE TABLE article_slug_history (
+  slug VARCHAR(255) PRIMARY KEY,
+  article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+
+-- Tabel article_previews (tautan pratinjau draf yang ditandatangani, bisa kedaluwarsa/dicabut/dibatasi jumlah view)
+CREATE TABLE article_previews (
+  id UUID PRIMARY KEY,
+  article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  max_views INT CHECK (max_views > 0),
+  view_count INT NOT NULL DEFAULT 0,
+  revoked_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+
+-- Tabel comment_moderation_policies (kebijakan moderasi komentar)
+-- Baris dengan article_id NULL adalah kebijakan global, baris per artikel menimpanya.
+-- auto_approve: semua komentar langsung tampil
+-- hold_first_time: komentar user yang belum punya komentar approved ditahan
+-- hold_untrusted: komentar ditahan sampai user cukup dipercaya (jumlah komentar approved minimal)
+-- Komentar editor/admin selalu langsung tampil.
+CREATE TABLE comment_moderation_policies (
+  article_id UUID NULL UNIQUE REFERENCES articles(id) ON DELETE CASCADE,
+  mode VARCHAR(20) NOT NULL CHECK (mode IN ('auto_approve', 'hold_first_time', 'hold_untrusted')),
+  updated_by UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+
+-- ========================================
+-- 2. DDL: INDEXES
+-- ========================================
+
+-- Index GIN untuk pencarian artikel (GET /articles/search)
+CREATE INDEX idx_articles_search_vector ON articles USING GIN (search_vector);
+
+-- Index riwayat status per artikel
+CREATE INDEX idx_article_status_transitions_article ON article_status_transitions (article_id, created_at);
+
+-- Index parsial untuk scheduler publish/unpublish
+CREATE INDEX idx_articles_publish_at ON articles (publish_at) WHERE status = 'scheduled';
+CREATE INDEX idx_articles_unpublish_at ON articles (unpublish_at) WHERE status = 'published' AND unpublish_at IS NOT NULL;
+
+-- Index analitik view per artikel
+CREATE INDEX idx_article_views_article ON article_views (article_id, viewed_at);
+
+-- Index ranking trending per jendela waktu (GET /articles/trending)
+CREATE INDEX idx_article_trending_scores_rank ON article_trending_scores (time_window, score DESC);
+
+-- Index aktivitas terbaru untuk perhitungan skor trending
+CREATE INDEX idx_article_views_viewed_at ON article_views (viewed_at);
+CREATE INDEX idx_likes_created_at ON likes (created_at);
+CREATE INDEX idx_comments_created_at ON comments (created_at);
+CREATE INDEX idx_bookmarks_created_at ON bookmarks (created_at);
+
+-- Index balasan komentar (thread GET /comments/article/:article_id)
+CREATE INDEX idx_comments_article ON comments (article_id, parent_comment_id);
+CREATE INDEX idx_comments_parent ON comments (parent_comment_id) WHERE parent_comment_id IS NOT NULL;
+
+-- Index halaman komentar utama per artikel (GET /comments/article/:article_id?sort=...)
+CREATE INDEX idx_comments_article_roots ON comments (article_id, created_at, id) WHERE parent_comment_id IS NULL;
+CREATE INDEX idx_comments_article_roots_liked ON comments (article_id, like_count, created_at, id) WHERE parent_comment_id IS NULL;
+
+-- Index riwayat edit per komentar (GET /comments/:comment_id/edits)
+CREATE INDEX idx_comment_edits_comment ON comment_edits (comment_id, edited_at);
+
+-- Index antrian moderasi komentar (GET /comments/moderation)
+CREATE INDEX idx_comments_moderation ON comments (status, created_at) WHERE status <> 'approved';
+
+-- Hanya boleh ada satu kebijakan moderasi global
+CREATE UNIQUE INDEX idx_comment_moderation_policies_global ON comment_moderation_policies ((article_id IS NULL)) WHERE article_id IS NULL;
+
+-- Index artikel yang ditulis bersama per user (GET /articles/author/:user_id)
+CREATE INDEX idx_article_authors_user ON article_authors (user_id, role);
+
+-- Index tautan pratinjau per artikel
+CREATE INDEX idx_article_previews_article ON article_previews (article_id, created_at);
+
+-- Index parsial tempat sampah (GET /trash dan job purge retensi)
+CREATE INDEX idx_articles_deleted_at ON articles (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_comments_deleted_at ON comments (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_products_deleted_at ON products (deleted_at) WHERE deleted_at IS NOT NULL;
+
+
+-- ========================================
+-- 3. DDL: TRIGGERS
+-- ========================================
+
+-- Menjaga articles.comment_count: hanya komentar approved yang belum dihapus yang dihitung.
+-- Trigger menangkap semua perubahan (komentar baru, moderasi, hapus/pulihkan dari tempat sampah,
+-- purge, dan cascade saat user dihapus) dalam transaksi yang sama.
+CREATE FUNCTION sync_article_comment_count() RETURNS TRIGGER AS $$
+DECLARE
+  old_visible INT := 0;
+  new_visible INT := 0;
+  target UUID;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    target := NEW.article_id;
+  ELSE
+    target := OLD.article_id;
+    IF OLD.status = 'approved' AND OLD.deleted_at IS NULL THEN
+      old_visible := 1;
+    END IF;
+  END IF;
+  IF TG_OP <> 'DELETE' AND NEW.status = 'approved' AND NEW.deleted_at IS NULL THEN
+    new_visible := 1;
+  END IF;
+
+  IF new_visible <> old_visible THEN
+    UPDATE articles SET comment_count = comment_count + new_visible - old_visible WHERE id = target;
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_comments_article_count
+AFTER INSERT OR DELETE OR UPDATE OF status, deleted_at ON comments
+FOR EACH ROW EXECUTE FUNCTION sync_article_comment_count();
+
+-- Menjaga comments.like_count saat like komentar ditambah atau dihapus
+CREATE FUNCTION sync_comment_like_count() RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    UPDATE comments SET like_count = like_count + 1 WHERE id = NEW.comment_id;
+  ELSE
+    UPDATE comments SET like_count = like_count - 1 WHERE id = OLD.comment_id;
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_comment_likes_count
+AFTER INSERT OR DELETE ON comment_likes
+FOR EACH ROW EXECUTE FUNCTION sync_comment_like_count();
@@ -10,6 +10,9 @@ import (
 
 type AuthMiddleware interface {
 	CheckToken(roles ...string) gin.HandlerFunc
+	// OptionalToken identifies the user when a valid token is sent and lets anonymous
+	// requests through, for public routes that show signed in users more
+	OptionalToken() gin.HandlerFunc
 }
 
 type authMiddleware struct {
@@ -48,6 +51,24 @@ func (a *authMiddleware) CheckToken(roles ...string) gin.HandlerFunc {
 	}
 }
 
+func (a *authMiddleware) OptionalToken() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		header := ctx.GetHeader("Authorization")
+		if header == "" {
+			ctx.Next()
+			return
+		}
+
+		// An expired or invalid token reads the route as an anonymous user
+		token := strings.Replace(header, "Bearer ", "", -1)
+		if claims, err := a.jwtService.VerifyToken(token); err == nil {
+			ctx.Set("userId", claims["userId"])
+			ctx.Set("role", claims["role"])
+		}
+
+		ctx.Next()
+	}
+}
 
 func NewAuthMiddleware(jwtService service.JwtService) AuthMiddleware {
 	return &authMiddleware{jwtService: jwtService}
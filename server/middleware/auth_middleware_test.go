@@ -31,6 +31,10 @@ func (suite *AuthMiddlewareTestSuite) SetupTest() {
 	suite.router.GET("/admin", authMiddleware.CheckToken("admin"), func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "ok"})
 	})
+	suite.router.GET("/optional", authMiddleware.OptionalToken(), func(c *gin.Context) {
+		userId, _ := c.Get("userId")
+		c.JSON(http.StatusOK, gin.H{"userId": userId})
+	})
 	suite.jwtService.On("VerifyToken", "").Return(jwt.MapClaims{}, assert.AnError)
 }
 
@@ -77,6 +81,40 @@ func (suite *AuthMiddlewareTestSuite) TestCheckToken_Forbidden() {
 	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
 }
 
+func (suite *AuthMiddlewareTestSuite) TestOptionalToken_Anonymous() {
+	req, _ := http.NewRequest(http.MethodGet, "/optional", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.JSONEq(suite.T(), `{"userId": null}`, w.Body.String())
+	suite.jwtService.AssertNotCalled(suite.T(), "VerifyToken", "")
+}
+
+func (suite *AuthMiddlewareTestSuite) TestOptionalToken_ValidToken() {
+	suite.jwtService.On("VerifyToken", "valid_token").Return(jwt.MapClaims{"userId": "user-1", "role": "user"}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/optional", nil)
+	req.Header.Set("Authorization", "Bearer valid_token")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.JSONEq(suite.T(), `{"userId": "user-1"}`, w.Body.String())
+}
+
+func (suite *AuthMiddlewareTestSuite) TestOptionalToken_InvalidToken() {
+	suite.jwtService.On("VerifyToken", "expired_token").Return(jwt.MapClaims{}, assert.AnError)
+
+	req, _ := http.NewRequest(http.MethodGet, "/optional", nil)
+	req.Header.Set("Authorization", "Bearer expired_token")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.JSONEq(suite.T(), `{"userId": null}`, w.Body.String())
+}
+
 func TestAuthMiddlewareTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthMiddlewareTestSuite))
 }
@@ -17,6 +17,8 @@ type ErrorHandler interface {
 	ValidationError(ctx context.Context, field string, message string) *utils.AppError
 	TimeoutError(ctx context.Context, operation string) *utils.AppError
 	CancellationError(ctx context.Context, operation string) *utils.AppError
+	PreconditionFailedError(ctx context.Context, resource string) *utils.AppError
+	PreconditionRequiredError(ctx context.Context, resource string) *utils.AppError
 }
 
 // errorHandler implements ErrorHandler interface
@@ -138,6 +140,16 @@ func (eh *errorHandler) CancellationError(ctx context.Context, operation string)
 	return eh.wrapper.CancellationError(ctx, operation)
 }
 
+// PreconditionFailedError creates a precondition failed error with context
+func (eh *errorHandler) PreconditionFailedError(ctx context.Context, resource string) *utils.AppError {
+	return eh.wrapper.PreconditionFailedError(ctx, resource)
+}
+
+// PreconditionRequiredError creates a precondition required error with context
+func (eh *errorHandler) PreconditionRequiredError(ctx context.Context, resource string) *utils.AppError {
+	return eh.wrapper.PreconditionRequiredError(ctx, resource)
+}
+
 // logError logs error with context information
 func (eh *errorHandler) logError(ctx context.Context, appErr *utils.AppError) {
 	fields := map[string]interface{}{
@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"develapar-server/utils"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cachePrivateKey marks a response as personal to the requester, see MarkPrivate
+const cachePrivateKey = "http_cache_private"
+
+// CachePolicy is the Cache-Control policy of a public read route
+type CachePolicy struct {
+	// MaxAge is how long browsers and shared caches reuse the response without asking again
+	MaxAge time.Duration
+	// StaleWhileRevalidate lets caches serve the stale response while they revalidate it
+	StaleWhileRevalidate time.Duration
+}
+
+// cacheControl renders the policy. Private responses may only be stored by the browser and
+// are revalidated on every use, since they can hold content other users must not see.
+func (p CachePolicy) cacheControl(private bool) string {
+	if private {
+		return "private, no-cache"
+	}
+
+	value := fmt.Sprintf("public, max-age=%d", int(p.MaxAge.Seconds()))
+	if p.StaleWhileRevalidate > 0 {
+		value += fmt.Sprintf(", stale-while-revalidate=%d", int(p.StaleWhileRevalidate.Seconds()))
+	}
+	return value
+}
+
+// MarkPrivate makes HTTPCacheMiddleware send the response as private even without an
+// Authorization header, for content unlocked by a token in the query string or another header
+func MarkPrivate(c *gin.Context) {
+	c.Set(cachePrivateKey, true)
+}
+
+// bufferedWriter holds the response back until the cache headers are known
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body   bytes.Buffer
+	status int
+}
+
+func (w *bufferedWriter) WriteHeader(statusCode int) {
+	w.status = statusCode
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return false
+}
+
+// HTTPCacheMiddleware adds caching metadata to a GET route and answers conditional requests.
+// Successful responses get a strong ETag computed from the payload, the Cache-Control of the
+// policy and the Last-Modified header the handler may have set. A request whose
+// If-None-Match or If-Modified-Since still matches gets 304 Not Modified without a body.
+// A version ETag set by the handler is kept as prefix, so the tag still works in If-Match.
+// Requests with an Authorization header or marked with MarkPrivate are cached privately.
+func HTTPCacheMiddleware(policy CachePolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		header := original.Header()
+		header.Add("Vary", "Authorization")
+
+		// Errors are never cached, a missing article may be published a second later
+		if writer.status != http.StatusOK {
+			header.Set("Cache-Control", "no-store")
+			original.WriteHeader(writer.status)
+			original.Write(writer.body.Bytes())
+			return
+		}
+
+		etag := strongETag(header.Get("ETag"), cachedPayload(writer.body.Bytes()))
+		header.Set("ETag", etag)
+
+		private := c.GetHeader("Authorization") != "" || c.GetBool(cachePrivateKey)
+		header.Set("Cache-Control", policy.cacheControl(private))
+
+		var lastModified time.Time
+		if value := header.Get("Last-Modified"); value != "" {
+			if parsed, err := http.ParseTime(value); err == nil {
+				lastModified = parsed
+			}
+		}
+
+		if utils.IsNotModified(c.Request, etag, lastModified) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+
+		original.WriteHeader(writer.status)
+		original.Write(writer.body.Bytes())
+	}
+}
+
+// cachedPayload returns the part of a response body that identifies its content. The
+// envelope of the API responses carries a timestamp, a request id and the processing time
+// that differ on every request, so only its data and pagination count. Bodies that are not
+// an API envelope are used as they are.
+func cachedPayload(body []byte) []byte {
+	var envelope struct {
+		Data       json.RawMessage            `json:"data"`
+		Pagination map[string]json.RawMessage `json:"pagination"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Data == nil {
+		return body
+	}
+	if envelope.Pagination == nil {
+		return envelope.Data
+	}
+
+	delete(envelope.Pagination, "request_id")
+	pagination, err := json.Marshal(envelope.Pagination)
+	if err != nil {
+		return body
+	}
+	return append(append(envelope.Data, '\n'), pagination...)
+}
+
+// strongETag derives the entity tag of a response body. A strong tag set by the handler,
+// such as a version tag, is extended with the body hash so the tag changes whenever the
+// bytes do, while still identifying the version it was made from.
+func strongETag(handlerTag string, body []byte) string {
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:16])
+
+	if handlerTag == "" || strings.HasPrefix(handlerTag, "W/") || len(handlerTag) < 2 {
+		return `"` + hash + `"`
+	}
+	return strings.TrimSuffix(handlerTag, `"`) + "-" + hash + `"`
+}
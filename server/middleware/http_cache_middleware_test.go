@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"develapar-server/model/dto"
+	"develapar-server/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCachePolicy = CachePolicy{MaxAge: time.Minute, StaleWhileRevalidate: 5 * time.Minute}
+
+func setupHTTPCacheRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/resource", HTTPCacheMiddleware(testCachePolicy), handler)
+	router.POST("/resource", HTTPCacheMiddleware(testCachePolicy), handler)
+	return router
+}
+
+func performCacheRequest(router *gin.Engine, method string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/resource", nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHTTPCacheMiddleware_PublicResponse(t *testing.T) {
+	router := setupHTTPCacheRouter(func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"name": "golang"})
+	})
+
+	w := performCacheRequest(router, http.MethodGet, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"name":"golang"}`, w.Body.String())
+	assert.Equal(t, "public, max-age=60, stale-while-revalidate=300", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "Authorization", w.Header().Get("Vary"))
+
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.NotContains(t, etag, "W/")
+
+	// The same body always gets the same tag
+	again := performCacheRequest(router, http.MethodGet, nil)
+	assert.Equal(t, etag, again.Header().Get("ETag"))
+}
+
+func TestHTTPCacheMiddleware_IfNoneMatch(t *testing.T) {
+	router := setupHTTPCacheRouter(func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"name": "golang"})
+	})
+
+	etag := performCacheRequest(router, http.MethodGet, nil).Header().Get("ETag")
+
+	w := performCacheRequest(router, http.MethodGet, map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	assert.Empty(t, w.Header().Get("Content-Type"))
+
+	w = performCacheRequest(router, http.MethodGet, map[string]string{"If-None-Match": `"stale"`})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Body.String())
+}
+
+func TestHTTPCacheMiddleware_IfModifiedSince(t *testing.T) {
+	modifiedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	router := setupHTTPCacheRouter(func(c *gin.Context) {
+		c.Header("Last-Modified", modifiedAt.Format(http.TimeFormat))
+		c.JSON(http.StatusOK, gin.H{"name": "golang"})
+	})
+
+	w := performCacheRequest(router, http.MethodGet, map[string]string{"If-Modified-Since": modifiedAt.Format(http.TimeFormat)})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, modifiedAt.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+
+	earlier := modifiedAt.Add(-time.Hour).Format(http.TimeFormat)
+	w = performCacheRequest(router, http.MethodGet, map[string]string{"If-Modified-Since": earlier})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHTTPCacheMiddleware_PrivateResponses(t *testing.T) {
+	t.Run("authorization header", func(t *testing.T) {
+		router := setupHTTPCacheRouter(func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"name": "golang"})
+		})
+
+		w := performCacheRequest(router, http.MethodGet, map[string]string{"Authorization": "Bearer token"})
+		assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+	})
+
+	t.Run("marked by handler", func(t *testing.T) {
+		router := setupHTTPCacheRouter(func(c *gin.Context) {
+			MarkPrivate(c)
+			c.JSON(http.StatusOK, gin.H{"name": "golang"})
+		})
+
+		w := performCacheRequest(router, http.MethodGet, nil)
+		assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+	})
+}
+
+func TestHTTPCacheMiddleware_VersionETag(t *testing.T) {
+	router := setupHTTPCacheRouter(func(c *gin.Context) {
+		c.Header("ETag", `"v3"`)
+		c.JSON(http.StatusOK, gin.H{"name": "golang"})
+	})
+
+	w := performCacheRequest(router, http.MethodGet, nil)
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^"v3-[0-9a-f]{32}"$`, etag)
+
+	w = performCacheRequest(router, http.MethodGet, map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
+
+func TestHTTPCacheMiddleware_ResponseHelperEnvelope(t *testing.T) {
+	name := "golang"
+	router := setupHTTPCacheRouter(func(c *gin.Context) {
+		// The envelope gets a new timestamp and processing time on every request
+		utils.NewResponseHelper().SendSuccess(c, gin.H{"name": name})
+	})
+
+	first := performCacheRequest(router, http.MethodGet, nil)
+	time.Sleep(time.Millisecond)
+	second := performCacheRequest(router, http.MethodGet, nil)
+	require.Equal(t, http.StatusOK, second.Code)
+	assert.NotEqual(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, first.Header().Get("ETag"), second.Header().Get("ETag"), "the tag only depends on the data")
+
+	w := performCacheRequest(router, http.MethodGet, map[string]string{"If-None-Match": first.Header().Get("ETag")})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	name = "rust"
+	w = performCacheRequest(router, http.MethodGet, map[string]string{"If-None-Match": first.Header().Get("ETag")})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, first.Header().Get("ETag"), w.Header().Get("ETag"))
+}
+
+func TestHTTPCacheMiddleware_PaginatedEnvelope(t *testing.T) {
+	total := 10
+	router := setupHTTPCacheRouter(func(c *gin.Context) {
+		utils.NewResponseHelper().SendSuccessWithPagination(c, []string{"golang"}, &dto.PaginationMetadata{Page: 1, Limit: 1, Total: total, RequestID: uuid.NewString()})
+	})
+
+	first := performCacheRequest(router, http.MethodGet, nil)
+	w := performCacheRequest(router, http.MethodGet, map[string]string{"If-None-Match": first.Header().Get("ETag")})
+	assert.Equal(t, http.StatusNotModified, w.Code, "the request id of the pagination is ignored")
+
+	total = 11
+	w = performCacheRequest(router, http.MethodGet, map[string]string{"If-None-Match": first.Header().Get("ETag")})
+	assert.Equal(t, http.StatusOK, w.Code, "a new total changes the tag")
+}
+
+func TestHTTPCacheMiddleware_ErrorsAreNotCached(t *testing.T) {
+	router := setupHTTPCacheRouter(func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+
+	w := performCacheRequest(router, http.MethodGet, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"not found"}`, w.Body.String())
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Empty(t, w.Header().Get("ETag"))
+}
+
+func TestHTTPCacheMiddleware_IgnoresOtherMethods(t *testing.T) {
+	router := setupHTTPCacheRouter(func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"name": "golang"})
+	})
+
+	w := performCacheRequest(router, http.MethodPost, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+	assert.Empty(t, w.Header().Get("ETag"))
+}
@@ -687,6 +687,45 @@ func (rlm *RateLimitMiddleware) calculateProcessingTime(ctx context.Context) tim
 	return 0
 }
 
+// RouteLimit returns a Gin middleware limiting a single route on top of the global limit.
+// Requests are counted per key returned by keyFn under their own name, and the limit applies
+// even when the global rate limiting is disabled. It is meant for routes open to guessing,
+// such as password checks.
+func (rlm *RateLimitMiddleware) RouteLimit(name string, limit int, window time.Duration, keyFn func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		key := "route:" + name + ":" + keyFn(c)
+
+		allowed, err := rlm.limiter.Allow(ctx, key, limit, window)
+		if err != nil {
+			rlm.logger.Error(ctx, "Route rate limit check failed", err, map[string]interface{}{
+				"key":       key,
+				"client_ip": c.ClientIP(),
+			})
+
+			// On error, allow the request but log the issue
+			c.Next()
+			return
+		}
+
+		if !allowed {
+			rlm.logger.Warn(ctx, "Route rate limit exceeded", map[string]interface{}{
+				"key":       key,
+				"client_ip": c.ClientIP(),
+				"limit":     limit,
+				"window":    window,
+			})
+
+			c.Header("Retry-After", fmt.Sprintf("%.0f", window.Seconds()))
+			c.JSON(429, dto.RateLimitErrorResponse(ctx, int(window.Seconds())))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // CleanupMiddleware returns a middleware that periodically cleans up expired rate limit entries
 func (rlm *RateLimitMiddleware) CleanupMiddleware(interval time.Duration) gin.HandlerFunc {
 	// Start cleanup goroutine
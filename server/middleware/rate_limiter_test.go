@@ -3,9 +3,12 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -784,4 +787,33 @@ func TestNewMonitoredRateLimitMiddleware(t *testing.T) {
 	monitor := middleware.GetMonitor()
 	assert.NotNil(t, monitor)
 	assert.Equal(t, middleware.monitor, monitor)
-}
\ No newline at end of file
+}
+
+func TestRateLimitMiddleware_RouteLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := &defaultLogger{}
+	limiter := NewSlidingWindowRateLimiter(NewInMemoryStore(logger), logger)
+	middleware := NewRateLimitMiddleware(limiter, DefaultRateLimitConfig(), logger)
+
+	router := gin.New()
+	router.POST("/articles/:article_id/unlock",
+		middleware.RouteLimit("unlock", 2, time.Minute, func(c *gin.Context) string {
+			return c.Param("article_id") + ":" + c.ClientIP()
+		}),
+		func(c *gin.Context) { c.Status(http.StatusOK) },
+	)
+
+	unlock := func(articleId, ip string) int {
+		req := httptest.NewRequest(http.MethodPost, "/articles/"+articleId+"/unlock", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, unlock("first", "203.0.113.7"))
+	assert.Equal(t, http.StatusOK, unlock("first", "203.0.113.7"))
+	assert.Equal(t, http.StatusTooManyRequests, unlock("first", "203.0.113.7"))
+	assert.Equal(t, http.StatusOK, unlock("second", "203.0.113.7"), "other articles are counted on their own")
+	assert.Equal(t, http.StatusOK, unlock("first", "198.51.100.1"), "other addresses are counted on their own")
+}
@@ -7,17 +7,38 @@ import (
 )
 
 type Article struct {
-	Id         uuid.UUID `json:"id"`
-	Title      string    `json:"title"`
-	Slug       string    `json:"slug"`
-	Content    string    `json:"content"`
-	UserId     uuid.UUID `json:"user_id"`
-	User       *User     `json:"user,omitempty"`
-	CategoryId uuid.UUID `json:"category_id"`
-	Category   *Category `json:"category,omitempty"`
-	Views      int       `json:"views"`
-	Status     string    `json:"status"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	Tags       []Tags    `json:"tags"`
+	Id                 uuid.UUID         `json:"id"`
+	Title              string            `json:"title"`
+	Slug               string            `json:"slug"`
+	SlugPinned         bool              `json:"slug_pinned"`
+	Content            string            `json:"content"`
+	ContentHTML        string            `json:"content_html"`
+	TOC                []TOCEntry        `json:"toc"`
+	Excerpt            string            `json:"excerpt"`
+	ReadingTimeMinutes int               `json:"reading_time_minutes"`
+	ContentHash        string            `json:"-"`
+	UserId             uuid.UUID         `json:"user_id"`
+	User               *User             `json:"user,omitempty"`
+	CategoryId         uuid.UUID         `json:"category_id"`
+	Category           *Category         `json:"category,omitempty"`
+	Views              int               `json:"views"`
+	CommentCount       int               `json:"comment_count"`
+	Status             string            `json:"status"`
+	Visibility         string            `json:"visibility"`
+	PasswordHash       string            `json:"-"`
+	PublishAt          *time.Time        `json:"publish_at"`
+	UnpublishAt        *time.Time        `json:"unpublish_at"`
+	Version            int               `json:"version"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	Tags               []Tags            `json:"tags"`
+	Authors            []ArticleAuthor   `json:"authors,omitempty"`
+	Series             *SeriesNavigation `json:"series,omitempty"`
+}
+
+// TOCEntry is a heading of the rendered article body, Anchor is the id of the heading element
+type TOCEntry struct {
+	Level  int    `json:"level"`
+	Text   string `json:"text"`
+	Anchor string `json:"anchor"`
 }
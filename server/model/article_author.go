@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Contributor roles of an article. The author is the user who created the article.
+const (
+	ArticleAuthorRoleAuthor   = "author"
+	ArticleAuthorRoleCoAuthor = "co-author"
+	ArticleAuthorRoleEditor   = "editor"
+	ArticleAuthorRoleReviewer = "reviewer"
+)
+
+// ArticleAuthorEditRoles are the contributor roles allowed to edit an article,
+// reviewers are credited but cannot change it
+var ArticleAuthorEditRoles = []string{ArticleAuthorRoleAuthor, ArticleAuthorRoleCoAuthor, ArticleAuthorRoleEditor}
+
+// ArticleAuthorBylineRoles are the contributor roles an article is listed under
+// on the author's article pages and feeds
+var ArticleAuthorBylineRoles = []string{ArticleAuthorRoleAuthor, ArticleAuthorRoleCoAuthor}
+
+type ArticleAuthor struct {
+	ArticleId uuid.UUID `json:"article_id"`
+	UserId    uuid.UUID `json:"user_id"`
+	User      *User     `json:"user,omitempty"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArticlePreview is a shareable link showing an unpublished article to readers without
+// an account. The link carries a signed token naming the preview; the preview row decides
+// whether the link still works, so it can be revoked or run out of views before it expires.
+type ArticlePreview struct {
+	Id        uuid.UUID  `json:"id"`
+	ArticleId uuid.UUID  `json:"article_id"`
+	CreatedBy uuid.UUID  `json:"created_by"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	MaxViews  *int       `json:"max_views"`
+	ViewCount int        `json:"view_count"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ArticleRevision struct {
+	Id             uuid.UUID `json:"id"`
+	ArticleId      uuid.UUID `json:"article_id"`
+	RevisionNumber int       `json:"revision_number"`
+	Title          string    `json:"title"`
+	Slug           string    `json:"slug"`
+	Content        string    `json:"content"`
+	CategoryId     uuid.UUID `json:"category_id"`
+	Status         string    `json:"status"`
+	EditorId       uuid.UUID `json:"editor_id"`
+	Editor         *User     `json:"editor,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
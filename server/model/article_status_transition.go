@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Article statuses of the editorial workflow
+const (
+	ArticleStatusDraft     = "draft"
+	ArticleStatusInReview  = "in_review"
+	ArticleStatusApproved  = "approved"
+	ArticleStatusScheduled = "scheduled"
+	ArticleStatusPublished = "published"
+	ArticleStatusArchived  = "archived"
+)
+
+type ArticleStatusTransition struct {
+	Id         uuid.UUID `json:"id"`
+	ArticleId  uuid.UUID `json:"article_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ActorId    uuid.UUID `json:"actor_id"`
+	Actor      *User     `json:"actor,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
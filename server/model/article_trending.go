@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Time windows of the trending article rankings
+const (
+	TrendingWindowDay   = "24h"
+	TrendingWindowWeek  = "7d"
+	TrendingWindowMonth = "30d"
+)
+
+// TrendingWindows maps every trending window to the activity period it counts
+var TrendingWindows = map[string]time.Duration{
+	TrendingWindowDay:   24 * time.Hour,
+	TrendingWindowWeek:  7 * 24 * time.Hour,
+	TrendingWindowMonth: 30 * 24 * time.Hour,
+}
+
+// ArticleActivity counts the interactions with a public article within a trending window
+type ArticleActivity struct {
+	ArticleId   uuid.UUID
+	PublishedAt time.Time
+	Views       int
+	Likes       int
+	Comments    int
+	Bookmarks   int
+}
+
+// ArticleTrendingScore is the score of an article in a trending window
+type ArticleTrendingScore struct {
+	ArticleActivity
+	Score float64
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArticleView is a single counted read of an article, kept for analytics
+type ArticleView struct {
+	Id        uuid.UUID `json:"id"`
+	ArticleId uuid.UUID `json:"article_id"`
+	UserId    uuid.UUID `json:"user_id"` // uuid.Nil for anonymous visitors
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	Referrer  string    `json:"referrer"`
+	ViewedAt  time.Time `json:"viewed_at"`
+}
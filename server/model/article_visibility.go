@@ -0,0 +1,11 @@
+package model
+
+// Article visibilities. Public articles are listed everywhere, unlisted ones are only
+// reachable through their link, private ones only by their authors, editors and admins,
+// and password protected ones by anyone who knows the password.
+const (
+	ArticleVisibilityPublic            = "public"
+	ArticleVisibilityUnlisted          = "unlisted"
+	ArticleVisibilityPrivate           = "private"
+	ArticleVisibilityPasswordProtected = "password_protected"
+)
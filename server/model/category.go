@@ -9,6 +9,8 @@ import (
 type Category struct {
 	Id        uuid.UUID `json:"id"`
 	Name      string    `json:"name"`
+	Slug      string    `json:"slug,omitempty"`
+	Version   int       `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -6,13 +6,58 @@ import (
 	"github.com/google/uuid"
 )
 
-type Comment struct {
-	Id        uuid.UUID `json:"id"`
-	ArticleId uuid.UUID `json:"article_id"`
-	UserId    uuid.UUID `json:"user_id"`
-	Article   *Article  `json:"article,omitempty"`
-	User      *User     `json:"user,omitempty"`
-	Content   string    `json:"content"`
+// Formats of the comments of an article: a list in thread order or nested replies
+const (
+	CommentFormatFlat = "flat"
+	CommentFormatTree = "tree"
+)
+
+// Orders of the top level comments of an article, replies always follow their parent
+// oldest first
+const (
+	CommentSortNewest    = "newest"
+	CommentSortOldest    = "oldest"
+	CommentSortMostLiked = "most_liked"
+)
+
+// CommentCursor marks the last top level comment of a page, the next page starts after it
+type CommentCursor struct {
+	Sort      string    `json:"sort"`
+	LikeCount int       `json:"like_count"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id        uuid.UUID `json:"id"`
+}
+
+// CommentDeletedContent replaces the content of a deleted comment kept in a thread
+// because it still has replies
+const CommentDeletedContent = "[deleted]"
+
+type Comment struct {
+	Id              uuid.UUID  `json:"id"`
+	ArticleId       uuid.UUID  `json:"article_id"`
+	UserId          uuid.UUID  `json:"user_id"`
+	ParentCommentId *uuid.UUID `json:"parent_comment_id"`
+	Article         *Article   `json:"article,omitempty"`
+	User            *User      `json:"user,omitempty"`
+	Content         string     `json:"content"`
+	Status          string     `json:"status,omitempty"`
+	LikeCount       int        `json:"like_count"`
+	// SpamScore and SpamSignals record how the spam filter scored the comment, for auditing.
+	// They are only filled in for moderators.
+	SpamScore   *float64           `json:"spam_score,omitempty"`
+	SpamSignals map[string]float64 `json:"spam_signals,omitempty"`
+	// IsEdited is set once the author changed the content, EditedAt is the last change
+	IsEdited bool       `json:"is_edited"`
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+	// IsDeleted marks a tombstone: a deleted comment shown only to keep its replies in place
+	IsDeleted bool `json:"is_deleted"`
+	// Depth and Path locate the comment in its thread, top level comments have depth 0 and
+	// the path lists the ids from the top level comment down to this one
+	Depth   int       `json:"depth"`
+	Path    string    `json:"path,omitempty"`
+	Replies []Comment `json:"replies,omitempty"`
+	// HasMoreReplies is set on a top level comment listed with only part of its replies
+	HasMoreReplies bool      `json:"has_more_replies,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommentEdit is one change of the content of a comment
+type CommentEdit struct {
+	Id              uuid.UUID  `json:"id"`
+	CommentId       uuid.UUID  `json:"comment_id"`
+	PreviousContent string     `json:"previous_content"`
+	Content         string     `json:"content"`
+	EditedBy        *uuid.UUID `json:"edited_by"`
+	Editor          *User      `json:"editor,omitempty"`
+	EditedAt        time.Time  `json:"edited_at"`
+}
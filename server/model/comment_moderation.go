@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment statuses. Only approved comments are shown to everyone, pending ones are
+// also shown to their own author.
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+)
+
+// Moderation actions of the queue and the status each one sets
+const (
+	CommentModerationApprove = "approve"
+	CommentModerationReject  = "reject"
+	CommentModerationSpam    = "spam"
+)
+
+// CommentModerationStatuses maps the moderation actions to the status they set
+var CommentModerationStatuses = map[string]string{
+	CommentModerationApprove: CommentStatusApproved,
+	CommentModerationReject:  CommentStatusRejected,
+	CommentModerationSpam:    CommentStatusSpam,
+}
+
+// Moderation policies deciding the status of a new comment. Comments of editors and
+// admins are always approved.
+const (
+	// CommentPolicyAutoApprove approves every comment
+	CommentPolicyAutoApprove = "auto_approve"
+	// CommentPolicyHoldFirstTime holds the comments of users without an approved comment
+	CommentPolicyHoldFirstTime = "hold_first_time"
+	// CommentPolicyHoldUntrusted holds the comments of users with fewer approved comments
+	// than the trusted threshold
+	CommentPolicyHoldUntrusted = "hold_untrusted"
+)
+
+// CommentModerationPolicy is the global policy when ArticleId is nil, or the policy of
+// one article overriding it
+type CommentModerationPolicy struct {
+	ArticleId *uuid.UUID `json:"article_id"`
+	Mode      string     `json:"mode"`
+	UpdatedBy *uuid.UUID `json:"updated_by,omitempty"`
+	// UpdatedAt is nil for the default policy of the configuration
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
@@ -1,22 +1,67 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
 
+	"github.com/google/uuid"
+)
+
+// CreateArticleRequest creates an article as draft or directly submitted for review;
+// publishing always goes through the editorial workflow. A future PublishAt makes the
+// publish step schedule the article, and UnpublishAt archives it again at that time.
+// Visibility defaults to public, password protected articles need a Password.
 type CreateArticleRequest struct {
-	Title      string    `json:"title" binding:"required"`
-	Content    string    `json:"content" binding:"required"`
-	Status     string    `json:"status" binding:"required,oneof=draft published"`
-	CategoryID uuid.UUID `json:"category_id" binding:"required"`
-	Tags       []string  `json:"tags,omitempty"`
+	Title       string     `json:"title" binding:"required"`
+	Content     string     `json:"content" binding:"required"`
+	Status      string     `json:"status" binding:"required,oneof=draft in_review"`
+	CategoryID  uuid.UUID  `json:"category_id" binding:"required"`
+	Tags        []string   `json:"tags,omitempty"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty"`
+	Visibility  string     `json:"visibility,omitempty" binding:"omitempty,oneof=public unlisted private password_protected"`
+	Password    string     `json:"password,omitempty" binding:"omitempty,max=72"`
 }
 
+// UpdateArticleRequest changes an article. A non-empty Slug pins a custom slug that
+// later title changes keep, an empty Slug unpins it and derives the slug from the title again.
 type UpdateArticleRequest struct {
-	Title      *string    `json:"title"`
-	Content    *string    `json:"content"`
-	CategoryID *uuid.UUID `json:"category_id"`
-	Tags       []string   `json:"tags,omitempty"`
+	Title       *string    `json:"title"`
+	Slug        *string    `json:"slug,omitempty" binding:"omitempty,max=255"`
+	Content     *string    `json:"content"`
+	CategoryID  *uuid.UUID `json:"category_id"`
+	Tags        []string   `json:"tags,omitempty"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty"`
+	Visibility  *string    `json:"visibility,omitempty" binding:"omitempty,oneof=public unlisted private password_protected"`
+	Password    *string    `json:"password,omitempty" binding:"omitempty,max=72"`
 }
 
 type UpdateCategoryRequest struct {
 	Name *string `json:"name"`
 }
+
+// ArticleTransitionRequest carries the optional note of a workflow transition.
+// A reason is required when rejecting an article.
+type ArticleTransitionRequest struct {
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+// ArticleAuthorRequest credits a user on an article. The author role belongs to the
+// user who created the article and cannot be assigned.
+type ArticleAuthorRequest struct {
+	UserId uuid.UUID `json:"user_id" binding:"required"`
+	Role   string    `json:"role" binding:"required,oneof=co-author editor reviewer"`
+}
+
+// UnlockArticleRequest carries the password of a password protected article
+type UnlockArticleRequest struct {
+	Password string `json:"password" binding:"required,max=72"`
+}
+
+// CreateArticlePreviewRequest configures a shareable preview link of an unpublished
+// article. The link expires after ExpiresInHours, 72 by default, and stops working
+// after MaxViews views when set.
+type CreateArticlePreviewRequest struct {
+	ExpiresInHours int  `json:"expires_in_hours" binding:"omitempty,min=1,max=720"`
+	MaxViews       *int `json:"max_views" binding:"omitempty,min=1"`
+}
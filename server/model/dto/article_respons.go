@@ -9,31 +9,133 @@ import (
 )
 
 type CommentResponse struct {
-	Id        int             `json:"id"`
+	Id        uuid.UUID       `json:"id"`
 	Content   string          `json:"content"`
+	IsEdited  bool            `json:"is_edited"`
+	EditedAt  *time.Time      `json:"edited_at,omitempty"`
 	CreatedAt time.Time       `json:"created_at"`
 	User      UserResponse    `json:"user"`
 	Article   ArticleResponse `json:"article"`
 }
 
 type UserResponse struct {
-	Id    int    `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Id    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
 }
 
 type ArticleResponse struct {
-	Id         uuid.UUID       `json:"id"`
-	Title      string          `json:"title"`
-	Slug       string          `json:"slug"`
-	Content    string          `json:"content"`
-	UserId     uuid.UUID       `json:"user_id"`
-	User       *model.User     `json:"user,omitempty"`
-	CategoryId uuid.UUID       `json:"category_id"`
-	Category   *model.Category `json:"category,omitempty"`
-	Views      int             `json:"views"`
-	Status     string          `json:"status"`
-	CreatedAt  time.Time       `json:"created_at"`
-	UpdatedAt  time.Time       `json:"updated_at"`
-	Tags       []model.Tags    `json:"tags"`
+	Id                 uuid.UUID        `json:"id"`
+	Title              string           `json:"title"`
+	Slug               string           `json:"slug"`
+	Content            string           `json:"content"`
+	ContentHTML        string           `json:"content_html"`
+	TOC                []model.TOCEntry `json:"toc"`
+	Excerpt            string           `json:"excerpt"`
+	ReadingTimeMinutes int              `json:"reading_time_minutes"`
+	UserId             uuid.UUID        `json:"user_id"`
+	User               *model.User      `json:"user,omitempty"`
+	CategoryId         uuid.UUID        `json:"category_id"`
+	Category           *model.Category  `json:"category,omitempty"`
+	Views              int              `json:"views"`
+	CommentCount       int              `json:"comment_count"`
+	Status             string           `json:"status"`
+	PublishAt          *time.Time       `json:"publish_at"`
+	UnpublishAt        *time.Time       `json:"unpublish_at"`
+	CreatedAt          time.Time        `json:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at"`
+	Tags               []model.Tags     `json:"tags"`
+}
+
+// ArticleSearchResult is a single full-text search hit with its ranking and
+// a highlighted snippet of the matching content.
+type ArticleSearchResult struct {
+	Id           uuid.UUID       `json:"id"`
+	Title        string          `json:"title"`
+	Slug         string          `json:"slug"`
+	UserId       uuid.UUID       `json:"user_id"`
+	User         *model.User     `json:"user,omitempty"`
+	CategoryId   uuid.UUID       `json:"category_id"`
+	Category     *model.Category `json:"category,omitempty"`
+	Views        int             `json:"views"`
+	CommentCount int             `json:"comment_count"`
+	Status       string          `json:"status"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	Rank         float64         `json:"rank"`
+	Snippet      string          `json:"snippet"`
+}
+
+// ArticleSearchFilter narrows a full-text search to a category and/or tag name.
+type ArticleSearchFilter struct {
+	Query    string
+	Category string
+	Tag      string
+}
+
+// RelatedArticle is a published article recommended next to another one.
+// Score combines tag overlap, category, text similarity and recency.
+type RelatedArticle struct {
+	model.Article
+	SharedTags int     `json:"shared_tags"`
+	Score      float64 `json:"score"`
+}
+
+// TrendingArticle is a published article ranked by its recent activity.
+// The counts are the interactions within the requested window.
+type TrendingArticle struct {
+	model.Article
+	Score     float64 `json:"score"`
+	Views     int     `json:"recent_views"`
+	Likes     int     `json:"recent_likes"`
+	Comments  int     `json:"recent_comments"`
+	Bookmarks int     `json:"recent_bookmarks"`
+}
+
+// ArticleFeedFilter narrows a syndication feed to a category name, a tag name or an author.
+// Empty fields do not filter.
+type ArticleFeedFilter struct {
+	Category string
+	Tag      string
+	UserId   uuid.UUID
+}
+
+// RevisionDiffLine is a single line of a line-level diff between two article revisions.
+// Op is one of "equal", "insert" or "delete"; line numbers are 1-based and zero when
+// the line does not exist on that side.
+type RevisionDiffLine struct {
+	Op      string `json:"op"`
+	OldLine int    `json:"old_line,omitempty"`
+	NewLine int    `json:"new_line,omitempty"`
+	Text    string `json:"text"`
+}
+
+// ArticleRevisionDiff describes the changes between two revisions of the same article.
+type ArticleRevisionDiff struct {
+	ArticleId    uuid.UUID          `json:"article_id"`
+	FromRevision int                `json:"from_revision"`
+	ToRevision   int                `json:"to_revision"`
+	TitleChanged bool               `json:"title_changed"`
+	FromTitle    string             `json:"from_title"`
+	ToTitle      string             `json:"to_title"`
+	Added        int                `json:"added"`
+	Removed      int                `json:"removed"`
+	Lines        []RevisionDiffLine `json:"lines"`
+}
+
+// ArticleAccessResponse is the token that unlocks a password protected article. It is
+// sent back in the X-Article-Token header.
+type ArticleAccessResponse struct {
+	ArticleId uuid.UUID `json:"article_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ArticlePreviewResponse is a new preview link. The token is only returned once, it is
+// sent back in the X-Preview-Token header. The link carries it in the URL fragment,
+// which browsers never send to a server.
+type ArticlePreviewResponse struct {
+	Preview model.ArticlePreview `json:"preview"`
+	Token   string               `json:"token"`
+	Url     string               `json:"url"`
 }
@@ -0,0 +1,32 @@
+package dto
+
+import "github.com/google/uuid"
+
+// CreateCommentRequest adds a comment to an article, or a reply when ParentCommentId is set
+type CreateCommentRequest struct {
+	ArticleId       uuid.UUID  `json:"article_id" binding:"required"`
+	ParentCommentId *uuid.UUID `json:"parent_comment_id,omitempty"`
+	Content         string     `json:"content" binding:"required"`
+}
+
+// ModerateCommentsRequest applies one moderation action to several comments at once
+type ModerateCommentsRequest struct {
+	CommentIds []uuid.UUID `json:"comment_ids" binding:"required,min=1,max=100"`
+	Action     string      `json:"action" binding:"required,oneof=approve reject spam"`
+}
+
+// SetCommentPolicyRequest sets the moderation policy of an article, or the global one
+// when ArticleId is not set
+type SetCommentPolicyRequest struct {
+	ArticleId *uuid.UUID `json:"article_id,omitempty"`
+	Mode      string     `json:"mode" binding:"required,oneof=auto_approve hold_first_time hold_untrusted"`
+}
+
+// CommentPageQuery selects a page of the comments of an article. Cursor is the next_cursor
+// of the previous page, empty for the first one.
+type CommentPageQuery struct {
+	Format string
+	Sort   string
+	Cursor string
+	Limit  int
+}
@@ -10,3 +10,10 @@ type JwtTokenClaims struct {
 	UserId uuid.UUID `json:"userId"`
 	Role   string    `json:"role"`
 }
+
+// ScopedTokenClaims grant a single purpose on the resource named by Subject, such as
+// reading one password protected article. They carry no user and never authenticate one.
+type ScopedTokenClaims struct {
+	jwt.RegisteredClaims
+	Purpose string `json:"purpose"`
+}
@@ -12,6 +12,7 @@ type ProductCategoryResponse struct {
 	Name        string    `json:"name"`
 	Slug        string    `json:"slug"`
 	Description *string   `json:"description"`
+	Version     int       `json:"version"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -21,6 +22,7 @@ type ProductAffiliateLinkResponse struct {
 	Id           uuid.UUID `json:"id"`
 	PlatformName string    `json:"platform_name"`
 	Url          string    `json:"url"`
+	Version      int       `json:"version"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -35,6 +37,7 @@ type ProductResponse struct {
 	ImageUrl          *string                        `json:"image_url"`
 	IsActive          bool                           `json:"is_active"`
 	AffiliateLinks    []ProductAffiliateLinkResponse `json:"affiliate_links"`
+	Version           int                            `json:"version"`
 	CreatedAt         time.Time                      `json:"created_at"`
 	UpdatedAt         time.Time                      `json:"updated_at"`
 }
@@ -48,6 +51,7 @@ type ProductListResponse struct {
 	Description       *string                  `json:"description"`
 	ImageUrl          *string                  `json:"image_url"`
 	IsActive          bool                     `json:"is_active"`
+	Version           int                      `json:"version"`
 	CreatedAt         time.Time                `json:"created_at"`
 	UpdatedAt         time.Time                `json:"updated_at"`
 }
\ No newline at end of file
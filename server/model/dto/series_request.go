@@ -0,0 +1,23 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateSeriesRequest struct {
+	Title       string `json:"title" binding:"required,max=200"`
+	Description string `json:"description" binding:"max=2000"`
+}
+
+type UpdateSeriesRequest struct {
+	Title       *string `json:"title" binding:"omitempty,max=200"`
+	Description *string `json:"description" binding:"omitempty,max=2000"`
+}
+
+// AddSeriesArticleRequest appends an article as the last part of a series
+type AddSeriesArticleRequest struct {
+	ArticleId uuid.UUID `json:"article_id" binding:"required"`
+}
+
+// ReorderSeriesRequest lists every article of a series in its new order
+type ReorderSeriesRequest struct {
+	ArticleIds []uuid.UUID `json:"article_ids" binding:"required,min=1"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// Sitemap entry kinds
+const (
+	SitemapKindArticle         = "article"
+	SitemapKindCategory        = "category"
+	SitemapKindTag             = "tag"
+	SitemapKindProductCategory = "product_category"
+)
+
+// SitemapEntry is a public page listed in the sitemap. Key is the slug or name
+// the page URL is built from.
+type SitemapEntry struct {
+	Kind    string
+	Key     string
+	LastMod time.Time
+}
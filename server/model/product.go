@@ -11,6 +11,7 @@ type ProductCategory struct {
 	Name        string    `json:"name"`
 	Slug        string    `json:"slug"`
 	Description *string   `json:"description"`
+	Version     int       `json:"version"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -23,6 +24,7 @@ type Product struct {
 	Description       *string          `json:"description"`
 	ImageUrl          *string          `json:"image_url"`
 	IsActive          bool             `json:"is_active"`
+	Version           int              `json:"version"`
 	CreatedAt         time.Time        `json:"created_at"`
 	UpdatedAt         time.Time        `json:"updated_at"`
 }
@@ -33,6 +35,7 @@ type ProductAffiliateLink struct {
 	Product      *Product  `json:"product,omitempty"`
 	PlatformName string    `json:"platform_name"`
 	Url          string    `json:"url"`
+	Version      int       `json:"version"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
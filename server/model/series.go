@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Series groups articles into an ordered, multi-part sequence such as a tutorial
+type Series struct {
+	Id          uuid.UUID       `json:"id"`
+	Title       string          `json:"title"`
+	Slug        string          `json:"slug"`
+	Description string          `json:"description"`
+	UserId      uuid.UUID       `json:"user_id"`
+	Articles    []SeriesArticle `json:"articles,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// SeriesArticle is one part of a series, Position starts at 1
+type SeriesArticle struct {
+	Position  int       `json:"position"`
+	ArticleId uuid.UUID `json:"article_id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	Status    string    `json:"status"`
+}
+
+// SeriesNavigation places an article within its series: part N of M with the
+// neighbouring parts. Previous and Next are nil at the ends of the series.
+type SeriesNavigation struct {
+	Id       uuid.UUID      `json:"id"`
+	Title    string         `json:"title"`
+	Slug     string         `json:"slug"`
+	Part     int            `json:"part"`
+	Total    int            `json:"total"`
+	Previous *SeriesArticle `json:"previous"`
+	Next     *SeriesArticle `json:"next"`
+}
@@ -16,6 +16,7 @@ type ArticleTag struct {
 type Tags struct {
 	Id        uuid.UUID `json:"id"`
 	Name      string    `json:"name"`
+	Version   int       `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Item types of the trash
+const (
+	TrashTypeArticle = "article"
+	TrashTypeComment = "comment"
+	TrashTypeProduct = "product"
+)
+
+// TrashItem is a soft deleted article, comment or product. It can be restored until
+// it is purged permanently after the retention period.
+type TrashItem struct {
+	Type      string    `json:"type"`
+	Id        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type ArticleAuthorRepository interface {
+	GetAuthors(ctx context.Context, articleId uuid.UUID) ([]model.ArticleAuthor, error)
+	// GetAuthorsByArticleIds loads the contributors of many articles at once, keyed by article
+	GetAuthorsByArticleIds(ctx context.Context, articleIds []uuid.UUID) (map[uuid.UUID][]model.ArticleAuthor, error)
+	// SetAuthor adds a contributor or changes the role of an existing one
+	SetAuthor(ctx context.Context, articleId, userId uuid.UUID, role string) (model.ArticleAuthor, error)
+	RemoveAuthor(ctx context.Context, articleId, userId uuid.UUID) error
+	// CanEdit reports whether the user is listed on the article with a role that may edit it
+	CanEdit(ctx context.Context, articleId, userId uuid.UUID) (bool, error)
+}
+
+type articleAuthorRepository struct {
+	db *sql.DB
+}
+
+// bylineCondition limits a query on alias "a" to articles credited to the user in
+// parameter param, either as the creating author or as a listed co-author
+func bylineCondition(param string) string {
+	return fmt.Sprintf(`(a.user_id = %[1]s OR EXISTS (
+		SELECT 1 FROM article_authors aa
+		WHERE aa.article_id = a.id AND aa.user_id = %[1]s AND aa.role IN ('author', 'co-author')))`, param)
+}
+
+// GetAuthors implements ArticleAuthorRepository.
+func (r *articleAuthorRepository) GetAuthors(ctx context.Context, articleId uuid.UUID) ([]model.ArticleAuthor, error) {
+	authors, err := r.GetAuthorsByArticleIds(ctx, []uuid.UUID{articleId})
+	if err != nil {
+		return nil, err
+	}
+	return authors[articleId], nil
+}
+
+// GetAuthorsByArticleIds implements ArticleAuthorRepository.
+// The creating author comes first, followed by the other contributors in the order they were added.
+func (r *articleAuthorRepository) GetAuthorsByArticleIds(ctx context.Context, articleIds []uuid.UUID) (map[uuid.UUID][]model.ArticleAuthor, error) {
+	ids := make([]string, len(articleIds))
+	for i, id := range articleIds {
+		ids[i] = id.String()
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT aa.article_id, aa.user_id, aa.role, aa.created_at, u.id, u.name, u.email, u.role
+	FROM article_authors aa
+	JOIN users u ON u.id = aa.user_id
+	WHERE aa.article_id = ANY($1::uuid[])
+	ORDER BY aa.article_id, aa.role <> 'author', aa.created_at`, pq.Array(ids))
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	authors := make(map[uuid.UUID][]model.ArticleAuthor, len(articleIds))
+	for rows.Next() {
+		var author model.ArticleAuthor
+		var user model.User
+		if err := rows.Scan(&author.ArticleId, &author.UserId, &author.Role, &author.CreatedAt, &user.Id, &user.Name, &user.Email, &user.Role); err != nil {
+			return nil, err
+		}
+		author.User = &user
+		authors[author.ArticleId] = append(authors[author.ArticleId], author)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return authors, nil
+}
+
+// SetAuthor implements ArticleAuthorRepository.
+func (r *articleAuthorRepository) SetAuthor(ctx context.Context, articleId, userId uuid.UUID, role string) (model.ArticleAuthor, error) {
+	var author model.ArticleAuthor
+	err := r.db.QueryRowContext(ctx, `
+	INSERT INTO article_authors (article_id, user_id, role, created_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (article_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	RETURNING article_id, user_id, role, created_at`, articleId, userId, role, time.Now()).Scan(
+		&author.ArticleId, &author.UserId, &author.Role, &author.CreatedAt,
+	)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return model.ArticleAuthor{}, ctx.Err()
+		}
+		return model.ArticleAuthor{}, err
+	}
+
+	return author, nil
+}
+
+// RemoveAuthor implements ArticleAuthorRepository.
+// It returns sql.ErrNoRows when the user is not listed on the article.
+func (r *articleAuthorRepository) RemoveAuthor(ctx context.Context, articleId, userId uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM article_authors WHERE article_id = $1 AND user_id = $2`, articleId, userId)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// CanEdit implements ArticleAuthorRepository.
+// The creating author can always edit, also for articles written before contributors were tracked.
+// Nobody can edit an article in the trash.
+func (r *articleAuthorRepository) CanEdit(ctx context.Context, articleId, userId uuid.UUID) (bool, error) {
+	var canEdit bool
+	err := r.db.QueryRowContext(ctx, `
+	SELECT EXISTS (
+		SELECT 1 FROM articles a
+		WHERE a.id = $1 AND a.deleted_at IS NULL
+		AND (a.user_id = $2
+			OR EXISTS (SELECT 1 FROM article_authors WHERE article_id = a.id AND user_id = $2 AND role = ANY($3))))`,
+		articleId, userId, pq.Array(model.ArticleAuthorEditRoles)).Scan(&canEdit)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return false, err
+	}
+
+	return canEdit, nil
+}
+
+func NewArticleAuthorRepository(database *sql.DB) ArticleAuthorRepository {
+	return &articleAuthorRepository{db: database}
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// GetFeedArticles implements ArticleRepository.
+// It returns the latest public articles with author, category and tag names,
+// newest publication first.
+func (a *articleRepository) GetFeedArticles(ctx context.Context, filter dto.ArticleFeedFilter, limit int) ([]model.Article, error) {
+	args := []interface{}{}
+	where := publicArticleCondition
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		where += fmt.Sprintf(` AND c.name = $%d`, len(args))
+	}
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		where += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM article_tags at
+			JOIN tags t ON at.tag_id = t.id
+			WHERE at.article_id = a.id AND t.name = $%d)`, len(args))
+	}
+	if filter.UserId != uuid.Nil {
+		args = append(args, filter.UserId)
+		where += ` AND ` + bylineCondition(fmt.Sprintf("$%d", len(args)))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`SELECT `+articleWithRelationsColumns+`,
+		ARRAY(
+			SELECT t.name FROM article_tags at
+			JOIN tags t ON at.tag_id = t.id
+			WHERE at.article_id = a.id
+			ORDER BY t.name
+		)`+articleWithRelationsJoins+`
+	WHERE %s
+	ORDER BY COALESCE(a.publish_at, a.created_at) DESC, a.id DESC
+	LIMIT $%d`, where, len(args))
+
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	articles := []model.Article{}
+	for rows.Next() {
+		// Check for context cancellation during iteration
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		var tagNames pq.StringArray
+		article, err := scanArticleWithRelations(withTrailingDest(rows, &tagNames))
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range tagNames {
+			article.Tags = append(article.Tags, model.Tags{Name: name})
+		}
+		articles = append(articles, article)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// GetPublicationStamp implements ArticleRepository.
+// It returns the latest change time of the public articles, their categories, tags and
+// tag links, and the number of public articles plus their tag links. Every publish,
+// update, archive or delete of a public article, every tag added to or removed from one
+// and every rename of a category or tag it uses changes the stamp, so it can be used to
+// tell whether anything derived from the public articles is still current.
+func (a *articleRepository) GetPublicationStamp(ctx context.Context) (time.Time, int, error) {
+	var lastModified time.Time
+	var count int
+	err := a.db.QueryRowContext(ctx, `
+	WITH public_articles AS (
+		SELECT a.id, GREATEST(a.updated_at, c.updated_at) AS updated_at
+		FROM articles a
+		JOIN categories c ON a.category_id = c.id
+		WHERE `+publicArticleCondition+`
+	), tag_links AS (
+		SELECT GREATEST(at.updated_at, t.updated_at) AS updated_at
+		FROM article_tags at
+		JOIN public_articles p ON p.id = at.article_id
+		JOIN tags t ON t.id = at.tag_id
+	)
+	SELECT
+		GREATEST(
+			COALESCE((SELECT MAX(updated_at) FROM public_articles), 'epoch'::timestamptz),
+			COALESCE((SELECT MAX(updated_at) FROM tag_links), 'epoch'::timestamptz)
+		),
+		(SELECT COUNT(*) FROM public_articles) + (SELECT COUNT(*) FROM tag_links)`).Scan(&lastModified, &count)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return time.Time{}, 0, ctx.Err()
+		}
+		return time.Time{}, 0, err
+	}
+	return lastModified, count, nil
+}
+
+// trailingScanner appends extra destinations after the ones given to Scan,
+// so the shared scan helpers can read queries with additional columns
+type trailingScanner struct {
+	row   rowScanner
+	extra []interface{}
+}
+
+func (s trailingScanner) Scan(dest ...interface{}) error {
+	return s.row.Scan(append(dest, s.extra...)...)
+}
+
+func withTrailingDest(row rowScanner, extra ...interface{}) rowScanner {
+	return trailingScanner{row: row, extra: extra}
+}
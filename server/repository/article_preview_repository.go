@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ArticlePreviewRepository interface {
+	CreatePreview(ctx context.Context, payload model.ArticlePreview) (model.ArticlePreview, error)
+	// GetPreviews lists the preview links of an article, newest first
+	GetPreviews(ctx context.Context, articleId uuid.UUID) ([]model.ArticlePreview, error)
+	// RevokePreview disables a preview link of an article, sql.ErrNoRows when the
+	// article has no such preview
+	RevokePreview(ctx context.Context, articleId, previewId uuid.UUID) (model.ArticlePreview, error)
+	// ConsumePreview counts a view on a preview link of an article. It returns
+	// sql.ErrNoRows when the link is unknown, revoked, expired or out of views.
+	ConsumePreview(ctx context.Context, articleId, previewId uuid.UUID) (model.ArticlePreview, error)
+}
+
+const articlePreviewColumns = `id, article_id, created_by, expires_at, max_views, view_count, revoked_at, created_at`
+
+type articlePreviewRepository struct {
+	db *sql.DB
+}
+
+func scanArticlePreview(row rowScanner) (model.ArticlePreview, error) {
+	var preview model.ArticlePreview
+	var maxViews sql.NullInt64
+	var revokedAt sql.NullTime
+	err := row.Scan(&preview.Id, &preview.ArticleId, &preview.CreatedBy, &preview.ExpiresAt, &maxViews, &preview.ViewCount, &revokedAt, &preview.CreatedAt)
+	if maxViews.Valid {
+		views := int(maxViews.Int64)
+		preview.MaxViews = &views
+	}
+	if revokedAt.Valid {
+		preview.RevokedAt = &revokedAt.Time
+	}
+	return preview, err
+}
+
+// CreatePreview implements ArticlePreviewRepository.
+func (r *articlePreviewRepository) CreatePreview(ctx context.Context, payload model.ArticlePreview) (model.ArticlePreview, error) {
+	newId := payload.Id
+	if newId == uuid.Nil {
+		newId = uuid.Must(uuid.NewV7())
+	}
+	preview, err := scanArticlePreview(r.db.QueryRowContext(ctx, `
+	INSERT INTO article_previews (id, article_id, created_by, expires_at, max_views, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING `+articlePreviewColumns,
+		newId, payload.ArticleId, payload.CreatedBy, payload.ExpiresAt, payload.MaxViews, time.Now()))
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return model.ArticlePreview{}, ctx.Err()
+		}
+		return model.ArticlePreview{}, err
+	}
+
+	return preview, nil
+}
+
+// GetPreviews implements ArticlePreviewRepository.
+func (r *articlePreviewRepository) GetPreviews(ctx context.Context, articleId uuid.UUID) ([]model.ArticlePreview, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+articlePreviewColumns+` FROM article_previews WHERE article_id = $1 ORDER BY created_at DESC`, articleId)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	previews := []model.ArticlePreview{}
+	for rows.Next() {
+		preview, err := scanArticlePreview(rows)
+		if err != nil {
+			return nil, err
+		}
+		previews = append(previews, preview)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return previews, nil
+}
+
+// RevokePreview implements ArticlePreviewRepository.
+// Revoking twice keeps the time of the first revocation.
+func (r *articlePreviewRepository) RevokePreview(ctx context.Context, articleId, previewId uuid.UUID) (model.ArticlePreview, error) {
+	preview, err := scanArticlePreview(r.db.QueryRowContext(ctx, `
+	UPDATE article_previews SET revoked_at = COALESCE(revoked_at, NOW())
+	WHERE id = $1 AND article_id = $2
+	RETURNING `+articlePreviewColumns, previewId, articleId))
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return model.ArticlePreview{}, ctx.Err()
+		}
+		return model.ArticlePreview{}, err
+	}
+
+	return preview, nil
+}
+
+// ConsumePreview implements ArticlePreviewRepository.
+// The checks and the count happen in one statement so concurrent readers cannot go
+// past the view limit.
+func (r *articlePreviewRepository) ConsumePreview(ctx context.Context, articleId, previewId uuid.UUID) (model.ArticlePreview, error) {
+	preview, err := scanArticlePreview(r.db.QueryRowContext(ctx, `
+	UPDATE article_previews SET view_count = view_count + 1
+	WHERE id = $1 AND article_id = $2
+		AND revoked_at IS NULL
+		AND expires_at > NOW()
+		AND (max_views IS NULL OR view_count < max_views)
+	RETURNING `+articlePreviewColumns, previewId, articleId))
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return model.ArticlePreview{}, ctx.Err()
+		}
+		return model.ArticlePreview{}, err
+	}
+
+	return preview, nil
+}
+
+func NewArticlePreviewRepository(database *sql.DB) ArticlePreviewRepository {
+	return &articlePreviewRepository{db: database}
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"develapar-server/model"
 	"develapar-server/model/dto"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,33 +16,154 @@ type ArticleRepository interface {
 	GetAll(ctx context.Context) ([]dto.ArticleResponse, error)
 	GetAllWithPagination(ctx context.Context, offset, limit int) ([]model.Article, int, error)
 	CreateArticle(ctx context.Context, payload model.Article) (model.Article, error)
-	UpdateArticle(ctx context.Context, article model.Article) (model.Article, error)
+	UpdateArticle(ctx context.Context, article model.Article, editorId uuid.UUID) (model.Article, error)
 	GetArticleById(ctx context.Context, id uuid.UUID) (model.Article, error)
-	GetArticleByUserId(ctx context.Context, userId uuid.UUID) ([]model.Article, error)
-	GetArticleByUserIdWithPagination(ctx context.Context, userId uuid.UUID, offset, limit int) ([]model.Article, int, error)
+	// GetArticlePasswordHash returns the stored password hash of an article, empty when it
+	// has none, or sql.ErrNoRows when the article is missing or in the trash
+	GetArticlePasswordHash(ctx context.Context, id uuid.UUID) (string, error)
+	// GetArticleByUserId lists the public articles credited to the user, includeHidden
+	// adds drafts, scheduled articles and the unlisted, private and password protected ones
+	GetArticleByUserId(ctx context.Context, userId uuid.UUID, includeHidden bool) ([]model.Article, error)
+	// GetArticleByUserIdWithPagination is GetArticleByUserId one page at a time
+	GetArticleByUserIdWithPagination(ctx context.Context, userId uuid.UUID, includeHidden bool, offset, limit int) ([]model.Article, int, error)
 	GetArticleBySlug(ctx context.Context, slug string) (model.Article, error)
+	// GetSlugRedirect returns the current slug of the article that used the old slug,
+	// or sql.ErrNoRows when the slug never belonged to an article
+	GetSlugRedirect(ctx context.Context, oldSlug string) (string, error)
 	GetArticleByCategory(ctx context.Context, cat string) ([]model.Article, error)
 	GetArticleByCategoryWithPagination(ctx context.Context, cat string, offset, limit int) ([]model.Article, int, error)
+	// DeleteArticle moves the article to the trash. Its tags, comments, likes and
+	// bookmarks are kept and come back when it is restored.
 	DeleteArticle(ctx context.Context, id uuid.UUID) error
+	Search(ctx context.Context, filter dto.ArticleSearchFilter, offset, limit int) ([]dto.ArticleSearchResult, int, error)
+	ApplySchedule(ctx context.Context) (published []uuid.UUID, unpublished []uuid.UUID, err error)
+	TransitionStatus(ctx context.Context, articleId uuid.UUID, from, to string, publishAt *time.Time, actorId uuid.UUID, reason string) (model.Article, error)
+	GetStatusTransitions(ctx context.Context, articleId uuid.UUID) ([]model.ArticleStatusTransition, error)
+	GetFeedArticles(ctx context.Context, filter dto.ArticleFeedFilter, limit int) ([]model.Article, error)
+	GetPublicationStamp(ctx context.Context) (time.Time, int, error)
+	// GetUnrenderedArticles returns up to limit articles ordered by id after the given id
+	// whose Markdown was never rendered, the ones written before rendering was introduced
+	GetUnrenderedArticles(ctx context.Context, after uuid.UUID, limit int) ([]model.Article, error)
+	// SaveRenderedContent stores the rendered output of an article without touching its
+	// version or updated_at. It is a no-op when the content changed in the meantime.
+	SaveRenderedContent(ctx context.Context, article model.Article) error
 }
 
-type articleRepository struct {
-	db *sql.DB
-}
+const (
+	// articleColumns is the column list of a single articles row, scanned by scanArticle
+	articleColumns = `id, title, slug, slug_pinned, content, content_html, toc, excerpt, reading_time_minutes, content_hash,
+		user_id, category_id, views, comment_count, status, visibility, password_hash, publish_at, unpublish_at, version, created_at, updated_at`
 
-// GetArticleByCategory implements ArticleRepository.
-func (a *articleRepository) GetArticleByCategory(ctx context.Context, cat string) ([]model.Article, error) {
-	query := `
-	SELECT 
-		a.id, a.title, a.slug, a.content, a.user_id, a.category_id, a.views, a.status, a.created_at, a.updated_at,
+	// articleWithRelationsColumns adds author and category, scanned by scanArticleWithRelations
+	articleWithRelationsColumns = `
+		a.id, a.title, a.slug, a.slug_pinned, a.content, a.content_html, a.toc, a.excerpt, a.reading_time_minutes, a.content_hash,
+		a.user_id, a.category_id, a.views, a.comment_count, a.status, a.visibility, a.password_hash, a.publish_at, a.unpublish_at, a.version, a.created_at, a.updated_at,
 		u.id, u.name, u.email, u.role,
-		c.id, c.name
+		c.id, c.name`
+
+	articleWithRelationsJoins = `
 	FROM articles a
 	JOIN users u ON a.user_id = u.id
-	JOIN categories c ON a.category_id = c.id
-	WHERE c.name = $1;
-	`
-	rows, err := a.db.QueryContext(ctx, query, cat)
+	JOIN categories c ON a.category_id = c.id`
+
+	// publishedArticleCondition limits a query on alias "a" to published articles that are
+	// neither scheduled for the future nor in the trash, whatever their visibility
+	publishedArticleCondition = `a.deleted_at IS NULL AND a.status = 'published' AND (a.publish_at IS NULL OR a.publish_at <= NOW())`
+
+	// publicArticleCondition limits a query on alias "a" to the articles listed publicly:
+	// published, not scheduled for the future and with public visibility. Unlisted, private
+	// and password protected articles never show up in listings, feeds or search.
+	publicArticleCondition = publishedArticleCondition + ` AND a.visibility = 'public'`
+
+	// ownArticleCondition limits a query on alias "a" to the articles not in the trash,
+	// whatever their status and visibility. It is for listings shown to authors and staff.
+	ownArticleCondition = `a.deleted_at IS NULL`
+
+	// SearchHighlightStart and SearchHighlightStop delimit the matches in a search
+	// snippet. They are control characters that never appear in article content, so
+	// the snippet can be HTML escaped as plain text before they are turned into markup.
+	SearchHighlightStart = "\x02"
+	SearchHighlightStop  = "\x03"
+
+	// scheduleLockKey is the advisory lock id guarding the publish scheduler across instances
+	scheduleLockKey = 727001
+)
+
+type articleRepository struct {
+	db *sql.DB
+}
+
+// scanArticle scans a row selected with articleColumns
+func scanArticle(row rowScanner) (model.Article, error) {
+	var article model.Article
+	var toc []byte
+	err := row.Scan(
+		&article.Id, &article.Title, &article.Slug, &article.SlugPinned, &article.Content,
+		&article.ContentHTML, &toc, &article.Excerpt, &article.ReadingTimeMinutes, &article.ContentHash,
+		&article.UserId, &article.CategoryId, &article.Views, &article.CommentCount, &article.Status,
+		&article.Visibility, &article.PasswordHash, &article.PublishAt, &article.UnpublishAt,
+		&article.Version, &article.CreatedAt, &article.UpdatedAt,
+	)
+	if err != nil {
+		return model.Article{}, err
+	}
+
+	article.TOC, err = decodeTOC(toc)
+	return article, err
+}
+
+// encodeTOC converts a table of contents to the JSONB value of the toc column
+func encodeTOC(toc []model.TOCEntry) ([]byte, error) {
+	if toc == nil {
+		toc = []model.TOCEntry{}
+	}
+	return json.Marshal(toc)
+}
+
+// decodeTOC parses the toc column, an empty value is an empty table of contents
+func decodeTOC(raw []byte) ([]model.TOCEntry, error) {
+	toc := []model.TOCEntry{}
+	if len(raw) == 0 {
+		return toc, nil
+	}
+	if err := json.Unmarshal(raw, &toc); err != nil {
+		return nil, fmt.Errorf("failed to decode article toc: %v", err)
+	}
+	return toc, nil
+}
+
+// scanArticleWithRelations scans a row selected with articleWithRelationsColumns
+func scanArticleWithRelations(row rowScanner) (model.Article, error) {
+	var article model.Article
+	var user model.User
+	var category model.Category
+	var toc []byte
+
+	err := row.Scan(
+		&article.Id, &article.Title, &article.Slug, &article.SlugPinned, &article.Content,
+		&article.ContentHTML, &toc, &article.Excerpt, &article.ReadingTimeMinutes, &article.ContentHash,
+		&article.UserId, &article.CategoryId, &article.Views, &article.CommentCount, &article.Status,
+		&article.Visibility, &article.PasswordHash, &article.PublishAt, &article.UnpublishAt,
+		&article.Version, &article.CreatedAt, &article.UpdatedAt,
+		&user.Id, &user.Name, &user.Email, &user.Role,
+		&category.Id, &category.Name,
+	)
+	if err != nil {
+		return model.Article{}, err
+	}
+
+	if article.TOC, err = decodeTOC(toc); err != nil {
+		return model.Article{}, err
+	}
+
+	article.User = &user
+	article.Category = &category
+	return article, nil
+}
+
+// queryArticlesWithRelations runs a query selecting articleWithRelationsColumns and collects the rows
+func (a *articleRepository) queryArticlesWithRelations(ctx context.Context, query string, args ...interface{}) ([]model.Article, error) {
+	rows, err := a.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		// Check if context was cancelled or timed out
 		if ctx.Err() != nil {
@@ -51,7 +174,6 @@ func (a *articleRepository) GetArticleByCategory(ctx context.Context, cat string
 	defer rows.Close()
 
 	var articles []model.Article
-
 	for rows.Next() {
 		// Check for context cancellation during iteration
 		select {
@@ -60,55 +182,46 @@ func (a *articleRepository) GetArticleByCategory(ctx context.Context, cat string
 		default:
 		}
 
-		var article model.Article
-		var user model.User
-		var category model.Category
-
-		err := rows.Scan(
-			&article.Id, &article.Title, &article.Slug, &article.Content,
-			&article.UserId, &article.CategoryId, &article.Views, &article.Status,
-			&article.CreatedAt, &article.UpdatedAt,
-			&user.Id, &user.Name, &user.Email, &user.Role,
-			&category.Id, &category.Name,
-		)
+		article, err := scanArticleWithRelations(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		article.User = &user
-		article.Category = &category
 		articles = append(articles, article)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return articles, nil
 }
 
-// GetArticleBySlug implements ArticleRepository.
-func (a *articleRepository) GetArticleBySlug(ctx context.Context, slug string) (model.Article, error) {
-	query := `
-	SELECT 
-		a.id, a.title, a.slug, a.content, a.user_id, a.category_id, a.views, a.status, a.created_at, a.updated_at,
-		u.id, u.name, u.email, u.role,
-		c.id, c.name
-	FROM articles a
-	JOIN users u ON a.user_id = u.id
-	JOIN categories c ON a.category_id = c.id
-	WHERE a.slug = $1;
-	`
+// countArticles runs a COUNT(*) query
+func (a *articleRepository) countArticles(ctx context.Context, query string, args ...interface{}) (int, error) {
+	var totalCount int
+	err := a.db.QueryRowContext(ctx, query, args...).Scan(&totalCount)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, err
+	}
+	return totalCount, nil
+}
 
-	row := a.db.QueryRowContext(ctx, query, slug)
+// GetArticleByCategory implements ArticleRepository.
+func (a *articleRepository) GetArticleByCategory(ctx context.Context, cat string) ([]model.Article, error) {
+	query := `SELECT ` + articleWithRelationsColumns + articleWithRelationsJoins + `
+	WHERE c.name = $1 AND ` + publicArticleCondition + `;`
+	return a.queryArticlesWithRelations(ctx, query, cat)
+}
 
-	var article model.Article
-	var user model.User
-	var category model.Category
+// GetArticleBySlug implements ArticleRepository.
+func (a *articleRepository) GetArticleBySlug(ctx context.Context, slug string) (model.Article, error) {
+	query := `SELECT ` + articleWithRelationsColumns + articleWithRelationsJoins + `
+	WHERE a.slug = $1 AND a.deleted_at IS NULL;`
 
-	err := row.Scan(
-		&article.Id, &article.Title, &article.Slug, &article.Content,
-		&article.UserId, &article.CategoryId, &article.Views, &article.Status,
-		&article.CreatedAt, &article.UpdatedAt,
-		&user.Id, &user.Name, &user.Email, &user.Role,
-		&category.Id, &category.Name,
-	)
+	article, err := scanArticleWithRelations(a.db.QueryRowContext(ctx, query, slug))
 	if err != nil {
 		// Check if context was cancelled or timed out
 		if ctx.Err() != nil {
@@ -117,14 +230,47 @@ func (a *articleRepository) GetArticleBySlug(ctx context.Context, slug string) (
 		return model.Article{}, err
 	}
 
-	article.User = &user
-	article.Category = &category
 	return article, nil
 }
 
+// GetSlugRedirect implements ArticleRepository.
+func (a *articleRepository) GetSlugRedirect(ctx context.Context, oldSlug string) (string, error) {
+	var slug string
+	err := a.db.QueryRowContext(ctx, `
+	SELECT a.slug
+	FROM article_slug_history h
+	JOIN articles a ON a.id = h.article_id
+	WHERE h.slug = $1 AND a.deleted_at IS NULL`, oldSlug).Scan(&slug)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", err
+	}
+
+	return slug, nil
+}
+
+// recordSlugChange keeps the previous slug of an article in the slug history. A slug
+// taken again by an article is live and no longer redirects, and an old slug that
+// another article used before now redirects to the article that used it last.
+func recordSlugChange(ctx context.Context, tx *sql.Tx, articleId uuid.UUID, previousSlug, newSlug string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM article_slug_history WHERE slug = $1`, newSlug); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+	INSERT INTO article_slug_history (slug, article_id, created_at)
+	VALUES ($1, $2, NOW())
+	ON CONFLICT (slug) DO UPDATE SET article_id = EXCLUDED.article_id, created_at = EXCLUDED.created_at`,
+		previousSlug, articleId)
+	return err
+}
+
 // DeleteArticle implements ArticleRepository.
 func (a *articleRepository) DeleteArticle(ctx context.Context, id uuid.UUID) error {
-	_, err := a.db.ExecContext(ctx, `DELETE FROM articles WHERE id = $1`, id)
+	_, err := a.db.ExecContext(ctx, `UPDATE articles SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id)
 	if err != nil {
 		// Check if context was cancelled or timed out
 		if ctx.Err() != nil {
@@ -136,18 +282,67 @@ func (a *articleRepository) DeleteArticle(ctx context.Context, id uuid.UUID) err
 	return nil
 }
 
+// GetUnrenderedArticles implements ArticleRepository.
+func (a *articleRepository) GetUnrenderedArticles(ctx context.Context, after uuid.UUID, limit int) ([]model.Article, error) {
+	rows, err := a.db.QueryContext(ctx, `
+	SELECT `+articleColumns+`
+	FROM articles
+	WHERE content_hash = '' AND id > $1
+	ORDER BY id
+	LIMIT $2`, after, limit)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	var articles []model.Article
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, article)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// SaveRenderedContent implements ArticleRepository.
+func (a *articleRepository) SaveRenderedContent(ctx context.Context, article model.Article) error {
+	toc, err := encodeTOC(article.TOC)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.ExecContext(ctx, `
+	UPDATE articles
+	SET content_html = $1, toc = $2, excerpt = $3, reading_time_minutes = $4, content_hash = $5
+	WHERE id = $6 AND content = $7`,
+		article.ContentHTML, toc, article.Excerpt, article.ReadingTimeMinutes, article.ContentHash,
+		article.Id, article.Content)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
 // GetArticleById implements ArticleRepository.
 func (a *articleRepository) GetArticleById(ctx context.Context, id uuid.UUID) (model.Article, error) {
-	query := `
-		SELECT id, title, slug, content, views, user_id, category_id, status, created_at, updated_at
-		FROM articles
-		WHERE id = $1
-	`
-	var arc model.Article
-	err := a.db.QueryRowContext(ctx, query, id).Scan(
-		&arc.Id, &arc.Title, &arc.Slug, &arc.Content, &arc.Views,
-		&arc.UserId, &arc.CategoryId, &arc.Status, &arc.CreatedAt, &arc.UpdatedAt,
-	)
+	query := `SELECT ` + articleColumns + ` FROM articles WHERE id = $1 AND deleted_at IS NULL`
+
+	arc, err := scanArticle(a.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		// Check if context was cancelled or timed out
 		if ctx.Err() != nil {
@@ -158,85 +353,97 @@ func (a *articleRepository) GetArticleById(ctx context.Context, id uuid.UUID) (m
 	return arc, nil
 }
 
-// GetArticleByUserId implements ArticleRepository.
-func (a *articleRepository) GetArticleByUserId(ctx context.Context, userId uuid.UUID) ([]model.Article, error) {
-	query := `
-	SELECT 
-		a.id, a.title, a.slug, a.content, a.user_id, a.category_id, a.views, a.status, a.created_at, a.updated_at,
-		u.id, u.name, u.email, u.role,
-		c.id, c.name
-	FROM articles a
-	JOIN users u ON a.user_id = u.id
-	JOIN categories c ON a.category_id = c.id
-	WHERE a.user_id = $1
-	ORDER BY a.created_at DESC;
-	`
-
-	rows, err := a.db.QueryContext(ctx, query, userId)
+// GetArticlePasswordHash implements ArticleRepository.
+func (a *articleRepository) GetArticlePasswordHash(ctx context.Context, id uuid.UUID) (string, error) {
+	var hash sql.NullString
+	err := a.db.QueryRowContext(ctx, `SELECT password_hash FROM articles WHERE id = $1 AND deleted_at IS NULL`, id).Scan(&hash)
 	if err != nil {
 		// Check if context was cancelled or timed out
 		if ctx.Err() != nil {
-			return nil, ctx.Err()
+			return "", ctx.Err()
 		}
-		return nil, err
+		return "", err
 	}
-	defer rows.Close()
+	return hash.String, nil
+}
 
-	var articles []model.Article
+// GetArticleByUserId implements ArticleRepository.
+func (a *articleRepository) GetArticleByUserId(ctx context.Context, userId uuid.UUID, includeHidden bool) ([]model.Article, error) {
+	query := `SELECT ` + articleWithRelationsColumns + articleWithRelationsJoins + `
+	WHERE ` + bylineCondition("$1") + ` AND ` + authorListingCondition(includeHidden) + `
+	ORDER BY a.created_at DESC;`
+	return a.queryArticlesWithRelations(ctx, query, userId)
+}
 
-	for rows.Next() {
-		// Check for context cancellation during iteration
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
+// UpdateArticle implements ArticleRepository.
+// The update and the snapshot of the new state are written in one transaction,
+// so every successful update has exactly one matching revision. The update only
+// applies while article.Version is the stored version, sql.ErrNoRows is returned otherwise.
+func (a *articleRepository) UpdateArticle(ctx context.Context, article model.Article, editorId uuid.UUID) (model.Article, error) {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
 		}
+		return model.Article{}, err
+	}
+	defer tx.Rollback()
 
-		var article model.Article
-		var user model.User
-		var category model.Category
+	// Lock the article row so concurrent updates get sequential revision numbers,
+	// an article changed since article.Version was read is not found
+	var previousSlug string
+	err = tx.QueryRowContext(ctx, `SELECT slug FROM articles WHERE id = $1 AND version = $2 AND deleted_at IS NULL FOR UPDATE`, article.Id, article.Version).Scan(&previousSlug)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		return model.Article{}, err
+	}
 
-		err := rows.Scan(
-			&article.Id, &article.Title, &article.Slug, &article.Content,
-			&article.UserId, &article.CategoryId, &article.Views, &article.Status,
-			&article.CreatedAt, &article.UpdatedAt,
-			&user.Id, &user.Name, &user.Email, &user.Role,
-			&category.Id, &category.Name,
-		)
-		if err != nil {
-			return nil, err
+	// Links shared under the previous slug keep working through a redirect
+	if previousSlug != article.Slug {
+		if err := recordSlugChange(ctx, tx, article.Id, previousSlug, article.Slug); err != nil {
+			if ctx.Err() != nil {
+				return model.Article{}, ctx.Err()
+			}
+			return model.Article{}, err
 		}
+	}
 
-		article.User = &user
-		article.Category = &category
-		articles = append(articles, article)
+	// Articles created before revision tracking have no history yet,
+	// keep their current state as the first revision before overwriting it
+	_, err = tx.ExecContext(ctx, `
+	INSERT INTO article_revisions (article_id, revision_number, title, slug, content, category_id, status, editor_id, created_at)
+	SELECT a.id, 1, a.title, a.slug, a.content, a.category_id, a.status, NULL, a.updated_at
+	FROM articles a
+	WHERE a.id = $1 AND NOT EXISTS (SELECT 1 FROM article_revisions r WHERE r.article_id = a.id)
+	`, article.Id)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		return model.Article{}, err
 	}
 
-	return articles, nil
-}
+	toc, err := encodeTOC(article.TOC)
+	if err != nil {
+		return model.Article{}, err
+	}
 
-// UpdateArticle implements ArticleRepository.
-func (a *articleRepository) UpdateArticle(ctx context.Context, article model.Article) (model.Article, error) {
 	query := `
 	UPDATE articles
-	SET title = $1, slug = $2, content = $3, category_id = $4, status = $5, updated_at = NOW()
-	WHERE id = $6
- 	RETURNING id, title, slug, content, user_id, category_id, views, status, created_at, updated_at
-	`
-	row := a.db.QueryRowContext(ctx, query, article.Title, article.Slug, article.Content, article.CategoryId, article.Status, article.Id)
-	var updated model.Article
-	err := row.Scan(
-		&updated.Id,
-		&updated.Title,
-		&updated.Slug,
-		&updated.Content,
-		&updated.UserId,
-		&updated.CategoryId,
-		&updated.Views,
-		&updated.Status,
-		&updated.CreatedAt,
-		&updated.UpdatedAt,
-	)
+	SET title = $1, slug = $2, content = $3, category_id = $4, status = $5, publish_at = $6, unpublish_at = $7,
+		content_html = $8, toc = $9, excerpt = $10, reading_time_minutes = $11, content_hash = $12, slug_pinned = $13,
+		visibility = $14, password_hash = $15, version = version + 1, updated_at = NOW()
+	WHERE id = $16 AND version = $17
+	RETURNING ` + articleColumns
+	updated, err := scanArticle(tx.QueryRowContext(ctx, query,
+		article.Title, article.Slug, article.Content, article.CategoryId, article.Status,
+		article.PublishAt, article.UnpublishAt,
+		article.ContentHTML, toc, article.Excerpt, article.ReadingTimeMinutes, article.ContentHash,
+		article.SlugPinned, article.Visibility, article.PasswordHash, article.Id, article.Version,
+	))
 	if err != nil {
 		// Check if context was cancelled or timed out
 		if ctx.Err() != nil {
@@ -245,6 +452,17 @@ func (a *articleRepository) UpdateArticle(ctx context.Context, article model.Art
 		return model.Article{}, err
 	}
 
+	if err := insertArticleRevision(ctx, tx, updated.Id, uuid.NullUUID{UUID: editorId, Valid: editorId != uuid.Nil}); err != nil {
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		return model.Article{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Article{}, err
+	}
+
 	return updated, nil
 }
 
@@ -252,7 +470,8 @@ func (a *articleRepository) UpdateArticle(ctx context.Context, article model.Art
 func (a *articleRepository) GetAll(ctx context.Context) ([]dto.ArticleResponse, error) {
 	query := `
     SELECT 
-        a.id, a.title, a.slug, a.content, a.user_id, a.category_id, a.views, a.status, a.created_at, a.updated_at,
+        a.id, a.title, a.slug, a.content, a.content_html, a.toc, a.excerpt, a.reading_time_minutes,
+        a.user_id, a.category_id, a.views, a.comment_count, a.status, a.publish_at, a.unpublish_at, a.created_at, a.updated_at,
         u.id, u.name, u.email, u.role,
         c.id, c.name,
         t.id, t.name
@@ -261,6 +480,7 @@ func (a *articleRepository) GetAll(ctx context.Context) ([]dto.ArticleResponse,
     JOIN categories c ON a.category_id = c.id
     LEFT JOIN article_tags at ON a.id = at.article_id
     LEFT JOIN tags t ON at.tag_id = t.id
+    WHERE ` + publicArticleCondition + `
     ORDER BY a.created_at DESC, a.id;
     `
 
@@ -280,10 +500,13 @@ func (a *articleRepository) GetAll(ctx context.Context) ([]dto.ArticleResponse,
 
 		var tagID sql.NullString
 		var tagName sql.NullString
+		var toc []byte
 
 		err := rows.Scan(
 			&article.Id, &article.Title, &article.Slug, &article.Content,
-			&article.UserId, &article.CategoryId, &article.Views, &article.Status,
+			&article.ContentHTML, &toc, &article.Excerpt, &article.ReadingTimeMinutes,
+			&article.UserId, &article.CategoryId, &article.Views, &article.CommentCount, &article.Status,
+			&article.PublishAt, &article.UnpublishAt,
 			&article.CreatedAt, &article.UpdatedAt,
 			&user.Id, &user.Name, &user.Email, &user.Role,
 			&category.Id, &category.Name,
@@ -299,6 +522,9 @@ func (a *articleRepository) GetAll(ctx context.Context) ([]dto.ArticleResponse,
 			article.User = &user
 			article.Category = &category
 			article.Tags = []model.Tags{} // Inisialisasi slice tags
+			if article.TOC, err = decodeTOC(toc); err != nil {
+				return nil, err
+			}
 
 			articles = append(articles, article)
 			articleMap[article.Id] = &articles[len(articles)-1] // Simpan pointer-nya di map
@@ -333,33 +559,44 @@ func (a *articleRepository) GetAll(ctx context.Context) ([]dto.ArticleResponse,
 
 // CreateArticle implements ArticleRepository.
 func (a *articleRepository) CreateArticle(ctx context.Context, payload model.Article) (model.Article, error) {
-	var arc model.Article
-	err := a.db.QueryRowContext(ctx, `
-  INSERT INTO articles (id, title, content, slug, user_id, category_id, status, created_at, updated_at) 
-  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
-  RETURNING id, title, slug, content, user_id, category_id, views, status, created_at, updated_at
-`,
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		return model.Article{}, err
+	}
+	defer tx.Rollback()
+
+	toc, err := encodeTOC(payload.TOC)
+	if err != nil {
+		return model.Article{}, err
+	}
+
+	arc, err := scanArticle(tx.QueryRowContext(ctx, `
+  INSERT INTO articles (id, title, content, content_html, toc, excerpt, reading_time_minutes, content_hash, slug, user_id, category_id, status, publish_at, unpublish_at, created_at, updated_at, visibility, password_hash) 
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18) 
+  RETURNING `+articleColumns,
 		payload.Id,
 		payload.Title,
 		payload.Content,
+		payload.ContentHTML,
+		toc,
+		payload.Excerpt,
+		payload.ReadingTimeMinutes,
+		payload.ContentHash,
 		payload.Slug,
 		payload.User.Id,
 		payload.Category.Id,
 		payload.Status,
+		payload.PublishAt,
+		payload.UnpublishAt,
 		time.Now(),
 		time.Now(),
-	).Scan(
-		&arc.Id,
-		&arc.Title,
-		&arc.Slug,
-		&arc.Content,
-		&arc.UserId,
-		&arc.CategoryId,
-		&arc.Views,
-		&arc.Status,
-		&arc.CreatedAt,
-		&arc.UpdatedAt,
-	)
+		payload.Visibility,
+		payload.PasswordHash,
+	))
 
 	if err != nil {
 		// Check if context was cancelled or timed out
@@ -368,158 +605,334 @@ func (a *articleRepository) CreateArticle(ctx context.Context, payload model.Art
 		}
 		return model.Article{}, err
 	}
+
+	// The creating user is credited as the author of the article
+	if _, err := tx.ExecContext(ctx, `
+	INSERT INTO article_authors (article_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)`,
+		arc.Id, arc.UserId, model.ArticleAuthorRoleAuthor, arc.CreatedAt); err != nil {
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		return model.Article{}, err
+	}
+
+	// The initial content is the first revision, authored by the article owner
+	if err := insertArticleRevision(ctx, tx, arc.Id, uuid.NullUUID{UUID: arc.UserId, Valid: true}); err != nil {
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		return model.Article{}, err
+	}
+
+	// An article created straight into review went through draft -> in_review,
+	// record it like the submit action would
+	if arc.Status != model.ArticleStatusDraft {
+		if err := insertStatusTransition(ctx, tx, arc.Id, model.ArticleStatusDraft, arc.Status, arc.UserId, ""); err != nil {
+			if ctx.Err() != nil {
+				return model.Article{}, ctx.Err()
+			}
+			return model.Article{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Article{}, err
+	}
 	return arc, nil
 }
 
 // GetAllWithPagination implements ArticleRepository.
 func (a *articleRepository) GetAllWithPagination(ctx context.Context, offset, limit int) ([]model.Article, int, error) {
 	// First get the total count
-	var totalCount int
-	countQuery := `SELECT COUNT(*) FROM articles`
-	err := a.db.QueryRowContext(ctx, countQuery).Scan(&totalCount)
+	totalCount, err := a.countArticles(ctx, `SELECT COUNT(*) FROM articles a WHERE `+publicArticleCondition)
 	if err != nil {
-		if ctx.Err() != nil {
-			return nil, 0, ctx.Err()
-		}
 		return nil, 0, err
 	}
 
 	// Then get the paginated results
-	query := `
-	SELECT 
-		a.id, a.title, a.slug, a.content, a.user_id, a.category_id, a.views, a.status, a.created_at, a.updated_at,
-		u.id, u.name, u.email, u.role,
-		c.id, c.name
-	FROM articles a
-	JOIN users u ON a.user_id = u.id
-	JOIN categories c ON a.category_id = c.id
+	query := `SELECT ` + articleWithRelationsColumns + articleWithRelationsJoins + `
+	WHERE ` + publicArticleCondition + `
 	ORDER BY a.created_at DESC
-	LIMIT $1 OFFSET $2;
-	`
+	LIMIT $1 OFFSET $2;`
 
-	rows, err := a.db.QueryContext(ctx, query, limit, offset)
+	articles, err := a.queryArticlesWithRelations(ctx, query, limit, offset)
 	if err != nil {
-		if ctx.Err() != nil {
-			return nil, 0, ctx.Err()
-		}
 		return nil, 0, err
 	}
-	defer rows.Close()
 
-	var articles []model.Article
-	for rows.Next() {
-		// Check for context cancellation during iteration
-		select {
-		case <-ctx.Done():
-			return nil, 0, ctx.Err()
-		default:
-		}
-
-		var article model.Article
-		var user model.User
-		var category model.Category
+	return articles, totalCount, nil
+}
 
-		err := rows.Scan(
-			&article.Id, &article.Title, &article.Slug, &article.Content,
-			&article.UserId, &article.CategoryId, &article.Views, &article.Status,
-			&article.CreatedAt, &article.UpdatedAt,
-			&user.Id, &user.Name, &user.Email, &user.Role,
-			&category.Id, &category.Name,
-		)
-		if err != nil {
-			return nil, 0, err
-		}
+// GetArticleByUserIdWithPagination implements ArticleRepository.
+func (a *articleRepository) GetArticleByUserIdWithPagination(ctx context.Context, userId uuid.UUID, includeHidden bool, offset, limit int) ([]model.Article, int, error) {
+	visible := authorListingCondition(includeHidden)
 
-		article.User = &user
-		article.Category = &category
-		articles = append(articles, article)
+	// First get the total count for this user
+	totalCount, err := a.countArticles(ctx, `SELECT COUNT(*) FROM articles a WHERE `+bylineCondition("$1")+` AND `+visible, userId)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if err := rows.Err(); err != nil {
+	// Then get the paginated results
+	query := `SELECT ` + articleWithRelationsColumns + articleWithRelationsJoins + `
+	WHERE ` + bylineCondition("$1") + ` AND ` + visible + `
+	ORDER BY a.created_at DESC
+	LIMIT $2 OFFSET $3;`
+
+	articles, err := a.queryArticlesWithRelations(ctx, query, userId, limit, offset)
+	if err != nil {
 		return nil, 0, err
 	}
 
 	return articles, totalCount, nil
 }
 
-// GetArticleByUserIdWithPagination implements ArticleRepository.
-func (a *articleRepository) GetArticleByUserIdWithPagination(ctx context.Context, userId uuid.UUID, offset, limit int) ([]model.Article, int, error) {
-	// First get the total count for this user
-	var totalCount int
-	countQuery := `SELECT COUNT(*) FROM articles WHERE user_id = $1`
-	err := a.db.QueryRowContext(ctx, countQuery, userId).Scan(&totalCount)
+// authorListingCondition picks the condition of an author listing: every article of the
+// author for the author and staff, only the public ones for everybody else
+func authorListingCondition(includeHidden bool) string {
+	if includeHidden {
+		return ownArticleCondition
+	}
+	return publicArticleCondition
+}
+
+// GetArticleByCategoryWithPagination implements ArticleRepository.
+func (a *articleRepository) GetArticleByCategoryWithPagination(ctx context.Context, cat string, offset, limit int) ([]model.Article, int, error) {
+	// First get the total count for this category
+	totalCount, err := a.countArticles(ctx, `SELECT COUNT(*) FROM articles a JOIN categories c ON a.category_id = c.id WHERE c.name = $1 AND `+publicArticleCondition, cat)
 	if err != nil {
-		if ctx.Err() != nil {
-			return nil, 0, ctx.Err()
-		}
 		return nil, 0, err
 	}
 
 	// Then get the paginated results
-	query := `
-	SELECT 
-		a.id, a.title, a.slug, a.content, a.user_id, a.category_id, a.views, a.status, a.created_at, a.updated_at,
-		u.id, u.name, u.email, u.role,
-		c.id, c.name
-	FROM articles a
-	JOIN users u ON a.user_id = u.id
-	JOIN categories c ON a.category_id = c.id
-	WHERE a.user_id = $1
+	query := `SELECT ` + articleWithRelationsColumns + articleWithRelationsJoins + `
+	WHERE c.name = $1 AND ` + publicArticleCondition + `
 	ORDER BY a.created_at DESC
-	LIMIT $2 OFFSET $3;
-	`
+	LIMIT $2 OFFSET $3;`
 
-	rows, err := a.db.QueryContext(ctx, query, userId, limit, offset)
+	articles, err := a.queryArticlesWithRelations(ctx, query, cat, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return articles, totalCount, nil
+}
+
+// ApplySchedule implements ArticleRepository.
+// It publishes scheduled articles whose publish_at has passed and archives published
+// articles whose unpublish_at has passed, recording each move as a status transition.
+// A transaction-scoped advisory lock makes sure only one instance applies the schedule
+// at a time; the conditional UPDATEs also make a second run a no-op, so an article is
+// never published twice.
+func (a *articleRepository) ApplySchedule(ctx context.Context) ([]uuid.UUID, []uuid.UUID, error) {
+	tx, err := a.db.BeginTx(ctx, nil)
 	if err != nil {
 		if ctx.Err() != nil {
-			return nil, 0, ctx.Err()
+			return nil, nil, ctx.Err()
 		}
-		return nil, 0, err
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var acquired bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, scheduleLockKey).Scan(&acquired); err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		return nil, nil, err
+	}
+	if !acquired {
+		// Another instance is applying the schedule right now
+		return nil, nil, nil
+	}
+
+	published, err := collectIds(ctx, tx, `
+	WITH changed AS (
+		UPDATE articles
+		SET status = 'published', version = version + 1, updated_at = NOW()
+		WHERE status = 'scheduled' AND publish_at <= NOW() AND deleted_at IS NULL
+		RETURNING id
+	)
+	INSERT INTO article_status_transitions (article_id, from_status, to_status, actor_id, reason)
+	SELECT id, 'scheduled', 'published', NULL, 'scheduled publish' FROM changed
+	RETURNING article_id`)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unpublished, err := collectIds(ctx, tx, `
+	WITH changed AS (
+		UPDATE articles
+		SET status = 'archived', unpublish_at = NULL, version = version + 1, updated_at = NOW()
+		WHERE status = 'published' AND unpublish_at <= NOW() AND deleted_at IS NULL
+		RETURNING id
+	)
+	INSERT INTO article_status_transitions (article_id, from_status, to_status, actor_id, reason)
+	SELECT id, 'published', 'archived', NULL, 'scheduled unpublish' FROM changed
+	RETURNING article_id`)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return published, unpublished, nil
+}
+
+// TransitionStatus implements ArticleRepository.
+// The status only changes when the article is still in the expected from status,
+// so concurrent transitions cannot both succeed; sql.ErrNoRows is returned otherwise.
+// A non-nil publishAt overwrites the stored publish time.
+func (a *articleRepository) TransitionStatus(ctx context.Context, articleId uuid.UUID, from, to string, publishAt *time.Time, actorId uuid.UUID, reason string) (model.Article, error) {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		return model.Article{}, err
+	}
+	defer tx.Rollback()
+
+	updated, err := scanArticle(tx.QueryRowContext(ctx, `
+	UPDATE articles
+	SET status = $1, publish_at = COALESCE($2, publish_at), version = version + 1, updated_at = NOW()
+	WHERE id = $3 AND status = $4 AND deleted_at IS NULL
+	RETURNING `+articleColumns, to, publishAt, articleId, from))
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		return model.Article{}, err
+	}
+
+	if err := insertStatusTransition(ctx, tx, articleId, from, to, actorId, reason); err != nil {
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		return model.Article{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Article{}, err
+	}
+
+	return updated, nil
+}
+
+// insertStatusTransition records a workflow transition of an article inside a transaction,
+// a nil actorId records a transition made by the system
+func insertStatusTransition(ctx context.Context, tx *sql.Tx, articleId uuid.UUID, from, to string, actorId uuid.UUID, reason string) error {
+	_, err := tx.ExecContext(ctx, `
+	INSERT INTO article_status_transitions (article_id, from_status, to_status, actor_id, reason)
+	VALUES ($1, $2, $3, $4, NULLIF($5, ''))`,
+		articleId, from, to, uuid.NullUUID{UUID: actorId, Valid: actorId != uuid.Nil}, reason)
+	return err
+}
+
+// GetStatusTransitions implements ArticleRepository.
+func (a *articleRepository) GetStatusTransitions(ctx context.Context, articleId uuid.UUID) ([]model.ArticleStatusTransition, error) {
+	rows, err := a.db.QueryContext(ctx, `
+	SELECT t.id, t.article_id, t.from_status, t.to_status, t.actor_id, COALESCE(t.reason, ''), t.created_at, u.id, u.name
+	FROM article_status_transitions t
+	LEFT JOIN users u ON t.actor_id = u.id
+	WHERE t.article_id = $1
+	ORDER BY t.created_at ASC`, articleId)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
 	}
 	defer rows.Close()
 
-	var articles []model.Article
+	transitions := []model.ArticleStatusTransition{}
 	for rows.Next() {
 		// Check for context cancellation during iteration
 		select {
 		case <-ctx.Done():
-			return nil, 0, ctx.Err()
+			return nil, ctx.Err()
 		default:
 		}
 
-		var article model.Article
-		var user model.User
-		var category model.Category
-
+		var transition model.ArticleStatusTransition
+		var actorId uuid.NullUUID
+		var actorName sql.NullString
 		err := rows.Scan(
-			&article.Id, &article.Title, &article.Slug, &article.Content,
-			&article.UserId, &article.CategoryId, &article.Views, &article.Status,
-			&article.CreatedAt, &article.UpdatedAt,
-			&user.Id, &user.Name, &user.Email, &user.Role,
-			&category.Id, &category.Name,
+			&transition.Id, &transition.ArticleId, &transition.FromStatus, &transition.ToStatus,
+			&transition.ActorId, &transition.Reason, &transition.CreatedAt,
+			&actorId, &actorName,
 		)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
-
-		article.User = &user
-		article.Category = &category
-		articles = append(articles, article)
+		if actorId.Valid {
+			transition.Actor = &model.User{Id: actorId.UUID, Name: actorName.String}
+		}
+		transitions = append(transitions, transition)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return articles, totalCount, nil
+	return transitions, nil
 }
 
-// GetArticleByCategoryWithPagination implements ArticleRepository.
-func (a *articleRepository) GetArticleByCategoryWithPagination(ctx context.Context, cat string, offset, limit int) ([]model.Article, int, error) {
-	// First get the total count for this category
+// collectIds runs a statement returning a single id column inside a transaction
+func collectIds(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Search implements ArticleRepository.
+// It runs a full-text search over published articles using the generated
+// search_vector column and returns hits ordered by rank with a snippet of the raw
+// content. The snippet is not escaped, its matches are wrapped in SearchHighlightStart
+// and SearchHighlightStop.
+func (a *articleRepository) Search(ctx context.Context, filter dto.ArticleSearchFilter, offset, limit int) ([]dto.ArticleSearchResult, int, error) {
+	// Build the shared WHERE clause, $1 is always the search query
+	args := []interface{}{filter.Query}
+	where := `a.search_vector @@ websearch_to_tsquery('simple', $1) AND ` + publicArticleCondition
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		where += fmt.Sprintf(` AND c.name = $%d`, len(args))
+	}
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		where += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM article_tags at
+			JOIN tags t ON at.tag_id = t.id
+			WHERE at.article_id = a.id AND t.name = $%d)`, len(args))
+	}
+
+	// First get the total count of matching articles
 	var totalCount int
-	countQuery := `SELECT COUNT(*) FROM articles a JOIN categories c ON a.category_id = c.id WHERE c.name = $1`
-	err := a.db.QueryRowContext(ctx, countQuery, cat).Scan(&totalCount)
+	countQuery := `
+	SELECT COUNT(*)
+	FROM articles a
+	JOIN categories c ON a.category_id = c.id
+	WHERE ` + where
+	err := a.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount)
 	if err != nil {
 		if ctx.Err() != nil {
 			return nil, 0, ctx.Err()
@@ -527,21 +940,24 @@ func (a *articleRepository) GetArticleByCategoryWithPagination(ctx context.Conte
 		return nil, 0, err
 	}
 
-	// Then get the paginated results
-	query := `
+	// Then get the ranked, paginated results
+	query := fmt.Sprintf(`
 	SELECT 
-		a.id, a.title, a.slug, a.content, a.user_id, a.category_id, a.views, a.status, a.created_at, a.updated_at,
+		a.id, a.title, a.slug, a.user_id, a.category_id, a.views, a.comment_count, a.status, a.created_at, a.updated_at,
 		u.id, u.name, u.email, u.role,
-		c.id, c.name
+		c.id, c.name,
+		ts_rank_cd(a.search_vector, websearch_to_tsquery('simple', $1)) AS rank,
+		ts_headline('simple', translate(a.content, chr(2) || chr(3), ''), websearch_to_tsquery('simple', $1),
+			'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" ... "') AS snippet
 	FROM articles a
 	JOIN users u ON a.user_id = u.id
 	JOIN categories c ON a.category_id = c.id
-	WHERE c.name = $1
-	ORDER BY a.created_at DESC
-	LIMIT $2 OFFSET $3;
-	`
+	WHERE %s
+	ORDER BY rank DESC, a.created_at DESC
+	LIMIT $%d OFFSET $%d;
+	`, where, len(args)+1, len(args)+2)
 
-	rows, err := a.db.QueryContext(ctx, query, cat, limit, offset)
+	rows, err := a.db.QueryContext(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		if ctx.Err() != nil {
 			return nil, 0, ctx.Err()
@@ -550,7 +966,7 @@ func (a *articleRepository) GetArticleByCategoryWithPagination(ctx context.Conte
 	}
 	defer rows.Close()
 
-	var articles []model.Article
+	results := []dto.ArticleSearchResult{}
 	for rows.Next() {
 		// Check for context cancellation during iteration
 		select {
@@ -559,31 +975,32 @@ func (a *articleRepository) GetArticleByCategoryWithPagination(ctx context.Conte
 		default:
 		}
 
-		var article model.Article
+		var result dto.ArticleSearchResult
 		var user model.User
 		var category model.Category
 
 		err := rows.Scan(
-			&article.Id, &article.Title, &article.Slug, &article.Content,
-			&article.UserId, &article.CategoryId, &article.Views, &article.Status,
-			&article.CreatedAt, &article.UpdatedAt,
+			&result.Id, &result.Title, &result.Slug,
+			&result.UserId, &result.CategoryId, &result.Views, &result.CommentCount, &result.Status,
+			&result.CreatedAt, &result.UpdatedAt,
 			&user.Id, &user.Name, &user.Email, &user.Role,
 			&category.Id, &category.Name,
+			&result.Rank, &result.Snippet,
 		)
 		if err != nil {
 			return nil, 0, err
 		}
 
-		article.User = &user
-		article.Category = &category
-		articles = append(articles, article)
+		result.User = &user
+		result.Category = &category
+		results = append(results, result)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, 0, err
 	}
 
-	return articles, totalCount, nil
+	return results, totalCount, nil
 }
 
 func NewArticleRepository(database *sql.DB) ArticleRepository {
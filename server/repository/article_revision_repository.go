@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+
+	"github.com/google/uuid"
+)
+
+type ArticleRevisionRepository interface {
+	GetRevisionsByArticleId(ctx context.Context, articleId uuid.UUID) ([]model.ArticleRevision, error)
+	GetRevision(ctx context.Context, articleId uuid.UUID, revisionNumber int) (model.ArticleRevision, error)
+}
+
+type articleRevisionRepository struct {
+	db *sql.DB
+}
+
+// insertArticleRevision snapshots the current row of an article as its next revision.
+// It must run inside the transaction that changed the article.
+func insertArticleRevision(ctx context.Context, tx *sql.Tx, articleId uuid.UUID, editorId uuid.NullUUID) error {
+	_, err := tx.ExecContext(ctx, `
+	INSERT INTO article_revisions (article_id, revision_number, title, slug, content, category_id, status, editor_id, created_at)
+	SELECT
+		a.id,
+		COALESCE((SELECT MAX(r.revision_number) FROM article_revisions r WHERE r.article_id = a.id), 0) + 1,
+		a.title, a.slug, a.content, a.category_id, a.status, $2, a.updated_at
+	FROM articles a
+	WHERE a.id = $1
+	`, articleId, editorId)
+	return err
+}
+
+// GetRevisionsByArticleId implements ArticleRevisionRepository.
+func (r *articleRevisionRepository) GetRevisionsByArticleId(ctx context.Context, articleId uuid.UUID) ([]model.ArticleRevision, error) {
+	query := `
+	SELECT
+		r.id, r.article_id, r.revision_number, r.title, r.slug, r.content, r.category_id, r.status, r.editor_id, r.created_at,
+		u.id, u.name
+	FROM article_revisions r
+	LEFT JOIN users u ON r.editor_id = u.id
+	WHERE r.article_id = $1
+	ORDER BY r.revision_number DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, articleId)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []model.ArticleRevision{}
+	for rows.Next() {
+		// Check for context cancellation during iteration
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		revision, err := scanArticleRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetRevision implements ArticleRevisionRepository.
+func (r *articleRevisionRepository) GetRevision(ctx context.Context, articleId uuid.UUID, revisionNumber int) (model.ArticleRevision, error) {
+	query := `
+	SELECT
+		r.id, r.article_id, r.revision_number, r.title, r.slug, r.content, r.category_id, r.status, r.editor_id, r.created_at,
+		u.id, u.name
+	FROM article_revisions r
+	LEFT JOIN users u ON r.editor_id = u.id
+	WHERE r.article_id = $1 AND r.revision_number = $2
+	`
+	revision, err := scanArticleRevision(r.db.QueryRowContext(ctx, query, articleId, revisionNumber))
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return model.ArticleRevision{}, ctx.Err()
+		}
+		return model.ArticleRevision{}, err
+	}
+
+	return revision, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanArticleRevision(row rowScanner) (model.ArticleRevision, error) {
+	var revision model.ArticleRevision
+	var editorId, editorUserId uuid.NullUUID
+	var editorName sql.NullString
+
+	err := row.Scan(
+		&revision.Id, &revision.ArticleId, &revision.RevisionNumber,
+		&revision.Title, &revision.Slug, &revision.Content,
+		&revision.CategoryId, &revision.Status, &editorId, &revision.CreatedAt,
+		&editorUserId, &editorName,
+	)
+	if err != nil {
+		return model.ArticleRevision{}, err
+	}
+
+	if editorId.Valid {
+		revision.EditorId = editorId.UUID
+	}
+	if editorUserId.Valid {
+		revision.Editor = &model.User{Id: editorUserId.UUID, Name: editorName.String}
+	}
+
+	return revision, nil
+}
+
+func NewArticleRevisionRepository(database *sql.DB) ArticleRevisionRepository {
+	return &articleRevisionRepository{db: database}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"develapar-server/model"
+	"develapar-server/model/dto"
+	"fmt"
 
 	"github.com/google/uuid"
 )
@@ -13,8 +15,22 @@ type ArticleTagRepository interface {
 	GetTagsByArticleId(ctx context.Context, articleId uuid.UUID) ([]model.Tags, error)
 	GetArticleByTagId(ctx context.Context, tagId uuid.UUID) ([]model.Article, error)
 	RemoveTagFromArticle(ctx context.Context, articleId, tagId uuid.UUID) error
+	GetRelatedArticles(ctx context.Context, articleId uuid.UUID, limit int) ([]dto.RelatedArticle, error)
 }
 
+// Weights of the related articles score. Tag overlap is the Jaccard index of both tag
+// sets, text similarity is the normalized rank of the candidate against the source title
+// and recency decays exponentially with relatedRecencyHalfLife.
+const (
+	relatedTagWeight      = 3.0
+	relatedCategoryWeight = 1.0
+	relatedTextWeight     = 2.0
+	relatedRecencyWeight  = 0.5
+
+	// relatedRecencyDecay is the age in seconds after which the recency boost dropped to 1/e (90 days)
+	relatedRecencyDecay = 90 * 24 * 60 * 60
+)
+
 type articleTagRepository struct {
 	db *sql.DB
 }
@@ -59,7 +75,7 @@ func (a *articleTagRepository) GetArticleByTagId(ctx context.Context, tagId uuid
 	JOIN users u ON a.user_id = u.id
 	JOIN categories c ON a.category_id = c.id
 	JOIN article_tags at ON at.article_id = a.id
-	WHERE at.tag_id = $1
+	WHERE at.tag_id = $1 AND ` + publicArticleCondition + `
 	ORDER BY a.created_at DESC`
 
 	rows, err := a.db.QueryContext(ctx, query, tagId)
@@ -109,6 +125,85 @@ func (a *articleTagRepository) GetTagsByArticleId(ctx context.Context, articleId
 	return tags, nil
 }
 
+// GetRelatedArticles implements ArticleTagRepository.
+// Candidates are the other public articles sharing at least a tag, the category or
+// words of the title with the source article, ordered by score. The title words are
+// matched with any of them present: the lexemes of the title are joined with "or" and
+// parsed by websearch_to_tsquery, which never fails on user text.
+func (a *articleTagRepository) GetRelatedArticles(ctx context.Context, articleId uuid.UUID, limit int) ([]dto.RelatedArticle, error) {
+	query := fmt.Sprintf(`
+	WITH source AS (
+		SELECT
+			s.id, s.category_id,
+			ARRAY(SELECT at.tag_id FROM article_tags at WHERE at.article_id = s.id) AS tag_ids,
+			websearch_to_tsquery('simple', array_to_string(tsvector_to_array(to_tsvector('simple', s.title)), ' or ')) AS title_query
+		FROM articles s
+		WHERE s.id = $1
+	),
+	scored AS (
+		SELECT
+			a.id,
+			(SELECT COUNT(*) FROM article_tags at WHERE at.article_id = a.id AND at.tag_id = ANY(src.tag_ids)) AS shared_tags,
+			(SELECT COUNT(*) FROM article_tags at WHERE at.article_id = a.id) AS tag_count,
+			cardinality(src.tag_ids) AS source_tag_count,
+			a.category_id = src.category_id AS same_category,
+			CASE WHEN numnode(src.title_query) = 0 THEN 0
+				ELSE ts_rank(a.search_vector, src.title_query, 32) END AS text_similarity,
+			EXP(-EXTRACT(EPOCH FROM (NOW() - COALESCE(a.publish_at, a.created_at))) / %d) AS recency
+		FROM articles a, source src
+		WHERE a.id <> src.id AND %s
+	),
+	candidates AS (
+		SELECT
+			id, shared_tags,
+			%f * COALESCE(shared_tags::float / NULLIF(tag_count + source_tag_count - shared_tags, 0), 0)
+			+ %f * CASE WHEN same_category THEN 1 ELSE 0 END
+			+ %f * text_similarity
+			+ %f * recency AS score
+		FROM scored
+		WHERE shared_tags > 0 OR same_category OR text_similarity > 0
+	)
+	SELECT `+articleWithRelationsColumns+`, r.shared_tags, r.score`+articleWithRelationsJoins+`
+	JOIN candidates r ON r.id = a.id
+	ORDER BY r.score DESC, a.created_at DESC
+	LIMIT $2`,
+		relatedRecencyDecay, publicArticleCondition,
+		relatedTagWeight, relatedCategoryWeight, relatedTextWeight, relatedRecencyWeight)
+
+	rows, err := a.db.QueryContext(ctx, query, articleId, limit)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := []dto.RelatedArticle{}
+	for rows.Next() {
+		// Check for context cancellation during iteration
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		var item dto.RelatedArticle
+		item.Article, err = scanArticleWithRelations(withTrailingDest(rows, &item.SharedTags, &item.Score))
+		if err != nil {
+			return nil, err
+		}
+		related = append(related, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return related, nil
+}
+
 func NewArticleTagRepository(database *sql.DB) ArticleTagRepository {
 	return &articleTagRepository{db: database}
 }
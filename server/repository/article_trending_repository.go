@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type ArticleTrendingRepository interface {
+	// GetActivity returns the interactions with every public article within period
+	GetActivity(ctx context.Context, period time.Duration) ([]model.ArticleActivity, error)
+	// ReplaceScores replaces the stored scores of a window and returns the number of stored
+	// scores. It stores nothing when another instance is replacing them at the same time.
+	ReplaceScores(ctx context.Context, window string, scores []model.ArticleTrendingScore) (int, error)
+	GetTrending(ctx context.Context, window string, offset, limit int) ([]dto.TrendingArticle, int, error)
+}
+
+// trendingLockKey is the advisory lock id guarding the trending recompute across instances
+const trendingLockKey = 727002
+
+type articleTrendingRepository struct {
+	db *sql.DB
+}
+
+// GetActivity implements ArticleTrendingRepository.
+// Articles are dated by their publish time, falling back to their creation.
+func (r *articleTrendingRepository) GetActivity(ctx context.Context, period time.Duration) ([]model.ArticleActivity, error) {
+	rows, err := r.db.QueryContext(ctx, `
+	WITH activity AS (
+		SELECT article_id, COUNT(*) AS views, 0 AS likes, 0 AS comments, 0 AS bookmarks
+		FROM article_views WHERE viewed_at >= NOW() - make_interval(secs => $1) GROUP BY article_id
+		UNION ALL
+		SELECT article_id, 0, COUNT(*), 0, 0
+		FROM likes WHERE created_at >= NOW() - make_interval(secs => $1) GROUP BY article_id
+		UNION ALL
+		SELECT article_id, 0, 0, COUNT(*), 0
+		FROM comments WHERE created_at >= NOW() - make_interval(secs => $1) AND deleted_at IS NULL AND status = 'approved' GROUP BY article_id
+		UNION ALL
+		SELECT article_id, 0, 0, 0, COUNT(*)
+		FROM bookmarks WHERE created_at >= NOW() - make_interval(secs => $1) GROUP BY article_id
+	)
+	SELECT t.article_id, COALESCE(a.publish_at, a.created_at), SUM(t.views), SUM(t.likes), SUM(t.comments), SUM(t.bookmarks)
+	FROM activity t
+	JOIN articles a ON a.id = t.article_id
+	WHERE `+publicArticleCondition+`
+	GROUP BY t.article_id, a.publish_at, a.created_at`, period.Seconds())
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	activity := []model.ArticleActivity{}
+	for rows.Next() {
+		var item model.ArticleActivity
+		if err := rows.Scan(&item.ArticleId, &item.PublishedAt, &item.Views, &item.Likes, &item.Comments, &item.Bookmarks); err != nil {
+			return nil, err
+		}
+		activity = append(activity, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return activity, nil
+}
+
+// ReplaceScores implements ArticleTrendingRepository.
+// The old scores are replaced in one transaction so readers never see a partially
+// computed ranking.
+func (r *articleTrendingRepository) ReplaceScores(ctx context.Context, window string, scores []model.ArticleTrendingScore) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var acquired bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, trendingLockKey).Scan(&acquired); err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, err
+	}
+	if !acquired {
+		// Another instance is recomputing the scores right now
+		return 0, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM article_trending_scores WHERE time_window = $1`, window); err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, err
+	}
+
+	articleIds := make([]string, len(scores))
+	values := make([]float64, len(scores))
+	views := make([]int64, len(scores))
+	likes := make([]int64, len(scores))
+	comments := make([]int64, len(scores))
+	bookmarks := make([]int64, len(scores))
+	for i, score := range scores {
+		articleIds[i] = score.ArticleId.String()
+		values[i] = score.Score
+		views[i] = int64(score.Views)
+		likes[i] = int64(score.Likes)
+		comments[i] = int64(score.Comments)
+		bookmarks[i] = int64(score.Bookmarks)
+	}
+
+	// Articles deleted since their activity was read are skipped
+	result, err := tx.ExecContext(ctx, `
+	INSERT INTO article_trending_scores (article_id, time_window, score, views, likes, comments, bookmarks, computed_at)
+	SELECT s.article_id, $1, s.score, s.views, s.likes, s.comments, s.bookmarks, NOW()
+	FROM unnest($2::uuid[], $3::float8[], $4::int[], $5::int[], $6::int[], $7::int[])
+		AS s(article_id, score, views, likes, comments, bookmarks)
+	JOIN articles a ON a.id = s.article_id`,
+		window, pq.Array(articleIds), pq.Array(values), pq.Array(views), pq.Array(likes), pq.Array(comments), pq.Array(bookmarks))
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, err
+	}
+
+	stored, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int(stored), nil
+}
+
+// GetTrending implements ArticleTrendingRepository.
+// Only the stored scores are read, articles unpublished since the last recompute are skipped.
+func (r *articleTrendingRepository) GetTrending(ctx context.Context, window string, offset, limit int) ([]dto.TrendingArticle, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `
+	SELECT COUNT(*)
+	FROM article_trending_scores s
+	JOIN articles a ON a.id = s.article_id
+	WHERE s.time_window = $1 AND `+publicArticleCondition, window).Scan(&total)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + articleWithRelationsColumns + `, s.score, s.views, s.likes, s.comments, s.bookmarks` + articleWithRelationsJoins + `
+	JOIN article_trending_scores s ON s.article_id = a.id
+	WHERE s.time_window = $1 AND ` + publicArticleCondition + `
+	ORDER BY s.score DESC, a.id
+	LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, window, limit, offset)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	trending := []dto.TrendingArticle{}
+	for rows.Next() {
+		// Check for context cancellation during iteration
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		default:
+		}
+
+		var item dto.TrendingArticle
+		item.Article, err = scanArticleWithRelations(withTrailingDest(rows, &item.Score, &item.Views, &item.Likes, &item.Comments, &item.Bookmarks))
+		if err != nil {
+			return nil, 0, err
+		}
+		trending = append(trending, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return trending, total, nil
+}
+
+func NewArticleTrendingRepository(database *sql.DB) ArticleTrendingRepository {
+	return &articleTrendingRepository{db: database}
+}
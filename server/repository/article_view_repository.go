@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type ArticleViewRepository interface {
+	RecordViews(ctx context.Context, views []model.ArticleView) error
+}
+
+type articleViewRepository struct {
+	db *sql.DB
+}
+
+// RecordViews implements ArticleViewRepository.
+// The raw views and the counter increments are written in one transaction. Views are
+// aggregated per article first, so each article row is updated once per batch; the rows
+// are updated in id order so concurrent flushes from several instances cannot deadlock.
+// Views of articles deleted in the meantime are dropped, views of users deleted in the
+// meantime are kept as anonymous views.
+func (r *articleViewRepository) RecordViews(ctx context.Context, views []model.ArticleView) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(views))
+	articleIds := make([]string, len(views))
+	userIds := make([]string, len(views))
+	ipAddresses := make([]string, len(views))
+	userAgents := make([]string, len(views))
+	referrers := make([]string, len(views))
+	viewedAts := make([]string, len(views))
+	counts := make(map[string]int64)
+
+	for i, view := range views {
+		ids[i] = view.Id.String()
+		articleIds[i] = view.ArticleId.String()
+		if view.UserId != uuid.Nil {
+			userIds[i] = view.UserId.String()
+		}
+		ipAddresses[i] = view.IPAddress
+		userAgents[i] = view.UserAgent
+		referrers[i] = view.Referrer
+		viewedAts[i] = view.ViewedAt.UTC().Format(time.RFC3339Nano)
+		counts[articleIds[i]]++
+	}
+
+	countedIds := make([]string, 0, len(counts))
+	for articleId := range counts {
+		countedIds = append(countedIds, articleId)
+	}
+	sort.Strings(countedIds)
+	countValues := make([]int64, len(countedIds))
+	for i, articleId := range countedIds {
+		countValues[i] = counts[articleId]
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+	INSERT INTO article_views (id, article_id, user_id, ip_address, user_agent, referrer, viewed_at)
+	SELECT v.id, v.article_id, u.id, v.ip_address, v.user_agent, NULLIF(v.referrer, ''), v.viewed_at
+	FROM unnest($1::uuid[], $2::uuid[], $3::text[], $4::text[], $5::text[], $6::text[], $7::timestamptz[])
+		AS v(id, article_id, user_id, ip_address, user_agent, referrer, viewed_at)
+	JOIN articles a ON a.id = v.article_id
+	LEFT JOIN users u ON u.id = NULLIF(v.user_id, '')::uuid
+	`, pq.Array(ids), pq.Array(articleIds), pq.Array(userIds), pq.Array(ipAddresses),
+		pq.Array(userAgents), pq.Array(referrers), pq.Array(viewedAts))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	// Lock the counted rows in a fixed order before incrementing them
+	_, err = tx.ExecContext(ctx, `SELECT id FROM articles WHERE id = ANY($1::uuid[]) ORDER BY id FOR UPDATE`, pq.Array(countedIds))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+	UPDATE articles a
+	SET views = a.views + v.count
+	FROM unnest($1::uuid[], $2::bigint[]) AS v(article_id, count)
+	WHERE a.id = v.article_id
+	`, pq.Array(countedIds), pq.Array(countValues))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func NewArticleViewRepository(database *sql.DB) ArticleViewRepository {
+	return &articleViewRepository{db: database}
+}
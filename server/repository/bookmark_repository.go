@@ -52,7 +52,7 @@ func (b *bookmarkRepository) GetByUserId(ctx context.Context, userId uuid.UUID)
 	JOIN articles a ON b.article_id = a.id
 	JOIN users u ON b.user_id = u.id
 	JOIN categories c ON a.category_id = c.id
-	WHERE b.user_id = $1
+	WHERE b.user_id = $1 AND a.deleted_at IS NULL
 	ORDER BY b.created_at DESC;
 	`
 
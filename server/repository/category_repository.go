@@ -13,6 +13,9 @@ type CategoryRepository interface {
 	GetAll(ctx context.Context) ([]model.Category, error)
 	CreateCategory(ctx context.Context, payload model.Category) (model.Category, error)
 	GetCategoryById(ctx context.Context, id uuid.UUID) (model.Category, error)
+	GetCategoryByName(ctx context.Context, name string) (model.Category, error)
+	// UpdateCategory applies only while payload.Version is the stored version and bumps it,
+	// a stale version answers sql.ErrNoRows
 	UpdateCategory(ctx context.Context, payload model.Category) (model.Category, error)
 	DeleteCategory(ctx context.Context, id uuid.UUID) error
 }
@@ -24,14 +27,27 @@ type categoryRepository struct {
 // GetCategoryById implements CategoryRepository.
 func (c *categoryRepository) GetCategoryById(ctx context.Context, id uuid.UUID) (model.Category, error) {
 	query := `
-	SELECT id, name, created_at, updated_at
+	SELECT id, name, slug, version, created_at, updated_at
 	FROM categories
 	WHERE id = $1
 	`
 
 	var cat model.Category
 	err := c.db.QueryRowContext(ctx, query, id).Scan(
-		&cat.Id, &cat.Name, &cat.CreatedAt, &cat.UpdatedAt,
+		&cat.Id, &cat.Name, &cat.Slug, &cat.Version, &cat.CreatedAt, &cat.UpdatedAt,
+	)
+	if err != nil {
+		return model.Category{}, err
+	}
+
+	return cat, nil
+}
+
+// GetCategoryByName implements CategoryRepository.
+func (c *categoryRepository) GetCategoryByName(ctx context.Context, name string) (model.Category, error) {
+	var cat model.Category
+	err := c.db.QueryRowContext(ctx, `SELECT id, name, slug, version, created_at, updated_at FROM categories WHERE name = $1`, name).Scan(
+		&cat.Id, &cat.Name, &cat.Slug, &cat.Version, &cat.CreatedAt, &cat.UpdatedAt,
 	)
 	if err != nil {
 		return model.Category{}, err
@@ -53,7 +69,7 @@ func (c *categoryRepository) DeleteCategory(ctx context.Context, id uuid.UUID) e
 // UpdateCategory implements CategoryRepository.
 func (c *categoryRepository) UpdateCategory(ctx context.Context, payload model.Category) (model.Category, error) {
 	var cat model.Category
-	err := c.db.QueryRowContext(ctx, `UPDATE categories SET name = $1, updated_at = $2 WHERE id = $3 RETURNING id, name, created_at, updated_at`, payload.Name, time.Now(), payload.Id).Scan(&cat.Id, &cat.Name, &cat.CreatedAt, &cat.UpdatedAt)
+	err := c.db.QueryRowContext(ctx, `UPDATE categories SET name = $1, slug = $2, updated_at = $3, version = version + 1 WHERE id = $4 AND version = $5 RETURNING id, name, slug, version, created_at, updated_at`, payload.Name, payload.Slug, time.Now(), payload.Id, payload.Version).Scan(&cat.Id, &cat.Name, &cat.Slug, &cat.Version, &cat.CreatedAt, &cat.UpdatedAt)
 
 	if err != nil {
 		return model.Category{}, err
@@ -64,9 +80,12 @@ func (c *categoryRepository) UpdateCategory(ctx context.Context, payload model.C
 
 // CreateCategory implements CategoryRepository.
 func (c *categoryRepository) CreateCategory(ctx context.Context, payload model.Category) (model.Category, error) {
-	newId := uuid.Must(uuid.NewV7())
+	newId := payload.Id
+	if newId == uuid.Nil {
+		newId = uuid.Must(uuid.NewV7())
+	}
 	var cat model.Category
-	err := c.db.QueryRowContext(ctx, `INSERT INTO categories (id, name, created_at, updated_at) VALUES($1, $2, $3, $4) RETURNING id, name, created_at, updated_at`, newId, payload.Name, time.Now(), time.Now()).Scan(&cat.Id, &cat.Name, &cat.CreatedAt, &cat.UpdatedAt)
+	err := c.db.QueryRowContext(ctx, `INSERT INTO categories (id, name, slug, created_at, updated_at) VALUES($1, $2, $3, $4, $5) RETURNING id, name, slug, version, created_at, updated_at`, newId, payload.Name, payload.Slug, time.Now(), time.Now()).Scan(&cat.Id, &cat.Name, &cat.Slug, &cat.Version, &cat.CreatedAt, &cat.UpdatedAt)
 	if err != nil {
 		return model.Category{}, err
 	}
@@ -77,7 +96,7 @@ func (c *categoryRepository) CreateCategory(ctx context.Context, payload model.C
 func (c *categoryRepository) GetAll(ctx context.Context) ([]model.Category, error) {
 	var listCategory []model.Category
 
-	rows, err := c.db.QueryContext(ctx, `SELECT id, name, created_at, updated_at FROM categories`)
+	rows, err := c.db.QueryContext(ctx, `SELECT id, name, slug, version, created_at, updated_at FROM categories`)
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +108,8 @@ func (c *categoryRepository) GetAll(ctx context.Context) ([]model.Category, erro
 		err := rows.Scan(
 			&category.Id,
 			&category.Name,
+			&category.Slug,
+			&category.Version,
 			&category.CreatedAt,
 			&category.UpdatedAt,
 		)
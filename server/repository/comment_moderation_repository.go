@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type CommentModerationRepository interface {
+	// GetQueue lists the comments with status, oldest first, optionally of one article only
+	GetQueue(ctx context.Context, status string, articleId *uuid.UUID, offset, limit int) ([]model.Comment, int, error)
+	// SetStatus moderates the comments that are not deleted and returns them with the status
+	// and moderation time they had before
+	SetStatus(ctx context.Context, ids []uuid.UUID, status string, moderatorId uuid.UUID) ([]ModeratedComment, error)
+	// CountApprovedByUser counts the approved comments of the user on every article
+	CountApprovedByUser(ctx context.Context, userId uuid.UUID) (int, error)
+	// GetPolicy returns the policy of the article, or the global policy when the article
+	// has none or articleId is nil, sql.ErrNoRows when neither is stored
+	GetPolicy(ctx context.Context, articleId *uuid.UUID) (model.CommentModerationPolicy, error)
+	SetPolicy(ctx context.Context, policy model.CommentModerationPolicy) (model.CommentModerationPolicy, error)
+	// DeletePolicy removes the policy of the article so the global one applies again
+	DeletePolicy(ctx context.Context, articleId uuid.UUID) error
+	// CountDuplicates counts the comments not deleted that were posted since by the user or on
+	// the article with the same content, ignoring case and surrounding whitespace
+	CountDuplicates(ctx context.Context, content string, userId, articleId uuid.UUID, since time.Time) (int, error)
+	// GetSpamDecisions returns the content and status of the most recent comments a moderator
+	// approved or marked as spam
+	GetSpamDecisions(ctx context.Context, limit int) ([]model.Comment, error)
+}
+
+// ModeratedComment is a comment changed by SetStatus
+type ModeratedComment struct {
+	Id        uuid.UUID
+	ArticleId uuid.UUID
+	Content   string
+	// PreviousStatus and PreviousModeratedAt are the values before the change, a nil
+	// PreviousModeratedAt means no moderator decided on the comment before
+	PreviousStatus      string
+	PreviousModeratedAt *time.Time
+}
+
+type commentModerationRepository struct {
+	db *sql.DB
+}
+
+// GetQueue implements CommentModerationRepository.
+func (r *commentModerationRepository) GetQueue(ctx context.Context, status string, articleId *uuid.UUID, offset, limit int) ([]model.Comment, int, error) {
+	where := `
+	FROM comments c
+	JOIN articles a ON c.article_id = a.id
+	JOIN users u ON c.user_id = u.id
+	WHERE c.status = $1 AND ($2::uuid IS NULL OR c.article_id = $2)
+		AND c.deleted_at IS NULL AND a.deleted_at IS NULL`
+
+	var totalCount int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+where, status, articleId).Scan(&totalCount); err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT
+		c.id, c.article_id, c.user_id, c.parent_comment_id, c.content, c.status, c.spam_score, c.spam_signals, c.edited_at, c.created_at, c.updated_at,
+		a.id, a.title, a.slug,
+		u.id, u.name, u.role`+where+`
+	ORDER BY c.created_at, c.id
+	LIMIT $3 OFFSET $4`, status, articleId, limit, offset)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	comments := []model.Comment{}
+	for rows.Next() {
+		var comment model.Comment
+		var article model.Article
+		var user model.User
+		var signals []byte
+
+		err := rows.Scan(
+			&comment.Id, &comment.ArticleId, &comment.UserId, &comment.ParentCommentId, &comment.Content, &comment.Status, &comment.SpamScore, &signals, &comment.EditedAt, &comment.CreatedAt, &comment.UpdatedAt,
+			&article.Id, &article.Title, &article.Slug,
+			&user.Id, &user.Name, &user.Role,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		if comment.SpamSignals, err = decodeSpamSignals(signals); err != nil {
+			return nil, 0, err
+		}
+
+		comment.IsEdited = comment.EditedAt != nil
+		comment.Article = &article
+		comment.User = &user
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return comments, totalCount, nil
+}
+
+// SetStatus implements CommentModerationRepository.
+// The self join reads the row as it was before the update.
+func (r *commentModerationRepository) SetStatus(ctx context.Context, ids []uuid.UUID, status string, moderatorId uuid.UUID) ([]ModeratedComment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+	UPDATE comments c SET status = $1, moderated_by = $2, moderated_at = NOW()
+	FROM comments previous
+	WHERE previous.id = c.id AND c.id = ANY($3) AND c.deleted_at IS NULL
+	RETURNING c.id, c.article_id, c.content, previous.status, previous.moderated_at`, status, moderatorId, pq.Array(ids))
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	var moderated []ModeratedComment
+	for rows.Next() {
+		var comment ModeratedComment
+		if err := rows.Scan(&comment.Id, &comment.ArticleId, &comment.Content, &comment.PreviousStatus, &comment.PreviousModeratedAt); err != nil {
+			return nil, err
+		}
+		moderated = append(moderated, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return moderated, nil
+}
+
+// CountApprovedByUser implements CommentModerationRepository.
+func (r *commentModerationRepository) CountApprovedByUser(ctx context.Context, userId uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM comments WHERE user_id = $1 AND status = 'approved' AND deleted_at IS NULL`, userId).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetPolicy implements CommentModerationRepository.
+func (r *commentModerationRepository) GetPolicy(ctx context.Context, articleId *uuid.UUID) (model.CommentModerationPolicy, error) {
+	var policy model.CommentModerationPolicy
+	err := r.db.QueryRowContext(ctx, `
+	SELECT article_id, mode, updated_by, updated_at FROM comment_moderation_policies
+	WHERE article_id = $1 OR article_id IS NULL
+	ORDER BY article_id IS NULL
+	LIMIT 1`, articleId).Scan(&policy.ArticleId, &policy.Mode, &policy.UpdatedBy, &policy.UpdatedAt)
+	if err != nil {
+		return model.CommentModerationPolicy{}, err
+	}
+
+	return policy, nil
+}
+
+// SetPolicy implements CommentModerationRepository.
+// The global policy is kept unique by a partial index, so it needs its own conflict target.
+func (r *commentModerationRepository) SetPolicy(ctx context.Context, policy model.CommentModerationPolicy) (model.CommentModerationPolicy, error) {
+	conflict := `(article_id)`
+	if policy.ArticleId == nil {
+		conflict = `((article_id IS NULL)) WHERE article_id IS NULL`
+	}
+
+	var saved model.CommentModerationPolicy
+	err := r.db.QueryRowContext(ctx, `
+	INSERT INTO comment_moderation_policies (article_id, mode, updated_by, updated_at)
+	VALUES ($1, $2, $3, NOW())
+	ON CONFLICT `+conflict+` DO UPDATE SET mode = EXCLUDED.mode, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+	RETURNING article_id, mode, updated_by, updated_at`, policy.ArticleId, policy.Mode, policy.UpdatedBy).Scan(
+		&saved.ArticleId, &saved.Mode, &saved.UpdatedBy, &saved.UpdatedAt,
+	)
+	if err != nil {
+		return model.CommentModerationPolicy{}, err
+	}
+
+	return saved, nil
+}
+
+// DeletePolicy implements CommentModerationRepository.
+func (r *commentModerationRepository) DeletePolicy(ctx context.Context, articleId uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM comment_moderation_policies WHERE article_id = $1`, articleId)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// CountDuplicates implements CommentModerationRepository.
+func (r *commentModerationRepository) CountDuplicates(ctx context.Context, content string, userId, articleId uuid.UUID, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+	SELECT COUNT(*) FROM comments
+	WHERE (user_id = $2 OR article_id = $3) AND created_at >= $4 AND deleted_at IS NULL
+		AND LOWER(BTRIM(content)) = LOWER(BTRIM($1))`, content, userId, articleId, since).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetSpamDecisions implements CommentModerationRepository.
+func (r *commentModerationRepository) GetSpamDecisions(ctx context.Context, limit int) ([]model.Comment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT id, content, status FROM comments
+	WHERE moderated_at IS NOT NULL AND status IN ('approved', 'spam')
+	ORDER BY moderated_at DESC
+	LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []model.Comment
+	for rows.Next() {
+		var comment model.Comment
+		if err := rows.Scan(&comment.Id, &comment.Content, &comment.Status); err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// encodeSpamSignals stores the signals of a spam score, nil for a comment that was not scored
+func encodeSpamSignals(signals map[string]float64) ([]byte, error) {
+	if signals == nil {
+		return nil, nil
+	}
+	return json.Marshal(signals)
+}
+
+// decodeSpamSignals parses the spam_signals column
+func decodeSpamSignals(raw []byte) (map[string]float64, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var signals map[string]float64
+	if err := json.Unmarshal(raw, &signals); err != nil {
+		return nil, fmt.Errorf("failed to decode comment spam signals: %v", err)
+	}
+	return signals, nil
+}
+
+func NewCommentModerationRepository(database *sql.DB) CommentModerationRepository {
+	return &commentModerationRepository{db: database}
+}
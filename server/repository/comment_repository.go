@@ -5,18 +5,52 @@ import (
 	"database/sql"
 	"develapar-server/model"
 	"develapar-server/model/dto"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// ErrCommentTooDeep is returned by CreateReply when the reply would be nested deeper
+// than allowed
+var ErrCommentTooDeep = errors.New("reply would be nested too deeply")
+
 type CommentRepository interface {
 	CreateComment(ctx context.Context, payload model.Comment) (model.Comment, error)
-	GetCommentByArticleId(ctx context.Context, articleId uuid.UUID) ([]model.Comment, error)
-	GetCommentByUserId(ctx context.Context, userId uuid.UUID) ([]dto.CommentResponse, error)
+	// CreateReply adds a reply to payload.ParentCommentId and returns it with its depth. The
+	// parent is locked while the reply is stored, so it cannot be deleted or hidden between
+	// the checks and the insert. sql.ErrNoRows is returned when the parent is gone, belongs to
+	// another article or is not approved (or pending and written by the author of the reply),
+	// ErrCommentTooDeep when the reply would be nested deeper than maxDepth.
+	CreateReply(ctx context.Context, payload model.Comment, maxDepth int) (model.Comment, error)
+	// GetCommentPage returns up to limit top level comments of the article in sort order after
+	// the cursor, each followed by its first maxReplies replies in depth first order with depth
+	// and path filled in, and whether more top level comments follow. HasMoreReplies is set on
+	// the top level comments with more replies. Replies are returned whatever their status,
+	// deleted ones included so their replies keep a parent, and count toward maxReplies.
+	GetCommentPage(ctx context.Context, articleId, viewerId uuid.UUID, sort string, after *model.CommentCursor, limit, maxReplies int) ([]model.Comment, bool, error)
+	// GetCommentByUserId returns the approved comments of the user, and the pending ones too
+	// when the user is viewerId. Only comments on publicly listed articles are returned,
+	// and on the articles viewerId may edit.
+	GetCommentByUserId(ctx context.Context, userId, viewerId uuid.UUID) ([]dto.CommentResponse, error)
 	GetCommentById(ctx context.Context, commentId uuid.UUID) (model.Comment, error)
-	UpdateComment(ctx context.Context, commentId uuid.UUID, content string, userId uuid.UUID) error
+	// UpdateComment replaces the content, status and spam score of the comment with those of
+	// comment and records the change as an edit by editorId. A moderator decision was made on
+	// the previous content, so it is cleared. Nothing is changed when the content is unchanged.
+	// sql.ErrNoRows is returned when the comment is gone, rejected, marked as spam or was not
+	// written by editorId.
+	UpdateComment(ctx context.Context, comment model.Comment, editorId uuid.UUID) error
+	// GetCommentEdits returns the edits of the comment oldest first, sql.ErrNoRows when the
+	// comment does not exist
+	GetCommentEdits(ctx context.Context, commentId uuid.UUID) ([]model.CommentEdit, error)
+	// DeleteComment moves the comment to the trash
 	DeleteComment(ctx context.Context, commentId uuid.UUID) error
+	// LikeComment and UnlikeComment add or remove the like of the user, doing nothing when it
+	// is already there or gone, and return the like count of the comment
+	LikeComment(ctx context.Context, commentId, userId uuid.UUID) (int, error)
+	UnlikeComment(ctx context.Context, commentId, userId uuid.UUID) (int, error)
 }
 
 type commentRepository struct {
@@ -26,36 +60,206 @@ type commentRepository struct {
 // GetCommentById implements CommentRepository.
 func (c *commentRepository) GetCommentById(ctx context.Context, commentId uuid.UUID) (model.Comment, error) {
 	var comment model.Comment
-	query := `SELECT id, article_id, user_id, content, created_at, updated_at FROM comments WHERE id = $1`
+	query := `SELECT id, article_id, user_id, parent_comment_id, content, status, edited_at, created_at, updated_at FROM comments WHERE id = $1 AND deleted_at IS NULL`
 
-	err := c.db.QueryRowContext(ctx, query, commentId).Scan(&comment.Id, &comment.ArticleId, &comment.UserId, &comment.Content, &comment.CreatedAt, &comment.UpdatedAt)
+	err := c.db.QueryRowContext(ctx, query, commentId).Scan(&comment.Id, &comment.ArticleId, &comment.UserId, &comment.ParentCommentId, &comment.Content, &comment.Status, &comment.EditedAt, &comment.CreatedAt, &comment.UpdatedAt)
 	if err != nil {
 		return model.Comment{}, err
 	}
+	comment.IsEdited = comment.EditedAt != nil
 
 	return comment, nil
 }
 
 // DeleteComment implements CommentRepository.
 func (c *commentRepository) DeleteComment(ctx context.Context, commentId uuid.UUID) error {
-	query := `DELETE FROM comments WHERE id = $1`
+	query := `UPDATE comments SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
 	_, err := c.db.ExecContext(ctx, query, commentId)
 	return err
 }
 
 // UpdateComment implements CommentRepository.
-func (c *commentRepository) UpdateComment(ctx context.Context, commentId uuid.UUID, content string, userId uuid.UUID) error {
-	query := `UPDATE comments SET content = $1, updated_at = NOW() WHERE id = $2 AND user_id=$3`
-	_, err := c.db.ExecContext(ctx, query, content, commentId, userId)
-	return err
+func (c *commentRepository) UpdateComment(ctx context.Context, comment model.Comment, editorId uuid.UUID) error {
+	signals, err := encodeSpamSignals(comment.SpamSignals)
+	if err != nil {
+		return err
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the comment row so concurrent edits are recorded one after the other, and so a
+	// moderator cannot reject the comment between this check and the update
+	var previousContent string
+	err = tx.QueryRowContext(ctx, `
+	SELECT content FROM comments
+	WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL AND status NOT IN ('rejected', 'spam')
+	FOR UPDATE`, comment.Id, editorId).Scan(&previousContent)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	if previousContent == comment.Content {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+	INSERT INTO comment_edits (id, comment_id, previous_content, content, edited_by, edited_at)
+	VALUES ($1, $2, $3, $4, $5, NOW())`,
+		uuid.Must(uuid.NewV7()), comment.Id, previousContent, comment.Content, uuid.NullUUID{UUID: editorId, Valid: editorId != uuid.Nil})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+	UPDATE comments
+	SET content = $1, status = $2, spam_score = $3, spam_signals = $4, moderated_by = NULL, moderated_at = NULL,
+		edited_at = NOW(), updated_at = NOW()
+	WHERE id = $5`, comment.Content, comment.Status, comment.SpamScore, signals, comment.Id)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetCommentEdits implements CommentRepository.
+// Edits of a comment in the trash stay visible to moderators.
+func (c *commentRepository) GetCommentEdits(ctx context.Context, commentId uuid.UUID) ([]model.CommentEdit, error) {
+	var exists bool
+	if err := c.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM comments WHERE id = $1)`, commentId).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+	SELECT
+		e.id, e.comment_id, e.previous_content, e.content, e.edited_by, e.edited_at,
+		u.id, u.name, u.role
+	FROM comment_edits e
+	LEFT JOIN users u ON e.edited_by = u.id
+	WHERE e.comment_id = $1
+	ORDER BY e.edited_at, e.id`, commentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	edits := []model.CommentEdit{}
+	for rows.Next() {
+		var edit model.CommentEdit
+		var editorId uuid.NullUUID
+		var editorName, editorRole sql.NullString
+
+		err := rows.Scan(
+			&edit.Id, &edit.CommentId, &edit.PreviousContent, &edit.Content, &edit.EditedBy, &edit.EditedAt,
+			&editorId, &editorName, &editorRole,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// The editor is gone when their account was deleted
+		if editorId.Valid {
+			edit.Editor = &model.User{Id: editorId.UUID, Name: editorName.String, Role: editorRole.String}
+		}
+		edits = append(edits, edit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return edits, nil
 }
 
 // CreateComment implements CommentRepository.
 func (c *commentRepository) CreateComment(ctx context.Context, payload model.Comment) (model.Comment, error) {
+	return insertComment(ctx, c.db, payload)
+}
+
+// CreateReply implements CommentRepository.
+func (c *commentRepository) CreateReply(ctx context.Context, payload model.Comment, maxDepth int) (model.Comment, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.Comment{}, ctx.Err()
+		}
+		return model.Comment{}, err
+	}
+	defer tx.Rollback()
+
+	// The share lock blocks deleting and moderating the parent until the reply is committed
+	var parentId uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+	SELECT id FROM comments
+	WHERE id = $1 AND article_id = $2 AND deleted_at IS NULL
+		AND (status = 'approved' OR (status = 'pending' AND user_id = $3))
+	FOR SHARE`, payload.ParentCommentId, payload.ArticleId, payload.UserId).Scan(&parentId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.Comment{}, ctx.Err()
+		}
+		return model.Comment{}, err
+	}
+
+	// The ancestors of a comment never change, so the depth of the locked parent holds
+	var parentDepth int
+	if err := tx.QueryRowContext(ctx, commentDepthQuery, parentId).Scan(&parentDepth); err != nil {
+		if ctx.Err() != nil {
+			return model.Comment{}, ctx.Err()
+		}
+		return model.Comment{}, err
+	}
+	if parentDepth+1 > maxDepth {
+		return model.Comment{}, ErrCommentTooDeep
+	}
+
+	comment, err := insertComment(ctx, tx, payload)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.Comment{}, ctx.Err()
+		}
+		return model.Comment{}, err
+	}
+	comment.Depth = parentDepth + 1
+
+	return comment, tx.Commit()
+}
+
+// rowQuerier is satisfied by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func insertComment(ctx context.Context, q rowQuerier, payload model.Comment) (model.Comment, error) {
 	newId := uuid.Must(uuid.NewV7())
 	var comment model.Comment
-	err := c.db.QueryRowContext(ctx, `INSERT INTO comments (id, article_id, user_id, content, created_at, updated_at) VALUES($1, $2, $3, $4, $5, $6) RETURNING id, article_id, user_id, content, created_at, updated_at`, newId, payload.ArticleId, payload.UserId, payload.Content, time.Now(), time.Now()).Scan(
-		&comment.Id, &comment.ArticleId, &comment.UserId, &comment.Content, &comment.CreatedAt, &comment.UpdatedAt,
+	signals, err := encodeSpamSignals(payload.SpamSignals)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	// The spam score is left out of the returned comment, it is only shown to moderators
+	err = q.QueryRowContext(ctx, `INSERT INTO comments (id, article_id, user_id, parent_comment_id, content, status, spam_score, spam_signals, created_at, updated_at) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, article_id, user_id, parent_comment_id, content, status, created_at, updated_at`, newId, payload.ArticleId, payload.UserId, payload.ParentCommentId, payload.Content, payload.Status, payload.SpamScore, signals, time.Now(), time.Now()).Scan(
+		&comment.Id, &comment.ArticleId, &comment.UserId, &comment.ParentCommentId, &comment.Content, &comment.Status, &comment.CreatedAt, &comment.UpdatedAt,
 	)
 
 	if err != nil {
@@ -65,78 +269,181 @@ func (c *commentRepository) CreateComment(ctx context.Context, payload model.Com
 	return comment, nil
 }
 
-// GetCommentByArticleId implements CommentRepository.
-func (c *commentRepository) GetCommentByArticleId(ctx context.Context, articleId uuid.UUID) ([]model.Comment, error) {
-	var comments []model.Comment
+// commentPageOrders are the orders of the top level comments for each sort with the condition
+// selecting the comments after the cursor pc. Every order ends with the id, so it is total and
+// a page never repeats or skips a comment whose sort key stays the same. The like count is the
+// one exception: it is read when each page is, so a comment liked or unliked between two pages
+// can cross the cursor.
+var commentPageOrders = map[string]struct{ order, after string }{
+	model.CommentSortNewest:    {order: `c.created_at DESC, c.id DESC`, after: `(c.created_at, c.id) < (pc.created_at, pc.id)`},
+	model.CommentSortOldest:    {order: `c.created_at, c.id`, after: `(c.created_at, c.id) > (pc.created_at, pc.id)`},
+	model.CommentSortMostLiked: {order: `c.like_count DESC, c.created_at DESC, c.id DESC`, after: `(c.like_count, c.created_at, c.id) < (pc.like_count, pc.created_at, pc.id)`},
+}
+
+// GetCommentPage implements CommentRepository.
+// The top level comments are numbered in page order, the replies of each follow it by path.
+// The path is built from the comment ids, which are UUIDv7 and sort by creation time, so
+// ordering by path lists every comment right after its parent and replies oldest first.
+func (c *commentRepository) GetCommentPage(ctx context.Context, articleId, viewerId uuid.UUID, sort string, after *model.CommentCursor, limit, maxReplies int) ([]model.Comment, bool, error) {
+	sortOrder, ok := commentPageOrders[sort]
+	if !ok {
+		return nil, false, fmt.Errorf("unknown comment sort %q", sort)
+	}
 
+	var afterCreatedAt *time.Time
+	var afterId *uuid.UUID
+	var afterLikeCount *int
+	if after != nil {
+		afterCreatedAt, afterId, afterLikeCount = &after.CreatedAt, &after.Id, &after.LikeCount
+	}
+
+	// One more top level comment than the limit tells whether there is a next page. Hidden
+	// top level comments are only taken when they have replies that may keep them as tombstone.
+	// The replies of each are numbered in thread order to cut them at maxReplies.
 	query := `
+	WITH RECURSIVE page_cursor AS (
+		SELECT $3::timestamptz AS created_at, $4::uuid AS id, $5::int AS like_count
+	), roots AS (
+		SELECT c.id, c.created_at, c.like_count
+		FROM comments c
+		JOIN articles a ON c.article_id = a.id
+		CROSS JOIN page_cursor pc
+		WHERE c.article_id = $1 AND c.parent_comment_id IS NULL AND a.deleted_at IS NULL
+			AND ((c.deleted_at IS NULL AND (c.status = 'approved' OR (c.status = 'pending' AND c.user_id = $2)))
+				OR EXISTS (SELECT 1 FROM comments reply WHERE reply.parent_comment_id = c.id))
+			AND (pc.id IS NULL OR ` + sortOrder.after + `)
+		ORDER BY ` + sortOrder.order + `
+		LIMIT $6
+	), positions AS (
+		SELECT c.id, ROW_NUMBER() OVER (ORDER BY ` + sortOrder.order + `) AS position
+		FROM roots c
+	), thread AS (
+		SELECT p.id, p.position, 0 AS depth, p.id::text AS path
+		FROM positions p
+		UNION ALL
+		SELECT c.id, t.position, t.depth + 1, t.path || '/' || c.id::text
+		FROM comments c
+		JOIN thread t ON c.parent_comment_id = t.id
+	), numbered AS (
+		SELECT t.id, t.position, t.depth, t.path,
+			ROW_NUMBER() OVER (PARTITION BY t.position ORDER BY t.path) - 1 AS reply_number,
+			COUNT(*) OVER (PARTITION BY t.position) - 1 AS reply_count
+		FROM thread t
+	)
 	SELECT
-		c.id, c.article_id, c.user_id, c.content, c.created_at, c.updated_at,
-		a.id, a.title, a.slug, a.content, a.user_id, a.category_id, a.views, a.status, a.created_at, a.updated_at,
-		u.id, u.name, u.email, u.role, u.created_at, u.updated_at,
-		ca.id, ca.name, ca.created_at, ca.updated_at
-	FROM comments c
-	JOIN articles a ON c.article_id = a.id
+		c.id, c.article_id, c.user_id, c.parent_comment_id, c.content, c.status, c.like_count, c.edited_at, c.deleted_at IS NOT NULL, c.created_at, c.updated_at,
+		t.depth, t.path, t.reply_count > $7,
+		u.id, u.name, u.role
+	FROM numbered t
+	JOIN comments c ON c.id = t.id
 	JOIN users u ON c.user_id = u.id
-	JOIN categories ca ON a.category_id = ca.id
-	WHERE c.article_id = $1
-	ORDER BY c.created_at DESC
+	WHERE t.reply_number <= $7
+	ORDER BY t.position, t.path
 	`
 
-	rows, err := c.db.QueryContext(ctx, query, articleId)
+	rows, err := c.db.QueryContext(ctx, query, articleId, viewerId, afterCreatedAt, afterId, afterLikeCount, limit+1, maxReplies)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer rows.Close()
 
+	var comments []model.Comment
+	roots := 0
 	for rows.Next() {
 		var comment model.Comment
-		var article model.Article
 		var user model.User
-		var category model.Category
 
 		err := rows.Scan(
-			&comment.Id, &comment.ArticleId, &comment.UserId, &comment.Content, &comment.CreatedAt, &comment.UpdatedAt,
-			&article.Id, &article.Title, &article.Slug, &article.Content, &article.UserId, &article.CategoryId, &article.Views, &article.Status, &article.CreatedAt, &article.UpdatedAt,
-			&user.Id, &user.Name, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt,
-			&category.Id, &category.Name, &category.CreatedAt, &category.UpdatedAt,
+			&comment.Id, &comment.ArticleId, &comment.UserId, &comment.ParentCommentId, &comment.Content, &comment.Status, &comment.LikeCount, &comment.EditedAt, &comment.IsDeleted, &comment.CreatedAt, &comment.UpdatedAt,
+			&comment.Depth, &comment.Path, &comment.HasMoreReplies,
+			&user.Id, &user.Name, &user.Role,
 		)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 
-		comment.Article = &article
-		comment.User = &user
-		article.User = &user
-		article.Category = &category
+		// The extra top level comment and its replies come last and only signal the next page
+		if comment.Depth == 0 {
+			if roots++; roots > limit {
+				return comments, true, nil
+			}
+		}
 
+		comment.IsEdited = comment.EditedAt != nil
+		comment.User = &user
 		comments = append(comments, comment)
-
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	return comments, nil
+	return comments, false, nil
+}
+
+// LikeComment implements CommentRepository.
+// like_count is kept by the trg_comment_likes_count trigger.
+func (c *commentRepository) LikeComment(ctx context.Context, commentId, userId uuid.UUID) (int, error) {
+	_, err := c.db.ExecContext(ctx, `INSERT INTO comment_likes (comment_id, user_id, created_at) VALUES ($1, $2, NOW()) ON CONFLICT DO NOTHING`, commentId, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.getLikeCount(ctx, commentId)
+}
+
+// UnlikeComment implements CommentRepository.
+func (c *commentRepository) UnlikeComment(ctx context.Context, commentId, userId uuid.UUID) (int, error) {
+	_, err := c.db.ExecContext(ctx, `DELETE FROM comment_likes WHERE comment_id = $1 AND user_id = $2`, commentId, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.getLikeCount(ctx, commentId)
 }
 
+func (c *commentRepository) getLikeCount(ctx context.Context, commentId uuid.UUID) (int, error) {
+	var count int
+	if err := c.db.QueryRowContext(ctx, `SELECT like_count FROM comments WHERE id = $1`, commentId).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// commentDepthQuery selects how many ancestors the comment $1 has, 0 for a top level comment
+const commentDepthQuery = `
+	WITH RECURSIVE ancestors AS (
+		SELECT id, parent_comment_id, 0 AS depth
+		FROM comments
+		WHERE id = $1
+		UNION ALL
+		SELECT c.id, c.parent_comment_id, a.depth + 1
+		FROM comments c
+		JOIN ancestors a ON c.id = a.parent_comment_id
+	)
+	SELECT depth FROM ancestors WHERE parent_comment_id IS NULL
+	`
+
 // GetCommentByUserId implements CommentRepository.
-func (c *commentRepository) GetCommentByUserId(ctx context.Context, userId uuid.UUID) ([]dto.CommentResponse, error) {
+func (c *commentRepository) GetCommentByUserId(ctx context.Context, userId, viewerId uuid.UUID) ([]dto.CommentResponse, error) {
 	var comments []dto.CommentResponse
 
 	query := `
 	SELECT 
-		c.id, c.content, c.created_at,
+		c.id, c.content, c.edited_at, c.created_at,
 		u.id, u.name, u.email,
 		a.id, a.title, a.slug
 	FROM comments c
 	JOIN users u ON c.user_id = u.id
 	JOIN articles a ON c.article_id = a.id
-	WHERE c.user_id = $1
+	WHERE c.user_id = $1 AND c.deleted_at IS NULL AND a.deleted_at IS NULL
+		AND (c.status = 'approved' OR (c.user_id = $2 AND c.status = 'pending'))
+		AND (` + publicArticleCondition + ` OR a.user_id = $2
+			OR EXISTS (SELECT 1 FROM article_authors aa WHERE aa.article_id = a.id AND aa.user_id = $2 AND aa.role = ANY($3)))
 	`
 
-	rows, err := c.db.QueryContext(ctx, query, userId)
+	rows, err := c.db.QueryContext(ctx, query, userId, viewerId, pq.Array(model.ArticleAuthorEditRoles))
 	if err != nil {
 		return nil, err
 	}
@@ -146,13 +453,14 @@ func (c *commentRepository) GetCommentByUserId(ctx context.Context, userId uuid.
 		var comment dto.CommentResponse
 
 		err := rows.Scan(
-			&comment.Id, &comment.Content, &comment.CreatedAt,
+			&comment.Id, &comment.Content, &comment.EditedAt, &comment.CreatedAt,
 			&comment.User.Id, &comment.User.Name, &comment.User.Email,
 			&comment.Article.Id, &comment.Article.Title, &comment.Article.Slug,
 		)
 		if err != nil {
 			return nil, err
 		}
+		comment.IsEdited = comment.EditedAt != nil
 
 		comments = append(comments, comment)
 	}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the PostgreSQL error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// IsUniqueViolation reports whether err is caused by a unique constraint violation
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// foreignKeyViolation is the PostgreSQL error code of a foreign key violation
+const foreignKeyViolation = "23503"
+
+// IsForeignKeyViolation reports whether err is caused by a reference to a missing row
+func IsForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation
+}
+
+// IsDataError reports whether err is caused by the data written rather than by the
+// connection or the server: a data exception or an integrity constraint violation.
+// Writing the same data again fails the same way.
+func IsDataError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	class := pqErr.Code.Class()
+	return class == "22" || class == "23"
+}
+
+// IsSlugViolation reports whether err is caused by a slug already used in table
+func IsSlugViolation(err error, table string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == table+"_slug_key"
+}
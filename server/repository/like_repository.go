@@ -110,7 +110,7 @@ func (l *likeRepository) GetLikeByUserId(ctx context.Context, userId uuid.UUID)
 		a.id, a.title, a.slug, a.content, a.user_id, a.category_id, a.views, a.status, a.created_at, a.updated_at
 	FROM likes l
 	JOIN articles a ON l.article_id = a.id
-	WHERE l.user_id = $1
+	WHERE l.user_id = $1 AND a.deleted_at IS NULL
 
 	`
 
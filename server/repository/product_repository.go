@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"develapar-server/model"
-	"develapar-server/utils"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +15,8 @@ type ProductRepository interface {
 	GetAllProductCategoriesWithPagination(ctx context.Context, offset, limit int) ([]model.ProductCategory, int, error)
 	GetProductCategoryById(ctx context.Context, id uuid.UUID) (model.ProductCategory, error)
 	GetProductCategoryBySlug(ctx context.Context, slug string) (model.ProductCategory, error)
+	// UpdateProductCategory applies only while payload.Version is the stored version and bumps it,
+	// a stale version answers sql.ErrNoRows
 	UpdateProductCategory(ctx context.Context, payload model.ProductCategory) (model.ProductCategory, error)
 	DeleteProductCategory(ctx context.Context, id uuid.UUID) error
 
@@ -26,12 +27,18 @@ type ProductRepository interface {
 	GetProductByIdWithAffiliateLinks(ctx context.Context, id uuid.UUID) (model.Product, []model.ProductAffiliateLink, error)
 	GetProductsByCategoryWithPagination(ctx context.Context, categoryId uuid.UUID, offset, limit int) ([]model.Product, int, error)
 	GetProductsByCategoryWithAffiliateLinksAndPagination(ctx context.Context, categoryId uuid.UUID, offset, limit int) ([]model.Product, map[uuid.UUID][]model.ProductAffiliateLink, int, error)
+	// UpdateProduct applies only while payload.Version is the stored version and bumps it,
+	// a stale version answers sql.ErrNoRows
 	UpdateProduct(ctx context.Context, payload model.Product) (model.Product, error)
+	// DeleteProduct moves the product to the trash, its affiliate links and article
+	// relations are kept for a restore
 	DeleteProduct(ctx context.Context, id uuid.UUID) error
 
 	// Product Affiliate Links
 	CreateProductAffiliateLink(ctx context.Context, payload model.ProductAffiliateLink) (model.ProductAffiliateLink, error)
 	GetAffiliateLinksbyProductId(ctx context.Context, productId uuid.UUID) ([]model.ProductAffiliateLink, error)
+	// UpdateProductAffiliateLink applies only while payload.Version is the stored version and bumps it,
+	// a stale version answers sql.ErrNoRows
 	UpdateProductAffiliateLink(ctx context.Context, payload model.ProductAffiliateLink) (model.ProductAffiliateLink, error)
 	DeleteProductAffiliateLink(ctx context.Context, id uuid.UUID) error
 
@@ -49,16 +56,18 @@ type productRepository struct {
 
 // CreateProductCategory implements ProductRepository
 func (r *productRepository) CreateProductCategory(ctx context.Context, payload model.ProductCategory) (model.ProductCategory, error) {
-	newId := uuid.Must(uuid.NewV7())
-	slug := utils.GenerateSlug(payload.Name)
+	newId := payload.Id
+	if newId == uuid.Nil {
+		newId = uuid.Must(uuid.NewV7())
+	}
 	var category model.ProductCategory
 
 	query := `INSERT INTO product_categories (id, name, slug, description, created_at, updated_at) 
 			  VALUES ($1, $2, $3, $4, $5, $6) 
-			  RETURNING id, name, slug, description, created_at, updated_at`
+			  RETURNING id, name, slug, description, version, created_at, updated_at`
 
-	err := r.db.QueryRowContext(ctx, query, newId, payload.Name, slug, payload.Description, time.Now(), time.Now()).
-		Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, newId, payload.Name, payload.Slug, payload.Description, time.Now(), time.Now()).
+		Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.Version, &category.CreatedAt, &category.UpdatedAt)
 
 	if err != nil {
 		if ctx.Err() != nil {
@@ -84,7 +93,7 @@ func (r *productRepository) GetAllProductCategoriesWithPagination(ctx context.Co
 	}
 
 	// Get paginated results
-	query := `SELECT id, name, slug, description, created_at, updated_at FROM product_categories ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+	query := `SELECT id, name, slug, description, version, created_at, updated_at FROM product_categories ORDER BY created_at DESC LIMIT $1 OFFSET $2`
 
 	rows, err := r.db.QueryContext(ctx, query, limit, offset)
 	if err != nil {
@@ -104,7 +113,7 @@ func (r *productRepository) GetAllProductCategoriesWithPagination(ctx context.Co
 		}
 
 		var category model.ProductCategory
-		err := rows.Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+		err := rows.Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.Version, &category.CreatedAt, &category.UpdatedAt)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -121,10 +130,10 @@ func (r *productRepository) GetAllProductCategoriesWithPagination(ctx context.Co
 // GetProductCategoryById implements ProductRepository
 func (r *productRepository) GetProductCategoryById(ctx context.Context, id uuid.UUID) (model.ProductCategory, error) {
 	var category model.ProductCategory
-	query := `SELECT id, name, slug, description, created_at, updated_at FROM product_categories WHERE id = $1`
+	query := `SELECT id, name, slug, description, version, created_at, updated_at FROM product_categories WHERE id = $1`
 
 	err := r.db.QueryRowContext(ctx, query, id).
-		Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+		Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.Version, &category.CreatedAt, &category.UpdatedAt)
 
 	if err != nil {
 		if ctx.Err() != nil {
@@ -139,10 +148,10 @@ func (r *productRepository) GetProductCategoryById(ctx context.Context, id uuid.
 // GetProductCategoryBySlug implements ProductRepository
 func (r *productRepository) GetProductCategoryBySlug(ctx context.Context, slug string) (model.ProductCategory, error) {
 	var category model.ProductCategory
-	query := `SELECT id, name, slug, description, created_at, updated_at FROM product_categories WHERE slug = $1`
+	query := `SELECT id, name, slug, description, version, created_at, updated_at FROM product_categories WHERE slug = $1`
 
 	err := r.db.QueryRowContext(ctx, query, slug).
-		Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+		Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.Version, &category.CreatedAt, &category.UpdatedAt)
 
 	if err != nil {
 		if ctx.Err() != nil {
@@ -157,12 +166,12 @@ func (r *productRepository) GetProductCategoryBySlug(ctx context.Context, slug s
 // UpdateProductCategory implements ProductRepository
 func (r *productRepository) UpdateProductCategory(ctx context.Context, payload model.ProductCategory) (model.ProductCategory, error) {
 	var category model.ProductCategory
-	query := `UPDATE product_categories SET name = $1, slug = $2, description = $3, updated_at = $4 
-			  WHERE id = $5 
-			  RETURNING id, name, slug, description, created_at, updated_at`
+	query := `UPDATE product_categories SET name = $1, slug = $2, description = $3, updated_at = $4, version = version + 1 
+			  WHERE id = $5 AND version = $6 
+			  RETURNING id, name, slug, description, version, created_at, updated_at`
 
-	err := r.db.QueryRowContext(ctx, query, payload.Name, payload.Slug, payload.Description, time.Now(), payload.Id).
-		Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, payload.Name, payload.Slug, payload.Description, time.Now(), payload.Id, payload.Version).
+		Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.Version, &category.CreatedAt, &category.UpdatedAt)
 
 	if err != nil {
 		if ctx.Err() != nil {
@@ -196,12 +205,12 @@ func (r *productRepository) CreateProduct(ctx context.Context, payload model.Pro
 
 	query := `INSERT INTO products (id, product_category_id, name, description, image_url, is_active, created_at, updated_at) 
 			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
-			  RETURNING id, product_category_id, name, description, image_url, is_active, created_at, updated_at`
+			  RETURNING id, product_category_id, name, description, image_url, is_active, version, created_at, updated_at`
 
 	err := r.db.QueryRowContext(ctx, query, newId, payload.ProductCategoryId, payload.Name, payload.Description,
 		payload.ImageUrl, payload.IsActive, time.Now(), time.Now()).
 		Scan(&product.Id, &product.ProductCategoryId, &product.Name, &product.Description,
-			&product.ImageUrl, &product.IsActive, &product.CreatedAt, &product.UpdatedAt)
+			&product.ImageUrl, &product.IsActive, &product.Version, &product.CreatedAt, &product.UpdatedAt)
 
 	if err != nil {
 		if ctx.Err() != nil {
@@ -219,7 +228,7 @@ func (r *productRepository) CreateProduct(ctx context.Context, payload model.Pro
 func (r *productRepository) GetAllProductsWithPagination(ctx context.Context, offset, limit int) ([]model.Product, int, error) {
 	// Get total count
 	var totalCount int
-	countQuery := `SELECT COUNT(*) FROM products`
+	countQuery := `SELECT COUNT(*) FROM products WHERE deleted_at IS NULL`
 	err := r.db.QueryRowContext(ctx, countQuery).Scan(&totalCount)
 	if err != nil {
 		if ctx.Err() != nil {
@@ -231,10 +240,11 @@ func (r *productRepository) GetAllProductsWithPagination(ctx context.Context, of
 	// Get paginated results
 	query := `
 		SELECT 
-			p.id, p.product_category_id, p.name, p.description, p.image_url, p.is_active, p.created_at, p.updated_at,
+			p.id, p.product_category_id, p.name, p.description, p.image_url, p.is_active, p.version, p.created_at, p.updated_at,
 			pc.id, pc.name, pc.slug, pc.description
 		FROM products p
 		LEFT JOIN product_categories pc ON p.product_category_id = pc.id
+		WHERE p.deleted_at IS NULL
 		ORDER BY p.created_at DESC
 		LIMIT $1 OFFSET $2
 	`
@@ -265,7 +275,7 @@ func (r *productRepository) GetAllProductsWithPagination(ctx context.Context, of
 
 		err := rows.Scan(
 			&product.Id, &product.ProductCategoryId, &product.Name, &product.Description,
-			&product.ImageUrl, &product.IsActive, &product.CreatedAt, &product.UpdatedAt,
+			&product.ImageUrl, &product.IsActive, &product.Version, &product.CreatedAt, &product.UpdatedAt,
 			&categoryId, &categoryName, &categorySlug, &categoryDesc,
 		)
 		if err != nil {
@@ -296,11 +306,11 @@ func (r *productRepository) GetAllProductsWithPagination(ctx context.Context, of
 func (r *productRepository) GetProductById(ctx context.Context, id uuid.UUID) (model.Product, error) {
 	query := `
 		SELECT 
-			p.id, p.product_category_id, p.name, p.description, p.image_url, p.is_active, p.created_at, p.updated_at,
+			p.id, p.product_category_id, p.name, p.description, p.image_url, p.is_active, p.version, p.created_at, p.updated_at,
 			pc.id, pc.name, pc.slug, pc.description
 		FROM products p
 		LEFT JOIN product_categories pc ON p.product_category_id = pc.id
-		WHERE p.id = $1
+		WHERE p.id = $1 AND p.deleted_at IS NULL
 	`
 
 	var product model.Product
@@ -312,7 +322,7 @@ func (r *productRepository) GetProductById(ctx context.Context, id uuid.UUID) (m
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&product.Id, &product.ProductCategoryId, &product.Name, &product.Description,
-		&product.ImageUrl, &product.IsActive, &product.CreatedAt, &product.UpdatedAt,
+		&product.ImageUrl, &product.IsActive, &product.Version, &product.CreatedAt, &product.UpdatedAt,
 		&categoryId, &categoryName, &categorySlug, &categoryDesc,
 	)
 
@@ -340,7 +350,7 @@ func (r *productRepository) GetProductById(ctx context.Context, id uuid.UUID) (m
 func (r *productRepository) GetProductsByCategoryWithPagination(ctx context.Context, categoryId uuid.UUID, offset, limit int) ([]model.Product, int, error) {
 	// Get total count
 	var totalCount int
-	countQuery := `SELECT COUNT(*) FROM products WHERE product_category_id = $1`
+	countQuery := `SELECT COUNT(*) FROM products WHERE product_category_id = $1 AND deleted_at IS NULL`
 	err := r.db.QueryRowContext(ctx, countQuery, categoryId).Scan(&totalCount)
 	if err != nil {
 		if ctx.Err() != nil {
@@ -352,11 +362,11 @@ func (r *productRepository) GetProductsByCategoryWithPagination(ctx context.Cont
 	// Get paginated results
 	query := `
 		SELECT 
-			p.id, p.product_category_id, p.name, p.description, p.image_url, p.is_active, p.created_at, p.updated_at,
+			p.id, p.product_category_id, p.name, p.description, p.image_url, p.is_active, p.version, p.created_at, p.updated_at,
 			pc.id, pc.name, pc.slug, pc.description
 		FROM products p
 		LEFT JOIN product_categories pc ON p.product_category_id = pc.id
-		WHERE p.product_category_id = $1
+		WHERE p.product_category_id = $1 AND p.deleted_at IS NULL
 		ORDER BY p.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -387,7 +397,7 @@ func (r *productRepository) GetProductsByCategoryWithPagination(ctx context.Cont
 
 		err := rows.Scan(
 			&product.Id, &product.ProductCategoryId, &product.Name, &product.Description,
-			&product.ImageUrl, &product.IsActive, &product.CreatedAt, &product.UpdatedAt,
+			&product.ImageUrl, &product.IsActive, &product.Version, &product.CreatedAt, &product.UpdatedAt,
 			&categoryIdStr, &categoryName, &categorySlug, &categoryDesc,
 		)
 		if err != nil {
@@ -417,14 +427,14 @@ func (r *productRepository) GetProductsByCategoryWithPagination(ctx context.Cont
 // UpdateProduct implements ProductRepository
 func (r *productRepository) UpdateProduct(ctx context.Context, payload model.Product) (model.Product, error) {
 	var product model.Product
-	query := `UPDATE products SET product_category_id = $1, name = $2, description = $3, image_url = $4, is_active = $5, updated_at = $6 
-			  WHERE id = $7 
-			  RETURNING id, product_category_id, name, description, image_url, is_active, created_at, updated_at`
+	query := `UPDATE products SET product_category_id = $1, name = $2, description = $3, image_url = $4, is_active = $5, updated_at = $6, version = version + 1 
+			  WHERE id = $7 AND version = $8 AND deleted_at IS NULL
+			  RETURNING id, product_category_id, name, description, image_url, is_active, version, created_at, updated_at`
 
 	err := r.db.QueryRowContext(ctx, query, payload.ProductCategoryId, payload.Name, payload.Description,
-		payload.ImageUrl, payload.IsActive, time.Now(), payload.Id).
+		payload.ImageUrl, payload.IsActive, time.Now(), payload.Id, payload.Version).
 		Scan(&product.Id, &product.ProductCategoryId, &product.Name, &product.Description,
-			&product.ImageUrl, &product.IsActive, &product.CreatedAt, &product.UpdatedAt)
+			&product.ImageUrl, &product.IsActive, &product.Version, &product.CreatedAt, &product.UpdatedAt)
 
 	if err != nil {
 		if ctx.Err() != nil {
@@ -438,7 +448,7 @@ func (r *productRepository) UpdateProduct(ctx context.Context, payload model.Pro
 
 // DeleteProduct implements ProductRepository
 func (r *productRepository) DeleteProduct(ctx context.Context, id uuid.UUID) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM products WHERE id = $1`, id)
+	_, err := r.db.ExecContext(ctx, `UPDATE products SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id)
 	if err != nil {
 		if ctx.Err() != nil {
 			return ctx.Err()
@@ -454,10 +464,10 @@ func (r *productRepository) CreateProductAffiliateLink(ctx context.Context, payl
 
 	query := `INSERT INTO product_affiliate_links (id, product_id, platform_name, url, created_at, updated_at) 
 			  VALUES ($1, $2, $3, $4, $5, $6) 
-			  RETURNING id, product_id, platform_name, url, created_at, updated_at`
+			  RETURNING id, product_id, platform_name, url, version, created_at, updated_at`
 
 	err := r.db.QueryRowContext(ctx, query, newId, payload.ProductId, payload.PlatformName, payload.Url, time.Now(), time.Now()).
-		Scan(&link.Id, &link.ProductId, &link.PlatformName, &link.Url, &link.CreatedAt, &link.UpdatedAt)
+		Scan(&link.Id, &link.ProductId, &link.PlatformName, &link.Url, &link.Version, &link.CreatedAt, &link.UpdatedAt)
 
 	if err != nil {
 		if ctx.Err() != nil {
@@ -471,7 +481,7 @@ func (r *productRepository) CreateProductAffiliateLink(ctx context.Context, payl
 
 // GetAffiliateLinksbyProductId implements ProductRepository
 func (r *productRepository) GetAffiliateLinksbyProductId(ctx context.Context, productId uuid.UUID) ([]model.ProductAffiliateLink, error) {
-	query := `SELECT id, product_id, platform_name, url, created_at, updated_at 
+	query := `SELECT id, product_id, platform_name, url, version, created_at, updated_at 
 			  FROM product_affiliate_links 
 			  WHERE product_id = $1 
 			  ORDER BY created_at DESC`
@@ -494,7 +504,7 @@ func (r *productRepository) GetAffiliateLinksbyProductId(ctx context.Context, pr
 		}
 
 		var link model.ProductAffiliateLink
-		err := rows.Scan(&link.Id, &link.ProductId, &link.PlatformName, &link.Url, &link.CreatedAt, &link.UpdatedAt)
+		err := rows.Scan(&link.Id, &link.ProductId, &link.PlatformName, &link.Url, &link.Version, &link.CreatedAt, &link.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -511,12 +521,12 @@ func (r *productRepository) GetAffiliateLinksbyProductId(ctx context.Context, pr
 // UpdateProductAffiliateLink implements ProductRepository
 func (r *productRepository) UpdateProductAffiliateLink(ctx context.Context, payload model.ProductAffiliateLink) (model.ProductAffiliateLink, error) {
 	var link model.ProductAffiliateLink
-	query := `UPDATE product_affiliate_links SET platform_name = $1, url = $2, updated_at = $3 
-			  WHERE id = $4 
-			  RETURNING id, product_id, platform_name, url, created_at, updated_at`
+	query := `UPDATE product_affiliate_links SET platform_name = $1, url = $2, updated_at = $3, version = version + 1 
+			  WHERE id = $4 AND version = $5 
+			  RETURNING id, product_id, platform_name, url, version, created_at, updated_at`
 
-	err := r.db.QueryRowContext(ctx, query, payload.PlatformName, payload.Url, time.Now(), payload.Id).
-		Scan(&link.Id, &link.ProductId, &link.PlatformName, &link.Url, &link.CreatedAt, &link.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, payload.PlatformName, payload.Url, time.Now(), payload.Id, payload.Version).
+		Scan(&link.Id, &link.ProductId, &link.PlatformName, &link.Url, &link.Version, &link.CreatedAt, &link.UpdatedAt)
 
 	if err != nil {
 		if ctx.Err() != nil {
@@ -572,7 +582,7 @@ func (r *productRepository) RemoveProductFromArticle(ctx context.Context, articl
 func (r *productRepository) GetProductsByArticleIdWithPagination(ctx context.Context, articleId uuid.UUID, offset, limit int) ([]model.Product, int, error) {
 	// Get total count
 	var totalCount int
-	countQuery := `SELECT COUNT(*) FROM article_product WHERE article_id = $1`
+	countQuery := `SELECT COUNT(*) FROM article_product ap JOIN products p ON p.id = ap.product_id WHERE ap.article_id = $1 AND p.deleted_at IS NULL`
 	err := r.db.QueryRowContext(ctx, countQuery, articleId).Scan(&totalCount)
 	if err != nil {
 		if ctx.Err() != nil {
@@ -584,12 +594,12 @@ func (r *productRepository) GetProductsByArticleIdWithPagination(ctx context.Con
 	// Get paginated results
 	query := `
 		SELECT 
-			p.id, p.product_category_id, p.name, p.description, p.image_url, p.is_active, p.created_at, p.updated_at,
+			p.id, p.product_category_id, p.name, p.description, p.image_url, p.is_active, p.version, p.created_at, p.updated_at,
 			pc.id, pc.name, pc.slug, pc.description
 		FROM products p
 		LEFT JOIN product_categories pc ON p.product_category_id = pc.id
 		INNER JOIN article_product ap ON p.id = ap.product_id
-		WHERE ap.article_id = $1
+		WHERE ap.article_id = $1 AND p.deleted_at IS NULL
 		ORDER BY ap.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -620,7 +630,7 @@ func (r *productRepository) GetProductsByArticleIdWithPagination(ctx context.Con
 
 		err := rows.Scan(
 			&product.Id, &product.ProductCategoryId, &product.Name, &product.Description,
-			&product.ImageUrl, &product.IsActive, &product.CreatedAt, &product.UpdatedAt,
+			&product.ImageUrl, &product.IsActive, &product.Version, &product.CreatedAt, &product.UpdatedAt,
 			&categoryId, &categoryName, &categorySlug, &categoryDesc,
 		)
 		if err != nil {
@@ -651,7 +661,7 @@ func (r *productRepository) GetProductsByArticleIdWithPagination(ctx context.Con
 func (r *productRepository) GetArticlesByProductIdWithPagination(ctx context.Context, productId uuid.UUID, offset, limit int) ([]model.Article, int, error) {
 	// Get total count
 	var totalCount int
-	countQuery := `SELECT COUNT(*) FROM article_product WHERE product_id = $1`
+	countQuery := `SELECT COUNT(*) FROM article_product ap JOIN articles a ON a.id = ap.article_id WHERE ap.product_id = $1 AND ` + publicArticleCondition
 	err := r.db.QueryRowContext(ctx, countQuery, productId).Scan(&totalCount)
 	if err != nil {
 		if ctx.Err() != nil {
@@ -670,7 +680,7 @@ func (r *productRepository) GetArticlesByProductIdWithPagination(ctx context.Con
 		JOIN users u ON a.user_id = u.id
 		JOIN categories c ON a.category_id = c.id
 		INNER JOIN article_product ap ON a.id = ap.article_id
-		WHERE ap.product_id = $1
+		WHERE ap.product_id = $1 AND ` + publicArticleCondition + `
 		ORDER BY ap.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
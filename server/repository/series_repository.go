@@ -0,0 +1,392 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrSeriesOrderMismatch is returned by ReorderArticles when the new order does not
+// contain exactly the current articles of the series
+var ErrSeriesOrderMismatch = errors.New("series order must contain every article of the series exactly once")
+
+type SeriesRepository interface {
+	GetAll(ctx context.Context) ([]model.Series, error)
+	CreateSeries(ctx context.Context, payload model.Series) (model.Series, error)
+	GetSeriesById(ctx context.Context, id uuid.UUID) (model.Series, error)
+	GetSeriesBySlug(ctx context.Context, slug string) (model.Series, error)
+	UpdateSeries(ctx context.Context, payload model.Series) (model.Series, error)
+	DeleteSeries(ctx context.Context, id uuid.UUID) error
+	// GetSeriesArticles returns the articles of a series in order. With publicOnly
+	// unpublished articles are left out and the positions are renumbered.
+	GetSeriesArticles(ctx context.Context, seriesId uuid.UUID, publicOnly bool) ([]model.SeriesArticle, error)
+	// GetSeriesByArticleId returns the series containing an article with its public
+	// articles and the article itself, sql.ErrNoRows when it is in no series
+	GetSeriesByArticleId(ctx context.Context, articleId uuid.UUID) (model.Series, error)
+	AppendArticle(ctx context.Context, seriesId, articleId uuid.UUID) error
+	RemoveArticle(ctx context.Context, seriesId, articleId uuid.UUID) error
+	ReorderArticles(ctx context.Context, seriesId uuid.UUID, articleIds []uuid.UUID) error
+}
+
+const seriesColumns = `id, title, slug, description, user_id, created_at, updated_at`
+
+type seriesRepository struct {
+	db *sql.DB
+}
+
+func scanSeries(row rowScanner) (model.Series, error) {
+	var series model.Series
+	err := row.Scan(&series.Id, &series.Title, &series.Slug, &series.Description, &series.UserId, &series.CreatedAt, &series.UpdatedAt)
+	return series, err
+}
+
+// GetAll implements SeriesRepository.
+func (s *seriesRepository) GetAll(ctx context.Context) ([]model.Series, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+seriesColumns+` FROM series ORDER BY created_at DESC`)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	listSeries := []model.Series{}
+	for rows.Next() {
+		series, err := scanSeries(rows)
+		if err != nil {
+			return nil, err
+		}
+		listSeries = append(listSeries, series)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return listSeries, nil
+}
+
+// CreateSeries implements SeriesRepository.
+func (s *seriesRepository) CreateSeries(ctx context.Context, payload model.Series) (model.Series, error) {
+	newId := payload.Id
+	if newId == uuid.Nil {
+		newId = uuid.Must(uuid.NewV7())
+	}
+	now := time.Now()
+	series, err := scanSeries(s.db.QueryRowContext(ctx, `
+	INSERT INTO series (id, title, slug, description, user_id, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING `+seriesColumns,
+		newId, payload.Title, payload.Slug, payload.Description, payload.UserId, now, now))
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return model.Series{}, ctx.Err()
+		}
+		return model.Series{}, err
+	}
+
+	return series, nil
+}
+
+// GetSeriesById implements SeriesRepository.
+func (s *seriesRepository) GetSeriesById(ctx context.Context, id uuid.UUID) (model.Series, error) {
+	series, err := scanSeries(s.db.QueryRowContext(ctx, `SELECT `+seriesColumns+` FROM series WHERE id = $1`, id))
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return model.Series{}, ctx.Err()
+		}
+		return model.Series{}, err
+	}
+
+	return series, nil
+}
+
+// GetSeriesBySlug implements SeriesRepository.
+func (s *seriesRepository) GetSeriesBySlug(ctx context.Context, slug string) (model.Series, error) {
+	series, err := scanSeries(s.db.QueryRowContext(ctx, `SELECT `+seriesColumns+` FROM series WHERE slug = $1`, slug))
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return model.Series{}, ctx.Err()
+		}
+		return model.Series{}, err
+	}
+
+	return series, nil
+}
+
+// UpdateSeries implements SeriesRepository.
+func (s *seriesRepository) UpdateSeries(ctx context.Context, payload model.Series) (model.Series, error) {
+	series, err := scanSeries(s.db.QueryRowContext(ctx, `
+	UPDATE series SET title = $1, slug = $2, description = $3, updated_at = $4
+	WHERE id = $5
+	RETURNING `+seriesColumns,
+		payload.Title, payload.Slug, payload.Description, time.Now(), payload.Id))
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return model.Series{}, ctx.Err()
+		}
+		return model.Series{}, err
+	}
+
+	return series, nil
+}
+
+// DeleteSeries implements SeriesRepository.
+// The articles themselves are kept, only their membership is removed.
+func (s *seriesRepository) DeleteSeries(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM series WHERE id = $1`, id)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetSeriesArticles implements SeriesRepository.
+func (s *seriesRepository) GetSeriesArticles(ctx context.Context, seriesId uuid.UUID, publicOnly bool) ([]model.SeriesArticle, error) {
+	query := `
+	SELECT sa.position, a.id, a.title, a.slug, a.status
+	FROM series_articles sa
+	JOIN articles a ON a.id = sa.article_id
+	WHERE sa.series_id = $1 AND a.deleted_at IS NULL`
+	if publicOnly {
+		query += ` AND ` + publicArticleCondition
+	}
+	query += ` ORDER BY sa.position`
+
+	return s.querySeriesArticles(ctx, query, publicOnly, seriesId)
+}
+
+// GetSeriesByArticleId implements SeriesRepository.
+func (s *seriesRepository) GetSeriesByArticleId(ctx context.Context, articleId uuid.UUID) (model.Series, error) {
+	series, err := scanSeries(s.db.QueryRowContext(ctx, `
+	SELECT s.id, s.title, s.slug, s.description, s.user_id, s.created_at, s.updated_at
+	FROM series s
+	JOIN series_articles sa ON sa.series_id = s.id
+	WHERE sa.article_id = $1`, articleId))
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return model.Series{}, ctx.Err()
+		}
+		return model.Series{}, err
+	}
+
+	// The requested article is always included so drafts can be previewed in place
+	series.Articles, err = s.querySeriesArticles(ctx, `
+	SELECT sa.position, a.id, a.title, a.slug, a.status
+	FROM series_articles sa
+	JOIN articles a ON a.id = sa.article_id
+	WHERE sa.series_id = $1 AND (`+publicArticleCondition+` OR a.id = $2)
+	ORDER BY sa.position`, true, series.Id, articleId)
+	if err != nil {
+		return model.Series{}, err
+	}
+
+	return series, nil
+}
+
+// querySeriesArticles runs a query selecting position, id, title, slug and status.
+// With renumber the positions are replaced by consecutive part numbers.
+func (s *seriesRepository) querySeriesArticles(ctx context.Context, query string, renumber bool, args ...interface{}) ([]model.SeriesArticle, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	articles := []model.SeriesArticle{}
+	for rows.Next() {
+		var article model.SeriesArticle
+		if err := rows.Scan(&article.Position, &article.ArticleId, &article.Title, &article.Slug, &article.Status); err != nil {
+			return nil, err
+		}
+		if renumber {
+			article.Position = len(articles) + 1
+		}
+		articles = append(articles, article)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// AppendArticle implements SeriesRepository.
+// The series row is locked so concurrent appends get distinct positions.
+func (s *seriesRepository) AppendArticle(ctx context.Context, seriesId, articleId uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockSeries(ctx, tx, seriesId); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+	INSERT INTO series_articles (series_id, article_id, position)
+	SELECT $1, $2, COALESCE(MAX(position), 0) + 1 FROM series_articles WHERE series_id = $1`, seriesId, articleId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	if err := touchSeries(ctx, tx, seriesId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveArticle implements SeriesRepository.
+// The following parts move up so positions stay consecutive.
+func (s *seriesRepository) RemoveArticle(ctx context.Context, seriesId, articleId uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockSeries(ctx, tx, seriesId); err != nil {
+		return err
+	}
+
+	var position int
+	err = tx.QueryRowContext(ctx, `
+	DELETE FROM series_articles WHERE series_id = $1 AND article_id = $2
+	RETURNING position`, seriesId, articleId).Scan(&position)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+	UPDATE series_articles SET position = position - 1
+	WHERE series_id = $1 AND position > $2`, seriesId, position)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	if err := touchSeries(ctx, tx, seriesId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReorderArticles implements SeriesRepository.
+// All positions are rewritten in one statement; the unique position constraint is
+// deferred to commit so intermediate duplicates do not fail the update.
+func (s *seriesRepository) ReorderArticles(ctx context.Context, seriesId uuid.UUID, articleIds []uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockSeries(ctx, tx, seriesId); err != nil {
+		return err
+	}
+
+	ids := make([]string, len(articleIds))
+	for i, id := range articleIds {
+		ids[i] = id.String()
+	}
+
+	var matches bool
+	err = tx.QueryRowContext(ctx, `
+	SELECT
+		COUNT(*) = cardinality($2::uuid[])
+		AND COUNT(*) = (SELECT COUNT(DISTINCT id) FROM unnest($2::uuid[]) AS id)
+		AND COALESCE(bool_and(article_id = ANY($2::uuid[])), TRUE)
+	FROM series_articles WHERE series_id = $1`, seriesId, pq.Array(ids)).Scan(&matches)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	if !matches {
+		return ErrSeriesOrderMismatch
+	}
+
+	_, err = tx.ExecContext(ctx, `
+	UPDATE series_articles sa SET position = o.position
+	FROM unnest($2::uuid[]) WITH ORDINALITY AS o(article_id, position)
+	WHERE sa.series_id = $1 AND sa.article_id = o.article_id`, seriesId, pq.Array(ids))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	if err := touchSeries(ctx, tx, seriesId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockSeries locks the series row for the rest of the transaction,
+// sql.ErrNoRows when the series does not exist
+func lockSeries(ctx context.Context, tx *sql.Tx, seriesId uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, `SELECT id FROM series WHERE id = $1 FOR UPDATE`, seriesId).Scan(&id)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// touchSeries bumps updated_at after the membership changed
+func touchSeries(ctx context.Context, tx *sql.Tx, seriesId uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `UPDATE series SET updated_at = NOW() WHERE id = $1`, seriesId)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func NewSeriesRepository(database *sql.DB) SeriesRepository {
+	return &seriesRepository{db: database}
+}
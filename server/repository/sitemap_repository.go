@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model/dto"
+	"time"
+)
+
+type SitemapRepository interface {
+	CountEntries(ctx context.Context) (int, time.Time, error)
+	EachEntry(ctx context.Context, offset, limit int, fn func(entry dto.SitemapEntry) error) error
+}
+
+// sitemapEntriesQuery lists every public page in a stable order: published articles,
+// categories and tags that have published articles, and all product categories
+const sitemapEntriesQuery = `
+	WITH entries AS (
+		SELECT 1 AS kind_order, 'article' AS kind, a.slug AS key, a.updated_at AS last_mod
+		FROM articles a
+		WHERE ` + publicArticleCondition + `
+		UNION ALL
+		SELECT 2, 'category', c.name, c.updated_at
+		FROM categories c
+		WHERE EXISTS (SELECT 1 FROM articles a WHERE a.category_id = c.id AND ` + publicArticleCondition + `)
+		UNION ALL
+		SELECT 3, 'tag', t.name, t.updated_at
+		FROM tags t
+		WHERE EXISTS (
+			SELECT 1 FROM article_tags at
+			JOIN articles a ON at.article_id = a.id
+			WHERE at.tag_id = t.id AND ` + publicArticleCondition + `)
+		UNION ALL
+		SELECT 4, 'product_category', pc.slug, pc.updated_at
+		FROM product_categories pc
+	)`
+
+type sitemapRepository struct {
+	db *sql.DB
+}
+
+// CountEntries implements SitemapRepository.
+// It returns the number of sitemap entries and the latest change among them.
+func (r *sitemapRepository) CountEntries(ctx context.Context) (int, time.Time, error) {
+	var count int
+	var lastMod time.Time
+	err := r.db.QueryRowContext(ctx, sitemapEntriesQuery+`
+	SELECT COUNT(*), COALESCE(MAX(last_mod), 'epoch'::timestamptz) FROM entries`).Scan(&count, &lastMod)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return 0, time.Time{}, ctx.Err()
+		}
+		return 0, time.Time{}, err
+	}
+	return count, lastMod, nil
+}
+
+// EachEntry implements SitemapRepository.
+// Rows are passed to fn as they are read, so callers can stream them without
+// collecting the whole sitemap first. Iteration stops at the first error of fn.
+func (r *sitemapRepository) EachEntry(ctx context.Context, offset, limit int, fn func(entry dto.SitemapEntry) error) error {
+	rows, err := r.db.QueryContext(ctx, sitemapEntriesQuery+`
+	SELECT kind, key, last_mod FROM entries
+	ORDER BY kind_order, key
+	LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		// Check for context cancellation during iteration
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		var entry dto.SitemapEntry
+		if err := rows.Scan(&entry.Kind, &entry.Key, &entry.LastMod); err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func NewSitemapRepository(database *sql.DB) SitemapRepository {
+	return &sitemapRepository{db: database}
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Tables with a unique slug column, the slug constraint of each is named <table>_slug_key
+const (
+	SlugTableArticles          = "articles"
+	SlugTableCategories        = "categories"
+	SlugTableProductCategories = "product_categories"
+	SlugTableSeries            = "series"
+)
+
+type SlugRepository interface {
+	// GetTakenSlugs returns the slugs of table that equal base or extend it with a suffix,
+	// the row ownerId is left out so a row never collides with itself
+	GetTakenSlugs(ctx context.Context, table, base string, ownerId uuid.UUID) ([]string, error)
+}
+
+// takenSlugQueries selects the slugs in use per table. Old article slugs still redirect
+// to their article and count as taken by it.
+var takenSlugQueries = map[string]string{
+	SlugTableArticles: `
+	SELECT slug FROM articles WHERE (slug = $1 OR slug LIKE $1 || '-%') AND id <> $2
+	UNION
+	SELECT slug FROM article_slug_history WHERE (slug = $1 OR slug LIKE $1 || '-%') AND article_id <> $2`,
+	SlugTableCategories:        `SELECT slug FROM categories WHERE (slug = $1 OR slug LIKE $1 || '-%') AND id <> $2`,
+	SlugTableProductCategories: `SELECT slug FROM product_categories WHERE (slug = $1 OR slug LIKE $1 || '-%') AND id <> $2`,
+	SlugTableSeries:            `SELECT slug FROM series WHERE (slug = $1 OR slug LIKE $1 || '-%') AND id <> $2`,
+}
+
+type slugRepository struct {
+	db *sql.DB
+}
+
+// GetTakenSlugs implements SlugRepository.
+// Slugs only hold lowercase letters, digits and hyphens, so base needs no LIKE escaping.
+func (s *slugRepository) GetTakenSlugs(ctx context.Context, table, base string, ownerId uuid.UUID) ([]string, error) {
+	query, ok := takenSlugQueries[table]
+	if !ok {
+		return nil, fmt.Errorf("table %q has no slug column", table)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, base, ownerId)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	var slugs []string
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		slugs = append(slugs, slug)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return slugs, nil
+}
+
+func NewSlugRepository(database *sql.DB) SlugRepository {
+	return &slugRepository{db: database}
+}
@@ -14,6 +14,8 @@ type TagRepository interface {
 	GetAllTag(ctx context.Context) ([]model.Tags, error)
 	GetTagById(ctx context.Context, id uuid.UUID) (model.Tags, error)
 	GetTagByName(ctx context.Context, name string) (model.Tags, error)
+	// UpdateTag applies only while payload.Version is the stored version and bumps it,
+	// a stale version answers sql.ErrNoRows
 	UpdateTag(ctx context.Context, payload model.Tags) (model.Tags, error)
 	DeleteTag(ctx context.Context, id uuid.UUID) error
 }
@@ -26,7 +28,7 @@ type tagRepository struct {
 func (t *tagRepository) GetTagByName(ctx context.Context, name string) (model.Tags, error) {
 	var tag model.Tags
 
-	err := t.db.QueryRowContext(ctx, `SELECT id, name, created_at, updated_at FROM tags WHERE name = $1`, name).Scan(&tag.Id, &tag.Name, &tag.CreatedAt, &tag.UpdatedAt)
+	err := t.db.QueryRowContext(ctx, `SELECT id, name, version, created_at, updated_at FROM tags WHERE name = $1`, name).Scan(&tag.Id, &tag.Name, &tag.Version, &tag.CreatedAt, &tag.UpdatedAt)
 	if err != nil {
 		return model.Tags{}, err
 	}
@@ -38,7 +40,7 @@ func (t *tagRepository) GetTagByName(ctx context.Context, name string) (model.Ta
 func (t *tagRepository) CreateTag(ctx context.Context, payload model.Tags) (model.Tags, error) {
 	newId := uuid.Must(uuid.NewV7())
 	var tag model.Tags
-	err := t.db.QueryRowContext(ctx, `INSERT INTO tags (id, name, created_at, updated_at) VALUES($1, $2, $3, $4) RETURNING id, name, created_at, updated_at`, newId, payload.Name, time.Now(), time.Now()).Scan(&tag.Id, &tag.Name, &tag.CreatedAt, &tag.UpdatedAt)
+	err := t.db.QueryRowContext(ctx, `INSERT INTO tags (id, name, created_at, updated_at) VALUES($1, $2, $3, $4) RETURNING id, name, version, created_at, updated_at`, newId, payload.Name, time.Now(), time.Now()).Scan(&tag.Id, &tag.Name, &tag.Version, &tag.CreatedAt, &tag.UpdatedAt)
 	if err != nil {
 		return model.Tags{}, err
 	}
@@ -49,7 +51,7 @@ func (t *tagRepository) CreateTag(ctx context.Context, payload model.Tags) (mode
 func (t *tagRepository) GetAllTag(ctx context.Context) ([]model.Tags, error) {
 	var listTag []model.Tags
 
-	rows, err := t.db.QueryContext(ctx, `SELECT id, name, created_at, updated_at FROM tags`)
+	rows, err := t.db.QueryContext(ctx, `SELECT id, name, version, created_at, updated_at FROM tags`)
 	if err != nil {
 		return nil, err
 	}
@@ -61,6 +63,7 @@ func (t *tagRepository) GetAllTag(ctx context.Context) ([]model.Tags, error) {
 		err := rows.Scan(
 			&tag.Id,
 			&tag.Name,
+			&tag.Version,
 			&tag.CreatedAt,
 			&tag.UpdatedAt,
 		)
@@ -83,7 +86,7 @@ func (t *tagRepository) GetAllTag(ctx context.Context) ([]model.Tags, error) {
 func (t *tagRepository) GetTagById(ctx context.Context, id uuid.UUID) (model.Tags, error) {
 	var tag model.Tags
 
-	err := t.db.QueryRowContext(ctx, `SELECT id, name, created_at, updated_at FROM tags WHERE id = $1`, id).Scan(&tag.Id, &tag.Name, &tag.CreatedAt, &tag.UpdatedAt)
+	err := t.db.QueryRowContext(ctx, `SELECT id, name, version, created_at, updated_at FROM tags WHERE id = $1`, id).Scan(&tag.Id, &tag.Name, &tag.Version, &tag.CreatedAt, &tag.UpdatedAt)
 	if err != nil {
 		return model.Tags{}, err
 	}
@@ -94,7 +97,7 @@ func (t *tagRepository) GetTagById(ctx context.Context, id uuid.UUID) (model.Tag
 // UpdateTag implements TagRepository.
 func (t *tagRepository) UpdateTag(ctx context.Context, payload model.Tags) (model.Tags, error) {
 	var tag model.Tags
-	err := t.db.QueryRowContext(ctx, `UPDATE tags SET name = $1, updated_at = $2 WHERE id = $3 RETURNING id, name, created_at, updated_at`, payload.Name, time.Now(), payload.Id).Scan(&tag.Id, &tag.Name, &tag.CreatedAt, &tag.UpdatedAt)
+	err := t.db.QueryRowContext(ctx, `UPDATE tags SET name = $1, updated_at = $2, version = version + 1 WHERE id = $3 AND version = $4 RETURNING id, name, version, created_at, updated_at`, payload.Name, time.Now(), payload.Id, payload.Version).Scan(&tag.Id, &tag.Name, &tag.Version, &tag.CreatedAt, &tag.UpdatedAt)
 	if err != nil {
 		return model.Tags{}, err
 	}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashRepository interface {
+	// GetTrash lists the soft deleted items of the given types, most recently deleted first
+	GetTrash(ctx context.Context, itemTypes []string, offset, limit int) ([]model.TrashItem, int, error)
+	// Restore takes an item out of the trash, sql.ErrNoRows when it is not in the trash.
+	// It returns the article the item belongs to, uuid.Nil for products.
+	Restore(ctx context.Context, itemType string, id uuid.UUID) (uuid.UUID, error)
+	// Purge permanently deletes the items trashed before cutoff and returns how many
+	// were deleted per type. Rows related to a purged article or product cascade with it.
+	// It deletes nothing when another instance is purging at the same time.
+	Purge(ctx context.Context, cutoff time.Time) (map[string]int64, error)
+}
+
+// trashPurgeLockKey is the advisory lock id guarding the trash purge across instances
+const trashPurgeLockKey = 727003
+
+// trashTable describes how an item type is stored
+type trashTable struct {
+	table string
+	title string
+	// keep is an extra condition for the purge, matching rows stay past their retention
+	keep string
+	// article is the column holding the article an item belongs to, empty for products
+	article string
+}
+
+// trashTables maps the item types to their tables. Comments are purged first so those
+// left on a purged article are not counted twice.
+var trashTables = map[string]trashTable{
+	model.TrashTypeComment: {table: "comments", title: "LEFT(content, 100)",
+		// A deleted comment with replies is shown as a tombstone holding its thread together
+		keep:    "EXISTS (SELECT 1 FROM comments reply WHERE reply.parent_comment_id = comments.id)",
+		article: "article_id"},
+	model.TrashTypeArticle: {table: "articles", title: "title", article: "id"},
+	model.TrashTypeProduct: {table: "products", title: "name"},
+}
+
+// TrashTypes are the item types of the trash in purge order
+var TrashTypes = []string{model.TrashTypeComment, model.TrashTypeArticle, model.TrashTypeProduct}
+
+type trashRepository struct {
+	db *sql.DB
+}
+
+// trashQuery selects type, id, title and deleted_at of the trashed items of the given types
+func trashQuery(itemTypes []string) (string, error) {
+	parts := make([]string, 0, len(itemTypes))
+	for _, itemType := range itemTypes {
+		t, ok := trashTables[itemType]
+		if !ok {
+			return "", fmt.Errorf("unknown trash item type %q", itemType)
+		}
+		parts = append(parts, fmt.Sprintf(`SELECT '%s' AS type, id, %s AS title, deleted_at FROM %s WHERE deleted_at IS NOT NULL`, itemType, t.title, t.table))
+	}
+	return strings.Join(parts, "\n\tUNION ALL\n\t"), nil
+}
+
+// GetTrash implements TrashRepository.
+func (r *trashRepository) GetTrash(ctx context.Context, itemTypes []string, offset, limit int) ([]model.TrashItem, int, error) {
+	union, err := trashQuery(itemTypes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM (`+union+`) trash`).Scan(&totalCount); err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT type, id, title, deleted_at FROM (`+union+`) trash
+	ORDER BY deleted_at DESC, id
+	LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []model.TrashItem{}
+	for rows.Next() {
+		var item model.TrashItem
+		if err := rows.Scan(&item.Type, &item.Id, &item.Title, &item.DeletedAt); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return items, totalCount, nil
+}
+
+// Restore implements TrashRepository.
+// Related rows such as tags, likes, bookmarks and affiliate links are never removed by a
+// soft delete, clearing deleted_at brings them back with the item.
+func (r *trashRepository) Restore(ctx context.Context, itemType string, id uuid.UUID) (uuid.UUID, error) {
+	t, ok := trashTables[itemType]
+	if !ok {
+		return uuid.Nil, fmt.Errorf("unknown trash item type %q", itemType)
+	}
+
+	article := "NULL::uuid"
+	if t.article != "" {
+		article = t.article
+	}
+
+	// No row is returned when the item is not in the trash
+	var articleId uuid.NullUUID
+	err := r.db.QueryRowContext(ctx, `UPDATE `+t.table+` SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING `+article, id).Scan(&articleId)
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if ctx.Err() != nil {
+			return uuid.Nil, ctx.Err()
+		}
+		return uuid.Nil, err
+	}
+
+	return articleId.UUID, nil
+}
+
+// Purge implements TrashRepository.
+func (r *trashRepository) Purge(ctx context.Context, cutoff time.Time) (map[string]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var acquired bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, trashPurgeLockKey).Scan(&acquired); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	if !acquired {
+		// Another instance is purging the trash right now
+		return map[string]int64{}, nil
+	}
+
+	purged := make(map[string]int64, len(TrashTypes))
+	for _, itemType := range TrashTypes {
+		t := trashTables[itemType]
+		query := `DELETE FROM ` + t.table + ` WHERE deleted_at < $1`
+		if t.keep != "" {
+			query += ` AND NOT ` + t.keep
+		}
+		result, err := tx.ExecContext(ctx, query, cutoff)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+		if purged[itemType], err = result.RowsAffected(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return purged, nil
+}
+
+func NewTrashRepository(database *sql.DB) TrashRepository {
+	return &trashRepository{db: database}
+}
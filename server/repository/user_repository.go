@@ -22,6 +22,8 @@ type UserRepository interface {
 	DeleteAllRefreshTOkensByUser(ctx context.Context, userId uuid.UUID) error
 	FindRefreshToken(ctx context.Context, token string) (model.RefreshToken, error)
 	UpdateRefreshToken(ctx context.Context, oldToken string, newToken string, expiresAt time.Time) error
+	// UpdateUser applies only while payload.Version is the stored version and bumps it,
+	// a stale version answers sql.ErrNoRows
 	UpdateUser(ctx context.Context, payload model.User) (model.User, error)
 	DeleteUser(ctx context.Context, id uuid.UUID) error
 }
@@ -100,7 +102,7 @@ func (u *userRepository) ValidateRefreshToken(ctx context.Context, token string)
 func (u *userRepository) GetByEmail(ctx context.Context, email string) (model.User, error) {
 	var user model.User
 
-	err := u.db.QueryRowContext(ctx, `SELECT id, name, email, password, role, created_at, updated_at FROM users WHERE email=$1`, email).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	err := u.db.QueryRowContext(ctx, `SELECT id, name, email, password, role, version, created_at, updated_at FROM users WHERE email=$1`, email).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.Version, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		// Check if context was cancelled or timed out
@@ -117,7 +119,7 @@ func (u *userRepository) GetByEmail(ctx context.Context, email string) (model.Us
 func (u *userRepository) GetAllUser(ctx context.Context) ([]model.User, error) {
 	var listUser []model.User
 
-	rows, err := u.db.QueryContext(ctx, `SELECT id, name, email, password, role, created_at, updated_at FROM users`)
+	rows, err := u.db.QueryContext(ctx, `SELECT id, name, email, password, role, version, created_at, updated_at FROM users`)
 	if err != nil {
 		// Check if context was cancelled or timed out
 		if ctx.Err() != nil {
@@ -143,6 +145,7 @@ func (u *userRepository) GetAllUser(ctx context.Context) ([]model.User, error) {
 			&user.Email,
 			&user.Password,
 			&user.Role,
+			&user.Version,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -177,7 +180,7 @@ func (u *userRepository) GetAllUserWithPagination(ctx context.Context, offset, l
 
 	// Then get the paginated results
 	var listUser []model.User
-	query := `SELECT id, name, email, password, role, created_at, updated_at FROM users ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+	query := `SELECT id, name, email, password, role, version, created_at, updated_at FROM users ORDER BY created_at DESC LIMIT $1 OFFSET $2`
 	rows, err := u.db.QueryContext(ctx, query, limit, offset)
 	if err != nil {
 		// Check if context was cancelled or timed out
@@ -203,6 +206,7 @@ func (u *userRepository) GetAllUserWithPagination(ctx context.Context, offset, l
 			&user.Email,
 			&user.Password,
 			&user.Role,
+			&user.Version,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -225,7 +229,7 @@ func (u *userRepository) GetAllUserWithPagination(ctx context.Context, offset, l
 func (u *userRepository) GetUserById(ctx context.Context, id uuid.UUID) (model.User, error) {
 	var user model.User
 
-	err := u.db.QueryRowContext(ctx, `SELECT id, name, email, password, role, created_at, updated_at FROM users WHERE id=$1`, id).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	err := u.db.QueryRowContext(ctx, `SELECT id, name, email, password, role, version, created_at, updated_at FROM users WHERE id=$1`, id).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.Version, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		// Check if context was cancelled or timed out
@@ -242,7 +246,7 @@ func (u *userRepository) CreateNewUser(ctx context.Context, payload model.User)
 	newId := uuid.Must(uuid.NewV7())
 
 	var user model.User
-	err := u.db.QueryRowContext(ctx, `INSERT INTO users (id, name, email, password, role,created_at, updated_at) VALUES($1, $2, $3, $4, $5,$6, $7) RETURNING id, name, email, role, created_at, updated_at`, newId, payload.Name, payload.Email, payload.Password, payload.Role, time.Now(), time.Now()).Scan(&user.Id, &user.Name, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	err := u.db.QueryRowContext(ctx, `INSERT INTO users (id, name, email, password, role,created_at, updated_at) VALUES($1, $2, $3, $4, $5,$6, $7) RETURNING id, name, email, role, version, created_at, updated_at`, newId, payload.Name, payload.Email, payload.Password, payload.Role, time.Now(), time.Now()).Scan(&user.Id, &user.Name, &user.Email, &user.Role, &user.Version, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		// Check if context was cancelled or timed out
 		if ctx.Err() != nil {
@@ -256,7 +260,7 @@ func (u *userRepository) CreateNewUser(ctx context.Context, payload model.User)
 // UpdateUser implements UserRepository.
 func (u *userRepository) UpdateUser(ctx context.Context, payload model.User) (model.User, error) {
 	var user model.User
-	err := u.db.QueryRowContext(ctx, `UPDATE users SET name = $1, email = $2, password = $3, updated_at = $4 WHERE id = $5 RETURNING id, name, email, role, created_at, updated_at`, payload.Name, payload.Email, payload.Password, time.Now(), payload.Id).Scan(&user.Id, &user.Name, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	err := u.db.QueryRowContext(ctx, `UPDATE users SET name = $1, email = $2, password = $3, updated_at = $4, version = version + 1 WHERE id = $5 AND version = $6 RETURNING id, name, email, role, version, created_at, updated_at`, payload.Name, payload.Email, payload.Password, time.Now(), payload.Id, payload.Version).Scan(&user.Id, &user.Name, &user.Email, &user.Role, &user.Version, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		// Check if context was cancelled or timed out
 		if ctx.Err() != nil {
@@ -9,14 +9,19 @@ import (
 	"develapar-server/repository"
 	"develapar-server/service"
 	"develapar-server/utils"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -57,34 +62,58 @@ type Server struct {
 	uS          service.UserService
 	cS          service.CategoryService
 	aS          service.ArticleService
+	raS         service.RelatedArticleService
+	trS         service.ArticleTrendingService
+	seS         service.SeriesService
+	arS         service.ArticleRevisionService
+	apS         service.ArticlePreviewService
 	bS          service.BookmarkService
 	tS          service.TagService
 	atS         service.ArticleTagService
 	coS         service.CommentService
+	cmS         service.CommentModerationService
 	lS          service.LikeService
 	pS          service.ProductService
+	fS          service.FeedService
+	smS         service.SitemapService
+	trashS      service.TrashService
 	jS          service.JwtService
 	mD          middleware.AuthMiddleware
+	rlMD        *middleware.RateLimitMiddleware
 	eMD         middleware.ErrorHandler
 	hC          *controller.HealthController
 	mC          *controller.MetricsController
 	poolManager config.ConnectionPoolManager
+	jobRunner   service.JobRunner
+	viewTracker service.ArticleViewTracker
 	engine      *gin.Engine
 	portApp     string
+
+	shutdownTimeout time.Duration
 }
 
 func (s *Server) initiateRoute() {
 	routerGroup := s.engine.Group("/api/v1")
 	controller.NewUserController(s.uS, s.mD, routerGroup, s.eMD).Route()
 	controller.NewCategoryController(s.cS, routerGroup, s.mD, s.eMD).Route()
-	controller.NewArticleController(s.aS, s.mD, routerGroup, s.eMD).Route()
+	controller.NewArticleController(s.aS, s.raS, s.trS, s.seS, s.apS, s.viewTracker, s.mD, s.rlMD, routerGroup, s.eMD).Route()
+	controller.NewArticleRevisionController(s.arS, s.aS, s.mD, routerGroup, s.eMD).Route()
+	controller.NewArticlePreviewController(s.apS, s.aS, s.mD, routerGroup, s.eMD).Route()
+	controller.NewTrashController(s.trashS, s.mD, routerGroup, s.eMD).Route()
+	controller.NewSeriesController(s.seS, s.aS, s.mD, routerGroup, s.eMD).Route()
 	controller.NewBookmarkController(s.bS, routerGroup, s.mD, s.eMD).Route()
 	controller.NewTagController(s.tS, routerGroup, s.mD, s.eMD).Route()
 	controller.NewArticleTagController(s.atS, routerGroup, s.mD, s.eMD).Route()
-	controller.NewCommentController(s.coS, routerGroup, s.mD, s.eMD).Route()
+	controller.NewCommentController(s.coS, s.aS, routerGroup, s.mD, s.eMD).Route()
+	controller.NewCommentModerationController(s.cmS, s.mD, routerGroup, s.eMD).Route()
 	controller.NewLikeController(s.lS, routerGroup, s.mD, s.eMD).Route()
 	controller.NewProductController(s.pS, routerGroup, s.mD, s.eMD).Route()
 
+	// Syndication feeds, sitemap and robots.txt are served from the site root, not the versioned API
+	siteGroup := s.engine.Group("")
+	controller.NewFeedController(s.fS, siteGroup, s.eMD).Route()
+	controller.NewSitemapController(s.smS, siteGroup, s.eMD).Route()
+
 	// Health check routes (no authentication required)
 	s.hC.Route(routerGroup)
 
@@ -97,7 +126,48 @@ func (s *Server) initiateRoute() {
 
 func (s *Server) Start() {
 	s.initiateRoute()
-	s.engine.Run(s.portApp)
+
+	// Stop on SIGINT/SIGTERM so in-flight requests and background jobs can finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s.jobRunner.Start(ctx)
+
+	httpServer := &http.Server{
+		Addr:    s.portApp,
+		Handler: s.engine,
+	}
+
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("failed to start server: %v", err)
+		}
+	}()
+	log.Printf("Server listening on %s", s.portApp)
+
+	<-ctx.Done()
+	log.Printf("Shutting down server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP server shutdown error: %v", err)
+	}
+	if err := s.jobRunner.Stop(shutdownCtx); err != nil {
+		log.Printf("Background jobs shutdown error: %v", err)
+	}
+	// Write views still buffered in memory before the database pool closes
+	if s.viewTracker != nil {
+		if err := s.viewTracker.Close(shutdownCtx); err != nil {
+			log.Printf("Article view flush error: %v", err)
+		}
+	}
+	if err := s.poolManager.Close(shutdownCtx); err != nil {
+		log.Printf("Database pool shutdown error: %v", err)
+	}
+
+	log.Printf("Server stopped")
 }
 
 // parseLogLevel converts string log level to utils.LogLevel
@@ -118,6 +188,28 @@ func parseLogLevel(level string) utils.LogLevel {
 	}
 }
 
+// newReadCache creates the cache behind the cached services, an in-process LRU or Redis
+func newReadCache(ctx context.Context, cacheConfig config.CacheConfig) (utils.Cache, error) {
+	if cacheConfig.Driver != "redis" {
+		return utils.NewLRUCache(cacheConfig.MaxEntries), nil
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     cacheConfig.RedisAddr,
+		Password: cacheConfig.RedisPassword,
+		DB:       cacheConfig.RedisDB,
+	})
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %v", err)
+	}
+
+	return utils.NewRedisCache(client, cacheConfig.KeyPrefix), nil
+}
+
 // CORSMiddleware adalah middleware yang akan menangani CORS
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -125,7 +217,8 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, If-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -258,14 +351,25 @@ func NewServer() *Server {
 	userRepo := repository.NewUserRepository(db)
 	categoryRepo := repository.NewCategoryRepository(db)
 	articleRepo := repository.NewArticleRepository(db)
+	articleRevisionRepo := repository.NewArticleRevisionRepository(db)
+	articlePreviewRepo := repository.NewArticlePreviewRepository(db)
 	bookmarkRepo := repository.NewBookmarkRepository(db)
 	tagRepo := repository.NewTagRepository(db)
 	articleTagRepo := repository.NewArticleTagRepository(db)
+	articleTrendingRepo := repository.NewArticleTrendingRepository(db)
+	seriesRepo := repository.NewSeriesRepository(db)
+	articleAuthorRepo := repository.NewArticleAuthorRepository(db)
 	commentRepo := repository.NewCommentRepository(db)
+	commentModerationRepo := repository.NewCommentModerationRepository(db)
 	likeRepo := repository.NewLikeRepository(db)
 	productRepo := repository.NewProductRepository(db)
+	articleViewRepo := repository.NewArticleViewRepository(db)
+	sitemapRepo := repository.NewSitemapRepository(db)
+	slugRepo := repository.NewSlugRepository(db)
+	trashRepo := repository.NewTrashRepository(db)
 
 	passwordHasher := utils.NewPasswordHasher()
+	markdownRenderer := utils.NewMarkdownRenderer()
 	jwtService := service.NewJwtService(co.SecurityConfig)
 
 	// Initialize error wrapper and validation service for pagination
@@ -273,15 +377,86 @@ func NewServer() *Server {
 	validationService := service.NewValidationService(errorWrapper)
 	paginationService := service.NewPaginationService(validationService, errorWrapper)
 
-	userService := service.NewUserservice(userRepo, jwtService, passwordHasher, paginationService, validationService)
-	categoryService := service.NewCategoryService(categoryRepo, validationService)
-	articleTagService := service.NewArticleTagService(tagRepo, articleTagRepo, validationService)
-	articleService := service.NewArticleService(articleRepo, articleTagService, paginationService, validationService)
+	userService := service.NewUserservice(userRepo, jwtService, passwordHasher, paginationService, validationService, errorWrapper)
+	slugAllocator := service.NewSlugAllocator(slugRepo, errorWrapper)
+	categoryService := service.NewCategoryService(categoryRepo, validationService, slugAllocator, errorWrapper)
+	articleTrendingService := service.NewArticleTrendingService(articleTrendingRepo, paginationService, co.TrendingConfig.Gravity, errorWrapper)
+	seriesService := service.NewSeriesService(seriesRepo, slugAllocator, loggerFactory.GetLogger("series"), errorWrapper)
+	// Writes publish cache events, the read cache subscribes to them below when it is enabled
+	cacheEvents := service.NewCacheEventBus()
+	// Related articles are always cached, every write to an article drops the lists it is in
+	relatedCache := utils.NewLRUCache(service.MaxCachedRelated)
+	cacheEvents.Subscribe(service.NewCacheInvalidator(relatedCache, loggerFactory.GetLogger("related_articles")))
+	relatedArticleService := service.NewRelatedArticleService(articleRepo, articleTagRepo, relatedCache, metricsService, loggerFactory.GetLogger("related_articles"), errorWrapper)
+	articleTagService := service.NewArticleTagService(tagRepo, articleTagRepo, validationService, cacheEvents)
+	articleService := service.NewPublishingArticleService(
+		service.NewArticleService(articleRepo, articleAuthorRepo, articleTagService, paginationService, validationService, slugAllocator, passwordHasher, jwtService, markdownRenderer, errorWrapper),
+		cacheEvents,
+	)
+	articleRevisionService := service.NewArticleRevisionService(articleRevisionRepo, articleRepo, slugAllocator, markdownRenderer, cacheEvents, errorWrapper)
+	articlePreviewService := service.NewArticlePreviewService(articlePreviewRepo, articleRepo, jwtService, co.SiteConfig, errorWrapper)
 	bookmarkService := service.NewBookmarkService(bookmarkRepo, validationService)
-	tagService := service.NewTagService(tagRepo, validationService)
-	commentService := service.NewCommentService(commentRepo, validationService)
+	tagService := service.NewTagService(tagRepo, validationService, errorWrapper)
+	// New comments are scored for spam, the classifier learns from past and future moderator decisions
+	spamClassifier := service.NewDisabledSpamClassifier()
+	if co.SpamConfig.Enabled {
+		spamClassifier = service.NewSpamClassifier(commentModerationRepo, rateLimitStore, co.SpamConfig, loggerFactory.GetLogger("spam"))
+		trained, err := spamClassifier.Train(ctx)
+		if err != nil {
+			log.Printf("Spam classifier training error: %v", err)
+		} else {
+			log.Printf("Spam classifier trained with %d moderated comments", trained)
+		}
+	}
+	commentModerationService := service.NewCommentModerationService(commentModerationRepo, paginationService, co.CommentConfig.ModerationPolicy, co.CommentConfig.TrustedApprovedCount, spamClassifier, cacheEvents, errorWrapper)
+	commentService := service.NewCommentService(commentRepo, commentModerationService, spamClassifier, cacheEvents, validationService, errorWrapper, co.CommentConfig)
 	likeService := service.NewLikeService(likeRepo, validationService)
-	productService := service.NewProductService(productRepo, validationService, paginationService)
+	productService := service.NewProductService(productRepo, validationService, paginationService, slugAllocator, errorWrapper)
+	feedService := service.NewFeedService(articleRepo, categoryRepo, tagRepo, userRepo, markdownRenderer, co.SiteConfig, errorWrapper)
+	sitemapService := service.NewSitemapService(sitemapRepo, co.SiteConfig, errorWrapper)
+	trashService := service.NewTrashService(trashRepo, paginationService, cacheEvents, co.TrashConfig.RetentionDays, errorWrapper)
+
+	// Hot lookups are served from the read cache, writes drop the entries they made stale
+	if co.CacheConfig.Enabled {
+		readCache, err := newReadCache(ctx, co.CacheConfig)
+		if err != nil {
+			log.Fatalf("failed to initialize read cache: %v", err)
+		}
+		cacheLogger := loggerFactory.GetLogger("cache")
+		cacheEvents.Subscribe(service.NewCacheInvalidator(readCache, cacheLogger))
+
+		articleService = service.NewCachedArticleService(articleService, readCache, metricsService, co.CacheConfig.TTL, cacheLogger)
+		categoryService = service.NewCachedCategoryService(categoryService, readCache, cacheEvents, metricsService, co.CacheConfig.TTL, cacheLogger)
+		tagService = service.NewCachedTagService(tagService, readCache, cacheEvents, metricsService, co.CacheConfig.TTL, cacheLogger)
+		productService = service.NewCachedProductService(productService, readCache, cacheEvents, metricsService, co.CacheConfig.TTL, cacheLogger)
+		log.Printf("Read cache initialized with %s driver", co.CacheConfig.Driver)
+	}
+
+	// Initialize background jobs, started and stopped together with the HTTP server
+	jobRunner := service.NewJobRunner(loggerFactory.GetLogger("jobs"), co.SchedulerConfig.JobTimeout)
+	if co.SchedulerConfig.Enabled {
+		jobRunner.Register(service.NewArticleScheduleJob(articleRepo, cacheEvents, loggerFactory.GetLogger("article_scheduler"), co.SchedulerConfig.PublishInterval))
+	}
+
+	// Articles written before Markdown rendering was introduced get their stored output once
+	jobRunner.Register(service.NewArticleRenderJob(articleRepo, markdownRenderer, cacheEvents, loggerFactory.GetLogger("article_render")))
+
+	// Article views are buffered in memory and written in batches by a background job
+	var viewTracker service.ArticleViewTracker
+	if co.ViewTrackingConfig.Enabled {
+		viewTracker = service.NewArticleViewTracker(articleViewRepo, loggerFactory.GetLogger("article_views"), co.ViewTrackingConfig.DedupWindow, co.ViewTrackingConfig.MaxBufferSize)
+		jobRunner.Register(service.NewArticleViewFlushJob(viewTracker, co.ViewTrackingConfig.FlushInterval))
+	}
+
+	// Trending scores are stored so GET /articles/trending stays a cheap indexed read
+	if co.TrendingConfig.Enabled {
+		jobRunner.Register(service.NewArticleTrendingJob(articleTrendingService, loggerFactory.GetLogger("article_trending"), co.TrendingConfig.RecomputeInterval))
+	}
+
+	// Deleted articles, comments and products stay restorable until the retention period ends
+	if co.TrashConfig.Enabled {
+		jobRunner.Register(service.NewTrashPurgeJob(trashService, loggerFactory.GetLogger("trash_purge"), co.TrashConfig.PurgeInterval))
+	}
 
 	authMiddleware := middleware.NewAuthMiddleware(jwtService)
 	healthController := controller.NewHealthController(poolManager)
@@ -290,19 +465,33 @@ func NewServer() *Server {
 		cS:          categoryService,
 		uS:          userService,
 		aS:          articleService,
+		raS:         relatedArticleService,
+		trS:         articleTrendingService,
+		seS:         seriesService,
+		arS:         articleRevisionService,
+		apS:         articlePreviewService,
 		bS:          bookmarkService,
 		tS:          tagService,
 		jS:          jwtService,
 		atS:         articleTagService,
 		coS:         commentService,
+		cmS:         commentModerationService,
 		lS:          likeService,
 		pS:          productService,
+		fS:          feedService,
+		smS:         sitemapService,
+		trashS:      trashService,
 		mD:          authMiddleware,
+		rlMD:        rateLimitMiddleware.RateLimitMiddleware,
 		eMD:         errorHandler,
 		hC:          healthController,
 		mC:          metricsController,
 		poolManager: poolManager,
+		jobRunner:   jobRunner,
+		viewTracker: viewTracker,
 		portApp:     portApp,
 		engine:      engine,
+
+		shutdownTimeout: co.SchedulerConfig.ShutdownTimeout,
 	}
 }
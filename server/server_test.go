@@ -26,7 +26,8 @@ func TestCORSMiddleware(t *testing.T) {
 	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
 	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
 	assert.Equal(t, "GET, POST, PUT, PATCH, DELETE, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
-	assert.Equal(t, "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, If-Match", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "ETag", w.Header().Get("Access-Control-Expose-Headers"))
 
 	// Test GET request
 	req, _ = http.NewRequest(http.MethodGet, "/test", nil)
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/repository"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// AddAuthor implements ArticleService.
+// The author role belongs to the user who created the article and cannot be assigned.
+func (a *articleService) AddAuthor(ctx context.Context, articleId, userId uuid.UUID, role string) (model.ArticleAuthor, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.ArticleAuthor{}, ctx.Err()
+	default:
+	}
+
+	if userId == uuid.Nil {
+		return model.ArticleAuthor{}, a.errorWrapper.ValidationError(ctx, "user_id", "user ID is required")
+	}
+	if role != model.ArticleAuthorRoleCoAuthor && role != model.ArticleAuthorRoleEditor && role != model.ArticleAuthorRoleReviewer {
+		return model.ArticleAuthor{}, a.errorWrapper.ValidationError(ctx, "role", "role must be co-author, editor or reviewer")
+	}
+
+	article, err := a.repo.GetArticleById(ctx, articleId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.ArticleAuthor{}, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ArticleAuthor{}, a.errorWrapper.NotFoundError(ctx, "Article")
+		}
+		return model.ArticleAuthor{}, fmt.Errorf("failed to fetch article: %v", err)
+	}
+	if article.UserId == userId {
+		return model.ArticleAuthor{}, a.errorWrapper.ValidationError(ctx, "user_id", "the role of the article author cannot be changed")
+	}
+
+	author, err := a.authorRepo.SetAuthor(ctx, articleId, userId, role)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.ArticleAuthor{}, ctx.Err()
+		}
+		if repository.IsForeignKeyViolation(err) {
+			return model.ArticleAuthor{}, a.errorWrapper.NotFoundError(ctx, "User")
+		}
+		return model.ArticleAuthor{}, fmt.Errorf("failed to add article author: %v", err)
+	}
+
+	return author, nil
+}
+
+// RemoveAuthor implements ArticleService.
+func (a *articleService) RemoveAuthor(ctx context.Context, articleId, userId uuid.UUID) error {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	article, err := a.repo.GetArticleById(ctx, articleId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return a.errorWrapper.NotFoundError(ctx, "Article")
+		}
+		return fmt.Errorf("failed to fetch article: %v", err)
+	}
+	if article.UserId == userId {
+		return a.errorWrapper.ValidationError(ctx, "user_id", "the article author cannot be removed")
+	}
+
+	if err := a.authorRepo.RemoveAuthor(ctx, articleId, userId); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return a.errorWrapper.NotFoundError(ctx, "Article author")
+		}
+		return fmt.Errorf("failed to remove article author: %v", err)
+	}
+
+	return nil
+}
+
+// CanEdit implements ArticleService.
+func (a *articleService) CanEdit(ctx context.Context, articleId, userId uuid.UUID) (bool, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	default:
+	}
+
+	canEdit, err := a.authorRepo.CanEdit(ctx, articleId, userId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return false, fmt.Errorf("failed to check article authors: %v", err)
+	}
+
+	return canEdit, nil
+}
+
+// attachAuthors loads the contributors of the articles in one query
+func (a *articleService) attachAuthors(ctx context.Context, articles []model.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(articles))
+	for i, article := range articles {
+		ids[i] = article.Id
+	}
+
+	authors, err := a.authorRepo.GetAuthorsByArticleIds(ctx, ids)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to fetch article authors: %v", err)
+	}
+
+	for i := range articles {
+		articles[i].Authors = authors[articles[i].Id]
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"develapar-server/model"
+	"develapar-server/utils"
+	"fmt"
+)
+
+// renderArticleContent fills the rendered fields of an article from its Markdown content.
+// The rendered output is stored with the article, so it is only recomputed when the hash
+// of the content differs from the one it was rendered from.
+func renderArticleContent(renderer utils.MarkdownRenderer, article *model.Article) error {
+	hash := utils.ContentHash(article.Content)
+	if article.ContentHash == hash {
+		return nil
+	}
+
+	rendered, err := renderer.Render(article.Content)
+	if err != nil {
+		return fmt.Errorf("failed to render article content: %v", err)
+	}
+
+	article.ContentHTML = rendered.HTML
+	article.TOC = rendered.TOC
+	article.Excerpt = rendered.Excerpt
+	article.ReadingTimeMinutes = rendered.ReadingTimeMinutes
+	article.ContentHash = hash
+	return nil
+}
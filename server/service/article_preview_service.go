@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/config"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ArticlePreviewPurpose is the purpose claim of preview link tokens, they cannot be
+	// used as access tokens of password protected articles nor to sign in
+	ArticlePreviewPurpose = "article_preview"
+	// defaultArticlePreviewTTL is how long a preview link works when no expiry is requested
+	defaultArticlePreviewTTL = 72 * time.Hour
+)
+
+type ArticlePreviewService interface {
+	// CreatePreview issues a signed preview link of an unpublished article
+	CreatePreview(ctx context.Context, articleId, userId uuid.UUID, req dto.CreateArticlePreviewRequest) (dto.ArticlePreviewResponse, error)
+	FindPreviews(ctx context.Context, articleId uuid.UUID) ([]model.ArticlePreview, error)
+	RevokePreview(ctx context.Context, articleId, previewId uuid.UUID) (model.ArticlePreview, error)
+	// OpenPreview checks a preview token for the article and counts the view. Unknown,
+	// expired, revoked and used up links are forbidden.
+	OpenPreview(ctx context.Context, articleId uuid.UUID, token string) (model.ArticlePreview, error)
+}
+
+type articlePreviewService struct {
+	repo         repository.ArticlePreviewRepository
+	articleRepo  repository.ArticleRepository
+	jwtService   JwtService
+	site         config.SiteConfig
+	errorWrapper utils.ErrorWrapper
+}
+
+// CreatePreview implements ArticlePreviewService.
+func (s *articlePreviewService) CreatePreview(ctx context.Context, articleId, userId uuid.UUID, req dto.CreateArticlePreviewRequest) (dto.ArticlePreviewResponse, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return dto.ArticlePreviewResponse{}, ctx.Err()
+	default:
+	}
+
+	article, err := s.articleRepo.GetArticleById(ctx, articleId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return dto.ArticlePreviewResponse{}, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.ArticlePreviewResponse{}, s.errorWrapper.NotFoundError(ctx, "Article")
+		}
+		return dto.ArticlePreviewResponse{}, fmt.Errorf("failed to fetch article: %v", err)
+	}
+	if isArticleLive(article) {
+		return dto.ArticlePreviewResponse{}, s.errorWrapper.ValidationError(ctx, "article_id", "Published articles are shared by their own URL")
+	}
+
+	ttl := defaultArticlePreviewTTL
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	preview, err := s.repo.CreatePreview(ctx, model.ArticlePreview{
+		ArticleId: article.Id,
+		CreatedBy: userId,
+		ExpiresAt: time.Now().Add(ttl),
+		MaxViews:  req.MaxViews,
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return dto.ArticlePreviewResponse{}, ctx.Err()
+		}
+		return dto.ArticlePreviewResponse{}, fmt.Errorf("failed to create article preview: %v", err)
+	}
+
+	// The token names the preview row, whose expiry, revocation and views decide
+	// whether the link still works
+	token, _, err := s.jwtService.GenerateScopedToken(ArticlePreviewPurpose, preview.Id.String(), time.Until(preview.ExpiresAt))
+	if err != nil {
+		return dto.ArticlePreviewResponse{}, fmt.Errorf("failed to sign article preview: %v", err)
+	}
+
+	return dto.ArticlePreviewResponse{
+		Preview: preview,
+		Token:   token,
+		Url:     s.site.BaseURL + "/articles/" + article.Slug + "#preview=" + token,
+	}, nil
+}
+
+// FindPreviews implements ArticlePreviewService.
+func (s *articlePreviewService) FindPreviews(ctx context.Context, articleId uuid.UUID) ([]model.ArticlePreview, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	previews, err := s.repo.GetPreviews(ctx, articleId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to fetch article previews: %v", err)
+	}
+
+	return previews, nil
+}
+
+// RevokePreview implements ArticlePreviewService.
+func (s *articlePreviewService) RevokePreview(ctx context.Context, articleId, previewId uuid.UUID) (model.ArticlePreview, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.ArticlePreview{}, ctx.Err()
+	default:
+	}
+
+	preview, err := s.repo.RevokePreview(ctx, articleId, previewId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.ArticlePreview{}, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ArticlePreview{}, s.errorWrapper.NotFoundError(ctx, "Article preview")
+		}
+		return model.ArticlePreview{}, fmt.Errorf("failed to revoke article preview: %v", err)
+	}
+
+	return preview, nil
+}
+
+// OpenPreview implements ArticlePreviewService.
+func (s *articlePreviewService) OpenPreview(ctx context.Context, articleId uuid.UUID, token string) (model.ArticlePreview, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.ArticlePreview{}, ctx.Err()
+	default:
+	}
+
+	subject, err := s.jwtService.VerifyScopedToken(token, ArticlePreviewPurpose)
+	if err != nil {
+		return model.ArticlePreview{}, s.errorWrapper.ForbiddenError(ctx, "Preview link is invalid or has expired")
+	}
+	previewId, err := uuid.Parse(subject)
+	if err != nil {
+		return model.ArticlePreview{}, s.errorWrapper.ForbiddenError(ctx, "Preview link is invalid or has expired")
+	}
+
+	preview, err := s.repo.ConsumePreview(ctx, articleId, previewId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.ArticlePreview{}, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ArticlePreview{}, s.errorWrapper.ForbiddenError(ctx, "Preview link is invalid, revoked or has run out of views")
+		}
+		return model.ArticlePreview{}, fmt.Errorf("failed to open article preview: %v", err)
+	}
+
+	return preview, nil
+}
+
+func NewArticlePreviewService(repo repository.ArticlePreviewRepository, articleRepo repository.ArticleRepository, jwtService JwtService, site config.SiteConfig, errorWrapper utils.ErrorWrapper) ArticlePreviewService {
+	return &articlePreviewService{
+		repo:         repo,
+		articleRepo:  articleRepo,
+		jwtService:   jwtService,
+		site:         site,
+		errorWrapper: errorWrapper,
+	}
+}
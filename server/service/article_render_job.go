@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// articleRenderBatchSize is how many unrendered articles are loaded at a time
+	articleRenderBatchSize = 100
+	// articleRenderInterval is how often an unfinished backfill is resumed
+	articleRenderInterval = time.Minute
+)
+
+// articleRenderJob renders and stores the Markdown of articles written before rendering was
+// introduced, so every read path gets stored output. It works through the articles once,
+// in id order, and is a no-op after it reached the end.
+type articleRenderJob struct {
+	repo        repository.ArticleRepository
+	renderer    utils.MarkdownRenderer
+	cacheEvents CacheEventPublisher
+	logger      utils.Logger
+	after       uuid.UUID
+	done        bool
+}
+
+// Name implements BackgroundJob.
+func (j *articleRenderJob) Name() string {
+	return "article_render_backfill"
+}
+
+// Interval implements BackgroundJob.
+func (j *articleRenderJob) Interval() time.Duration {
+	return articleRenderInterval
+}
+
+// Run implements BackgroundJob.
+// Runs of a job never overlap, so the position needs no locking.
+func (j *articleRenderJob) Run(ctx context.Context) error {
+	if j.done {
+		return nil
+	}
+
+	rendered := 0
+	for {
+		articles, err := j.repo.GetUnrenderedArticles(ctx, j.after, articleRenderBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			j.done = true
+			if rendered > 0 {
+				j.logger.Info(ctx, "Rendered stored article content", utils.IntField("articles", rendered))
+			}
+			return nil
+		}
+
+		ids := make([]uuid.UUID, 0, len(articles))
+		for _, article := range articles {
+			// A failing article is skipped, it keeps being rendered on read
+			if err := renderArticleContent(j.renderer, &article); err != nil {
+				j.logger.Warn(ctx, "Failed to render article content",
+					utils.StringField("article_id", article.Id.String()),
+					utils.ErrorField(err),
+				)
+			} else if err := j.repo.SaveRenderedContent(ctx, article); err != nil {
+				return err
+			} else {
+				ids = append(ids, article.Id)
+			}
+			j.after = article.Id
+		}
+
+		rendered += len(ids)
+		if len(ids) > 0 {
+			j.cacheEvents.Publish(ctx, articleChanged(ids...))
+		}
+	}
+}
+
+// NewArticleRenderJob creates the background job that backfills the rendered content of old articles
+func NewArticleRenderJob(repo repository.ArticleRepository, renderer utils.MarkdownRenderer, cacheEvents CacheEventPublisher, logger utils.Logger) BackgroundJob {
+	return &articleRenderJob{
+		repo:        repo,
+		renderer:    renderer,
+		cacheEvents: cacheEvents,
+		logger:      logger,
+	}
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type ArticleRevisionService interface {
+	FindRevisions(ctx context.Context, articleId uuid.UUID) ([]model.ArticleRevision, error)
+	FindRevision(ctx context.Context, articleId uuid.UUID, revisionNumber int) (model.ArticleRevision, error)
+	DiffRevisions(ctx context.Context, articleId uuid.UUID, fromRevision, toRevision int) (dto.ArticleRevisionDiff, error)
+	RestoreRevision(ctx context.Context, articleId uuid.UUID, revisionNumber int, editorID uuid.UUID) (model.Article, error)
+}
+
+type articleRevisionService struct {
+	repo             repository.ArticleRevisionRepository
+	articleRepo      repository.ArticleRepository
+	slugAllocator    SlugAllocator
+	markdownRenderer utils.MarkdownRenderer
+	cacheEvents      CacheEventPublisher
+	errorWrapper     utils.ErrorWrapper
+}
+
+// FindRevisions implements ArticleRevisionService.
+func (s *articleRevisionService) FindRevisions(ctx context.Context, articleId uuid.UUID) ([]model.ArticleRevision, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	// Validate ID
+	if articleId == uuid.Nil {
+		return nil, fmt.Errorf("article ID must be greater than 0")
+	}
+
+	revisions, err := s.repo.GetRevisionsByArticleId(ctx, articleId)
+	if err != nil {
+		// Check if context was cancelled during repository operation
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to fetch article revisions: %v", err)
+	}
+
+	return revisions, nil
+}
+
+// FindRevision implements ArticleRevisionService.
+func (s *articleRevisionService) FindRevision(ctx context.Context, articleId uuid.UUID, revisionNumber int) (model.ArticleRevision, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.ArticleRevision{}, ctx.Err()
+	default:
+	}
+
+	// Validate ID and revision number
+	if articleId == uuid.Nil {
+		return model.ArticleRevision{}, fmt.Errorf("article ID must be greater than 0")
+	}
+	if revisionNumber <= 0 {
+		return model.ArticleRevision{}, s.errorWrapper.ValidationError(ctx, "revision", "Revision must be a positive integer")
+	}
+
+	revision, err := s.repo.GetRevision(ctx, articleId, revisionNumber)
+	if err != nil {
+		// Check if context was cancelled during repository operation
+		if ctx.Err() != nil {
+			return model.ArticleRevision{}, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ArticleRevision{}, s.errorWrapper.NotFoundError(ctx, fmt.Sprintf("Revision %d", revisionNumber))
+		}
+		return model.ArticleRevision{}, fmt.Errorf("failed to fetch article revision: %v", err)
+	}
+
+	return revision, nil
+}
+
+// DiffRevisions implements ArticleRevisionService.
+// The diff is computed from the content of fromRevision to the content of toRevision.
+func (s *articleRevisionService) DiffRevisions(ctx context.Context, articleId uuid.UUID, fromRevision, toRevision int) (dto.ArticleRevisionDiff, error) {
+	from, err := s.FindRevision(ctx, articleId, fromRevision)
+	if err != nil {
+		return dto.ArticleRevisionDiff{}, err
+	}
+
+	to, err := s.FindRevision(ctx, articleId, toRevision)
+	if err != nil {
+		return dto.ArticleRevisionDiff{}, err
+	}
+
+	diff := dto.ArticleRevisionDiff{
+		ArticleId:    articleId,
+		FromRevision: from.RevisionNumber,
+		ToRevision:   to.RevisionNumber,
+		TitleChanged: from.Title != to.Title,
+		FromTitle:    from.Title,
+		ToTitle:      to.Title,
+		Lines:        []dto.RevisionDiffLine{},
+	}
+
+	for _, line := range utils.DiffLines(from.Content, to.Content) {
+		switch line.Op {
+		case utils.DiffInsert:
+			diff.Added++
+		case utils.DiffDelete:
+			diff.Removed++
+		}
+		diff.Lines = append(diff.Lines, dto.RevisionDiffLine{
+			Op:      line.Op,
+			OldLine: line.OldLine,
+			NewLine: line.NewLine,
+			Text:    line.Text,
+		})
+	}
+
+	return diff, nil
+}
+
+// RestoreRevision implements ArticleRevisionService.
+// Title, slug, content and category of the revision become the current version, a pinned slug is kept;
+// the status is left untouched so restoring never publishes or unpublishes an article.
+// The restore goes through the regular update path and is therefore recorded as a new revision.
+func (s *articleRevisionService) RestoreRevision(ctx context.Context, articleId uuid.UUID, revisionNumber int, editorID uuid.UUID) (model.Article, error) {
+	revision, err := s.FindRevision(ctx, articleId, revisionNumber)
+	if err != nil {
+		return model.Article{}, err
+	}
+
+	article, err := s.articleRepo.GetArticleById(ctx, articleId)
+	if err != nil {
+		// Check if context was cancelled during repository operation
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		return model.Article{}, fmt.Errorf("failed to fetch article for restore: %v", err)
+	}
+
+	article.Title = revision.Title
+	// A pinned custom slug outlives title changes, restoring included
+	restoreSlug := !article.SlugPinned && article.Slug != revision.Slug
+	article.Content = revision.Content
+	if revision.CategoryId != uuid.Nil {
+		article.CategoryId = revision.CategoryId
+	}
+	if err := renderArticleContent(s.markdownRenderer, &article); err != nil {
+		return model.Article{}, err
+	}
+
+	// Check context cancellation before update
+	select {
+	case <-ctx.Done():
+		return model.Article{}, ctx.Err()
+	default:
+	}
+
+	// The slug of the revision may belong to another article by now, it then gets a suffix
+	var restored model.Article
+	update := func(slug string) error {
+		article.Slug = slug
+		var err error
+		restored, err = s.articleRepo.UpdateArticle(ctx, article, editorID)
+		return err
+	}
+	if restoreSlug {
+		err = s.slugAllocator.Save(ctx, repository.SlugTableArticles, revision.Slug, article.Id, update)
+	} else {
+		err = update(article.Slug)
+	}
+	if err != nil {
+		// Check if context was cancelled during repository operation
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		// Another update landed between reading the article and restoring the revision
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Article{}, s.errorWrapper.ConflictError(ctx, "Article", "Article was modified during the restore, retry")
+		}
+		return model.Article{}, fmt.Errorf("failed to restore article revision: %v", err)
+	}
+	s.cacheEvents.Publish(ctx, articleChanged(articleId))
+
+	return restored, nil
+}
+
+func NewArticleRevisionService(repo repository.ArticleRevisionRepository, articleRepo repository.ArticleRepository, slugAllocator SlugAllocator, markdownRenderer utils.MarkdownRenderer, cacheEvents CacheEventPublisher, errorWrapper utils.ErrorWrapper) ArticleRevisionService {
+	return &articleRevisionService{
+		repo:             repo,
+		articleRepo:      articleRepo,
+		slugAllocator:    slugAllocator,
+		markdownRenderer: markdownRenderer,
+		cacheEvents:      cacheEvents,
+		errorWrapper:     errorWrapper,
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"time"
+)
+
+// articleScheduleJob publishes and archives articles according to their publish_at/unpublish_at
+type articleScheduleJob struct {
+	repo        repository.ArticleRepository
+	cacheEvents CacheEventPublisher
+	logger      utils.Logger
+	interval    time.Duration
+}
+
+// Name implements BackgroundJob.
+func (j *articleScheduleJob) Name() string {
+	return "article_schedule"
+}
+
+// Interval implements BackgroundJob.
+func (j *articleScheduleJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run implements BackgroundJob.
+func (j *articleScheduleJob) Run(ctx context.Context) error {
+	published, unpublished, err := j.repo.ApplySchedule(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(published) > 0 || len(unpublished) > 0 {
+		j.cacheEvents.Publish(ctx, articleChanged(append(published, unpublished...)...))
+		j.logger.Info(ctx, "Applied article schedule",
+			utils.IntField("published", len(published)),
+			utils.IntField("unpublished", len(unpublished)),
+		)
+	}
+
+	return nil
+}
+
+// NewArticleScheduleJob creates the background job that applies article publish schedules
+func NewArticleScheduleJob(repo repository.ArticleRepository, cacheEvents CacheEventPublisher, logger utils.Logger, interval time.Duration) BackgroundJob {
+	return &articleScheduleJob{
+		repo:        repo,
+		cacheEvents: cacheEvents,
+		logger:      logger,
+		interval:    interval,
+	}
+}
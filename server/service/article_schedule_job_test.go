@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"develapar-server/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScheduleArticleRepository returns fixed schedule changes
+type fakeScheduleArticleRepository struct {
+	repository.ArticleRepository
+	published   []uuid.UUID
+	unpublished []uuid.UUID
+	err         error
+}
+
+func (r *fakeScheduleArticleRepository) ApplySchedule(ctx context.Context) ([]uuid.UUID, []uuid.UUID, error) {
+	return r.published, r.unpublished, r.err
+}
+
+func TestArticleScheduleJob_Run(t *testing.T) {
+	published := []uuid.UUID{uuid.New(), uuid.New()}
+	unpublished := []uuid.UUID{uuid.New()}
+
+	tests := []struct {
+		name        string
+		repo        *fakeScheduleArticleRepository
+		wantErr     bool
+		wantChanged []uuid.UUID
+	}{
+		{
+			name:        "published and unpublished articles",
+			repo:        &fakeScheduleArticleRepository{published: published, unpublished: unpublished},
+			wantChanged: append(append([]uuid.UUID{}, published...), unpublished...),
+		},
+		{
+			name:        "only unpublished articles",
+			repo:        &fakeScheduleArticleRepository{unpublished: unpublished},
+			wantChanged: unpublished,
+		},
+		{
+			name: "nothing due",
+			repo: &fakeScheduleArticleRepository{},
+		},
+		{
+			name:    "repository failure",
+			repo:    &fakeScheduleArticleRepository{err: errors.New("connection reset")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &recordingPublisher{}
+			job := NewArticleScheduleJob(tt.repo, events, newTestLogger(), time.Minute)
+
+			err := job.Run(context.Background())
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Empty(t, events.events)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.wantChanged == nil {
+				assert.Empty(t, events.events)
+				return
+			}
+			require.Len(t, events.events, 1)
+			assert.Equal(t, articleChanged(tt.wantChanged...), events.events[0])
+		})
+	}
+}
@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"develapar-server/model"
 	"develapar-server/model/dto"
 	"develapar-server/repository"
 	"develapar-server/utils"
+	"errors"
 	"fmt"
+	"html"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,21 +20,53 @@ type ArticleService interface {
 	CreateArticleWithTags(ctx context.Context, req dto.CreateArticleRequest, userID uuid.UUID) (model.Article, error)
 	FindAll(ctx context.Context) ([]dto.ArticleResponse, error)
 	FindAllWithPagination(ctx context.Context, page, limit int) (PaginationResult, error)
-	UpdateArticle(ctx context.Context, id uuid.UUID, req dto.UpdateArticleRequest) (model.Article, error)
+	// UpdateArticle applies the update only if version is still the current one
+	UpdateArticle(ctx context.Context, id uuid.UUID, version int, req dto.UpdateArticleRequest, editorID uuid.UUID) (model.Article, error)
 	FindById(ctx context.Context, id uuid.UUID) (model.Article, error)
 	FindBySlug(ctx context.Context, slug string) (model.Article, error)
-	FindByUserId(ctx context.Context, userId uuid.UUID) ([]model.Article, error)
-	FindByUserIdWithPagination(ctx context.Context, userId uuid.UUID, page, limit int) (PaginationResult, error)
+	// FindSlugRedirect returns the current slug of an article that was renamed away
+	// from slug, or an empty string when slug never belonged to an article
+	FindSlugRedirect(ctx context.Context, slug string) (string, error)
+	// FindByUserId lists the articles credited to the user. The user and editors or
+	// admins also get drafts, scheduled articles and the unlisted, private and password
+	// protected ones, everybody else only the public ones.
+	FindByUserId(ctx context.Context, userId uuid.UUID, viewer ArticleViewer) ([]model.Article, error)
+	// FindByUserIdWithPagination is FindByUserId one page at a time
+	FindByUserIdWithPagination(ctx context.Context, userId uuid.UUID, viewer ArticleViewer, page, limit int) (PaginationResult, error)
 	FindByCategory(ctx context.Context, catId string) ([]model.Article, error)
 	FindByCategoryWithPagination(ctx context.Context, catId string, page, limit int) (PaginationResult, error)
 	DeleteArticle(ctx context.Context, id uuid.UUID) error
+	Search(ctx context.Context, filter dto.ArticleSearchFilter, page, limit int) (PaginationResult, error)
+	SubmitArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (model.Article, error)
+	ApproveArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID, note string) (model.Article, error)
+	RejectArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID, reason string) (model.Article, error)
+	PublishArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (model.Article, error)
+	ArchiveArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (model.Article, error)
+	FindStatusTransitions(ctx context.Context, id uuid.UUID) ([]model.ArticleStatusTransition, error)
+	// AddAuthor credits a user on an article as co-author, editor or reviewer,
+	// changing the role when the user is already listed
+	AddAuthor(ctx context.Context, articleId, userId uuid.UUID, role string) (model.ArticleAuthor, error)
+	RemoveAuthor(ctx context.Context, articleId, userId uuid.UUID) error
+	// CanEdit reports whether the user is listed on the article with edit rights
+	CanEdit(ctx context.Context, articleId, userId uuid.UUID) (bool, error)
+	// AuthorizeView checks that the viewer may read the article under its visibility
+	AuthorizeView(ctx context.Context, article model.Article, viewer ArticleViewer) error
+	// UnlockArticle checks the password of a password protected article and issues a
+	// short-lived token that lets the holder read it
+	UnlockArticle(ctx context.Context, articleId uuid.UUID, password string) (dto.ArticleAccessResponse, error)
 }
 
 type articleService struct {
 	repo              repository.ArticleRepository
+	authorRepo        repository.ArticleAuthorRepository
 	articleTagService ArticleTagService
 	paginationService PaginationService
 	validationService ValidationService
+	slugAllocator     SlugAllocator
+	passwordHasher    utils.PasswordHasher
+	jwtService        JwtService
+	markdownRenderer  utils.MarkdownRenderer
+	errorWrapper      utils.ErrorWrapper
 }
 
 // FindById implements ArticleService.
@@ -57,6 +93,14 @@ func (a *articleService) FindById(ctx context.Context, id uuid.UUID) (model.Arti
 		return model.Article{}, fmt.Errorf("failed to fetch article: %v", err)
 	}
 
+	article.Authors, err = a.authorRepo.GetAuthors(ctx, article.Id)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		return model.Article{}, fmt.Errorf("failed to fetch article authors: %v", err)
+	}
+
 	return article, nil
 }
 
@@ -115,7 +159,7 @@ func (a *articleService) DeleteArticle(ctx context.Context, id uuid.UUID) error
 }
 
 // FindByUserId implements ArticleService.
-func (a *articleService) FindByUserId(ctx context.Context, userId uuid.UUID) ([]model.Article, error) {
+func (a *articleService) FindByUserId(ctx context.Context, userId uuid.UUID, viewer ArticleViewer) ([]model.Article, error) {
 	// Check context cancellation
 	select {
 	case <-ctx.Done():
@@ -129,7 +173,7 @@ func (a *articleService) FindByUserId(ctx context.Context, userId uuid.UUID) ([]
 	}
 
 	// Get articles by user from repository with context
-	articles, err := a.repo.GetArticleByUserId(ctx, userId)
+	articles, err := a.repo.GetArticleByUserId(ctx, userId, viewer.ownsListing(userId))
 	if err != nil {
 		// Check if context was cancelled during repository operation
 		if ctx.Err() != nil {
@@ -162,14 +206,62 @@ func (a *articleService) FindBySlug(ctx context.Context, slug string) (model.Art
 		if ctx.Err() != nil {
 			return model.Article{}, ctx.Err()
 		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Article{}, a.errorWrapper.NotFoundError(ctx, "Article")
+		}
 		return model.Article{}, fmt.Errorf("failed to fetch article by slug: %v", err)
 	}
 
+	// Articles written before rendering was introduced have no stored output until the
+	// backfill job reaches them, the first read renders and stores it
+	if article.ContentHash == "" {
+		if err := renderArticleContent(a.markdownRenderer, &article); err != nil {
+			return model.Article{}, err
+		}
+		if err := a.repo.SaveRenderedContent(ctx, article); err != nil {
+			if ctx.Err() != nil {
+				return model.Article{}, ctx.Err()
+			}
+			return model.Article{}, fmt.Errorf("failed to store rendered article content: %v", err)
+		}
+	}
+
+	article.Authors, err = a.authorRepo.GetAuthors(ctx, article.Id)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		return model.Article{}, fmt.Errorf("failed to fetch article authors: %v", err)
+	}
+
 	return article, nil
 }
 
+// FindSlugRedirect implements ArticleService.
+func (a *articleService) FindSlugRedirect(ctx context.Context, slug string) (string, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	default:
+	}
+
+	current, err := a.repo.GetSlugRedirect(ctx, slug)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to fetch slug redirect: %v", err)
+	}
+
+	return current, nil
+}
+
 // UpdateArticle implements ArticleService.
-func (a *articleService) UpdateArticle(ctx context.Context, id uuid.UUID, req dto.UpdateArticleRequest) (model.Article, error) {
+func (a *articleService) UpdateArticle(ctx context.Context, id uuid.UUID, version int, req dto.UpdateArticleRequest, editorID uuid.UUID) (model.Article, error) {
 	// Check context cancellation
 	select {
 	case <-ctx.Done():
@@ -189,20 +281,46 @@ func (a *articleService) UpdateArticle(ctx context.Context, id uuid.UUID, req dt
 		if ctx.Err() != nil {
 			return model.Article{}, ctx.Err()
 		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Article{}, a.errorWrapper.NotFoundError(ctx, "Article")
+		}
 		return model.Article{}, fmt.Errorf("failed to fetch article for update: %v", err)
 	}
 
-	// Update fields if provided
+	// Refuse updates made from an outdated copy of the article
+	if article.Version != version {
+		return model.Article{}, a.errorWrapper.PreconditionFailedError(ctx, "Article")
+	}
+
+	// Update fields if provided. A new title derives a new slug unless a custom slug is pinned,
+	// the unique slug is allocated when the article is stored.
+	reallocateSlug := false
 	if req.Title != nil {
 		article.Title = *req.Title
-		// Generate new slug automatically when title is updated
-		article.Slug = utils.GenerateSlug(*req.Title)
+		if !article.SlugPinned {
+			article.Slug = utils.GenerateSlug(*req.Title)
+			reallocateSlug = true
+		}
+	}
+	if req.Slug != nil {
+		if err := a.applyCustomSlug(ctx, &article, *req.Slug); err != nil {
+			return model.Article{}, err
+		}
+		reallocateSlug = !article.SlugPinned
 	}
 	if req.Content != nil {
 		article.Content = *req.Content
 	}
 	if req.CategoryID != nil {
-		article.Category.Id = *req.CategoryID
+		article.CategoryId = *req.CategoryID
+	}
+	if err := a.applySchedule(ctx, &article, req.PublishAt, req.UnpublishAt); err != nil {
+		return model.Article{}, err
+	}
+	if req.Visibility != nil || req.Password != nil {
+		if err := a.applyVisibility(ctx, &article, req.Visibility, req.Password); err != nil {
+			return model.Article{}, err
+		}
 	}
 
 	// Validate updated article data
@@ -210,6 +328,11 @@ func (a *articleService) UpdateArticle(ctx context.Context, id uuid.UUID, req dt
 		return model.Article{}, validationErr
 	}
 
+	// Re-render only if the content changed
+	if err := renderArticleContent(a.markdownRenderer, &article); err != nil {
+		return model.Article{}, err
+	}
+
 	// Check context cancellation before update
 	select {
 	case <-ctx.Done():
@@ -217,13 +340,31 @@ func (a *articleService) UpdateArticle(ctx context.Context, id uuid.UUID, req dt
 	default:
 	}
 
-	// Update article in repository with context
-	updatedArticle, err := a.repo.UpdateArticle(ctx, article)
+	// Update article in repository with context, a revision is recorded for the editor
+	var updatedArticle model.Article
+	update := func(slug string) error {
+		article.Slug = slug
+		var err error
+		updatedArticle, err = a.repo.UpdateArticle(ctx, article, editorID)
+		return err
+	}
+	if reallocateSlug {
+		err = a.slugAllocator.Save(ctx, repository.SlugTableArticles, article.Title, article.Id, update)
+	} else {
+		err = update(article.Slug)
+	}
 	if err != nil {
 		// Check if context was cancelled during repository operation
 		if ctx.Err() != nil {
 			return model.Article{}, ctx.Err()
 		}
+		if repository.IsSlugViolation(err, repository.SlugTableArticles) {
+			return model.Article{}, a.errorWrapper.ConflictError(ctx, "Article", "Slug is already used by another article")
+		}
+		// The article was changed by someone else since it was read
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Article{}, a.errorWrapper.PreconditionFailedError(ctx, "Article")
+		}
 		return model.Article{}, fmt.Errorf("failed to update article: %v", err)
 	}
 
@@ -242,6 +383,45 @@ func (a *articleService) UpdateArticle(ctx context.Context, id uuid.UUID, req dt
 	return updatedArticle, nil
 }
 
+// applyCustomSlug pins a custom slug on the article, normalized the same way as generated
+// slugs. An empty slug removes the pin and derives the slug from the title again.
+func (a *articleService) applyCustomSlug(ctx context.Context, article *model.Article, slug string) error {
+	if strings.TrimSpace(slug) == "" {
+		article.SlugPinned = false
+		article.Slug = utils.GenerateSlug(article.Title)
+		return nil
+	}
+
+	claimed, err := a.slugAllocator.Claim(ctx, repository.SlugTableArticles, slug, article.Id)
+	if err != nil {
+		return err
+	}
+	article.Slug = claimed
+	article.SlugPinned = true
+	return nil
+}
+
+// applySchedule validates the requested publish/unpublish times and stores them on the article.
+// The times only take effect through the workflow: publishing an article whose publish time is
+// in the future makes it "scheduled", and the scheduler job publishes it once the time has passed.
+func (a *articleService) applySchedule(ctx context.Context, article *model.Article, publishAt, unpublishAt *time.Time) error {
+	if publishAt != nil {
+		article.PublishAt = publishAt
+	}
+
+	if unpublishAt != nil {
+		if !unpublishAt.After(time.Now()) {
+			return a.errorWrapper.ValidationError(ctx, "unpublish_at", "Unpublish time must be in the future")
+		}
+		if article.PublishAt != nil && !unpublishAt.After(*article.PublishAt) {
+			return a.errorWrapper.ValidationError(ctx, "unpublish_at", "Unpublish time must be after publish time")
+		}
+		article.UnpublishAt = unpublishAt
+	}
+
+	return nil
+}
+
 // assignTagsToArticle is a helper method to assign tags to an article
 // Uses ArticleTagService to avoid code duplication
 func (a *articleService) assignTagsToArticle(ctx context.Context, articleId uuid.UUID, tagNames []string) error {
@@ -263,27 +443,33 @@ func (a *articleService) CreateArticleWithTags(ctx context.Context, req dto.Crea
 	}
 
 	if req.Status == "" {
-		req.Status = "draft" // Default status if not provided
+		req.Status = model.ArticleStatusDraft // Default status if not provided
 	}
 
 	// Generate slug automatically from title
 	slug := utils.GenerateSlug(req.Title)
 
-	fmt.Println("userID:", userID)
-	fmt.Println("categoryID:", req.CategoryID)
-
 	// Create article object
 	article := model.Article{
-		Id:        uuid.Must(uuid.NewV7()),
-		Title:     req.Title,
-		Slug:      slug,
-		Content:   req.Content,
-		Status:    req.Status,
-		User:      &model.User{Id: userID},
-		Category:  &model.Category{Id: req.CategoryID},
-		Views:     0,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		Id:         uuid.Must(uuid.NewV7()),
+		Title:      req.Title,
+		Slug:       slug,
+		Content:    req.Content,
+		Status:     req.Status,
+		UserId:     userID,
+		User:       &model.User{Id: userID},
+		CategoryId: req.CategoryID,
+		Category:   &model.Category{Id: req.CategoryID},
+		Views:      0,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	if err := a.applySchedule(ctx, &article, req.PublishAt, req.UnpublishAt); err != nil {
+		return model.Article{}, err
+	}
+	if err := a.applyVisibility(ctx, &article, &req.Visibility, &req.Password); err != nil {
+		return model.Article{}, err
 	}
 
 	// Validate article data using validation service
@@ -291,6 +477,10 @@ func (a *articleService) CreateArticleWithTags(ctx context.Context, req dto.Crea
 		return model.Article{}, validationErr
 	}
 
+	if err := renderArticleContent(a.markdownRenderer, &article); err != nil {
+		return model.Article{}, err
+	}
+
 	// Check context cancellation after validation
 	select {
 	case <-ctx.Done():
@@ -298,8 +488,14 @@ func (a *articleService) CreateArticleWithTags(ctx context.Context, req dto.Crea
 	default:
 	}
 
-	// Create article in repository with context
-	createdArticle, err := a.repo.CreateArticle(ctx, article)
+	// Create article in repository with context, the slug gets a suffix when another article uses it
+	var createdArticle model.Article
+	err := a.slugAllocator.Save(ctx, repository.SlugTableArticles, article.Title, article.Id, func(slug string) error {
+		article.Slug = slug
+		var err error
+		createdArticle, err = a.repo.CreateArticle(ctx, article)
+		return err
+	})
 	if err != nil {
 		// Check if context was cancelled during repository operation
 		if ctx.Err() != nil {
@@ -371,6 +567,10 @@ func (a *articleService) FindAllWithPagination(ctx context.Context, page, limit
 		return PaginationResult{}, fmt.Errorf("failed to fetch articles: %v", repoErr)
 	}
 
+	if err := a.attachAuthors(ctx, articles); err != nil {
+		return PaginationResult{}, err
+	}
+
 	// Create pagination result
 	result, paginationErr := a.paginationService.Paginate(ctx, articles, total, query)
 	if paginationErr != nil {
@@ -381,7 +581,7 @@ func (a *articleService) FindAllWithPagination(ctx context.Context, page, limit
 }
 
 // FindByUserIdWithPagination implements ArticleService with pagination support for user articles
-func (a *articleService) FindByUserIdWithPagination(ctx context.Context, userId uuid.UUID, page, limit int) (PaginationResult, error) {
+func (a *articleService) FindByUserIdWithPagination(ctx context.Context, userId uuid.UUID, viewer ArticleViewer, page, limit int) (PaginationResult, error) {
 	// Check context cancellation
 	select {
 	case <-ctx.Done():
@@ -396,7 +596,7 @@ func (a *articleService) FindByUserIdWithPagination(ctx context.Context, userId
 	}
 
 	// Get paginated articles by user from repository
-	articles, total, repoErr := a.repo.GetArticleByUserIdWithPagination(ctx, userId, query.Offset, query.Limit)
+	articles, total, repoErr := a.repo.GetArticleByUserIdWithPagination(ctx, userId, viewer.ownsListing(userId), query.Offset, query.Limit)
 	if repoErr != nil {
 		// Check if context was cancelled during repository operation
 		if ctx.Err() != nil {
@@ -405,6 +605,10 @@ func (a *articleService) FindByUserIdWithPagination(ctx context.Context, userId
 		return PaginationResult{}, fmt.Errorf("failed to fetch user articles: %v", repoErr)
 	}
 
+	if err := a.attachAuthors(ctx, articles); err != nil {
+		return PaginationResult{}, err
+	}
+
 	// Create pagination result
 	result, paginationErr := a.paginationService.Paginate(ctx, articles, total, query)
 	if paginationErr != nil {
@@ -439,6 +643,10 @@ func (a *articleService) FindByCategoryWithPagination(ctx context.Context, catId
 		return PaginationResult{}, fmt.Errorf("failed to fetch category articles: %v", repoErr)
 	}
 
+	if err := a.attachAuthors(ctx, articles); err != nil {
+		return PaginationResult{}, err
+	}
+
 	// Create pagination result
 	result, paginationErr := a.paginationService.Paginate(ctx, articles, total, query)
 	if paginationErr != nil {
@@ -448,11 +656,68 @@ func (a *articleService) FindByCategoryWithPagination(ctx context.Context, catId
 	return result, nil
 }
 
-func NewArticleService(repository repository.ArticleRepository, articleTagService ArticleTagService, paginationService PaginationService, validationService ValidationService) ArticleService {
+// Search implements ArticleService with full-text search over published articles
+func (a *articleService) Search(ctx context.Context, filter dto.ArticleSearchFilter, page, limit int) (PaginationResult, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return PaginationResult{}, ctx.Err()
+	default:
+	}
+
+	// Validate search query
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Query == "" {
+		return PaginationResult{}, fmt.Errorf("search query is required")
+	}
+
+	// Parse and validate pagination query, results are always ordered by rank
+	query, err := a.paginationService.ParseQuery(ctx, page, limit, "rank", "desc")
+	if err != nil {
+		return PaginationResult{}, fmt.Errorf("pagination validation failed: %v", err)
+	}
+
+	// Search articles in repository
+	results, total, repoErr := a.repo.Search(ctx, filter, query.Offset, query.Limit)
+	if repoErr != nil {
+		// Check if context was cancelled during repository operation
+		if ctx.Err() != nil {
+			return PaginationResult{}, ctx.Err()
+		}
+		return PaginationResult{}, fmt.Errorf("failed to search articles: %v", repoErr)
+	}
+	for i := range results {
+		results[i].Snippet = highlightSnippet(results[i].Snippet)
+	}
+
+	// Create pagination result
+	result, paginationErr := a.paginationService.Paginate(ctx, results, total, query)
+	if paginationErr != nil {
+		return PaginationResult{}, fmt.Errorf("failed to create pagination result: %v", paginationErr)
+	}
+
+	return result, nil
+}
+
+// highlightSnippet HTML escapes a search snippet and only then wraps its matches in
+// <mark> tags, so markup written in the article never reaches the client as HTML
+func highlightSnippet(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, repository.SearchHighlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, repository.SearchHighlightStop, "</mark>")
+}
+
+func NewArticleService(repository repository.ArticleRepository, authorRepo repository.ArticleAuthorRepository, articleTagService ArticleTagService, paginationService PaginationService, validationService ValidationService, slugAllocator SlugAllocator, passwordHasher utils.PasswordHasher, jwtService JwtService, markdownRenderer utils.MarkdownRenderer, errorWrapper utils.ErrorWrapper) ArticleService {
 	return &articleService{
 		repo:              repository,
+		authorRepo:        authorRepo,
 		articleTagService: articleTagService,
 		paginationService: paginationService,
 		validationService: validationService,
+		slugAllocator:     slugAllocator,
+		passwordHasher:    passwordHasher,
+		jwtService:        jwtService,
+		markdownRenderer:  markdownRenderer,
+		errorWrapper:      errorWrapper,
 	}
 }
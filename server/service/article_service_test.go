@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSearchArticleRepository returns fixed search hits and records the query it got
+type fakeSearchArticleRepository struct {
+	repository.ArticleRepository
+	results []dto.ArticleSearchResult
+	filter  dto.ArticleSearchFilter
+	offset  int
+	limit   int
+	calls   int
+}
+
+func (r *fakeSearchArticleRepository) Search(ctx context.Context, filter dto.ArticleSearchFilter, offset, limit int) ([]dto.ArticleSearchResult, int, error) {
+	r.calls++
+	r.filter, r.offset, r.limit = filter, offset, limit
+	return r.results, len(r.results), nil
+}
+
+func newTestSearchService(repo *fakeSearchArticleRepository) ArticleService {
+	errorWrapper := utils.NewErrorWrapper()
+	pagination := NewPaginationService(NewValidationService(errorWrapper), errorWrapper)
+	return NewArticleService(repo, nil, nil, pagination, nil, nil, nil, nil, nil, errorWrapper)
+}
+
+func TestArticleService_Search(t *testing.T) {
+	first := dto.ArticleSearchResult{Id: uuid.New(), Title: "Go generics", Rank: 0.9}
+	second := dto.ArticleSearchResult{Id: uuid.New(), Title: "Go channels", Rank: 0.4}
+	repo := &fakeSearchArticleRepository{results: []dto.ArticleSearchResult{first, second}}
+	service := newTestSearchService(repo)
+
+	result, err := service.Search(context.Background(), dto.ArticleSearchFilter{Query: "  go  ", Category: "Programming", Tag: "golang"}, 2, 5)
+	require.NoError(t, err)
+
+	// The query is trimmed, the filters are passed through and the page becomes an offset
+	assert.Equal(t, dto.ArticleSearchFilter{Query: "go", Category: "Programming", Tag: "golang"}, repo.filter)
+	assert.Equal(t, 5, repo.offset)
+	assert.Equal(t, 5, repo.limit)
+
+	// Hits keep the rank order of the repository
+	hits, ok := result.Data.([]dto.ArticleSearchResult)
+	require.True(t, ok)
+	require.Len(t, hits, 2)
+	assert.Equal(t, first.Id, hits[0].Id)
+	assert.Equal(t, second.Id, hits[1].Id)
+	assert.Equal(t, 2, result.Metadata.Total)
+	assert.Equal(t, 2, result.Metadata.Page)
+}
+
+func TestArticleService_SearchRequiresQuery(t *testing.T) {
+	repo := &fakeSearchArticleRepository{}
+	service := newTestSearchService(repo)
+
+	_, err := service.Search(context.Background(), dto.ArticleSearchFilter{Query: "   "}, 1, 10)
+	assert.Error(t, err)
+	assert.Zero(t, repo.calls)
+}
+
+func TestArticleService_SearchEscapesSnippets(t *testing.T) {
+	start, stop := repository.SearchHighlightStart, repository.SearchHighlightStop
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{
+			name:    "plain text",
+			snippet: "learn " + start + "go" + stop + " today",
+			want:    "learn <mark>go</mark> today",
+		},
+		{
+			name:    "script in content",
+			snippet: "<script>alert('" + start + "go" + stop + "')</script>",
+			want:    "&lt;script&gt;alert(&#39;<mark>go</mark>&#39;)&lt;/script&gt;",
+		},
+		{
+			name:    "markup written as mark tags",
+			snippet: "<mark onclick=\"x()\">" + start + "go" + stop + "</mark> & more",
+			want:    "&lt;mark onclick=&#34;x()&#34;&gt;<mark>go</mark>&lt;/mark&gt; &amp; more",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeSearchArticleRepository{results: []dto.ArticleSearchResult{{Id: uuid.New(), Snippet: tt.snippet}}}
+			service := newTestSearchService(repo)
+
+			result, err := service.Search(context.Background(), dto.ArticleSearchFilter{Query: "go"}, 1, 10)
+			require.NoError(t, err)
+			hits := result.Data.([]dto.ArticleSearchResult)
+			require.Len(t, hits, 1)
+			assert.Equal(t, tt.want, hits[0].Snippet)
+		})
+	}
+}
+
+// fakeSlugRepository holds the current slug of each row and the old slugs of renamed
+// articles, which stay taken by their article
+type fakeSlugRepository struct {
+	slugs   map[string]map[uuid.UUID]string
+	history map[string]uuid.UUID
+}
+
+func (r *fakeSlugRepository) GetTakenSlugs(ctx context.Context, table, base string, ownerId uuid.UUID) ([]string, error) {
+	matches := func(slug string) bool {
+		return slug == base || strings.HasPrefix(slug, base+"-")
+	}
+
+	var taken []string
+	for id, slug := range r.slugs[table] {
+		if id != ownerId && matches(slug) {
+			taken = append(taken, slug)
+		}
+	}
+	if table == repository.SlugTableArticles {
+		for slug, id := range r.history {
+			if id != ownerId && matches(slug) {
+				taken = append(taken, slug)
+			}
+		}
+	}
+	return taken, nil
+}
+
+// fakeSlugArticleUpdateRepository holds one article, updates it and answers slug redirects
+type fakeSlugArticleUpdateRepository struct {
+	repository.ArticleRepository
+	article   model.Article
+	redirects map[string]string
+	redirErr  error
+}
+
+func (r *fakeSlugArticleUpdateRepository) GetArticleById(ctx context.Context, id uuid.UUID) (model.Article, error) {
+	if id != r.article.Id {
+		return model.Article{}, sql.ErrNoRows
+	}
+	return r.article, nil
+}
+
+func (r *fakeSlugArticleUpdateRepository) UpdateArticle(ctx context.Context, article model.Article, editorId uuid.UUID) (model.Article, error) {
+	article.Version++
+	r.article = article
+	return article, nil
+}
+
+func (r *fakeSlugArticleUpdateRepository) GetSlugRedirect(ctx context.Context, oldSlug string) (string, error) {
+	if r.redirErr != nil {
+		return "", r.redirErr
+	}
+	current, ok := r.redirects[oldSlug]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return current, nil
+}
+
+func TestArticleService_FindSlugRedirect(t *testing.T) {
+	repo := &fakeSlugArticleUpdateRepository{redirects: map[string]string{"old-title": "new-title"}}
+	service := NewArticleService(repo, nil, nil, nil, nil, nil, nil, nil, nil, utils.NewErrorWrapper())
+
+	current, err := service.FindSlugRedirect(context.Background(), "old-title")
+	require.NoError(t, err)
+	assert.Equal(t, "new-title", current)
+
+	// A slug that never belonged to an article is no error, there is just no redirect
+	current, err = service.FindSlugRedirect(context.Background(), "never-used")
+	require.NoError(t, err)
+	assert.Empty(t, current)
+
+	repo.redirErr = errors.New("connection refused")
+	_, err = service.FindSlugRedirect(context.Background(), "old-title")
+	assert.Error(t, err)
+}
+
+func TestArticleService_UpdateArticleSlug(t *testing.T) {
+	other := uuid.New()
+	title := func(s string) *string { return &s }
+
+	tests := []struct {
+		name       string
+		pinned     bool
+		req        dto.UpdateArticleRequest
+		wantSlug   string
+		wantPinned bool
+		wantStatus int
+	}{
+		{name: "new title derives a new slug", req: dto.UpdateArticleRequest{Title: title("Go Tips")}, wantSlug: "go-tips"},
+		{name: "new title gets a suffix when the slug is taken", req: dto.UpdateArticleRequest{Title: title("Taken")}, wantSlug: "taken-2"},
+		{name: "new title keeps a pinned slug", pinned: true, req: dto.UpdateArticleRequest{Title: title("Go Tips")}, wantSlug: "my-article", wantPinned: true},
+		{name: "custom slug is normalized and pinned", req: dto.UpdateArticleRequest{Slug: title("  My Custom Slug ")}, wantSlug: "my-custom-slug", wantPinned: true},
+		{name: "custom slug replaces a pinned one", pinned: true, req: dto.UpdateArticleRequest{Slug: title("another")}, wantSlug: "another", wantPinned: true},
+		{name: "empty slug unpins and derives from the title", pinned: true, req: dto.UpdateArticleRequest{Slug: title("")}, wantSlug: "my-article"},
+		{name: "empty slug with a new title", pinned: true, req: dto.UpdateArticleRequest{Title: title("Go Tips"), Slug: title("")}, wantSlug: "go-tips"},
+		{name: "custom slug used by another article", req: dto.UpdateArticleRequest{Slug: title("taken")}, wantStatus: 409},
+		{name: "custom slug redirecting to another article", req: dto.UpdateArticleRequest{Slug: title("old-title")}, wantStatus: 409},
+		{name: "reserved custom slug", req: dto.UpdateArticleRequest{Slug: title("rss")}, wantStatus: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "Some content long enough for an article body."
+			article := model.Article{
+				Id: uuid.New(), Title: "My Article", Slug: "my-article", SlugPinned: tt.pinned,
+				Content: content, ContentHash: utils.ContentHash(content),
+				UserId: uuid.New(), CategoryId: uuid.New(), Status: model.ArticleStatusDraft, Version: 1,
+			}
+			repo := &fakeSlugArticleUpdateRepository{article: article}
+			slugs := &fakeSlugRepository{
+				slugs:   map[string]map[uuid.UUID]string{repository.SlugTableArticles: {article.Id: article.Slug, other: "taken"}},
+				history: map[string]uuid.UUID{"old-title": other},
+			}
+			errorWrapper := utils.NewErrorWrapper()
+			service := NewArticleService(repo, nil, nil, nil, NewValidationService(errorWrapper), NewSlugAllocator(slugs, errorWrapper), nil, nil, nil, errorWrapper)
+
+			updated, err := service.UpdateArticle(context.Background(), article.Id, article.Version, tt.req, uuid.New())
+			if tt.wantStatus != 0 {
+				var appErr *utils.AppError
+				require.True(t, errors.As(err, &appErr), "got %v", err)
+				assert.Equal(t, tt.wantStatus, appErr.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSlug, updated.Slug)
+			assert.Equal(t, tt.wantPinned, updated.SlugPinned)
+		})
+	}
+}
@@ -22,6 +22,7 @@ type articleTagService struct {
 	articleTagRepo    repository.ArticleTagRepository
 	tagRepo           repository.TagRepository
 	validationService ValidationService
+	cacheEvents       CacheEventPublisher
 }
 
 // RemoveTagFromArticle implements ArticleTagService.
@@ -51,6 +52,9 @@ func (a *articleTagService) RemoveTagFromArticle(ctx context.Context, articleId,
 		return fmt.Errorf("failed to remove tag from article: %v", err)
 	}
 
+	// Related articles are scored by tag overlap, the event also drops their cached lists
+	a.cacheEvents.Publish(ctx, articleChanged(articleId))
+
 	return nil
 }
 
@@ -105,6 +109,7 @@ func (a *articleTagService) AsignTagsByName(ctx context.Context, articleId uuid.
 				return fmt.Errorf("failed to create tag '%s': %v", tagName, createErr)
 			}
 			tagIds = append(tagIds, newTag.Id)
+			a.cacheEvents.Publish(ctx, tagListChanged())
 		} else {
 			tagIds = append(tagIds, tag.Id)
 		}
@@ -127,6 +132,9 @@ func (a *articleTagService) AsignTagsByName(ctx context.Context, articleId uuid.
 		return fmt.Errorf("failed to assign tags to article: %v", err)
 	}
 
+	// Related articles are scored by tag overlap, the event also drops their cached lists
+	a.cacheEvents.Publish(ctx, articleChanged(articleId))
+
 	return nil
 }
 
@@ -164,6 +172,9 @@ func (a *articleTagService) AssignTags(ctx context.Context, articleId uuid.UUID,
 		return fmt.Errorf("failed to assign tags to article: %v", err)
 	}
 
+	// Related articles are scored by tag overlap, the event also drops their cached lists
+	a.cacheEvents.Publish(ctx, articleChanged(articleId))
+
 	return nil
 }
 
@@ -221,10 +232,11 @@ func (a *articleTagService) FindTagByArticleId(ctx context.Context, articleId uu
 	return tags, nil
 }
 
-func NewArticleTagService(tagRepo repository.TagRepository, articleTagRepo repository.ArticleTagRepository, validationService ValidationService) ArticleTagService {
+func NewArticleTagService(tagRepo repository.TagRepository, articleTagRepo repository.ArticleTagRepository, validationService ValidationService, cacheEvents CacheEventPublisher) ArticleTagService {
 	return &articleTagService{
 		tagRepo:           tagRepo,
 		articleTagRepo:    articleTagRepo,
 		validationService: validationService,
+		cacheEvents:       cacheEvents,
 	}
 }
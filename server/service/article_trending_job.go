@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"develapar-server/model"
+	"develapar-server/utils"
+	"time"
+)
+
+// articleTrendingJob periodically recomputes the stored trending article scores
+type articleTrendingJob struct {
+	service  ArticleTrendingService
+	logger   utils.Logger
+	interval time.Duration
+}
+
+// Name implements BackgroundJob.
+func (j *articleTrendingJob) Name() string {
+	return "article_trending"
+}
+
+// Interval implements BackgroundJob.
+func (j *articleTrendingJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run implements BackgroundJob.
+func (j *articleTrendingJob) Run(ctx context.Context) error {
+	scored, err := j.service.RecomputeScores(ctx)
+	if err != nil {
+		return err
+	}
+
+	j.logger.Debug(ctx, "Recomputed trending scores",
+		utils.IntField(model.TrendingWindowDay, scored[model.TrendingWindowDay]),
+		utils.IntField(model.TrendingWindowWeek, scored[model.TrendingWindowWeek]),
+		utils.IntField(model.TrendingWindowMonth, scored[model.TrendingWindowMonth]),
+	)
+
+	return nil
+}
+
+// NewArticleTrendingJob creates the background job that recomputes trending article scores
+func NewArticleTrendingJob(service ArticleTrendingService, logger utils.Logger, interval time.Duration) BackgroundJob {
+	return &articleTrendingJob{
+		service:  service,
+		logger:   logger,
+		interval: interval,
+	}
+}
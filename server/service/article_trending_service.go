@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"develapar-server/model"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Weights of the interactions counted as trending points. Stronger signals of
+// interest weigh more than a single view.
+const (
+	trendingViewWeight     = 1
+	trendingLikeWeight     = 3
+	trendingCommentWeight  = 4
+	trendingBookmarkWeight = 5
+)
+
+type ArticleTrendingService interface {
+	// FindTrending returns the published articles ranked by their stored trending score in window
+	FindTrending(ctx context.Context, window string, page, limit int) (PaginationResult, error)
+	// RecomputeScores recomputes the stored scores of every trending window and returns
+	// the number of scored articles per window
+	RecomputeScores(ctx context.Context) (map[string]int, error)
+}
+
+type articleTrendingService struct {
+	repo              repository.ArticleTrendingRepository
+	paginationService PaginationService
+	gravity           float64
+	errorWrapper      utils.ErrorWrapper
+	now               func() time.Time
+}
+
+// FindTrending implements ArticleTrendingService.
+func (a *articleTrendingService) FindTrending(ctx context.Context, window string, page, limit int) (PaginationResult, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return PaginationResult{}, ctx.Err()
+	default:
+	}
+
+	// Validate window
+	if _, ok := model.TrendingWindows[window]; !ok {
+		return PaginationResult{}, a.errorWrapper.ValidationError(ctx, "window", fmt.Sprintf("window must be one of %s, %s or %s",
+			model.TrendingWindowDay, model.TrendingWindowWeek, model.TrendingWindowMonth))
+	}
+
+	// Parse and validate pagination query, results are always ordered by score
+	query, err := a.paginationService.ParseQuery(ctx, page, limit, "score", "desc")
+	if err != nil {
+		return PaginationResult{}, fmt.Errorf("pagination validation failed: %v", err)
+	}
+
+	articles, total, repoErr := a.repo.GetTrending(ctx, window, query.Offset, query.Limit)
+	if repoErr != nil {
+		// Check if context was cancelled during repository operation
+		if ctx.Err() != nil {
+			return PaginationResult{}, ctx.Err()
+		}
+		return PaginationResult{}, fmt.Errorf("failed to fetch trending articles: %v", repoErr)
+	}
+
+	// Create pagination result
+	result, paginationErr := a.paginationService.Paginate(ctx, articles, total, query)
+	if paginationErr != nil {
+		return PaginationResult{}, fmt.Errorf("failed to create pagination result: %v", paginationErr)
+	}
+
+	return result, nil
+}
+
+// RecomputeScores implements ArticleTrendingService.
+func (a *articleTrendingService) RecomputeScores(ctx context.Context) (map[string]int, error) {
+	scored := make(map[string]int, len(model.TrendingWindows))
+	for window, period := range model.TrendingWindows {
+		// Check context cancellation between windows
+		select {
+		case <-ctx.Done():
+			return scored, ctx.Err()
+		default:
+		}
+
+		activity, err := a.repo.GetActivity(ctx, period)
+		if err != nil {
+			if ctx.Err() != nil {
+				return scored, ctx.Err()
+			}
+			return scored, fmt.Errorf("failed to load %s trending activity: %v", window, err)
+		}
+
+		count, err := a.repo.ReplaceScores(ctx, window, scoreTrending(activity, a.now(), a.gravity))
+		if err != nil {
+			if ctx.Err() != nil {
+				return scored, ctx.Err()
+			}
+			return scored, fmt.Errorf("failed to recompute %s trending scores: %v", window, err)
+		}
+		scored[window] = count
+	}
+
+	return scored, nil
+}
+
+// scoreTrending scores the activity of a window, highest score first
+func scoreTrending(activity []model.ArticleActivity, now time.Time, gravity float64) []model.ArticleTrendingScore {
+	scores := make([]model.ArticleTrendingScore, len(activity))
+	for i, item := range activity {
+		scores[i] = model.ArticleTrendingScore{ArticleActivity: item, Score: trendingScore(item, now, gravity)}
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+	return scores
+}
+
+// trendingScore decays the weighted interactions of an article by its age:
+// points / (age_hours + 2) ^ gravity. Articles published in the future count as new.
+func trendingScore(activity model.ArticleActivity, now time.Time, gravity float64) float64 {
+	points := activity.Views*trendingViewWeight +
+		activity.Likes*trendingLikeWeight +
+		activity.Comments*trendingCommentWeight +
+		activity.Bookmarks*trendingBookmarkWeight
+
+	ageHours := math.Max(now.Sub(activity.PublishedAt).Hours(), 0)
+	return float64(points) / math.Pow(ageHours+2, gravity)
+}
+
+func NewArticleTrendingService(repo repository.ArticleTrendingRepository, paginationService PaginationService, gravity float64, errorWrapper utils.ErrorWrapper) ArticleTrendingService {
+	return &articleTrendingService{
+		repo:              repo,
+		paginationService: paginationService,
+		gravity:           gravity,
+		errorWrapper:      errorWrapper,
+		now:               time.Now,
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTrendingRepository serves activity per period and records the stored scores per window
+type fakeTrendingRepository struct {
+	repository.ArticleTrendingRepository
+	activity map[time.Duration][]model.ArticleActivity
+	stored   map[string][]model.ArticleTrendingScore
+	failOn   string
+	windows  []string
+}
+
+func (r *fakeTrendingRepository) GetActivity(ctx context.Context, period time.Duration) ([]model.ArticleActivity, error) {
+	return r.activity[period], nil
+}
+
+func (r *fakeTrendingRepository) ReplaceScores(ctx context.Context, window string, scores []model.ArticleTrendingScore) (int, error) {
+	r.windows = append(r.windows, window)
+	if window == r.failOn {
+		return 0, errors.New("connection refused")
+	}
+	r.stored[window] = scores
+	return len(scores), nil
+}
+
+func (r *fakeTrendingRepository) GetTrending(ctx context.Context, window string, offset, limit int) ([]dto.TrendingArticle, int, error) {
+	return []dto.TrendingArticle{}, 0, nil
+}
+
+func newTestTrendingService(repo *fakeTrendingRepository, now time.Time) ArticleTrendingService {
+	errorWrapper := utils.NewErrorWrapper()
+	service := NewArticleTrendingService(repo, NewPaginationService(NewValidationService(errorWrapper), errorWrapper), 1.5, errorWrapper)
+	service.(*articleTrendingService).now = func() time.Time { return now }
+	return service
+}
+
+func TestTrendingScore(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		activity model.ArticleActivity
+		gravity  float64
+		want     float64
+	}{
+		{name: "view weighs one point", activity: model.ArticleActivity{PublishedAt: now.Add(-2 * time.Hour), Views: 4}, gravity: 1, want: 1},
+		{name: "like weighs three points", activity: model.ArticleActivity{PublishedAt: now.Add(-2 * time.Hour), Likes: 4}, gravity: 1, want: 3},
+		{name: "comment weighs four points", activity: model.ArticleActivity{PublishedAt: now.Add(-2 * time.Hour), Comments: 4}, gravity: 1, want: 4},
+		{name: "bookmark weighs five points", activity: model.ArticleActivity{PublishedAt: now.Add(-2 * time.Hour), Bookmarks: 4}, gravity: 1, want: 5},
+		{name: "new article is divided by two", activity: model.ArticleActivity{PublishedAt: now, Views: 8}, gravity: 1, want: 4},
+		{name: "gravity raises the age", activity: model.ArticleActivity{PublishedAt: now.Add(-2 * time.Hour), Views: 16}, gravity: 2, want: 1},
+		{name: "future article counts as new", activity: model.ArticleActivity{PublishedAt: now.Add(time.Hour), Views: 8}, gravity: 1, want: 4},
+		{name: "no activity scores nothing", activity: model.ArticleActivity{PublishedAt: now}, gravity: 1.5, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, trendingScore(tt.activity, now, tt.gravity), 1e-9)
+		})
+	}
+}
+
+func TestScoreTrending_OlderArticlesDecay(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	old := model.ArticleActivity{ArticleId: uuid.New(), PublishedAt: now.Add(-72 * time.Hour), Views: 100}
+	fresh := model.ArticleActivity{ArticleId: uuid.New(), PublishedAt: now.Add(-time.Hour), Views: 10}
+
+	scores := scoreTrending([]model.ArticleActivity{old, fresh}, now, 1.5)
+	require.Len(t, scores, 2)
+	assert.Equal(t, fresh.ArticleId, scores[0].ArticleId, "fewer recent views outrank many old ones")
+	assert.Equal(t, old.ArticleId, scores[1].ArticleId)
+	assert.Equal(t, 100, scores[1].Views)
+}
+
+func TestArticleTrendingService_RecomputeScores(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("should score every window from the activity of its period", func(t *testing.T) {
+		repo := &fakeTrendingRepository{
+			activity: map[time.Duration][]model.ArticleActivity{
+				24 * time.Hour:      {{ArticleId: uuid.New(), PublishedAt: now, Views: 1}},
+				7 * 24 * time.Hour:  {{ArticleId: uuid.New(), PublishedAt: now, Views: 1}, {ArticleId: uuid.New(), PublishedAt: now, Likes: 1}},
+				30 * 24 * time.Hour: {},
+			},
+			stored: map[string][]model.ArticleTrendingScore{},
+		}
+
+		scored, err := newTestTrendingService(repo, now).RecomputeScores(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{
+			model.TrendingWindowDay:   1,
+			model.TrendingWindowWeek:  2,
+			model.TrendingWindowMonth: 0,
+		}, scored)
+		assert.Equal(t, repo.activity[7*24*time.Hour][1].ArticleId, repo.stored[model.TrendingWindowWeek][0].ArticleId, "the like outranks the view")
+	})
+
+	t.Run("should stop at a failing window", func(t *testing.T) {
+		repo := &fakeTrendingRepository{stored: map[string][]model.ArticleTrendingScore{}, failOn: model.TrendingWindowWeek}
+
+		scored, err := newTestTrendingService(repo, now).RecomputeScores(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), model.TrendingWindowWeek)
+		assert.NotContains(t, scored, model.TrendingWindowWeek)
+		assert.Equal(t, model.TrendingWindowWeek, repo.windows[len(repo.windows)-1])
+	})
+}
+
+func TestArticleTrendingService_FindTrendingWindow(t *testing.T) {
+	service := newTestTrendingService(&fakeTrendingRepository{}, time.Now())
+
+	tests := []struct {
+		window  string
+		wantErr bool
+	}{
+		{window: model.TrendingWindowDay},
+		{window: model.TrendingWindowWeek},
+		{window: model.TrendingWindowMonth},
+		{window: "1y", wantErr: true},
+		{window: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.window, func(t *testing.T) {
+			_, err := service.FindTrending(context.Background(), tt.window, 1, 10)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var appErr *utils.AppError
+			require.True(t, errors.As(err, &appErr))
+			assert.Equal(t, 400, appErr.StatusCode)
+		})
+	}
+}
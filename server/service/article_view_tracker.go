@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"develapar-server/model"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArticleViewTracker counts article reads without writing to the database on every request.
+// Views are deduplicated per visitor, buffered in memory and written in batches by Flush.
+type ArticleViewTracker interface {
+	// RecordView buffers a view and reports whether it was counted. Bots and repeated
+	// reads by the same visitor within the dedup window are not counted.
+	RecordView(ctx context.Context, view model.ArticleView) bool
+	Flush(ctx context.Context) error
+	// Close waits for a running flush to finish and writes the views still buffered.
+	// It is called on shutdown, after the last RecordView.
+	Close(ctx context.Context) error
+}
+
+const (
+	// maxBufferFactor caps the buffer at this multiple of MaxBufferSize while the database
+	// is unreachable; the oldest views are dropped beyond that
+	maxBufferFactor = 10
+	// maxViewFlushAttempts is how many flushes a view is retried in before it is dropped
+	maxViewFlushAttempts = 5
+	// earlyFlushTimeout bounds a flush triggered by a full buffer
+	earlyFlushTimeout = 30 * time.Second
+)
+
+// bufferedView is a view waiting to be written and the number of failed flushes it was in
+type bufferedView struct {
+	view     model.ArticleView
+	attempts int
+}
+
+type articleViewTracker struct {
+	repo          repository.ArticleViewRepository
+	logger        utils.Logger
+	dedupWindow   time.Duration
+	maxBufferSize int
+
+	mu     sync.Mutex
+	seen   map[string]time.Time // dedup key -> time the key expires
+	buffer []bufferedView
+
+	flushMu  sync.Mutex     // one flush at a time
+	flushing atomic.Bool    // an early flush triggered by a full buffer is running
+	wg       sync.WaitGroup // tracks the early flush so Close can wait for it
+}
+
+// RecordView implements ArticleViewTracker.
+func (t *articleViewTracker) RecordView(ctx context.Context, view model.ArticleView) bool {
+	if view.ArticleId == uuid.Nil || utils.IsBotUserAgent(view.UserAgent) {
+		return false
+	}
+
+	now := time.Now()
+	key := viewDedupKey(view)
+
+	t.mu.Lock()
+	if expiresAt, ok := t.seen[key]; ok && now.Before(expiresAt) {
+		t.mu.Unlock()
+		return false
+	}
+	t.seen[key] = now.Add(t.dedupWindow)
+
+	view.Id = uuid.Must(uuid.NewV7())
+	view.ViewedAt = now
+	t.buffer = append(t.buffer, bufferedView{view: view})
+	full := len(t.buffer) >= t.maxBufferSize
+	t.mu.Unlock()
+
+	if full && t.flushing.CompareAndSwap(false, true) {
+		// Flush outside the request, it must not be cancelled with it
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			defer t.flushing.Store(false)
+			flushCtx, cancel := context.WithTimeout(context.Background(), earlyFlushTimeout)
+			defer cancel()
+			if err := t.Flush(flushCtx); err != nil {
+				t.logger.Error(flushCtx, "Failed to flush article views", err)
+			}
+		}()
+	}
+
+	return true
+}
+
+// Flush implements ArticleViewTracker.
+// It writes all buffered views in one batch and forgets expired dedup keys. When the batch
+// is refused because of its data, the views are written one by one so a single bad view
+// cannot hold back the others, and the bad ones are dropped. Views that failed for any
+// other reason are put back and retried with the next flush, up to maxViewFlushAttempts.
+func (t *articleViewTracker) Flush(ctx context.Context) error {
+	t.flushMu.Lock()
+	defer t.flushMu.Unlock()
+
+	now := time.Now()
+	t.mu.Lock()
+	batch := t.buffer
+	t.buffer = nil
+	for key, expiresAt := range t.seen {
+		if !now.Before(expiresAt) {
+			delete(t.seen, key)
+		}
+	}
+	t.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	views := make([]model.ArticleView, len(batch))
+	for i, buffered := range batch {
+		views[i] = buffered.view
+	}
+
+	err := t.repo.RecordViews(ctx, views)
+	if err == nil {
+		t.logger.Debug(ctx, "Flushed article views", utils.IntField("views", len(batch)))
+		return nil
+	}
+	if !repository.IsDataError(err) {
+		t.requeue(ctx, batch)
+		return err
+	}
+
+	var failed []bufferedView
+	invalid := 0
+	for _, buffered := range batch {
+		viewErr := t.repo.RecordViews(ctx, []model.ArticleView{buffered.view})
+		switch {
+		case viewErr == nil:
+		case repository.IsDataError(viewErr):
+			invalid++
+		default:
+			err = viewErr
+			failed = append(failed, buffered)
+		}
+	}
+	if invalid > 0 {
+		t.logger.Warn(ctx, "Dropped invalid article views", utils.IntField("dropped", invalid))
+	}
+	if len(failed) > 0 {
+		t.requeue(ctx, failed)
+		return err
+	}
+	return nil
+}
+
+// Close implements ArticleViewTracker.
+func (t *articleViewTracker) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return t.Flush(ctx)
+}
+
+// requeue puts failed views in front of the views buffered since. Views that failed
+// maxViewFlushAttempts times are dropped.
+func (t *articleViewTracker) requeue(ctx context.Context, batch []bufferedView) {
+	retry := make([]bufferedView, 0, len(batch))
+	for _, buffered := range batch {
+		buffered.attempts++
+		if buffered.attempts < maxViewFlushAttempts {
+			retry = append(retry, buffered)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	dropped := len(batch) - len(retry)
+	t.buffer = append(retry, t.buffer...)
+	if limit := t.maxBufferSize * maxBufferFactor; len(t.buffer) > limit {
+		dropped += len(t.buffer) - limit
+		t.buffer = append([]bufferedView(nil), t.buffer[len(t.buffer)-limit:]...)
+	}
+	if dropped > 0 {
+		t.logger.Warn(ctx, "Dropped buffered article views", utils.IntField("dropped", dropped))
+	}
+}
+
+// viewDedupKey identifies a visitor of an article: the user when logged in,
+// otherwise a hash of IP address and User-Agent
+func viewDedupKey(view model.ArticleView) string {
+	if view.UserId != uuid.Nil {
+		return view.ArticleId.String() + ":u:" + view.UserId.String()
+	}
+	sum := sha256.Sum256([]byte(view.IPAddress + "|" + view.UserAgent))
+	return view.ArticleId.String() + ":a:" + hex.EncodeToString(sum[:16])
+}
+
+// NewArticleViewTracker creates a new instance of ArticleViewTracker
+func NewArticleViewTracker(repo repository.ArticleViewRepository, logger utils.Logger, dedupWindow time.Duration, maxBufferSize int) ArticleViewTracker {
+	return &articleViewTracker{
+		repo:          repo,
+		logger:        logger,
+		dedupWindow:   dedupWindow,
+		maxBufferSize: maxBufferSize,
+		seen:          make(map[string]time.Time),
+	}
+}
+
+// articleViewFlushJob periodically writes buffered article views to the database
+type articleViewFlushJob struct {
+	tracker  ArticleViewTracker
+	interval time.Duration
+}
+
+// Name implements BackgroundJob.
+func (j *articleViewFlushJob) Name() string {
+	return "article_view_flush"
+}
+
+// Interval implements BackgroundJob.
+func (j *articleViewFlushJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run implements BackgroundJob.
+func (j *articleViewFlushJob) Run(ctx context.Context) error {
+	return j.tracker.Flush(ctx)
+}
+
+// NewArticleViewFlushJob creates the background job that flushes buffered article views
+func NewArticleViewFlushJob(tracker ArticleViewTracker, interval time.Duration) BackgroundJob {
+	return &articleViewFlushJob{
+		tracker:  tracker,
+		interval: interval,
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"develapar-server/model"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeArticleViewRepository records written views; fail decides whether a write fails
+type fakeArticleViewRepository struct {
+	mu       sync.Mutex
+	recorded []model.ArticleView
+	writes   int
+	fail     func(views []model.ArticleView) error
+	release  chan struct{}
+}
+
+func (r *fakeArticleViewRepository) RecordViews(ctx context.Context, views []model.ArticleView) error {
+	if r.release != nil {
+		<-r.release
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writes++
+	if r.fail != nil {
+		if err := r.fail(views); err != nil {
+			return err
+		}
+	}
+	r.recorded = append(r.recorded, views...)
+	return nil
+}
+
+func (r *fakeArticleViewRepository) recordedArticles() []uuid.UUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]uuid.UUID, len(r.recorded))
+	for i, view := range r.recorded {
+		ids[i] = view.ArticleId
+	}
+	return ids
+}
+
+func newTestViewTracker(repo *fakeArticleViewRepository, maxBufferSize int) *articleViewTracker {
+	return NewArticleViewTracker(repo, newTestLogger(), time.Hour, maxBufferSize).(*articleViewTracker)
+}
+
+func testView(articleId uuid.UUID) model.ArticleView {
+	return model.ArticleView{ArticleId: articleId, IPAddress: "203.0.113.7", UserAgent: "Mozilla/5.0"}
+}
+
+func TestArticleViewTracker_RecordView(t *testing.T) {
+	tracker := newTestViewTracker(&fakeArticleViewRepository{}, 100)
+	ctx := context.Background()
+	articleId := uuid.New()
+	userId := uuid.New()
+
+	tests := []struct {
+		name string
+		view model.ArticleView
+		want bool
+	}{
+		{name: "first anonymous view is counted", view: testView(articleId), want: true},
+		{name: "repeated anonymous view is not counted", view: testView(articleId), want: false},
+		{name: "same visitor on another article is counted", view: testView(uuid.New()), want: true},
+		{name: "signed in user is counted separately", view: model.ArticleView{ArticleId: articleId, UserId: userId, IPAddress: "203.0.113.7", UserAgent: "Mozilla/5.0"}, want: true},
+		{name: "signed in user from another address is not counted again", view: model.ArticleView{ArticleId: articleId, UserId: userId, IPAddress: "198.51.100.1", UserAgent: "curl/8.0"}, want: false},
+		{name: "bot is not counted", view: model.ArticleView{ArticleId: articleId, IPAddress: "198.51.100.2", UserAgent: "Googlebot/2.1 (+http://www.google.com/bot.html)"}, want: false},
+		{name: "missing article is not counted", view: testView(uuid.Nil), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tracker.RecordView(ctx, tt.view))
+		})
+	}
+	assert.Len(t, tracker.buffer, 3)
+}
+
+func TestArticleViewTracker_Flush(t *testing.T) {
+	ctx := context.Background()
+	badArticle := uuid.New()
+	dataErr := &pq.Error{Code: "23503"}
+	connErr := errors.New("connection refused")
+
+	t.Run("should write the buffer in one batch", func(t *testing.T) {
+		repo := &fakeArticleViewRepository{}
+		tracker := newTestViewTracker(repo, 100)
+		first, second := uuid.New(), uuid.New()
+		tracker.RecordView(ctx, testView(first))
+		tracker.RecordView(ctx, testView(second))
+
+		require.NoError(t, tracker.Flush(ctx))
+		assert.Equal(t, []uuid.UUID{first, second}, repo.recordedArticles())
+		assert.Equal(t, 1, repo.writes)
+		assert.Empty(t, tracker.buffer)
+	})
+
+	t.Run("should drop only the invalid view when the batch is refused for its data", func(t *testing.T) {
+		repo := &fakeArticleViewRepository{fail: func(views []model.ArticleView) error {
+			for _, view := range views {
+				if view.ArticleId == badArticle {
+					return dataErr
+				}
+			}
+			return nil
+		}}
+		tracker := newTestViewTracker(repo, 100)
+		first, second := uuid.New(), uuid.New()
+		tracker.RecordView(ctx, testView(first))
+		tracker.RecordView(ctx, testView(badArticle))
+		tracker.RecordView(ctx, testView(second))
+
+		require.NoError(t, tracker.Flush(ctx))
+		assert.Equal(t, []uuid.UUID{first, second}, repo.recordedArticles())
+		assert.Empty(t, tracker.buffer)
+	})
+
+	t.Run("should retry views after a connection error", func(t *testing.T) {
+		down := true
+		repo := &fakeArticleViewRepository{fail: func(views []model.ArticleView) error {
+			if down {
+				return connErr
+			}
+			return nil
+		}}
+		tracker := newTestViewTracker(repo, 100)
+		first, second := uuid.New(), uuid.New()
+		tracker.RecordView(ctx, testView(first))
+
+		assert.ErrorIs(t, tracker.Flush(ctx), connErr)
+		tracker.RecordView(ctx, testView(second))
+
+		down = false
+		require.NoError(t, tracker.Flush(ctx))
+		assert.Equal(t, []uuid.UUID{first, second}, repo.recordedArticles(), "requeued views keep their order")
+	})
+
+	t.Run("should drop views after the last attempt", func(t *testing.T) {
+		repo := &fakeArticleViewRepository{fail: func(views []model.ArticleView) error { return connErr }}
+		tracker := newTestViewTracker(repo, 100)
+		tracker.RecordView(ctx, testView(uuid.New()))
+
+		for i := 1; i < maxViewFlushAttempts; i++ {
+			assert.ErrorIs(t, tracker.Flush(ctx), connErr)
+			require.Len(t, tracker.buffer, 1)
+			assert.Equal(t, i, tracker.buffer[0].attempts)
+		}
+		assert.ErrorIs(t, tracker.Flush(ctx), connErr)
+		assert.Empty(t, tracker.buffer)
+		assert.NoError(t, tracker.Flush(ctx))
+		assert.Equal(t, maxViewFlushAttempts, repo.writes)
+	})
+}
+
+func TestArticleViewTracker_CloseWaitsForEarlyFlush(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeArticleViewRepository{release: make(chan struct{})}
+	tracker := newTestViewTracker(repo, 2)
+
+	tracker.RecordView(ctx, testView(uuid.New()))
+	tracker.RecordView(ctx, testView(uuid.New())) // full buffer, starts an early flush
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- tracker.Close(ctx)
+	}()
+
+	select {
+	case <-closed:
+		t.Fatal("Close returned while the early flush was still writing")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(repo.release)
+	select {
+	case err := <-closed:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Close did not return after the early flush finished")
+	}
+	assert.Len(t, repo.recordedArticles(), 2)
+}
+
+func TestArticleViewTracker_CloseHonoursContext(t *testing.T) {
+	repo := &fakeArticleViewRepository{release: make(chan struct{})}
+	defer close(repo.release)
+	tracker := newTestViewTracker(repo, 1)
+	tracker.RecordView(context.Background(), testView(uuid.New()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, tracker.Close(ctx), context.DeadlineExceeded)
+}
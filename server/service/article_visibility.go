@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ArticleAccessPurpose is the purpose claim of tokens unlocking a password protected article
+	ArticleAccessPurpose = "article_access"
+	// articleAccessTokenTTL is how long an unlocked article stays readable with its token
+	articleAccessTokenTTL = 30 * time.Minute
+	// minArticlePasswordLength is the shortest accepted article password
+	minArticlePasswordLength = 8
+)
+
+// ArticleViewer is who reads an article: the signed in user, if any, and the access
+// token of a password protected article sent along with the request
+type ArticleViewer struct {
+	UserId      uuid.UUID
+	Role        string
+	AccessToken string
+}
+
+// isStaff reports whether the viewer is an editor or admin, who see every article
+func (v ArticleViewer) isStaff() bool {
+	return v.Role == "editor" || v.Role == "admin"
+}
+
+// ownsListing reports whether the viewer sees every article of the author listing of userId:
+// the author themselves, editors and admins
+func (v ArticleViewer) ownsListing(userId uuid.UUID) bool {
+	return v.isStaff() || (v.UserId != uuid.Nil && v.UserId == userId)
+}
+
+// canSeeHidden reports whether the viewer may read the article whatever its status and visibility:
+// editors, admins and the authors with edit rights
+func (a *articleService) canSeeHidden(ctx context.Context, article model.Article, viewer ArticleViewer) (bool, error) {
+	if viewer.isStaff() {
+		return true, nil
+	}
+	if viewer.UserId == uuid.Nil {
+		return false, nil
+	}
+	return a.CanEdit(ctx, article.Id, viewer.UserId)
+}
+
+// AuthorizeView implements ArticleService.
+// Unpublished and private articles answer as not found so their existence is not revealed,
+// password protected ones answer as forbidden with the article id to unlock it with.
+func (a *articleService) AuthorizeView(ctx context.Context, article model.Article, viewer ArticleViewer) error {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	live := isArticleLive(article)
+	if live && article.Visibility != model.ArticleVisibilityPrivate && article.Visibility != model.ArticleVisibilityPasswordProtected {
+		return nil
+	}
+
+	// Authors, editors and admins always see the article
+	hiddenVisible, err := a.canSeeHidden(ctx, article, viewer)
+	if err != nil {
+		return err
+	}
+	if hiddenVisible {
+		return nil
+	}
+
+	if !live || article.Visibility == model.ArticleVisibilityPrivate {
+		return a.errorWrapper.NotFoundError(ctx, "Article")
+	}
+
+	if viewer.AccessToken != "" {
+		subject, err := a.jwtService.VerifyScopedToken(viewer.AccessToken, ArticleAccessPurpose)
+		if err == nil {
+			// The password hash is not kept with cached articles, it is read from the
+			// database so a changed password revokes the token right away
+			article.PasswordHash, err = a.repo.GetArticlePasswordHash(ctx, article.Id)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if errors.Is(err, sql.ErrNoRows) {
+					return a.errorWrapper.NotFoundError(ctx, "Article")
+				}
+				return fmt.Errorf("failed to fetch article password: %v", err)
+			}
+			if subject == articleAccessSubject(article) {
+				return nil
+			}
+		}
+	}
+
+	appErr := a.errorWrapper.ForbiddenError(ctx, "This article is password protected")
+	appErr.Details = map[string]string{
+		"article_id": article.Id.String(),
+		"visibility": article.Visibility,
+	}
+	return appErr
+}
+
+// UnlockArticle implements ArticleService.
+func (a *articleService) UnlockArticle(ctx context.Context, articleId uuid.UUID, password string) (dto.ArticleAccessResponse, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return dto.ArticleAccessResponse{}, ctx.Err()
+	default:
+	}
+
+	article, err := a.repo.GetArticleById(ctx, articleId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return dto.ArticleAccessResponse{}, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.ArticleAccessResponse{}, a.errorWrapper.NotFoundError(ctx, "Article")
+		}
+		return dto.ArticleAccessResponse{}, fmt.Errorf("failed to fetch article: %v", err)
+	}
+
+	// Unpublished articles and other visibilities answer the same as a missing article,
+	// private ones stay hidden. Articles in the trash are not found at all.
+	if !isArticleLive(article) || article.Visibility != model.ArticleVisibilityPasswordProtected {
+		return dto.ArticleAccessResponse{}, a.errorWrapper.NotFoundError(ctx, "Article")
+	}
+	if err := a.passwordHasher.ComparePasswordHash(article.PasswordHash, password); err != nil {
+		return dto.ArticleAccessResponse{}, a.errorWrapper.UnauthorizedError(ctx, "Incorrect article password")
+	}
+
+	token, expiresAt, err := a.jwtService.GenerateScopedToken(ArticleAccessPurpose, articleAccessSubject(article), articleAccessTokenTTL)
+	if err != nil {
+		return dto.ArticleAccessResponse{}, fmt.Errorf("failed to issue article access token: %v", err)
+	}
+
+	return dto.ArticleAccessResponse{
+		ArticleId: article.Id,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// applyVisibility sets the requested visibility on the article. Password protected
+// articles need a password, stored hashed; other visibilities drop the stored hash.
+// A nil visibility keeps the current one, a nil password keeps the current hash.
+func (a *articleService) applyVisibility(ctx context.Context, article *model.Article, visibility, password *string) error {
+	if visibility != nil && *visibility != "" {
+		article.Visibility = *visibility
+	}
+	if article.Visibility == "" {
+		article.Visibility = model.ArticleVisibilityPublic
+	}
+
+	switch article.Visibility {
+	case model.ArticleVisibilityPublic, model.ArticleVisibilityUnlisted, model.ArticleVisibilityPrivate:
+		if password != nil && *password != "" {
+			return a.errorWrapper.ValidationError(ctx, "password", "A password can only be set on password protected articles")
+		}
+		article.PasswordHash = ""
+		return nil
+	case model.ArticleVisibilityPasswordProtected:
+		// Needs a password, checked below
+	default:
+		return a.errorWrapper.ValidationError(ctx, "visibility", "visibility must be public, unlisted, private or password_protected")
+	}
+
+	if password == nil || *password == "" {
+		if article.PasswordHash == "" {
+			return a.errorWrapper.ValidationError(ctx, "password", "Password protected articles need a password")
+		}
+		return nil
+	}
+	if len(*password) < minArticlePasswordLength {
+		return a.errorWrapper.ValidationError(ctx, "password", fmt.Sprintf("Password must be at least %d characters long", minArticlePasswordLength))
+	}
+
+	hash, err := a.passwordHasher.EncryptPassword(*password)
+	if err != nil {
+		return fmt.Errorf("failed to hash article password: %v", err)
+	}
+	article.PasswordHash = hash
+	return nil
+}
+
+// isArticleLive reports whether the article is published and not scheduled for the future
+func isArticleLive(article model.Article) bool {
+	if article.Status != model.ArticleStatusPublished {
+		return false
+	}
+	return article.PublishAt == nil || !article.PublishAt.After(time.Now())
+}
+
+// articleAccessSubject binds an access token to the article and its current password,
+// changing the password revokes every token issued for the old one
+func articleAccessSubject(article model.Article) string {
+	fingerprint := sha256.Sum256([]byte(article.PasswordHash))
+	return article.Id.String() + "." + hex.EncodeToString(fingerprint[:8])
+}
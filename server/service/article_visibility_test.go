@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/config"
+	"develapar-server/model"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIdArticleRepository finds the articles not in the trash by id
+type fakeIdArticleRepository struct {
+	repository.ArticleRepository
+	articles map[uuid.UUID]model.Article
+}
+
+func (r *fakeIdArticleRepository) GetArticleById(ctx context.Context, id uuid.UUID) (model.Article, error) {
+	article, ok := r.articles[id]
+	if !ok {
+		return model.Article{}, sql.ErrNoRows
+	}
+	return article, nil
+}
+
+func (r *fakeIdArticleRepository) GetArticlePasswordHash(ctx context.Context, id uuid.UUID) (string, error) {
+	article, ok := r.articles[id]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return article.PasswordHash, nil
+}
+
+func TestArticleService_UnlockArticle(t *testing.T) {
+	hasher := utils.NewPasswordHasher()
+	hash, err := hasher.EncryptPassword("correct horse")
+	require.NoError(t, err)
+	future := time.Now().Add(time.Hour)
+
+	protected := func(status string) model.Article {
+		return model.Article{Id: uuid.New(), Status: status, Visibility: model.ArticleVisibilityPasswordProtected, PasswordHash: hash}
+	}
+	published := protected(model.ArticleStatusPublished)
+	draft := protected(model.ArticleStatusDraft)
+	scheduled := protected(model.ArticleStatusPublished)
+	scheduled.PublishAt = &future
+	public := model.Article{Id: uuid.New(), Status: model.ArticleStatusPublished, Visibility: model.ArticleVisibilityPublic}
+
+	repo := &fakeIdArticleRepository{articles: map[uuid.UUID]model.Article{
+		published.Id: published, draft.Id: draft, scheduled.Id: scheduled, public.Id: public,
+	}}
+	jwtService := NewJwtService(config.SecurityConfig{Key: "test-secret", Durasi: time.Hour, Issues: "test"})
+	service := NewArticleService(repo, nil, nil, nil, nil, nil, hasher, jwtService, nil, utils.NewErrorWrapper())
+
+	tests := []struct {
+		name       string
+		articleId  uuid.UUID
+		password   string
+		wantStatus int
+	}{
+		{name: "correct password", articleId: published.Id, password: "correct horse"},
+		{name: "wrong password", articleId: published.Id, password: "battery staple", wantStatus: 401},
+		{name: "unpublished article", articleId: draft.Id, password: "correct horse", wantStatus: 404},
+		{name: "scheduled article", articleId: scheduled.Id, password: "correct horse", wantStatus: 404},
+		{name: "article without password", articleId: public.Id, password: "correct horse", wantStatus: 404},
+		{name: "deleted or missing article", articleId: uuid.New(), password: "correct horse", wantStatus: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access, err := service.UnlockArticle(context.Background(), tt.articleId, tt.password)
+			if tt.wantStatus != 0 {
+				var appErr *utils.AppError
+				require.True(t, errors.As(err, &appErr), "got %v", err)
+				assert.Equal(t, tt.wantStatus, appErr.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.articleId, access.ArticleId)
+			assert.NotEmpty(t, access.Token)
+		})
+	}
+}
+
+func TestArticleService_PasswordLength(t *testing.T) {
+	service := NewArticleService(nil, nil, nil, nil, nil, nil, utils.NewPasswordHasher(), nil, nil, utils.NewErrorWrapper()).(*articleService)
+	visibility := model.ArticleVisibilityPasswordProtected
+
+	short := "1234567"
+	article := model.Article{}
+	var appErr *utils.AppError
+	require.True(t, errors.As(service.applyVisibility(context.Background(), &article, &visibility, &short), &appErr))
+	assert.Equal(t, 400, appErr.StatusCode)
+
+	long := "12345678"
+	require.NoError(t, service.applyVisibility(context.Background(), &article, &visibility, &long))
+	assert.NotEmpty(t, article.PasswordHash)
+}
+
+func TestArticleService_AuthorizeViewWithToken(t *testing.T) {
+	hasher := utils.NewPasswordHasher()
+	hash, err := hasher.EncryptPassword("correct horse")
+	require.NoError(t, err)
+
+	article := model.Article{Id: uuid.New(), Status: model.ArticleStatusPublished, Visibility: model.ArticleVisibilityPasswordProtected, PasswordHash: hash}
+	repo := &fakeIdArticleRepository{articles: map[uuid.UUID]model.Article{article.Id: article}}
+	jwtService := NewJwtService(config.SecurityConfig{Key: "test-secret", Durasi: time.Hour, Issues: "test"})
+	service := NewArticleService(repo, nil, nil, nil, nil, nil, hasher, jwtService, nil, utils.NewErrorWrapper())
+
+	access, err := service.UnlockArticle(context.Background(), article.Id, "correct horse")
+	require.NoError(t, err)
+
+	// Cached articles come without their password hash
+	cached := article
+	cached.PasswordHash = ""
+
+	tests := []struct {
+		name       string
+		token      string
+		rehash     bool
+		remove     bool
+		wantStatus int
+	}{
+		{name: "valid token", token: access.Token},
+		{name: "no token", wantStatus: 403},
+		{name: "invalid token", token: "not-a-token", wantStatus: 403},
+		{name: "password changed after unlocking", token: access.Token, rehash: true, wantStatus: 403},
+		{name: "article deleted after caching", token: access.Token, remove: true, wantStatus: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := article
+			if tt.rehash {
+				stored.PasswordHash, err = hasher.EncryptPassword("battery staple")
+				require.NoError(t, err)
+			}
+			repo.articles[article.Id] = stored
+			if tt.remove {
+				delete(repo.articles, article.Id)
+			}
+
+			err := service.AuthorizeView(context.Background(), cached, ArticleViewer{AccessToken: tt.token})
+			if tt.wantStatus != 0 {
+				var appErr *utils.AppError
+				require.True(t, errors.As(err, &appErr), "got %v", err)
+				assert.Equal(t, tt.wantStatus, appErr.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
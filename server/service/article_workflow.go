@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Editorial workflow actions
+const (
+	ArticleActionSubmit  = "submit"
+	ArticleActionApprove = "approve"
+	ArticleActionReject  = "reject"
+	ArticleActionPublish = "publish"
+	ArticleActionArchive = "archive"
+)
+
+// articleTransition describes which statuses an action may start from and where it leads
+type articleTransition struct {
+	from []string
+	to   string
+}
+
+// articleWorkflow is the editorial state machine:
+// draft -> in_review -> approved -> (scheduled ->) published -> archived.
+// Publishing an approved article whose publish time is in the future leads to
+// scheduled instead; the scheduler job moves it on to published.
+var articleWorkflow = map[string]articleTransition{
+	ArticleActionSubmit:  {from: []string{model.ArticleStatusDraft}, to: model.ArticleStatusInReview},
+	ArticleActionApprove: {from: []string{model.ArticleStatusInReview}, to: model.ArticleStatusApproved},
+	ArticleActionReject:  {from: []string{model.ArticleStatusInReview, model.ArticleStatusApproved}, to: model.ArticleStatusDraft},
+	ArticleActionPublish: {from: []string{model.ArticleStatusApproved}, to: model.ArticleStatusPublished},
+	ArticleActionArchive: {from: []string{model.ArticleStatusDraft, model.ArticleStatusApproved, model.ArticleStatusScheduled, model.ArticleStatusPublished}, to: model.ArticleStatusArchived},
+}
+
+// SubmitArticle implements ArticleService.
+func (a *articleService) SubmitArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (model.Article, error) {
+	return a.transition(ctx, id, ArticleActionSubmit, actorID, "")
+}
+
+// ApproveArticle implements ArticleService.
+func (a *articleService) ApproveArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID, note string) (model.Article, error) {
+	return a.transition(ctx, id, ArticleActionApprove, actorID, note)
+}
+
+// RejectArticle implements ArticleService.
+func (a *articleService) RejectArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID, reason string) (model.Article, error) {
+	if strings.TrimSpace(reason) == "" {
+		return model.Article{}, a.errorWrapper.ValidationError(ctx, "reason", "Reason is required when rejecting an article")
+	}
+	return a.transition(ctx, id, ArticleActionReject, actorID, reason)
+}
+
+// PublishArticle implements ArticleService.
+func (a *articleService) PublishArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (model.Article, error) {
+	return a.transition(ctx, id, ArticleActionPublish, actorID, "")
+}
+
+// ArchiveArticle implements ArticleService.
+func (a *articleService) ArchiveArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (model.Article, error) {
+	return a.transition(ctx, id, ArticleActionArchive, actorID, "")
+}
+
+// FindStatusTransitions implements ArticleService.
+func (a *articleService) FindStatusTransitions(ctx context.Context, id uuid.UUID) ([]model.ArticleStatusTransition, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	// Validate ID
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("article ID must be greater than 0")
+	}
+
+	transitions, err := a.repo.GetStatusTransitions(ctx, id)
+	if err != nil {
+		// Check if context was cancelled during repository operation
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to fetch article status transitions: %v", err)
+	}
+
+	return transitions, nil
+}
+
+// transition applies a workflow action to an article and records it.
+// Actions that are not allowed from the current status return a conflict error.
+func (a *articleService) transition(ctx context.Context, id uuid.UUID, action string, actorID uuid.UUID, reason string) (model.Article, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.Article{}, ctx.Err()
+	default:
+	}
+
+	// Validate ID
+	if id == uuid.Nil {
+		return model.Article{}, fmt.Errorf("article ID must be greater than 0")
+	}
+
+	rule, ok := articleWorkflow[action]
+	if !ok {
+		return model.Article{}, fmt.Errorf("unknown article action: %s", action)
+	}
+
+	article, err := a.repo.GetArticleById(ctx, id)
+	if err != nil {
+		// Check if context was cancelled during repository operation
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Article{}, a.errorWrapper.NotFoundError(ctx, "Article")
+		}
+		return model.Article{}, fmt.Errorf("failed to fetch article: %v", err)
+	}
+
+	if !containsStatus(rule.from, article.Status) {
+		return model.Article{}, a.illegalTransitionError(ctx, action, article.Status)
+	}
+
+	to := rule.to
+	var publishAt *time.Time
+	if action == ArticleActionPublish {
+		now := time.Now()
+		if article.PublishAt != nil && article.PublishAt.After(now) {
+			to = model.ArticleStatusScheduled
+		} else if article.PublishAt == nil {
+			publishAt = &now
+		}
+	}
+
+	updated, err := a.repo.TransitionStatus(ctx, id, article.Status, to, publishAt, actorID, strings.TrimSpace(reason))
+	if err != nil {
+		// Check if context was cancelled during repository operation
+		if ctx.Err() != nil {
+			return model.Article{}, ctx.Err()
+		}
+		// The status changed between reading and updating the article
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Article{}, a.illegalTransitionError(ctx, action, article.Status)
+		}
+		return model.Article{}, fmt.Errorf("failed to %s article: %v", action, err)
+	}
+
+	return updated, nil
+}
+
+// illegalTransitionError builds the conflict error returned for actions not allowed from a status
+func (a *articleService) illegalTransitionError(ctx context.Context, action, status string) error {
+	appErr := a.errorWrapper.ConflictError(ctx, "article", fmt.Sprintf("Cannot %s an article with status %s", action, status))
+	appErr.Details = map[string]string{
+		"action": action,
+		"status": status,
+	}
+	return appErr
+}
+
+func containsStatus(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWorkflowArticleRepository holds one article and applies status transitions to it.
+// When race is set, the status changes before the transition is applied.
+type fakeWorkflowArticleRepository struct {
+	repository.ArticleRepository
+	article     model.Article
+	race        bool
+	transitions int
+	to          string
+	publishAt   *time.Time
+	reason      string
+}
+
+func (r *fakeWorkflowArticleRepository) GetArticleById(ctx context.Context, id uuid.UUID) (model.Article, error) {
+	if id != r.article.Id {
+		return model.Article{}, sql.ErrNoRows
+	}
+	return r.article, nil
+}
+
+func (r *fakeWorkflowArticleRepository) TransitionStatus(ctx context.Context, articleId uuid.UUID, from, to string, publishAt *time.Time, actorId uuid.UUID, reason string) (model.Article, error) {
+	if r.race || articleId != r.article.Id || from != r.article.Status {
+		return model.Article{}, sql.ErrNoRows
+	}
+	r.transitions++
+	r.to, r.publishAt, r.reason = to, publishAt, reason
+	r.article.Status = to
+	if publishAt != nil {
+		r.article.PublishAt = publishAt
+	}
+	return r.article, nil
+}
+
+func applyArticleAction(service ArticleService, action string, id uuid.UUID, reason string) (model.Article, error) {
+	ctx := context.Background()
+	actor := uuid.New()
+	switch action {
+	case ArticleActionSubmit:
+		return service.SubmitArticle(ctx, id, actor)
+	case ArticleActionApprove:
+		return service.ApproveArticle(ctx, id, actor, reason)
+	case ArticleActionReject:
+		return service.RejectArticle(ctx, id, actor, reason)
+	case ArticleActionPublish:
+		return service.PublishArticle(ctx, id, actor)
+	default:
+		return service.ArchiveArticle(ctx, id, actor)
+	}
+}
+
+func TestArticleService_WorkflowTransitions(t *testing.T) {
+	statuses := []string{
+		model.ArticleStatusDraft, model.ArticleStatusInReview, model.ArticleStatusApproved,
+		model.ArticleStatusScheduled, model.ArticleStatusPublished, model.ArticleStatusArchived,
+	}
+	allowed := map[string]map[string]string{
+		ArticleActionSubmit:  {model.ArticleStatusDraft: model.ArticleStatusInReview},
+		ArticleActionApprove: {model.ArticleStatusInReview: model.ArticleStatusApproved},
+		ArticleActionReject: {
+			model.ArticleStatusInReview: model.ArticleStatusDraft,
+			model.ArticleStatusApproved: model.ArticleStatusDraft,
+		},
+		ArticleActionPublish: {model.ArticleStatusApproved: model.ArticleStatusPublished},
+		ArticleActionArchive: {
+			model.ArticleStatusDraft:     model.ArticleStatusArchived,
+			model.ArticleStatusApproved:  model.ArticleStatusArchived,
+			model.ArticleStatusScheduled: model.ArticleStatusArchived,
+			model.ArticleStatusPublished: model.ArticleStatusArchived,
+		},
+	}
+
+	for action, targets := range allowed {
+		for _, status := range statuses {
+			t.Run(action+" from "+status, func(t *testing.T) {
+				article := model.Article{Id: uuid.New(), Status: status}
+				repo := &fakeWorkflowArticleRepository{article: article}
+				service := NewArticleService(repo, nil, nil, nil, nil, nil, nil, nil, nil, utils.NewErrorWrapper())
+
+				updated, err := applyArticleAction(service, action, article.Id, "needs work")
+				to, ok := targets[status]
+				if !ok {
+					var appErr *utils.AppError
+					require.True(t, errors.As(err, &appErr), "got %v", err)
+					assert.Equal(t, 409, appErr.StatusCode)
+					assert.Equal(t, map[string]string{"action": action, "status": status}, appErr.Details)
+					assert.Zero(t, repo.transitions)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, to, updated.Status)
+				assert.Equal(t, 1, repo.transitions)
+			})
+		}
+	}
+}
+
+func TestArticleService_RejectRequiresReason(t *testing.T) {
+	article := model.Article{Id: uuid.New(), Status: model.ArticleStatusInReview}
+	repo := &fakeWorkflowArticleRepository{article: article}
+	service := NewArticleService(repo, nil, nil, nil, nil, nil, nil, nil, nil, utils.NewErrorWrapper())
+
+	for _, reason := range []string{"", "   "} {
+		_, err := service.RejectArticle(context.Background(), article.Id, uuid.New(), reason)
+		var appErr *utils.AppError
+		require.True(t, errors.As(err, &appErr), "got %v", err)
+		assert.Equal(t, 400, appErr.StatusCode)
+	}
+	assert.Zero(t, repo.transitions)
+
+	_, err := service.RejectArticle(context.Background(), article.Id, uuid.New(), "  missing sources  ")
+	require.NoError(t, err)
+	assert.Equal(t, "missing sources", repo.reason)
+}
+
+func TestArticleService_PublishSchedule(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		publishAt     *time.Time
+		wantStatus    string
+		wantPublishAt bool
+	}{
+		{name: "publish time in the future", publishAt: &future, wantStatus: model.ArticleStatusScheduled},
+		{name: "publish time in the past", publishAt: &past, wantStatus: model.ArticleStatusPublished},
+		{name: "no publish time", wantStatus: model.ArticleStatusPublished, wantPublishAt: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := model.Article{Id: uuid.New(), Status: model.ArticleStatusApproved, PublishAt: tt.publishAt}
+			repo := &fakeWorkflowArticleRepository{article: article}
+			service := NewArticleService(repo, nil, nil, nil, nil, nil, nil, nil, nil, utils.NewErrorWrapper())
+
+			updated, err := service.PublishArticle(context.Background(), article.Id, uuid.New())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, updated.Status)
+			// Only articles without a publish time get stamped with the current time
+			assert.Equal(t, tt.wantPublishAt, repo.publishAt != nil)
+		})
+	}
+}
+
+func TestArticleService_TransitionRace(t *testing.T) {
+	article := model.Article{Id: uuid.New(), Status: model.ArticleStatusInReview}
+	repo := &fakeWorkflowArticleRepository{article: article, race: true}
+	service := NewArticleService(repo, nil, nil, nil, nil, nil, nil, nil, nil, utils.NewErrorWrapper())
+
+	_, err := service.ApproveArticle(context.Background(), article.Id, uuid.New(), "")
+	var appErr *utils.AppError
+	require.True(t, errors.As(err, &appErr), "got %v", err)
+	assert.Equal(t, 409, appErr.StatusCode)
+	assert.Equal(t, map[string]string{"action": ArticleActionApprove, "status": model.ArticleStatusInReview}, appErr.Details)
+}
+
+func TestArticleService_TransitionMissingArticle(t *testing.T) {
+	repo := &fakeWorkflowArticleRepository{article: model.Article{Id: uuid.New(), Status: model.ArticleStatusDraft}}
+	service := NewArticleService(repo, nil, nil, nil, nil, nil, nil, nil, nil, utils.NewErrorWrapper())
+
+	_, err := service.SubmitArticle(context.Background(), uuid.New(), uuid.New())
+	var appErr *utils.AppError
+	require.True(t, errors.As(err, &appErr), "got %v", err)
+	assert.Equal(t, 404, appErr.StatusCode)
+}
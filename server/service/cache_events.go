@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"develapar-server/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// cacheInvalidationTimeout bounds dropping stale entries after a write. The write is
+// already committed, so the invalidation must not be cut short with the request.
+const cacheInvalidationTimeout = 2 * time.Second
+
+// CacheEvent announces a write. It names the cached reads that are stale now, by key for
+// a single entry or by tag for every entry built from the written record.
+type CacheEvent struct {
+	Keys []string
+	Tags []string
+}
+
+// CacheEventPublisher is used by services to announce their writes
+type CacheEventPublisher interface {
+	Publish(ctx context.Context, event CacheEvent)
+}
+
+// CacheEventSubscriber handles a published event
+type CacheEventSubscriber func(ctx context.Context, event CacheEvent)
+
+// CacheEventBus delivers published events to its subscribers within the process.
+// Subscribers run before Publish returns, so a client reading right after its own
+// write never gets the entry the write replaced.
+type CacheEventBus interface {
+	CacheEventPublisher
+	Subscribe(subscriber CacheEventSubscriber)
+}
+
+type cacheEventBus struct {
+	mu          sync.RWMutex
+	subscribers []CacheEventSubscriber
+}
+
+// Publish implements CacheEventBus.
+func (b *cacheEventBus) Publish(ctx context.Context, event CacheEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, subscriber := range b.subscribers {
+		subscriber(ctx, event)
+	}
+}
+
+// Subscribe implements CacheEventBus.
+func (b *cacheEventBus) Subscribe(subscriber CacheEventSubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, subscriber)
+}
+
+// NewCacheEventBus creates an event bus without subscribers, publishing is a no-op
+// until the read cache subscribes
+func NewCacheEventBus() CacheEventBus {
+	return &cacheEventBus{}
+}
+
+// NewCacheInvalidator returns the subscriber that drops the cached reads named by an
+// event. A failure is logged and the entries stay until their ttl passes.
+func NewCacheInvalidator(cache utils.Cache, logger utils.Logger) CacheEventSubscriber {
+	return func(ctx context.Context, event CacheEvent) {
+		invalidateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheInvalidationTimeout)
+		defer cancel()
+
+		if len(event.Keys) > 0 {
+			if err := cache.Delete(invalidateCtx, event.Keys...); err != nil {
+				logger.Error(ctx, "Failed to delete cached entries", err,
+					utils.StringField("keys", strings.Join(event.Keys, ",")))
+			}
+		}
+		if len(event.Tags) > 0 {
+			if err := cache.InvalidateTags(invalidateCtx, event.Tags...); err != nil {
+				logger.Error(ctx, "Failed to invalidate cached entries", err,
+					utils.StringField("tags", strings.Join(event.Tags, ",")))
+			}
+		}
+	}
+}
+
+// Cache keys of the lists, a write to one of their records drops them by key
+const (
+	categoryListCacheKey = "categories:all"
+	tagListCacheKey      = "tags:all"
+)
+
+// allProductsCacheTag is attached to every cached product, for the writes that cannot
+// tell which product they belong to. The per record tags below are attached to every
+// cached read containing the record.
+const allProductsCacheTag = "products"
+
+func articleCacheTag(id uuid.UUID) string         { return "article:" + id.String() }
+func categoryCacheTag(id uuid.UUID) string        { return "category:" + id.String() }
+func tagCacheTag(id uuid.UUID) string             { return "tag:" + id.String() }
+func productCacheTag(id uuid.UUID) string         { return "product:" + id.String() }
+func productCategoryCacheTag(id uuid.UUID) string { return "product-category:" + id.String() }
+
+// categoryListChanged drops the category list after a category was added
+func categoryListChanged() CacheEvent {
+	return CacheEvent{Keys: []string{categoryListCacheKey}}
+}
+
+// tagListChanged drops the tag list after a tag was added
+func tagListChanged() CacheEvent {
+	return CacheEvent{Keys: []string{tagListCacheKey}}
+}
+
+// articleChanged is published by every write to an article, its authors or its tags
+func articleChanged(ids ...uuid.UUID) CacheEvent {
+	event := CacheEvent{}
+	for _, id := range ids {
+		event.Tags = append(event.Tags, articleCacheTag(id))
+	}
+	return event
+}
+
+// commentsChanged is published by every write to a comment. The article shows its
+// comment count, and its validators are derived from what it shows. Likes leave the
+// count alone and publish nothing, or a busy thread would keep emptying the cache.
+func commentsChanged(articleIds ...uuid.UUID) CacheEvent {
+	return articleChanged(articleIds...)
+}
+
+// categoryChanged drops the category, the category list and the articles showing its name
+func categoryChanged(id uuid.UUID) CacheEvent {
+	return CacheEvent{Keys: []string{categoryListCacheKey}, Tags: []string{categoryCacheTag(id)}}
+}
+
+// tagChanged drops the tag, the tag list and the articles listing it
+func tagChanged(id uuid.UUID) CacheEvent {
+	return CacheEvent{Keys: []string{tagListCacheKey}, Tags: []string{tagCacheTag(id)}}
+}
+
+// productChanged is published by every write to a product or its affiliate links
+func productChanged(id uuid.UUID) CacheEvent {
+	return CacheEvent{Tags: []string{productCacheTag(id)}}
+}
+
+// productCategoryChanged drops the product category and the products showing it
+func productCategoryChanged(id uuid.UUID) CacheEvent {
+	return CacheEvent{Tags: []string{productCategoryCacheTag(id)}}
+}
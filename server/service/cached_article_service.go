@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"develapar-server/model"
+	"develapar-server/utils"
+	"time"
+)
+
+// cachedArticleService caches article lookups by slug, other calls go straight to ArticleService
+type cachedArticleService struct {
+	ArticleService
+	reads readCache
+}
+
+// cachedArticle carries the article fields that are never sent to clients. The password
+// hash is left out of the cache: whether the article has one is told by its visibility,
+// and AuthorizeView reads the hash from the database when it checks an access token.
+type cachedArticle struct {
+	Article     model.Article `json:"article"`
+	ContentHash string        `json:"content_hash"`
+}
+
+// FindBySlug implements ArticleService.
+// The entry is tagged with the article, its category and its tags, so renaming any of
+// them drops it.
+func (c *cachedArticleService) FindBySlug(ctx context.Context, slug string) (model.Article, error) {
+	cached, err := cachedRead(ctx, c.reads, "article:slug:"+slug, func() (cachedArticle, error) {
+		article, err := c.ArticleService.FindBySlug(ctx, slug)
+		return cachedArticle{Article: article, ContentHash: article.ContentHash}, err
+	}, func(cached cachedArticle) []string {
+		tags := []string{articleCacheTag(cached.Article.Id), categoryCacheTag(cached.Article.CategoryId)}
+		for _, tag := range cached.Article.Tags {
+			tags = append(tags, tagCacheTag(tag.Id))
+		}
+		return tags
+	})
+	if err != nil {
+		return model.Article{}, err
+	}
+
+	article := cached.Article
+	article.ContentHash = cached.ContentHash
+	return article, nil
+}
+
+// NewCachedArticleService wraps an ArticleService with the read cache
+func NewCachedArticleService(next ArticleService, cache utils.Cache, metrics MetricsService, ttl time.Duration, logger utils.Logger) ArticleService {
+	return &cachedArticleService{
+		ArticleService: next,
+		reads:          newReadCache("article", cache, metrics, ttl, logger),
+	}
+}
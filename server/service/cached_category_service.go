@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// cachedCategoryService caches the category list and lookups by id and announces every
+// write to a category
+type cachedCategoryService struct {
+	CategoryService
+	reads  readCache
+	events CacheEventPublisher
+}
+
+// FindAll implements CategoryService.
+func (c *cachedCategoryService) FindAll(ctx context.Context) ([]model.Category, error) {
+	return cachedRead(ctx, c.reads, categoryListCacheKey, func() ([]model.Category, error) {
+		return c.CategoryService.FindAll(ctx)
+	}, func([]model.Category) []string { return nil })
+}
+
+// FindById implements CategoryService.
+func (c *cachedCategoryService) FindById(ctx context.Context, id uuid.UUID) (model.Category, error) {
+	return cachedRead(ctx, c.reads, "category:id:"+id.String(), func() (model.Category, error) {
+		return c.CategoryService.FindById(ctx, id)
+	}, func(model.Category) []string { return []string{categoryCacheTag(id)} })
+}
+
+// CreateCategory implements CategoryService.
+func (c *cachedCategoryService) CreateCategory(ctx context.Context, payload model.Category) (model.Category, error) {
+	category, err := c.CategoryService.CreateCategory(ctx, payload)
+	if err == nil {
+		c.events.Publish(ctx, categoryListChanged())
+	}
+	return category, err
+}
+
+// UpdateCategory implements CategoryService.
+func (c *cachedCategoryService) UpdateCategory(ctx context.Context, id uuid.UUID, version int, req dto.UpdateCategoryRequest) (model.Category, error) {
+	category, err := c.CategoryService.UpdateCategory(ctx, id, version, req)
+	if err == nil {
+		c.events.Publish(ctx, categoryChanged(id))
+	}
+	return category, err
+}
+
+// DeleteCategory implements CategoryService.
+func (c *cachedCategoryService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	err := c.CategoryService.DeleteCategory(ctx, id)
+	if err == nil {
+		c.events.Publish(ctx, categoryChanged(id))
+	}
+	return err
+}
+
+// NewCachedCategoryService wraps a CategoryService with the read cache
+func NewCachedCategoryService(next CategoryService, cache utils.Cache, events CacheEventPublisher, metrics MetricsService, ttl time.Duration, logger utils.Logger) CategoryService {
+	return &cachedCategoryService{
+		CategoryService: next,
+		reads:           newReadCache("category", cache, metrics, ttl, logger),
+		events:          events,
+	}
+}
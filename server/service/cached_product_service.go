@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"develapar-server/model/dto"
+	"develapar-server/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// cachedProductService caches product lookups by id and announces every write to a
+// product, its affiliate links or its category
+type cachedProductService struct {
+	ProductService
+	reads  readCache
+	events CacheEventPublisher
+}
+
+// GetProductById implements ProductService.
+// The entry is tagged with the product category it shows, so renaming it drops the entry.
+func (p *cachedProductService) GetProductById(ctx context.Context, id uuid.UUID) (dto.ProductResponse, error) {
+	return cachedRead(ctx, p.reads, "product:id:"+id.String(), func() (dto.ProductResponse, error) {
+		return p.ProductService.GetProductById(ctx, id)
+	}, func(product dto.ProductResponse) []string {
+		tags := []string{productCacheTag(id), allProductsCacheTag}
+		if product.ProductCategoryId != nil {
+			tags = append(tags, productCategoryCacheTag(*product.ProductCategoryId))
+		}
+		return tags
+	})
+}
+
+// UpdateProductCategory implements ProductService.
+func (p *cachedProductService) UpdateProductCategory(ctx context.Context, id uuid.UUID, version int, req dto.UpdateProductCategoryRequest) (dto.ProductCategoryResponse, error) {
+	category, err := p.ProductService.UpdateProductCategory(ctx, id, version, req)
+	return category, p.published(ctx, productCategoryChanged(id), err)
+}
+
+// DeleteProductCategory implements ProductService.
+func (p *cachedProductService) DeleteProductCategory(ctx context.Context, id uuid.UUID) error {
+	return p.published(ctx, productCategoryChanged(id), p.ProductService.DeleteProductCategory(ctx, id))
+}
+
+// UpdateProduct implements ProductService.
+func (p *cachedProductService) UpdateProduct(ctx context.Context, id uuid.UUID, version int, req dto.UpdateProductRequest) (dto.ProductResponse, error) {
+	product, err := p.ProductService.UpdateProduct(ctx, id, version, req)
+	return product, p.published(ctx, productChanged(id), err)
+}
+
+// DeleteProduct implements ProductService.
+func (p *cachedProductService) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	return p.published(ctx, productChanged(id), p.ProductService.DeleteProduct(ctx, id))
+}
+
+// CreateProductAffiliateLink implements ProductService.
+func (p *cachedProductService) CreateProductAffiliateLink(ctx context.Context, productId uuid.UUID, req dto.CreateProductAffiliateLinkRequest) (dto.ProductAffiliateLinkResponse, error) {
+	link, err := p.ProductService.CreateProductAffiliateLink(ctx, productId, req)
+	return link, p.published(ctx, productChanged(productId), err)
+}
+
+// UpdateProductAffiliateLink implements ProductService.
+func (p *cachedProductService) UpdateProductAffiliateLink(ctx context.Context, productId, affiliateId uuid.UUID, version int, req dto.UpdateProductAffiliateLinkRequest) (dto.ProductAffiliateLinkResponse, error) {
+	link, err := p.ProductService.UpdateProductAffiliateLink(ctx, productId, affiliateId, version, req)
+	return link, p.published(ctx, productChanged(productId), err)
+}
+
+// DeleteProductAffiliateLink implements ProductService.
+// Only the link id is known here, so every cached product is dropped.
+func (p *cachedProductService) DeleteProductAffiliateLink(ctx context.Context, id uuid.UUID) error {
+	err := p.ProductService.DeleteProductAffiliateLink(ctx, id)
+	return p.published(ctx, CacheEvent{Tags: []string{allProductsCacheTag}}, err)
+}
+
+// published announces the write when it succeeded and passes err through
+func (p *cachedProductService) published(ctx context.Context, event CacheEvent, err error) error {
+	if err == nil {
+		p.events.Publish(ctx, event)
+	}
+	return err
+}
+
+// NewCachedProductService wraps a ProductService with the read cache
+func NewCachedProductService(next ProductService, cache utils.Cache, events CacheEventPublisher, metrics MetricsService, ttl time.Duration, logger utils.Logger) ProductService {
+	return &cachedProductService{
+		ProductService: next,
+		reads:          newReadCache("product", cache, metrics, ttl, logger),
+		events:         events,
+	}
+}
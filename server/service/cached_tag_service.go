@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"develapar-server/model"
+	"develapar-server/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// cachedTagService caches the tag list and lookups by id and announces every write to a tag
+type cachedTagService struct {
+	TagService
+	reads  readCache
+	events CacheEventPublisher
+}
+
+// FindAll implements TagService.
+func (t *cachedTagService) FindAll(ctx context.Context) ([]model.Tags, error) {
+	return cachedRead(ctx, t.reads, tagListCacheKey, func() ([]model.Tags, error) {
+		return t.TagService.FindAll(ctx)
+	}, func([]model.Tags) []string { return nil })
+}
+
+// FindById implements TagService.
+func (t *cachedTagService) FindById(ctx context.Context, id uuid.UUID) (model.Tags, error) {
+	return cachedRead(ctx, t.reads, "tag:id:"+id.String(), func() (model.Tags, error) {
+		return t.TagService.FindById(ctx, id)
+	}, func(model.Tags) []string { return []string{tagCacheTag(id)} })
+}
+
+// CreateTag implements TagService.
+func (t *cachedTagService) CreateTag(ctx context.Context, payload model.Tags) (model.Tags, error) {
+	tag, err := t.TagService.CreateTag(ctx, payload)
+	if err == nil {
+		t.events.Publish(ctx, tagListChanged())
+	}
+	return tag, err
+}
+
+// UpdateTag implements TagService.
+func (t *cachedTagService) UpdateTag(ctx context.Context, id uuid.UUID, version int, payload model.Tags) (model.Tags, error) {
+	tag, err := t.TagService.UpdateTag(ctx, id, version, payload)
+	if err == nil {
+		t.events.Publish(ctx, tagChanged(id))
+	}
+	return tag, err
+}
+
+// DeleteTag implements TagService.
+func (t *cachedTagService) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	err := t.TagService.DeleteTag(ctx, id)
+	if err == nil {
+		t.events.Publish(ctx, tagChanged(id))
+	}
+	return err
+}
+
+// NewCachedTagService wraps a TagService with the read cache
+func NewCachedTagService(next TagService, cache utils.Cache, events CacheEventPublisher, metrics MetricsService, ttl time.Duration, logger utils.Logger) TagService {
+	return &cachedTagService{
+		TagService: next,
+		reads:      newReadCache("tag", cache, metrics, ttl, logger),
+		events:     events,
+	}
+}
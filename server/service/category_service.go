@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"develapar-server/model"
 	"develapar-server/model/dto"
 	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -15,13 +18,16 @@ type CategoryService interface {
 	CreateCategory(ctx context.Context, payload model.Category) (model.Category, error)
 	FindAll(ctx context.Context) ([]model.Category, error)
 	FindById(ctx context.Context, id uuid.UUID) (model.Category, error)
-	UpdateCategory(ctx context.Context, id uuid.UUID, req dto.UpdateCategoryRequest) (model.Category, error)
+	// UpdateCategory applies the update only if version is still the current one
+	UpdateCategory(ctx context.Context, id uuid.UUID, version int, req dto.UpdateCategoryRequest) (model.Category, error)
 	DeleteCategory(ctx context.Context, id uuid.UUID) error
 }
 
 type categoryService struct {
 	repo              repository.CategoryRepository
 	validationService ValidationService
+	slugAllocator     SlugAllocator
+	errorWrapper      utils.ErrorWrapper
 }
 
 // DeleteCategory implements CategoryService.
@@ -52,7 +58,7 @@ func (c *categoryService) DeleteCategory(ctx context.Context, id uuid.UUID) erro
 }
 
 // UpdateCategory implements CategoryService.
-func (c *categoryService) UpdateCategory(ctx context.Context, id uuid.UUID, req dto.UpdateCategoryRequest) (model.Category, error) {
+func (c *categoryService) UpdateCategory(ctx context.Context, id uuid.UUID, version int, req dto.UpdateCategoryRequest) (model.Category, error) {
 	// Check context cancellation
 	select {
 	case <-ctx.Done():
@@ -72,9 +78,17 @@ func (c *categoryService) UpdateCategory(ctx context.Context, id uuid.UUID, req
 		if ctx.Err() != nil {
 			return model.Category{}, ctx.Err()
 		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Category{}, c.errorWrapper.NotFoundError(ctx, "Category")
+		}
 		return model.Category{}, fmt.Errorf("failed to fetch category for update: %v", err)
 	}
 
+	// Refuse updates made from an outdated copy of the category
+	if cat.Version != version {
+		return model.Category{}, c.errorWrapper.PreconditionFailedError(ctx, "Category")
+	}
+
 	// Update fields if provided
 	if req.Name != nil {
 		cat.Name = strings.ToLower(*req.Name)
@@ -92,13 +106,23 @@ func (c *categoryService) UpdateCategory(ctx context.Context, id uuid.UUID, req
 	default:
 	}
 
-	// Update category in repository with context
-	updatedCategory, err := c.repo.UpdateCategory(ctx, cat)
+	// Update category in repository with context, a renamed category gets a new slug
+	var updatedCategory model.Category
+	err = c.slugAllocator.Save(ctx, repository.SlugTableCategories, cat.Name, cat.Id, func(slug string) error {
+		cat.Slug = slug
+		var err error
+		updatedCategory, err = c.repo.UpdateCategory(ctx, cat)
+		return err
+	})
 	if err != nil {
 		// Check if context was cancelled during repository operation
 		if ctx.Err() != nil {
 			return model.Category{}, ctx.Err()
 		}
+		// The category was changed by someone else since it was read
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Category{}, c.errorWrapper.PreconditionFailedError(ctx, "Category")
+		}
 		return model.Category{}, fmt.Errorf("failed to update category: %v", err)
 	}
 
@@ -130,7 +154,14 @@ func (c *categoryService) CreateCategory(ctx context.Context, payload model.Cate
 	}
 
 	// Create category in repository with context
-	createdCategory, err := c.repo.CreateCategory(ctx, payload)
+	payload.Id = uuid.Must(uuid.NewV7())
+	var createdCategory model.Category
+	err := c.slugAllocator.Save(ctx, repository.SlugTableCategories, payload.Name, payload.Id, func(slug string) error {
+		payload.Slug = slug
+		var err error
+		createdCategory, err = c.repo.CreateCategory(ctx, payload)
+		return err
+	})
 	if err != nil {
 		// Check if context was cancelled during repository operation
 		if ctx.Err() != nil {
@@ -191,9 +222,11 @@ func (c *categoryService) FindById(ctx context.Context, id uuid.UUID) (model.Cat
 	return category, nil
 }
 
-func NewCategoryService(repository repository.CategoryRepository, validationService ValidationService) CategoryService {
+func NewCategoryService(repository repository.CategoryRepository, validationService ValidationService, slugAllocator SlugAllocator, errorWrapper utils.ErrorWrapper) CategoryService {
 	return &categoryService{
 		repo:              repository,
 		validationService: validationService,
+		slugAllocator:     slugAllocator,
+		errorWrapper:      errorWrapper,
 	}
 }
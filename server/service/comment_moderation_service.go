@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type CommentModerationService interface {
+	// FindQueue lists the comments with status, pending when empty, oldest first
+	FindQueue(ctx context.Context, status string, articleId *uuid.UUID, page, limit int) (PaginationResult, error)
+	// Moderate applies action to every comment in ids and returns how many were changed.
+	// Approving a comment or marking it as spam trains the spam classifier.
+	Moderate(ctx context.Context, ids []uuid.UUID, action string, moderatorId uuid.UUID) (int64, error)
+	// GetPolicy returns the policy applied to the comments of the article, or the global
+	// policy when articleId is nil
+	GetPolicy(ctx context.Context, articleId *uuid.UUID) (model.CommentModerationPolicy, error)
+	SetPolicy(ctx context.Context, articleId *uuid.UUID, mode string, updatedBy uuid.UUID) (model.CommentModerationPolicy, error)
+	DeletePolicy(ctx context.Context, articleId uuid.UUID) error
+	// InitialStatus decides the status of a new comment by the user with role on the article,
+	// a comment the spam classifier flagged is marked as spam or held for moderation
+	InitialStatus(ctx context.Context, articleId, userId uuid.UUID, role string, verdict SpamVerdict) (string, error)
+}
+
+type commentModerationService struct {
+	repo              repository.CommentModerationRepository
+	paginationService PaginationService
+	// defaultMode applies while no global policy is stored
+	defaultMode string
+	// trustedApprovedCount is how many approved comments make a user trusted
+	trustedApprovedCount int
+	spam                 SpamClassifier
+	cacheEvents          CacheEventPublisher
+	errorWrapper         utils.ErrorWrapper
+}
+
+// FindQueue implements CommentModerationService.
+func (s *commentModerationService) FindQueue(ctx context.Context, status string, articleId *uuid.UUID, page, limit int) (PaginationResult, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return PaginationResult{}, ctx.Err()
+	default:
+	}
+
+	// Validate status
+	if status == "" {
+		status = model.CommentStatusPending
+	}
+	switch status {
+	case model.CommentStatusPending, model.CommentStatusApproved, model.CommentStatusRejected, model.CommentStatusSpam:
+	default:
+		return PaginationResult{}, s.errorWrapper.ValidationError(ctx, "status", "status must be pending, approved, rejected or spam")
+	}
+
+	// Parse and validate pagination query
+	query, paginationErr := s.paginationService.ParseQuery(ctx, page, limit, "created_at", "asc")
+	if paginationErr != nil {
+		return PaginationResult{}, fmt.Errorf("pagination validation failed: %v", paginationErr)
+	}
+
+	comments, total, err := s.repo.GetQueue(ctx, status, articleId, query.Offset, query.Limit)
+	if err != nil {
+		if ctx.Err() != nil {
+			return PaginationResult{}, ctx.Err()
+		}
+		return PaginationResult{}, fmt.Errorf("failed to fetch moderation queue: %v", err)
+	}
+
+	result, paginationErr := s.paginationService.Paginate(ctx, comments, total, query)
+	if paginationErr != nil {
+		return PaginationResult{}, fmt.Errorf("failed to create pagination result: %v", paginationErr)
+	}
+
+	return result, nil
+}
+
+// Moderate implements CommentModerationService.
+func (s *commentModerationService) Moderate(ctx context.Context, ids []uuid.UUID, action string, moderatorId uuid.UUID) (int64, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+
+	status, ok := model.CommentModerationStatuses[action]
+	if !ok {
+		return 0, s.errorWrapper.ValidationError(ctx, "action", "action must be approve, reject or spam")
+	}
+	if len(ids) == 0 {
+		return 0, s.errorWrapper.ValidationError(ctx, "comment_ids", "at least one comment is required")
+	}
+
+	moderated, err := s.repo.SetStatus(ctx, ids, status, moderatorId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, fmt.Errorf("failed to moderate comments: %v", err)
+	}
+
+	var articleIds []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, comment := range moderated {
+		s.learnDecision(comment, status)
+		if !seen[comment.ArticleId] {
+			seen[comment.ArticleId] = true
+			articleIds = append(articleIds, comment.ArticleId)
+		}
+	}
+	if len(articleIds) > 0 {
+		s.cacheEvents.Publish(ctx, commentsChanged(articleIds...))
+	}
+
+	return int64(len(moderated)), nil
+}
+
+// learnDecision trains the spam classifier with the new status of a moderated comment.
+// A changed decision is forgotten first so the comment is counted only once, rejected
+// comments are neither spam nor ham.
+func (s *commentModerationService) learnDecision(comment repository.ModeratedComment, status string) {
+	if comment.PreviousModeratedAt != nil && isSpamDecision(comment.PreviousStatus) {
+		if comment.PreviousStatus == status {
+			return
+		}
+		s.spam.Forget(comment.Content, comment.PreviousStatus == model.CommentStatusSpam)
+	}
+	if isSpamDecision(status) {
+		s.spam.Learn(comment.Content, status == model.CommentStatusSpam)
+	}
+}
+
+func isSpamDecision(status string) bool {
+	return status == model.CommentStatusApproved || status == model.CommentStatusSpam
+}
+
+// GetPolicy implements CommentModerationService.
+func (s *commentModerationService) GetPolicy(ctx context.Context, articleId *uuid.UUID) (model.CommentModerationPolicy, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.CommentModerationPolicy{}, ctx.Err()
+	default:
+	}
+
+	policy, err := s.repo.GetPolicy(ctx, articleId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.CommentModerationPolicy{}, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.CommentModerationPolicy{Mode: s.defaultMode}, nil
+		}
+		return model.CommentModerationPolicy{}, fmt.Errorf("failed to get comment moderation policy: %v", err)
+	}
+
+	return policy, nil
+}
+
+// SetPolicy implements CommentModerationService.
+func (s *commentModerationService) SetPolicy(ctx context.Context, articleId *uuid.UUID, mode string, updatedBy uuid.UUID) (model.CommentModerationPolicy, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.CommentModerationPolicy{}, ctx.Err()
+	default:
+	}
+
+	switch mode {
+	case model.CommentPolicyAutoApprove, model.CommentPolicyHoldFirstTime, model.CommentPolicyHoldUntrusted:
+	default:
+		return model.CommentModerationPolicy{}, s.errorWrapper.ValidationError(ctx, "mode", "mode must be auto_approve, hold_first_time or hold_untrusted")
+	}
+
+	policy, err := s.repo.SetPolicy(ctx, model.CommentModerationPolicy{ArticleId: articleId, Mode: mode, UpdatedBy: &updatedBy})
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.CommentModerationPolicy{}, ctx.Err()
+		}
+		if repository.IsForeignKeyViolation(err) {
+			return model.CommentModerationPolicy{}, s.errorWrapper.NotFoundError(ctx, "Article")
+		}
+		return model.CommentModerationPolicy{}, fmt.Errorf("failed to set comment moderation policy: %v", err)
+	}
+
+	return policy, nil
+}
+
+// DeletePolicy implements CommentModerationService.
+func (s *commentModerationService) DeletePolicy(ctx context.Context, articleId uuid.UUID) error {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := s.repo.DeletePolicy(ctx, articleId); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return s.errorWrapper.NotFoundError(ctx, "Comment moderation policy")
+		}
+		return fmt.Errorf("failed to delete comment moderation policy: %v", err)
+	}
+
+	return nil
+}
+
+// InitialStatus implements CommentModerationService.
+func (s *commentModerationService) InitialStatus(ctx context.Context, articleId, userId uuid.UUID, role string, verdict SpamVerdict) (string, error) {
+	if role == "editor" || role == "admin" {
+		return model.CommentStatusApproved, nil
+	}
+	if verdict.Spam {
+		return model.CommentStatusSpam, nil
+	}
+
+	policy, err := s.GetPolicy(ctx, &articleId)
+	if err != nil {
+		return "", err
+	}
+
+	// Users need at least this many approved comments to skip the queue
+	required := 0
+	switch policy.Mode {
+	case model.CommentPolicyHoldFirstTime:
+		required = 1
+	case model.CommentPolicyHoldUntrusted:
+		required = s.trustedApprovedCount
+	}
+	if required == 0 {
+		return s.unlessHeld(verdict), nil
+	}
+
+	approved, err := s.repo.CountApprovedByUser(ctx, userId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("failed to count approved comments: %v", err)
+	}
+	if approved < required {
+		return model.CommentStatusPending, nil
+	}
+
+	return s.unlessHeld(verdict), nil
+}
+
+// unlessHeld holds a comment the policy would approve when its spam score is suspicious
+func (s *commentModerationService) unlessHeld(verdict SpamVerdict) string {
+	if verdict.Hold {
+		return model.CommentStatusPending
+	}
+	return model.CommentStatusApproved
+}
+
+func NewCommentModerationService(repo repository.CommentModerationRepository, paginationService PaginationService, defaultMode string, trustedApprovedCount int, spam SpamClassifier, cacheEvents CacheEventPublisher, errorWrapper utils.ErrorWrapper) CommentModerationService {
+	return &commentModerationService{
+		repo:                 repo,
+		paginationService:    paginationService,
+		defaultMode:          defaultMode,
+		trustedApprovedCount: trustedApprovedCount,
+		spam:                 spam,
+		cacheEvents:          cacheEvents,
+		errorWrapper:         errorWrapper,
+	}
+}
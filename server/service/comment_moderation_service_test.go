@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCommentModerationRepository serves stored policies, approved comment counts, a fixed
+// duplicate count and the comments SetStatus changes
+type fakeCommentModerationRepository struct {
+	repository.CommentModerationRepository
+	global           *model.CommentModerationPolicy
+	policies         map[uuid.UUID]model.CommentModerationPolicy
+	approved         map[uuid.UUID]int
+	duplicates       int
+	duplicateQueries int
+	moderated        []repository.ModeratedComment
+}
+
+func (r *fakeCommentModerationRepository) CountDuplicates(ctx context.Context, content string, userId, articleId uuid.UUID, since time.Time) (int, error) {
+	r.duplicateQueries++
+	return r.duplicates, nil
+}
+
+func (r *fakeCommentModerationRepository) SetStatus(ctx context.Context, ids []uuid.UUID, status string, moderatorId uuid.UUID) ([]repository.ModeratedComment, error) {
+	return r.moderated, nil
+}
+
+func (r *fakeCommentModerationRepository) GetPolicy(ctx context.Context, articleId *uuid.UUID) (model.CommentModerationPolicy, error) {
+	if articleId != nil {
+		if policy, ok := r.policies[*articleId]; ok {
+			return policy, nil
+		}
+	}
+	if r.global == nil {
+		return model.CommentModerationPolicy{}, sql.ErrNoRows
+	}
+	return *r.global, nil
+}
+
+func (r *fakeCommentModerationRepository) CountApprovedByUser(ctx context.Context, userId uuid.UUID) (int, error) {
+	return r.approved[userId], nil
+}
+
+func newTestModerationService(repo repository.CommentModerationRepository) CommentModerationService {
+	errorWrapper := utils.NewErrorWrapper()
+	pagination := NewPaginationService(NewValidationService(errorWrapper), errorWrapper)
+	return NewCommentModerationService(repo, pagination, model.CommentPolicyHoldFirstTime, 3, &fixedSpamClassifier{}, &recordingPublisher{}, errorWrapper)
+}
+
+func TestCommentModerationService_InitialStatus(t *testing.T) {
+	autoArticle, firstTimeArticle, untrustedArticle, defaultArticle := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	newcomer, regular, trusted := uuid.New(), uuid.New(), uuid.New()
+	repo := &fakeCommentModerationRepository{
+		policies: map[uuid.UUID]model.CommentModerationPolicy{
+			autoArticle:      {ArticleId: &autoArticle, Mode: model.CommentPolicyAutoApprove},
+			firstTimeArticle: {ArticleId: &firstTimeArticle, Mode: model.CommentPolicyHoldFirstTime},
+			untrustedArticle: {ArticleId: &untrustedArticle, Mode: model.CommentPolicyHoldUntrusted},
+		},
+		approved: map[uuid.UUID]int{regular: 1, trusted: 3},
+	}
+	service := newTestModerationService(repo)
+
+	tests := []struct {
+		name      string
+		articleId uuid.UUID
+		userId    uuid.UUID
+		role      string
+		verdict   SpamVerdict
+		want      string
+	}{
+		{name: "auto approve approves a newcomer", articleId: autoArticle, userId: newcomer, role: "user", want: model.CommentStatusApproved},
+		{name: "hold first time holds a newcomer", articleId: firstTimeArticle, userId: newcomer, role: "user", want: model.CommentStatusPending},
+		{name: "hold first time approves a user with an approved comment", articleId: firstTimeArticle, userId: regular, role: "user", want: model.CommentStatusApproved},
+		{name: "hold untrusted holds a user below the trusted count", articleId: untrustedArticle, userId: regular, role: "user", want: model.CommentStatusPending},
+		{name: "hold untrusted approves a trusted user", articleId: untrustedArticle, userId: trusted, role: "user", want: model.CommentStatusApproved},
+		{name: "default mode applies without a stored policy", articleId: defaultArticle, userId: newcomer, role: "user", want: model.CommentStatusPending},
+		{name: "editor skips the queue", articleId: untrustedArticle, userId: newcomer, role: "editor", want: model.CommentStatusApproved},
+		{name: "admin skips the queue", articleId: firstTimeArticle, userId: newcomer, role: "admin", want: model.CommentStatusApproved},
+		{name: "admin is never marked as spam", articleId: autoArticle, userId: newcomer, role: "admin", verdict: SpamVerdict{Spam: true, Hold: true}, want: model.CommentStatusApproved},
+		{name: "spam verdict marks as spam", articleId: autoArticle, userId: trusted, role: "user", verdict: SpamVerdict{Spam: true, Hold: true}, want: model.CommentStatusSpam},
+		{name: "suspicious verdict holds a trusted user", articleId: autoArticle, userId: trusted, role: "user", verdict: SpamVerdict{Hold: true}, want: model.CommentStatusPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := service.InitialStatus(context.Background(), tt.articleId, tt.userId, tt.role, tt.verdict)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, status)
+		})
+	}
+}
+
+func TestCommentModerationService_GlobalPolicy(t *testing.T) {
+	newcomer := uuid.New()
+	repo := &fakeCommentModerationRepository{global: &model.CommentModerationPolicy{Mode: model.CommentPolicyAutoApprove}}
+	service := newTestModerationService(repo)
+
+	status, err := service.InitialStatus(context.Background(), uuid.New(), newcomer, "user", SpamVerdict{})
+	require.NoError(t, err)
+	assert.Equal(t, model.CommentStatusApproved, status, "a stored global policy replaces the default mode")
+}
+
+func TestCommentModerationService_LearnDecision(t *testing.T) {
+	moderatedAt := time.Now()
+
+	tests := []struct {
+		name     string
+		previous string
+		// moderated tells whether a moderator decided on the previous status
+		moderated bool
+		action    string
+		want      []string
+	}{
+		{name: "approving a new comment learns ham", previous: model.CommentStatusPending, action: model.CommentModerationApprove, want: []string{"learn ham"}},
+		{name: "marking a new comment as spam learns spam", previous: model.CommentStatusApproved, action: model.CommentModerationSpam, want: []string{"learn spam"}},
+		{name: "rejecting learns nothing", previous: model.CommentStatusPending, action: model.CommentModerationReject},
+		{name: "repeating a decision learns nothing", previous: model.CommentStatusSpam, moderated: true, action: model.CommentModerationSpam},
+		{name: "changed decision forgets the previous one", previous: model.CommentStatusApproved, moderated: true, action: model.CommentModerationSpam, want: []string{"forget ham", "learn spam"}},
+		{name: "rejecting after spam forgets spam", previous: model.CommentStatusSpam, moderated: true, action: model.CommentModerationReject, want: []string{"forget spam"}},
+		{name: "approval by the policy was never learned", previous: model.CommentStatusApproved, action: model.CommentModerationReject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := repository.ModeratedComment{Id: uuid.New(), Content: "buy cheap pills", PreviousStatus: tt.previous}
+			if tt.moderated {
+				comment.PreviousModeratedAt = &moderatedAt
+			}
+			repo := &fakeCommentModerationRepository{moderated: []repository.ModeratedComment{comment}}
+			spam := &fixedSpamClassifier{}
+			errorWrapper := utils.NewErrorWrapper()
+			service := NewCommentModerationService(repo, NewPaginationService(NewValidationService(errorWrapper), errorWrapper), model.CommentPolicyAutoApprove, 3, spam, &recordingPublisher{}, errorWrapper)
+
+			count, err := service.Moderate(context.Background(), []uuid.UUID{comment.Id}, tt.action, uuid.New())
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+			assert.Equal(t, tt.want, spam.decisions)
+		})
+	}
+}
+
+func TestCommentModerationService_ModeratePublishesEvents(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	repo := &fakeCommentModerationRepository{moderated: []repository.ModeratedComment{
+		{Id: uuid.New(), ArticleId: first, PreviousStatus: model.CommentStatusPending},
+		{Id: uuid.New(), ArticleId: second, PreviousStatus: model.CommentStatusPending},
+		{Id: uuid.New(), ArticleId: first, PreviousStatus: model.CommentStatusPending},
+	}}
+	events := &recordingPublisher{}
+	errorWrapper := utils.NewErrorWrapper()
+	service := NewCommentModerationService(repo, NewPaginationService(NewValidationService(errorWrapper), errorWrapper), model.CommentPolicyAutoApprove, 3, &fixedSpamClassifier{}, events, errorWrapper)
+
+	count, err := service.Moderate(context.Background(), []uuid.UUID{uuid.New()}, model.CommentModerationApprove, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.Equal(t, []CacheEvent{commentsChanged(first, second)}, events.events, "every article is announced once")
+
+	repo.moderated = nil
+	events.events = nil
+	_, err = service.Moderate(context.Background(), []uuid.UUID{uuid.New()}, model.CommentModerationApprove, uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, events.events, "nothing changed, nothing to announce")
+}
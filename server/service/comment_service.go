@@ -2,27 +2,81 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"develapar-server/config"
 	"develapar-server/model"
 	"develapar-server/model/dto"
 	"develapar-server/repository"
+	"develapar-server/utils"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type CommentService interface {
-	CreateComment(ctx context.Context, payload model.Comment) (model.Comment, error)
-	FindCommentByArticleId(ctx context.Context, articleId uuid.UUID) ([]model.Comment, error)
-	FindCommentByUserId(ctx context.Context, userId uuid.UUID) ([]dto.CommentResponse, error)
-	EditComment(ctx context.Context, commentId uuid.UUID, content string, userId uuid.UUID) error
+	// CreateComment adds a comment, or a reply when ParentCommentId is set. The parent must be
+	// a comment of the same article that is not nested deeper than the configured maximum.
+	// The moderation policy decides from the author and their role whether it shows right away,
+	// and the spam classifier scores it to hold or reject it as spam.
+	CreateComment(ctx context.Context, payload model.Comment, role string) (model.Comment, error)
+	// FindCommentByArticleId returns a page of the thread of the article: top level comments in
+	// the sort order of query with their replies, in query.Format. model.CommentFormatTree
+	// nests the replies, model.CommentFormatFlat lists them right after their parent. Only approved
+	// comments are included, and the pending ones of viewerId. A zero query.Limit takes the
+	// configured page size. Each top level comment lists at most the configured number of
+	// replies and sets HasMoreReplies when there are more.
+	//
+	// Pages are read with a keyset cursor, so a comment posted or deleted between two pages never
+	// shifts the next one. model.CommentSortMostLiked orders by the like counts at the time each
+	// page is read, newest first among equal counts: a comment whose count changes between two
+	// pages can move across the cursor and be skipped or listed twice.
+	FindCommentByArticleId(ctx context.Context, articleId uuid.UUID, query dto.CommentPageQuery, viewerId uuid.UUID) (CommentPage, error)
+	// FindCommentByUserId returns the approved comments of the user, and the pending ones too
+	// when the user views their own comments. Comments on articles that are not listed publicly
+	// are left out, unless viewerId may edit the article.
+	FindCommentByUserId(ctx context.Context, userId uuid.UUID, viewerId uuid.UUID) ([]dto.CommentResponse, error)
+	// EditComment replaces the content of a comment of the user and keeps the previous content
+	// in its edit history. Only admins can edit after the grace period. The new content is
+	// scored and moderated like a new comment, so an edit can send an approved comment back to
+	// the moderation queue but never approves a pending one. Rejected and spam comments cannot
+	// be edited.
+	EditComment(ctx context.Context, commentId uuid.UUID, content string, userId uuid.UUID, role string) error
+	// FindCommentEdits returns the edit history of a comment, oldest first
+	FindCommentEdits(ctx context.Context, commentId uuid.UUID) ([]model.CommentEdit, error)
 	DeleteComment(ctx context.Context, commentId uuid.UUID, userId uuid.UUID) error
+	// LikeComment adds the like of the user to an approved comment, UnlikeComment removes it.
+	// Both return the like count of the comment.
+	LikeComment(ctx context.Context, commentId uuid.UUID, userId uuid.UUID) (int, error)
+	UnlikeComment(ctx context.Context, commentId uuid.UUID, userId uuid.UUID) (int, error)
 }
 
 var ErrUnauthorized = errors.New("unauthorized")
 
+// CommentPage is a page of the thread of an article. NextCursor asks for the page after it
+// and is only set when HasMore.
+type CommentPage struct {
+	Comments   []model.Comment `json:"comments"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
+}
+
 type commentService struct {
 	repo              repository.CommentRepository
+	moderation        CommentModerationService
+	spam              SpamClassifier
+	cacheEvents       CacheEventPublisher
 	validationService ValidationService
+	errorWrapper      utils.ErrorWrapper
+	// maxDepth is the deepest a reply can be nested, top level comments have depth 0
+	maxDepth int
+	// editGracePeriod is how long after posting a comment can be edited, 0 for no limit
+	editGracePeriod time.Duration
+	// pageSize is the number of top level comments on a page when the client asks for none
+	pageSize int
+	// maxReplies is the number of replies listed with each top level comment
+	maxReplies int
 }
 
 // DeleteComment implements CommentService.
@@ -53,7 +107,7 @@ func (c *commentService) DeleteComment(ctx context.Context, commentId uuid.UUID,
 	}
 
 	// Check authorization
-	if comment.User.Id != userId {
+	if comment.UserId != userId {
 		return ErrUnauthorized
 	}
 
@@ -73,12 +127,13 @@ func (c *commentService) DeleteComment(ctx context.Context, commentId uuid.UUID,
 		}
 		return err
 	}
+	c.cacheEvents.Publish(ctx, commentsChanged(comment.ArticleId))
 
 	return nil
 }
 
 // EditComment implements CommentService.
-func (c *commentService) EditComment(ctx context.Context, commentId uuid.UUID, content string, userId uuid.UUID) error {
+func (c *commentService) EditComment(ctx context.Context, commentId uuid.UUID, content string, userId uuid.UUID, role string) error {
 	// Check context cancellation
 	select {
 	case <-ctx.Done():
@@ -104,13 +159,25 @@ func (c *commentService) EditComment(ctx context.Context, commentId uuid.UUID, c
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.errorWrapper.NotFoundError(ctx, "Comment")
+		}
 		return err
 	}
 
 	// Check authorization
-	if comment.User.Id != userId {
+	if comment.UserId != userId {
 		return ErrUnauthorized
 	}
+	if comment.Status == model.CommentStatusRejected || comment.Status == model.CommentStatusSpam {
+		return c.errorWrapper.ForbiddenError(ctx, "Rejected comments cannot be edited")
+	}
+	if role != "admin" && c.editGracePeriod > 0 && time.Since(comment.CreatedAt) > c.editGracePeriod {
+		return c.errorWrapper.ForbiddenError(ctx, fmt.Sprintf("Comments can only be edited within %s of posting", c.editGracePeriod))
+	}
+	if comment.Content == content {
+		return nil
+	}
 
 	// Create comment object for validation
 	updatedComment := comment
@@ -121,6 +188,23 @@ func (c *commentService) EditComment(ctx context.Context, commentId uuid.UUID, c
 		return validationErr
 	}
 
+	// The new content goes through the spam filter and the moderation policy again
+	verdict := c.classify(ctx, updatedComment, role)
+	updatedComment.SpamScore, updatedComment.SpamSignals = nil, nil
+	if verdict.Signals != nil {
+		updatedComment.SpamScore = &verdict.Score
+		updatedComment.SpamSignals = verdict.Signals
+	}
+	status, err := c.moderation.InitialStatus(ctx, comment.ArticleId, userId, role, verdict)
+	if err != nil {
+		return err
+	}
+	// A comment waiting for a moderator keeps waiting
+	if comment.Status == model.CommentStatusPending && status == model.CommentStatusApproved {
+		status = model.CommentStatusPending
+	}
+	updatedComment.Status = status
+
 	// Check context cancellation before update
 	select {
 	case <-ctx.Done():
@@ -129,20 +213,25 @@ func (c *commentService) EditComment(ctx context.Context, commentId uuid.UUID, c
 	}
 
 	// Update comment with context
-	err = c.repo.UpdateComment(ctx, commentId, content, userId)
+	err = c.repo.UpdateComment(ctx, updatedComment, userId)
 	if err != nil {
 		// Check if context was cancelled during repository operation
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
+		// The comment was deleted, rejected or marked as spam since it was read
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.errorWrapper.NotFoundError(ctx, "Comment")
+		}
 		return err
 	}
+	c.cacheEvents.Publish(ctx, commentsChanged(comment.ArticleId))
 
 	return nil
 }
 
 // CreateComment implements CommentService.
-func (c *commentService) CreateComment(ctx context.Context, payload model.Comment) (model.Comment, error) {
+func (c *commentService) CreateComment(ctx context.Context, payload model.Comment, role string) (model.Comment, error) {
 	// Check context cancellation
 	select {
 	case <-ctx.Done():
@@ -162,48 +251,243 @@ func (c *commentService) CreateComment(ctx context.Context, payload model.Commen
 	default:
 	}
 
-	// Create comment in repository with context
-	createdComment, err := c.repo.CreateComment(ctx, payload)
+	// Replies must stay in the thread of the same article
+	if payload.ParentCommentId != nil {
+		if err := c.checkReplyParent(ctx, payload); err != nil {
+			return model.Comment{}, err
+		}
+	}
+
+	// Score the comment for spam, the score is kept for moderators
+	verdict := c.classify(ctx, payload, role)
+	if verdict.Signals != nil {
+		payload.SpamScore = &verdict.Score
+		payload.SpamSignals = verdict.Signals
+	}
+
+	// Hold the comment for moderation when the policy of the article or its spam score asks for it
+	status, err := c.moderation.InitialStatus(ctx, payload.ArticleId, payload.UserId, role, verdict)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	payload.Status = status
+
+	// Create comment in repository with context. A reply is only stored while its parent
+	// can still be answered and within the depth limit.
+	var createdComment model.Comment
+	if payload.ParentCommentId != nil {
+		createdComment, err = c.repo.CreateReply(ctx, payload, c.maxDepth)
+	} else {
+		createdComment, err = c.repo.CreateComment(ctx, payload)
+	}
 	if err != nil {
 		// Check if context was cancelled during repository operation
 		if ctx.Err() != nil {
 			return model.Comment{}, ctx.Err()
 		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Comment{}, c.errorWrapper.ValidationError(ctx, "parent_comment_id", "Parent comment not found")
+		}
+		if errors.Is(err, repository.ErrCommentTooDeep) {
+			return model.Comment{}, c.errorWrapper.ValidationError(ctx, "parent_comment_id", fmt.Sprintf("Replies can be nested at most %d levels deep", c.maxDepth))
+		}
 		return model.Comment{}, err
 	}
+	c.spam.RecordPosting(ctx, createdComment)
+	c.cacheEvents.Publish(ctx, commentsChanged(createdComment.ArticleId))
 
 	return createdComment, nil
 }
 
+// classify scores a comment for spam. The comments of editors and admins are approved
+// whatever their score, so they are not scored.
+func (c *commentService) classify(ctx context.Context, comment model.Comment, role string) SpamVerdict {
+	if role == "editor" || role == "admin" {
+		return SpamVerdict{}
+	}
+	return c.spam.Classify(ctx, comment)
+}
+
+// checkReplyParent checks that the parent of a reply can be answered by its author. The
+// repository checks it again, along with the depth, when it stores the reply.
+func (c *commentService) checkReplyParent(ctx context.Context, payload model.Comment) error {
+	parent, err := c.repo.GetCommentById(ctx, *payload.ParentCommentId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.errorWrapper.ValidationError(ctx, "parent_comment_id", "Parent comment not found")
+		}
+		return fmt.Errorf("failed to get parent comment: %v", err)
+	}
+	// Comments waiting for moderation can only be answered by their own author
+	if parent.Status != model.CommentStatusApproved && (parent.Status != model.CommentStatusPending || parent.UserId != payload.UserId) {
+		return c.errorWrapper.ValidationError(ctx, "parent_comment_id", "Parent comment not found")
+	}
+	if parent.ArticleId != payload.ArticleId {
+		return c.errorWrapper.ValidationError(ctx, "parent_comment_id", "Parent comment belongs to another article")
+	}
+
+	return nil
+}
+
 // FindCommentByArticleId implements CommentService.
-func (c *commentService) FindCommentByArticleId(ctx context.Context, articleId uuid.UUID) ([]model.Comment, error) {
+func (c *commentService) FindCommentByArticleId(ctx context.Context, articleId uuid.UUID, query dto.CommentPageQuery, viewerId uuid.UUID) (CommentPage, error) {
 	// Check context cancellation
 	select {
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return CommentPage{}, ctx.Err()
 	default:
 	}
 
 	// Validate article ID
 	if articleId == uuid.Nil {
-		return nil, errors.New("article ID must be greater than 0")
+		return CommentPage{}, errors.New("article ID must be greater than 0")
+	}
+
+	// Validate format, sort and limit
+	if query.Format != model.CommentFormatFlat && query.Format != model.CommentFormatTree {
+		return CommentPage{}, c.errorWrapper.ValidationError(ctx, "format", fmt.Sprintf("format must be %s or %s", model.CommentFormatFlat, model.CommentFormatTree))
+	}
+	if query.Sort == "" {
+		query.Sort = model.CommentSortNewest
+	}
+	switch query.Sort {
+	case model.CommentSortNewest, model.CommentSortOldest, model.CommentSortMostLiked:
+	default:
+		return CommentPage{}, c.errorWrapper.ValidationError(ctx, "sort", fmt.Sprintf("sort must be %s, %s or %s", model.CommentSortNewest, model.CommentSortOldest, model.CommentSortMostLiked))
+	}
+	if query.Limit == 0 {
+		query.Limit = c.pageSize
+	}
+	if query.Limit < 0 || query.Limit > 100 {
+		return CommentPage{}, c.errorWrapper.ValidationError(ctx, "limit", "Limit must be a positive integer between 1 and 100")
+	}
+
+	// The cursor only continues the sort order it was made for
+	var after *model.CommentCursor
+	if query.Cursor != "" {
+		var cursor model.CommentCursor
+		if err := utils.DecodeCursor(query.Cursor, &cursor); err != nil || cursor.Id == uuid.Nil {
+			return CommentPage{}, c.errorWrapper.ValidationError(ctx, "cursor", "Invalid cursor")
+		}
+		if cursor.Sort != query.Sort {
+			return CommentPage{}, c.errorWrapper.ValidationError(ctx, "cursor", "Cursor belongs to another sort order")
+		}
+		after = &cursor
 	}
 
-	// Get comments by article from repository with context
-	comments, err := c.repo.GetCommentByArticleId(ctx, articleId)
+	// Get the page of the thread from repository with context
+	comments, hasMore, err := c.repo.GetCommentPage(ctx, articleId, viewerId, query.Sort, after, query.Limit, c.maxReplies)
 	if err != nil {
 		// Check if context was cancelled during repository operation
 		if ctx.Err() != nil {
-			return nil, ctx.Err()
+			return CommentPage{}, ctx.Err()
 		}
-		return nil, err
+		return CommentPage{}, err
 	}
 
-	return comments, nil
+	page := CommentPage{HasMore: hasMore}
+	if hasMore {
+		// The next page starts after the last top level comment fetched, even a hidden one
+		for i := len(comments) - 1; i >= 0; i-- {
+			if root := comments[i]; root.Depth == 0 {
+				page.NextCursor, err = utils.EncodeCursor(model.CommentCursor{
+					Sort:      query.Sort,
+					LikeCount: root.LikeCount,
+					CreatedAt: root.CreatedAt,
+					Id:        root.Id,
+				})
+				if err != nil {
+					return CommentPage{}, fmt.Errorf("failed to encode comment cursor: %v", err)
+				}
+				break
+			}
+		}
+	}
+
+	// Comments the viewer may not see are left out like deleted ones
+	for i := range comments {
+		visible := comments[i].Status == model.CommentStatusApproved ||
+			(comments[i].Status == model.CommentStatusPending && viewerId != uuid.Nil && comments[i].UserId == viewerId)
+		if !visible {
+			comments[i].IsDeleted = true
+		}
+	}
+
+	page.Comments = buildCommentThread(comments, query.Format)
+	return page, nil
+}
+
+// buildCommentThread arranges comments, given in depth first order with replies oldest
+// first, into format keeping the order of the top level comments. A deleted comment is kept
+// as a tombstone while it has replies left, listed or not, otherwise it is dropped.
+func buildCommentThread(comments []model.Comment, format string) []model.Comment {
+	// Replies follow their parent, so walking backwards sees them before the parent
+	keep := make([]bool, len(comments))
+	hasReplies := make(map[uuid.UUID]bool)
+	for i := len(comments) - 1; i >= 0; i-- {
+		comment := comments[i]
+		keep[i] = !comment.IsDeleted || hasReplies[comment.Id] || comment.HasMoreReplies
+		if keep[i] && comment.ParentCommentId != nil {
+			hasReplies[*comment.ParentCommentId] = true
+		}
+	}
+
+	var roots []int
+	replies := make(map[uuid.UUID][]int)
+	for i := range comments {
+		if !keep[i] {
+			continue
+		}
+		if comments[i].IsDeleted {
+			comments[i].Content = model.CommentDeletedContent
+			comments[i].Status = ""
+			comments[i].LikeCount = 0
+			comments[i].IsEdited = false
+			comments[i].EditedAt = nil
+			comments[i].UserId = uuid.Nil
+			comments[i].User = nil
+		}
+		if parentId := comments[i].ParentCommentId; parentId != nil {
+			replies[*parentId] = append(replies[*parentId], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	var nest func(i int) model.Comment
+	nest = func(i int) model.Comment {
+		comment := comments[i]
+		for _, reply := range replies[comment.Id] {
+			comment.Replies = append(comment.Replies, nest(reply))
+		}
+		return comment
+	}
+
+	var flatten func(i int, thread []model.Comment) []model.Comment
+	flatten = func(i int, thread []model.Comment) []model.Comment {
+		thread = append(thread, comments[i])
+		for _, reply := range replies[comments[i].Id] {
+			thread = flatten(reply, thread)
+		}
+		return thread
+	}
+
+	thread := make([]model.Comment, 0, len(comments))
+	for _, root := range roots {
+		if format == model.CommentFormatTree {
+			thread = append(thread, nest(root))
+		} else {
+			thread = flatten(root, thread)
+		}
+	}
+	return thread
 }
 
 // FindCommentByUserId implements CommentService.
-func (c *commentService) FindCommentByUserId(ctx context.Context, userId uuid.UUID) ([]dto.CommentResponse, error) {
+func (c *commentService) FindCommentByUserId(ctx context.Context, userId uuid.UUID, viewerId uuid.UUID) ([]dto.CommentResponse, error) {
 	// Check context cancellation
 	select {
 	case <-ctx.Done():
@@ -217,7 +501,7 @@ func (c *commentService) FindCommentByUserId(ctx context.Context, userId uuid.UU
 	}
 
 	// Get comments by user from repository with context
-	comments, err := c.repo.GetCommentByUserId(ctx, userId)
+	comments, err := c.repo.GetCommentByUserId(ctx, userId, viewerId)
 	if err != nil {
 		// Check if context was cancelled during repository operation
 		if ctx.Err() != nil {
@@ -229,9 +513,109 @@ func (c *commentService) FindCommentByUserId(ctx context.Context, userId uuid.UU
 	return comments, nil
 }
 
-func NewCommentService(repository repository.CommentRepository, validationService ValidationService) CommentService {
+// LikeComment implements CommentService.
+func (c *commentService) LikeComment(ctx context.Context, commentId uuid.UUID, userId uuid.UUID) (int, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+
+	// Only comments shown to everyone can be liked
+	comment, err := c.repo.GetCommentById(ctx, commentId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, c.errorWrapper.NotFoundError(ctx, "Comment")
+		}
+		return 0, fmt.Errorf("failed to get comment: %v", err)
+	}
+	if comment.Status != model.CommentStatusApproved {
+		return 0, c.errorWrapper.NotFoundError(ctx, "Comment")
+	}
+
+	likes, err := c.repo.LikeComment(ctx, commentId, userId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, fmt.Errorf("failed to like comment: %v", err)
+	}
+
+	return likes, nil
+}
+
+// UnlikeComment implements CommentService.
+func (c *commentService) UnlikeComment(ctx context.Context, commentId uuid.UUID, userId uuid.UUID) (int, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+
+	_, err := c.repo.GetCommentById(ctx, commentId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, c.errorWrapper.NotFoundError(ctx, "Comment")
+		}
+		return 0, fmt.Errorf("failed to get comment: %v", err)
+	}
+
+	likes, err := c.repo.UnlikeComment(ctx, commentId, userId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, c.errorWrapper.NotFoundError(ctx, "Comment")
+		}
+		return 0, fmt.Errorf("failed to unlike comment: %v", err)
+	}
+
+	return likes, nil
+}
+
+// FindCommentEdits implements CommentService.
+func (c *commentService) FindCommentEdits(ctx context.Context, commentId uuid.UUID) ([]model.CommentEdit, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	edits, err := c.repo.GetCommentEdits(ctx, commentId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, c.errorWrapper.NotFoundError(ctx, "Comment")
+		}
+		return nil, fmt.Errorf("failed to get comment edits: %v", err)
+	}
+
+	return edits, nil
+}
+
+func NewCommentService(repository repository.CommentRepository, moderation CommentModerationService, spam SpamClassifier, cacheEvents CacheEventPublisher, validationService ValidationService, errorWrapper utils.ErrorWrapper, commentConfig config.CommentConfig) CommentService {
 	return &commentService{
 		repo:              repository,
+		moderation:        moderation,
+		spam:              spam,
+		cacheEvents:       cacheEvents,
 		validationService: validationService,
+		errorWrapper:      errorWrapper,
+		maxDepth:          commentConfig.MaxDepth,
+		editGracePeriod:   commentConfig.EditGracePeriod,
+		pageSize:          commentConfig.PageSize,
+		maxReplies:        commentConfig.MaxRepliesPerComment,
 	}
 }
@@ -0,0 +1,888 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/config"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCommentRepository keeps comments in a map, their edits oldest first and likes as a set
+// of users per comment; parentGone makes CreateReply find the parent deleted after the
+// service checked it, rejectedMidEdit makes UpdateComment find the comment rejected
+type fakeCommentRepository struct {
+	repository.CommentRepository
+	comments        map[uuid.UUID]model.Comment
+	edits           map[uuid.UUID][]model.CommentEdit
+	likes           map[uuid.UUID]map[uuid.UUID]bool
+	parentGone      bool
+	rejectedMidEdit bool
+}
+
+func (r *fakeCommentRepository) GetCommentById(ctx context.Context, commentId uuid.UUID) (model.Comment, error) {
+	comment, ok := r.comments[commentId]
+	if !ok || comment.IsDeleted {
+		return model.Comment{}, sql.ErrNoRows
+	}
+	return comment, nil
+}
+
+func (r *fakeCommentRepository) CreateComment(ctx context.Context, payload model.Comment) (model.Comment, error) {
+	payload.Id = uuid.New()
+	payload.CreatedAt = time.Now()
+	r.comments[payload.Id] = payload
+	return payload, nil
+}
+
+func (r *fakeCommentRepository) CreateReply(ctx context.Context, payload model.Comment, maxDepth int) (model.Comment, error) {
+	parent, err := r.GetCommentById(ctx, *payload.ParentCommentId)
+	if err != nil || r.parentGone || parent.ArticleId != payload.ArticleId {
+		return model.Comment{}, sql.ErrNoRows
+	}
+	depth := 1
+	for parent.ParentCommentId != nil {
+		parent = r.comments[*parent.ParentCommentId]
+		depth++
+	}
+	if depth > maxDepth {
+		return model.Comment{}, repository.ErrCommentTooDeep
+	}
+
+	comment, err := r.CreateComment(ctx, payload)
+	comment.Depth = depth
+	return comment, err
+}
+
+func (r *fakeCommentRepository) UpdateComment(ctx context.Context, comment model.Comment, editorId uuid.UUID) error {
+	previous, ok := r.comments[comment.Id]
+	if r.rejectedMidEdit {
+		previous.Status = model.CommentStatusRejected
+	}
+	if !ok || previous.IsDeleted || previous.UserId != editorId ||
+		previous.Status == model.CommentStatusRejected || previous.Status == model.CommentStatusSpam {
+		return sql.ErrNoRows
+	}
+	if previous.Content == comment.Content {
+		return nil
+	}
+	if r.edits == nil {
+		r.edits = make(map[uuid.UUID][]model.CommentEdit)
+	}
+	editedAt := time.Now()
+	r.edits[comment.Id] = append(r.edits[comment.Id], model.CommentEdit{
+		Id: uuid.New(), CommentId: comment.Id, PreviousContent: previous.Content, Content: comment.Content, EditedBy: &editorId, EditedAt: editedAt,
+	})
+	comment.EditedAt, comment.IsEdited = &editedAt, true
+	r.comments[comment.Id] = comment
+	return nil
+}
+
+func (r *fakeCommentRepository) GetCommentEdits(ctx context.Context, commentId uuid.UUID) ([]model.CommentEdit, error) {
+	if _, ok := r.comments[commentId]; !ok {
+		return nil, sql.ErrNoRows
+	}
+	return append([]model.CommentEdit{}, r.edits[commentId]...), nil
+}
+
+func (r *fakeCommentRepository) DeleteComment(ctx context.Context, commentId uuid.UUID) error {
+	comment := r.comments[commentId]
+	comment.IsDeleted = true
+	r.comments[commentId] = comment
+	return nil
+}
+
+func (r *fakeCommentRepository) LikeComment(ctx context.Context, commentId, userId uuid.UUID) (int, error) {
+	if r.likes == nil {
+		r.likes = make(map[uuid.UUID]map[uuid.UUID]bool)
+	}
+	if r.likes[commentId] == nil {
+		r.likes[commentId] = make(map[uuid.UUID]bool)
+	}
+	r.likes[commentId][userId] = true
+	return r.setLikeCount(commentId), nil
+}
+
+func (r *fakeCommentRepository) UnlikeComment(ctx context.Context, commentId, userId uuid.UUID) (int, error) {
+	delete(r.likes[commentId], userId)
+	return r.setLikeCount(commentId), nil
+}
+
+func (r *fakeCommentRepository) setLikeCount(commentId uuid.UUID) int {
+	comment := r.comments[commentId]
+	comment.LikeCount = len(r.likes[commentId])
+	r.comments[commentId] = comment
+	return comment.LikeCount
+}
+
+// GetCommentPage orders the top level comments like the repository, ids breaking the ties,
+// and lists the replies of each depth first, oldest first
+func (r *fakeCommentRepository) GetCommentPage(ctx context.Context, articleId, viewerId uuid.UUID, order string, after *model.CommentCursor, limit, maxReplies int) ([]model.Comment, bool, error) {
+	var roots []model.Comment
+	replies := make(map[uuid.UUID][]model.Comment)
+	for _, comment := range r.comments {
+		if comment.ArticleId != articleId {
+			continue
+		}
+		if comment.ParentCommentId == nil {
+			roots = append(roots, comment)
+		} else {
+			replies[*comment.ParentCommentId] = append(replies[*comment.ParentCommentId], comment)
+		}
+	}
+
+	oldest := order == model.CommentSortOldest
+	before := func(a, b model.Comment) bool {
+		if a.Id == b.Id {
+			return false
+		}
+		if order == model.CommentSortMostLiked && a.LikeCount != b.LikeCount {
+			return a.LikeCount > b.LikeCount
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt) != oldest
+		}
+		return (a.Id.String() > b.Id.String()) != oldest
+	}
+	sort.Slice(roots, func(i, j int) bool { return before(roots[i], roots[j]) })
+	if after != nil {
+		cursor := model.Comment{Id: after.Id, CreatedAt: after.CreatedAt, LikeCount: after.LikeCount}
+		for len(roots) > 0 && !before(cursor, roots[0]) {
+			roots = roots[1:]
+		}
+	}
+	hasMore := len(roots) > limit
+	if hasMore {
+		roots = roots[:limit]
+	}
+
+	var thread []model.Comment
+	var walk func(parentId uuid.UUID, depth int)
+	walk = func(parentId uuid.UUID, depth int) {
+		children := replies[parentId]
+		sort.Slice(children, func(i, j int) bool { return children[i].CreatedAt.Before(children[j].CreatedAt) })
+		for _, reply := range children {
+			reply.Depth = depth
+			thread = append(thread, reply)
+			walk(reply.Id, depth+1)
+		}
+	}
+	var page []model.Comment
+	for _, root := range roots {
+		thread = nil
+		walk(root.Id, 1)
+		if len(thread) > maxReplies {
+			thread = thread[:maxReplies]
+			root.HasMoreReplies = true
+		}
+		page = append(page, root)
+		page = append(page, thread...)
+	}
+	return page, hasMore, nil
+}
+
+// approvingModeration approves every comment
+type approvingModeration struct {
+	CommentModerationService
+}
+
+func (m approvingModeration) InitialStatus(ctx context.Context, articleId, userId uuid.UUID, role string, verdict SpamVerdict) (string, error) {
+	return model.CommentStatusApproved, nil
+}
+
+// fixedSpamClassifier gives every comment the same verdict, counts the comments it scored
+// and recorded and lists the decisions it learned and forgot
+type fixedSpamClassifier struct {
+	SpamClassifier
+	verdict    SpamVerdict
+	classified int
+	recorded   int
+	decisions  []string
+}
+
+func (s *fixedSpamClassifier) Classify(ctx context.Context, comment model.Comment) SpamVerdict {
+	s.classified++
+	return s.verdict
+}
+
+func (s *fixedSpamClassifier) RecordPosting(ctx context.Context, comment model.Comment) {
+	s.recorded++
+}
+
+func (s *fixedSpamClassifier) Learn(content string, spam bool) {
+	s.decisions = append(s.decisions, "learn "+spamLabel(spam))
+}
+
+func (s *fixedSpamClassifier) Forget(content string, spam bool) {
+	s.decisions = append(s.decisions, "forget "+spamLabel(spam))
+}
+
+func spamLabel(spam bool) string {
+	if spam {
+		return "spam"
+	}
+	return "ham"
+}
+
+func newTestCommentService(repo repository.CommentRepository, moderation CommentModerationService, spam SpamClassifier) CommentService {
+	return newTestCommentServiceWithEvents(repo, moderation, spam, &recordingPublisher{})
+}
+
+func newTestCommentServiceWithEvents(repo repository.CommentRepository, moderation CommentModerationService, spam SpamClassifier, events CacheEventPublisher) CommentService {
+	errorWrapper := utils.NewErrorWrapper()
+	commentConfig := config.CommentConfig{MaxDepth: 2, EditGracePeriod: 15 * time.Minute, PageSize: 3, MaxRepliesPerComment: 2}
+	return NewCommentService(repo, moderation, spam, events, NewValidationService(errorWrapper), errorWrapper, commentConfig)
+}
+
+func TestCommentService_CreateReply(t *testing.T) {
+	articleId := uuid.New()
+	authorId := uuid.New()
+	root := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), Status: model.CommentStatusApproved}
+	child := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), ParentCommentId: &root.Id, Status: model.CommentStatusApproved}
+	grandchild := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), ParentCommentId: &child.Id, Status: model.CommentStatusApproved}
+	elsewhere := model.Comment{Id: uuid.New(), ArticleId: uuid.New(), UserId: uuid.New(), Status: model.CommentStatusApproved}
+	pendingOwn := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: authorId, Status: model.CommentStatusPending}
+	pendingOther := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), Status: model.CommentStatusPending}
+	deleted := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), Status: model.CommentStatusApproved, IsDeleted: true}
+
+	tests := []struct {
+		name       string
+		parentId   uuid.UUID
+		parentGone bool
+		wantDepth  int
+		wantStatus int
+	}{
+		{name: "reply to a top level comment", parentId: root.Id, wantDepth: 1},
+		{name: "reply at the depth limit", parentId: child.Id, wantDepth: 2},
+		{name: "reply beyond the depth limit", parentId: grandchild.Id, wantStatus: 400},
+		{name: "parent from another article", parentId: elsewhere.Id, wantStatus: 400},
+		{name: "unknown parent", parentId: uuid.New(), wantStatus: 400},
+		{name: "deleted parent", parentId: deleted.Id, wantStatus: 400},
+		{name: "own pending parent", parentId: pendingOwn.Id, wantDepth: 1},
+		{name: "pending parent of another user", parentId: pendingOther.Id, wantStatus: 400},
+		{name: "parent deleted while replying", parentId: root.Id, parentGone: true, wantStatus: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeCommentRepository{parentGone: tt.parentGone, comments: map[uuid.UUID]model.Comment{}}
+			for _, comment := range []model.Comment{root, child, grandchild, elsewhere, pendingOwn, pendingOther, deleted} {
+				repo.comments[comment.Id] = comment
+			}
+			service := newTestCommentService(repo, approvingModeration{}, &fixedSpamClassifier{})
+
+			parentId := tt.parentId
+			reply, err := service.CreateComment(context.Background(), model.Comment{
+				ArticleId: articleId, UserId: authorId, ParentCommentId: &parentId, Content: "Thanks!",
+			}, "user")
+			if tt.wantStatus != 0 {
+				var appErr *utils.AppError
+				require.True(t, errors.As(err, &appErr), "got %v", err)
+				assert.Equal(t, tt.wantStatus, appErr.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDepth, reply.Depth)
+		})
+	}
+}
+
+func TestBuildCommentThread(t *testing.T) {
+	userId := uuid.New()
+	ids := make([]uuid.UUID, 6)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	comment := func(i int, parent int, deleted bool) model.Comment {
+		c := model.Comment{Id: ids[i], UserId: userId, User: &model.User{Id: userId}, Content: "comment", Status: model.CommentStatusApproved, LikeCount: 3, IsDeleted: deleted}
+		if parent >= 0 {
+			c.ParentCommentId = &ids[parent]
+		}
+		return c
+	}
+	idsOf := func(comments []model.Comment) []uuid.UUID {
+		var list []uuid.UUID
+		for _, c := range comments {
+			list = append(list, c.Id)
+		}
+		return list
+	}
+
+	tests := []struct {
+		name     string
+		comments []model.Comment
+		format   string
+		want     []uuid.UUID
+		check    func(t *testing.T, thread []model.Comment)
+	}{
+		{
+			name:     "flat lists replies after their parent",
+			comments: []model.Comment{comment(0, -1, false), comment(1, 0, false), comment(2, 1, false), comment(3, 0, false), comment(4, -1, false)},
+			format:   model.CommentFormatFlat,
+			want:     []uuid.UUID{ids[0], ids[1], ids[2], ids[3], ids[4]},
+		},
+		{
+			name:     "tree nests replies",
+			comments: []model.Comment{comment(0, -1, false), comment(1, 0, false), comment(2, 1, false), comment(3, 0, false), comment(4, -1, false)},
+			format:   model.CommentFormatTree,
+			want:     []uuid.UUID{ids[0], ids[4]},
+			check: func(t *testing.T, thread []model.Comment) {
+				require.Len(t, thread[0].Replies, 2)
+				assert.Equal(t, ids[1], thread[0].Replies[0].Id)
+				assert.Equal(t, ids[3], thread[0].Replies[1].Id)
+				require.Len(t, thread[0].Replies[0].Replies, 1)
+				assert.Equal(t, ids[2], thread[0].Replies[0].Replies[0].Id)
+				assert.Empty(t, thread[1].Replies)
+			},
+		},
+		{
+			name:     "deleted comment with replies is a tombstone",
+			comments: []model.Comment{comment(0, -1, true), comment(1, 0, false)},
+			format:   model.CommentFormatFlat,
+			want:     []uuid.UUID{ids[0], ids[1]},
+			check: func(t *testing.T, thread []model.Comment) {
+				tombstone := thread[0]
+				assert.True(t, tombstone.IsDeleted)
+				assert.Equal(t, model.CommentDeletedContent, tombstone.Content)
+				assert.Equal(t, uuid.Nil, tombstone.UserId)
+				assert.Nil(t, tombstone.User)
+				assert.Zero(t, tombstone.LikeCount)
+				assert.Empty(t, tombstone.Status)
+				assert.Equal(t, "comment", thread[1].Content)
+			},
+		},
+		{
+			name:     "deleted comment without replies is dropped",
+			comments: []model.Comment{comment(0, -1, false), comment(1, 0, true), comment(2, -1, true)},
+			format:   model.CommentFormatFlat,
+			want:     []uuid.UUID{ids[0]},
+		},
+		{
+			name:     "deleted chain without a live reply is dropped",
+			comments: []model.Comment{comment(0, -1, true), comment(1, 0, true), comment(2, 1, true), comment(3, -1, false)},
+			format:   model.CommentFormatTree,
+			want:     []uuid.UUID{ids[3]},
+		},
+		{
+			name: "deleted comment with unlisted replies is a tombstone",
+			comments: []model.Comment{func() model.Comment {
+				c := comment(0, -1, true)
+				c.HasMoreReplies = true
+				return c
+			}()},
+			format: model.CommentFormatTree,
+			want:   []uuid.UUID{ids[0]},
+		},
+		{
+			name:     "tombstones are kept up to a live reply",
+			comments: []model.Comment{comment(0, -1, true), comment(1, 0, true), comment(2, 1, false), comment(3, 0, true)},
+			format:   model.CommentFormatFlat,
+			want:     []uuid.UUID{ids[0], ids[1], ids[2]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thread := buildCommentThread(tt.comments, tt.format)
+			assert.Equal(t, tt.want, idsOf(thread))
+			if tt.check != nil {
+				tt.check(t, thread)
+			}
+		})
+	}
+}
+
+func TestCommentService_EditComment(t *testing.T) {
+	articleId := uuid.New()
+	newcomer, trusted := uuid.New(), uuid.New()
+	moderationRepo := &fakeCommentModerationRepository{
+		policies: map[uuid.UUID]model.CommentModerationPolicy{articleId: {ArticleId: &articleId, Mode: model.CommentPolicyHoldFirstTime}},
+		approved: map[uuid.UUID]int{trusted: 5},
+	}
+
+	tests := []struct {
+		name            string
+		userId          uuid.UUID
+		role            string
+		status          string
+		verdict         SpamVerdict
+		rejectedMidEdit bool
+		deleted         bool
+		missing         bool
+		wantStatus      string
+		wantErr         int
+	}{
+		{name: "approved comment stays approved", userId: trusted, role: "user", status: model.CommentStatusApproved, wantStatus: model.CommentStatusApproved},
+		{name: "suspicious edit goes back to the queue", userId: trusted, role: "user", status: model.CommentStatusApproved, verdict: SpamVerdict{Score: 0.6, Signals: map[string]float64{SpamSignalLinks: 0.6}, Hold: true}, wantStatus: model.CommentStatusPending},
+		{name: "spam edit is marked as spam", userId: trusted, role: "user", status: model.CommentStatusApproved, verdict: SpamVerdict{Score: 0.95, Signals: map[string]float64{SpamSignalBlocklist: 1}, Spam: true, Hold: true}, wantStatus: model.CommentStatusSpam},
+		{name: "pending comment is not approved by an edit", userId: trusted, role: "user", status: model.CommentStatusPending, wantStatus: model.CommentStatusPending},
+		{name: "policy applies to the edit of a newcomer", userId: newcomer, role: "user", status: model.CommentStatusApproved, wantStatus: model.CommentStatusPending},
+		{name: "editor edits stay approved", userId: newcomer, role: "editor", status: model.CommentStatusApproved, wantStatus: model.CommentStatusApproved},
+		{name: "rejected comment cannot be edited", userId: trusted, role: "user", status: model.CommentStatusRejected, wantErr: 403},
+		{name: "spam comment cannot be edited", userId: trusted, role: "user", status: model.CommentStatusSpam, wantErr: 403},
+		{name: "comment rejected while editing", userId: trusted, role: "user", status: model.CommentStatusApproved, rejectedMidEdit: true, wantErr: 404},
+		{name: "deleted comment", userId: trusted, role: "user", status: model.CommentStatusApproved, deleted: true, wantErr: 404},
+		{name: "missing comment", userId: trusted, role: "user", status: model.CommentStatusApproved, missing: true, wantErr: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: tt.userId, Content: "First take", Status: tt.status, CreatedAt: time.Now(), IsDeleted: tt.deleted}
+			repo := &fakeCommentRepository{comments: map[uuid.UUID]model.Comment{comment.Id: comment}, rejectedMidEdit: tt.rejectedMidEdit}
+			service := newTestCommentService(repo, newTestModerationService(moderationRepo), &fixedSpamClassifier{verdict: tt.verdict})
+
+			commentId := comment.Id
+			if tt.missing {
+				commentId = uuid.New()
+			}
+			err := service.EditComment(context.Background(), commentId, "Second take", tt.userId, tt.role)
+			if tt.wantErr != 0 {
+				var appErr *utils.AppError
+				require.True(t, errors.As(err, &appErr), "got %v", err)
+				assert.Equal(t, tt.wantErr, appErr.StatusCode)
+				assert.Equal(t, "First take", repo.comments[comment.Id].Content)
+				return
+			}
+			require.NoError(t, err)
+			edited := repo.comments[comment.Id]
+			assert.Equal(t, "Second take", edited.Content)
+			assert.Equal(t, tt.wantStatus, edited.Status)
+			if tt.verdict.Signals != nil {
+				require.NotNil(t, edited.SpamScore)
+				assert.Equal(t, tt.verdict.Score, *edited.SpamScore)
+			}
+		})
+	}
+}
+
+func TestCommentService_PendingVisibility(t *testing.T) {
+	articleId := uuid.New()
+	author, other := uuid.New(), uuid.New()
+	now := time.Now()
+	approved := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: other, Content: "approved", Status: model.CommentStatusApproved, CreatedAt: now}
+	pending := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: author, Content: "pending", Status: model.CommentStatusPending, CreatedAt: now.Add(time.Second)}
+	spam := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: author, Content: "spam", Status: model.CommentStatusSpam, CreatedAt: now.Add(2 * time.Second)}
+	repo := &fakeCommentRepository{comments: map[uuid.UUID]model.Comment{approved.Id: approved, pending.Id: pending, spam.Id: spam}}
+	service := newTestCommentService(repo, approvingModeration{}, &fixedSpamClassifier{})
+
+	tests := []struct {
+		name     string
+		viewerId uuid.UUID
+		want     []string
+	}{
+		{name: "anonymous viewer sees approved comments", viewerId: uuid.Nil, want: []string{"approved"}},
+		{name: "other user does not see pending comments", viewerId: other, want: []string{"approved"}},
+		{name: "author sees their pending comment", viewerId: author, want: []string{"approved", "pending"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := service.FindCommentByArticleId(context.Background(), articleId, dto.CommentPageQuery{
+				Format: model.CommentFormatFlat, Sort: model.CommentSortOldest, Limit: 10,
+			}, tt.viewerId)
+			require.NoError(t, err)
+			var contents []string
+			for _, comment := range page.Comments {
+				contents = append(contents, comment.Content)
+			}
+			assert.Equal(t, tt.want, contents)
+		})
+	}
+}
+
+func TestCommentService_CreateCommentSpamFilter(t *testing.T) {
+	articleId := uuid.New()
+	parent := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), Status: model.CommentStatusApproved}
+
+	tests := []struct {
+		name           string
+		role           string
+		parentGone     bool
+		wantErr        bool
+		wantClassified int
+		wantRecorded   int
+	}{
+		{name: "user comment is scored and recorded", role: "user", wantClassified: 1, wantRecorded: 1},
+		{name: "editor comment is not scored", role: "editor", wantRecorded: 1},
+		{name: "admin comment is not scored", role: "admin", wantRecorded: 1},
+		{name: "reply refused on insert is not recorded", role: "user", parentGone: true, wantErr: true, wantClassified: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeCommentRepository{parentGone: tt.parentGone, comments: map[uuid.UUID]model.Comment{parent.Id: parent}}
+			spam := &fixedSpamClassifier{}
+			service := newTestCommentService(repo, approvingModeration{}, spam)
+
+			_, err := service.CreateComment(context.Background(), model.Comment{
+				ArticleId: articleId, UserId: uuid.New(), ParentCommentId: &parent.Id, Content: "Nice write-up",
+			}, tt.role)
+			assert.Equal(t, tt.wantErr, err != nil, "got %v", err)
+			assert.Equal(t, tt.wantClassified, spam.classified)
+			assert.Equal(t, tt.wantRecorded, spam.recorded)
+		})
+	}
+}
+
+func TestCommentService_FindCommentPages(t *testing.T) {
+	articleId := uuid.New()
+	now := time.Now()
+	likes := []int{2, 5, 0, 5, 1, 2, 0}
+	roots := make([]model.Comment, len(likes))
+	repo := &fakeCommentRepository{comments: map[uuid.UUID]model.Comment{}}
+	for i, count := range likes {
+		roots[i] = model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), Content: "comment", Status: model.CommentStatusApproved, LikeCount: count, CreatedAt: now.Add(time.Duration(i) * time.Second)}
+		repo.comments[roots[i].Id] = roots[i]
+	}
+	service := newTestCommentService(repo, approvingModeration{}, &fixedSpamClassifier{})
+
+	tests := []struct {
+		sort string
+		want []int
+	}{
+		{sort: model.CommentSortNewest, want: []int{6, 5, 4, 3, 2, 1, 0}},
+		{sort: model.CommentSortOldest, want: []int{0, 1, 2, 3, 4, 5, 6}},
+		// Equal like counts go newest first, the page boundary falls between 5 and 0 with two likes each
+		{sort: model.CommentSortMostLiked, want: []int{3, 1, 5, 0, 4, 6, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			var got []uuid.UUID
+			var sizes []int
+			cursor := ""
+			for {
+				page, err := service.FindCommentByArticleId(context.Background(), articleId, dto.CommentPageQuery{
+					Format: model.CommentFormatFlat, Sort: tt.sort, Cursor: cursor, Limit: 3,
+				}, uuid.Nil)
+				require.NoError(t, err)
+				for _, comment := range page.Comments {
+					got = append(got, comment.Id)
+				}
+				sizes = append(sizes, len(page.Comments))
+				if !page.HasMore {
+					assert.Empty(t, page.NextCursor, "the last page has no cursor")
+					break
+				}
+				require.NotEmpty(t, page.NextCursor)
+				cursor = page.NextCursor
+			}
+
+			want := make([]uuid.UUID, len(tt.want))
+			for i, index := range tt.want {
+				want[i] = roots[index].Id
+			}
+			assert.Equal(t, want, got, "every comment once, in sort order")
+			assert.Equal(t, []int{3, 3, 1}, sizes)
+		})
+	}
+}
+
+func TestCommentService_FindCommentPageQuery(t *testing.T) {
+	articleId := uuid.New()
+	now := time.Now()
+	repo := &fakeCommentRepository{comments: map[uuid.UUID]model.Comment{}}
+	for i := 0; i < 4; i++ {
+		comment := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), Content: "comment", Status: model.CommentStatusApproved, CreatedAt: now.Add(time.Duration(i) * time.Second)}
+		repo.comments[comment.Id] = comment
+	}
+	service := newTestCommentService(repo, approvingModeration{}, &fixedSpamClassifier{})
+
+	first, err := service.FindCommentByArticleId(context.Background(), articleId, dto.CommentPageQuery{
+		Format: model.CommentFormatFlat, Sort: model.CommentSortNewest, Limit: 1,
+	}, uuid.Nil)
+	require.NoError(t, err)
+	require.True(t, first.HasMore)
+
+	tests := []struct {
+		name       string
+		query      dto.CommentPageQuery
+		wantStatus int
+		wantLen    int
+		wantMore   bool
+	}{
+		{name: "no limit takes the page size", query: dto.CommentPageQuery{Sort: model.CommentSortNewest}, wantLen: 3, wantMore: true},
+		{name: "limit covering every comment", query: dto.CommentPageQuery{Sort: model.CommentSortNewest, Limit: 4}, wantLen: 4},
+		{name: "limit at the maximum", query: dto.CommentPageQuery{Sort: model.CommentSortNewest, Limit: 100}, wantLen: 4},
+		{name: "limit above the maximum", query: dto.CommentPageQuery{Sort: model.CommentSortNewest, Limit: 101}, wantStatus: 400},
+		{name: "negative limit", query: dto.CommentPageQuery{Sort: model.CommentSortNewest, Limit: -1}, wantStatus: 400},
+		{name: "unknown sort", query: dto.CommentPageQuery{Sort: "random", Limit: 1}, wantStatus: 400},
+		{name: "cursor continues its sort", query: dto.CommentPageQuery{Sort: model.CommentSortNewest, Cursor: first.NextCursor, Limit: 10}, wantLen: 3},
+		{name: "cursor of another sort", query: dto.CommentPageQuery{Sort: model.CommentSortOldest, Cursor: first.NextCursor, Limit: 1}, wantStatus: 400},
+		{name: "invalid cursor", query: dto.CommentPageQuery{Sort: model.CommentSortNewest, Cursor: "not-a-cursor", Limit: 1}, wantStatus: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Format = model.CommentFormatFlat
+			page, err := service.FindCommentByArticleId(context.Background(), articleId, tt.query, uuid.Nil)
+			if tt.wantStatus != 0 {
+				var appErr *utils.AppError
+				require.True(t, errors.As(err, &appErr), "got %v", err)
+				assert.Equal(t, tt.wantStatus, appErr.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, page.Comments, tt.wantLen)
+			assert.Equal(t, tt.wantMore, page.HasMore)
+			assert.Equal(t, tt.wantMore, page.NextCursor != "")
+		})
+	}
+}
+
+func TestCommentService_MostLikedChangingBetweenPages(t *testing.T) {
+	articleId := uuid.New()
+	now := time.Now()
+	repo := &fakeCommentRepository{comments: map[uuid.UUID]model.Comment{}}
+	roots := make([]model.Comment, 4)
+	for i := range roots {
+		roots[i] = model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), Content: "comment", Status: model.CommentStatusApproved, LikeCount: 4 - i, CreatedAt: now}
+		repo.comments[roots[i].Id] = roots[i]
+	}
+	service := newTestCommentService(repo, approvingModeration{}, &fixedSpamClassifier{})
+	query := dto.CommentPageQuery{Format: model.CommentFormatFlat, Sort: model.CommentSortMostLiked, Limit: 2}
+
+	first, err := service.FindCommentByArticleId(context.Background(), articleId, query, uuid.Nil)
+	require.NoError(t, err)
+	require.Len(t, first.Comments, 2)
+
+	// The last comment is liked past the cursor before the second page is read
+	liked := repo.comments[roots[3].Id]
+	liked.LikeCount = 10
+	repo.comments[liked.Id] = liked
+
+	query.Cursor = first.NextCursor
+	second, err := service.FindCommentByArticleId(context.Background(), articleId, query, uuid.Nil)
+	require.NoError(t, err)
+	assert.False(t, second.HasMore)
+	require.Len(t, second.Comments, 1, "a comment liked past the cursor is skipped")
+	assert.Equal(t, roots[2].Id, second.Comments[0].Id, "the comments whose count stayed keep their place")
+}
+
+func TestCommentService_ReplyCap(t *testing.T) {
+	articleId := uuid.New()
+	now := time.Now()
+	busy := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), Content: "busy", Status: model.CommentStatusApproved, CreatedAt: now.Add(time.Second)}
+	quiet := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), Content: "quiet", Status: model.CommentStatusApproved, CreatedAt: now}
+	repo := &fakeCommentRepository{comments: map[uuid.UUID]model.Comment{busy.Id: busy, quiet.Id: quiet}}
+	reply := func(parent model.Comment, content string, offset time.Duration) model.Comment {
+		comment := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), ParentCommentId: &parent.Id, Content: content, Status: model.CommentStatusApproved, CreatedAt: now.Add(offset)}
+		repo.comments[comment.Id] = comment
+		return comment
+	}
+	first := reply(busy, "first", 2*time.Second)
+	reply(first, "nested", 3*time.Second)
+	reply(busy, "second", 4*time.Second)
+	reply(quiet, "only", 5*time.Second)
+	service := newTestCommentService(repo, approvingModeration{}, &fixedSpamClassifier{})
+
+	page, err := service.FindCommentByArticleId(context.Background(), articleId, dto.CommentPageQuery{
+		Format: model.CommentFormatTree, Sort: model.CommentSortNewest, Limit: 10,
+	}, uuid.Nil)
+	require.NoError(t, err)
+	require.Len(t, page.Comments, 2)
+
+	listed := page.Comments[0]
+	assert.Equal(t, "busy", listed.Content)
+	assert.True(t, listed.HasMoreReplies)
+	require.Len(t, listed.Replies, 1)
+	assert.Equal(t, "first", listed.Replies[0].Content)
+	require.Len(t, listed.Replies[0].Replies, 1, "nested replies count toward the cap")
+	assert.Equal(t, "nested", listed.Replies[0].Replies[0].Content)
+
+	assert.Equal(t, "quiet", page.Comments[1].Content)
+	assert.False(t, page.Comments[1].HasMoreReplies)
+	assert.Len(t, page.Comments[1].Replies, 1)
+}
+
+func TestCommentService_LikeComment(t *testing.T) {
+	articleId := uuid.New()
+	approved := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), Status: model.CommentStatusApproved}
+	pending := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: uuid.New(), Status: model.CommentStatusPending}
+	repo := &fakeCommentRepository{comments: map[uuid.UUID]model.Comment{approved.Id: approved, pending.Id: pending}}
+	events := &recordingPublisher{}
+	service := newTestCommentServiceWithEvents(repo, approvingModeration{}, &fixedSpamClassifier{}, events)
+	ctx := context.Background()
+	reader, other := uuid.New(), uuid.New()
+
+	steps := []struct {
+		name       string
+		commentId  uuid.UUID
+		userId     uuid.UUID
+		unlike     bool
+		wantLikes  int
+		wantStatus int
+	}{
+		{name: "like", commentId: approved.Id, userId: reader, wantLikes: 1},
+		{name: "like again", commentId: approved.Id, userId: reader, wantLikes: 1},
+		{name: "like of another user", commentId: approved.Id, userId: other, wantLikes: 2},
+		{name: "unlike", commentId: approved.Id, userId: reader, unlike: true, wantLikes: 1},
+		{name: "unlike again", commentId: approved.Id, userId: reader, unlike: true, wantLikes: 1},
+		{name: "like of a pending comment", commentId: pending.Id, userId: reader, wantStatus: 404},
+		{name: "like of an unknown comment", commentId: uuid.New(), userId: reader, wantStatus: 404},
+		{name: "unlike of an unknown comment", commentId: uuid.New(), userId: reader, unlike: true, wantStatus: 404},
+	}
+	for _, step := range steps {
+		events.events = nil
+		var likes int
+		var err error
+		if step.unlike {
+			likes, err = service.UnlikeComment(ctx, step.commentId, step.userId)
+		} else {
+			likes, err = service.LikeComment(ctx, step.commentId, step.userId)
+		}
+		if step.wantStatus != 0 {
+			var appErr *utils.AppError
+			require.True(t, errors.As(err, &appErr), "%s: got %v", step.name, err)
+			assert.Equal(t, step.wantStatus, appErr.StatusCode, step.name)
+			assert.Empty(t, events.events, step.name)
+			continue
+		}
+		require.NoError(t, err, step.name)
+		assert.Equal(t, step.wantLikes, likes, step.name)
+		// Likes don't change anything a cached article shows
+		assert.Empty(t, events.events, step.name)
+	}
+}
+
+func TestCommentService_WritesPublishEvents(t *testing.T) {
+	ctx := context.Background()
+	articleId := uuid.New()
+	authorId := uuid.New()
+
+	tests := []struct {
+		name  string
+		write func(s CommentService, comment model.Comment) error
+		// wantEvent tells whether the write succeeds and announces it
+		wantEvent bool
+	}{
+		{name: "create", write: func(s CommentService, comment model.Comment) error {
+			_, err := s.CreateComment(ctx, model.Comment{ArticleId: articleId, UserId: authorId, Content: "Another take"}, "user")
+			return err
+		}, wantEvent: true},
+		{name: "reply", write: func(s CommentService, comment model.Comment) error {
+			_, err := s.CreateComment(ctx, model.Comment{ArticleId: articleId, UserId: authorId, ParentCommentId: &comment.Id, Content: "Agreed"}, "user")
+			return err
+		}, wantEvent: true},
+		{name: "edit", write: func(s CommentService, comment model.Comment) error {
+			return s.EditComment(ctx, comment.Id, "Second take", authorId, "user")
+		}, wantEvent: true},
+		{name: "unchanged edit", write: func(s CommentService, comment model.Comment) error {
+			return s.EditComment(ctx, comment.Id, comment.Content, authorId, "user")
+		}},
+		{name: "delete", write: func(s CommentService, comment model.Comment) error {
+			return s.DeleteComment(ctx, comment.Id, authorId)
+		}, wantEvent: true},
+		{name: "delete by another user", write: func(s CommentService, comment model.Comment) error {
+			return s.DeleteComment(ctx, comment.Id, uuid.New())
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := model.Comment{Id: uuid.New(), ArticleId: articleId, UserId: authorId, Content: "First take", Status: model.CommentStatusApproved, CreatedAt: time.Now()}
+			repo := &fakeCommentRepository{comments: map[uuid.UUID]model.Comment{comment.Id: comment}}
+			events := &recordingPublisher{}
+			service := newTestCommentServiceWithEvents(repo, approvingModeration{}, &fixedSpamClassifier{}, events)
+
+			err := tt.write(service, comment)
+			if !tt.wantEvent {
+				assert.Empty(t, events.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []CacheEvent{commentsChanged(articleId)}, events.events)
+		})
+	}
+}
+
+func TestCommentService_EditGracePeriod(t *testing.T) {
+	authorId := uuid.New()
+
+	tests := []struct {
+		name     string
+		age      time.Duration
+		content  string
+		userId   uuid.UUID
+		role     string
+		wantErr  int
+		wantEdit bool
+	}{
+		{name: "edit within the grace period", age: time.Minute, content: "Second take", role: "user", wantEdit: true},
+		{name: "edit after the grace period", age: time.Hour, content: "Second take", role: "user", wantErr: 403},
+		{name: "editor is held to the grace period", age: time.Hour, content: "Second take", role: "editor", wantErr: 403},
+		{name: "admin edits after the grace period", age: time.Hour, content: "Second take", role: "admin", wantEdit: true},
+		{name: "unchanged content is a no-op", age: time.Minute, content: "First take", role: "user"},
+		{name: "comment of another user", age: time.Minute, content: "Second take", userId: uuid.New(), role: "admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := model.Comment{Id: uuid.New(), ArticleId: uuid.New(), UserId: authorId, Content: "First take", Status: model.CommentStatusApproved, CreatedAt: time.Now().Add(-tt.age)}
+			repo := &fakeCommentRepository{comments: map[uuid.UUID]model.Comment{comment.Id: comment}}
+			spam := &fixedSpamClassifier{}
+			events := &recordingPublisher{}
+			service := newTestCommentServiceWithEvents(repo, approvingModeration{}, spam, events)
+
+			userId := authorId
+			if tt.userId != uuid.Nil {
+				userId = tt.userId
+			}
+			err := service.EditComment(context.Background(), comment.Id, tt.content, userId, tt.role)
+			switch {
+			case tt.wantErr != 0:
+				var appErr *utils.AppError
+				require.True(t, errors.As(err, &appErr), "got %v", err)
+				assert.Equal(t, tt.wantErr, appErr.StatusCode)
+			case userId != authorId:
+				assert.ErrorIs(t, err, ErrUnauthorized)
+			default:
+				require.NoError(t, err)
+			}
+
+			edited := repo.comments[comment.Id]
+			if !tt.wantEdit {
+				assert.Equal(t, "First take", edited.Content)
+				assert.False(t, edited.IsEdited)
+				assert.Empty(t, repo.edits[comment.Id])
+				assert.Zero(t, spam.classified, "a refused or empty edit is not scored")
+				assert.Empty(t, events.events)
+				return
+			}
+			assert.Equal(t, tt.content, edited.Content)
+			assert.True(t, edited.IsEdited)
+			require.Len(t, repo.edits[comment.Id], 1)
+			assert.Equal(t, "First take", repo.edits[comment.Id][0].PreviousContent)
+		})
+	}
+}
+
+func TestCommentService_FindCommentEdits(t *testing.T) {
+	ctx := context.Background()
+	authorId := uuid.New()
+	comment := model.Comment{Id: uuid.New(), ArticleId: uuid.New(), UserId: authorId, Content: "First take", Status: model.CommentStatusApproved, CreatedAt: time.Now()}
+	untouched := model.Comment{Id: uuid.New(), ArticleId: comment.ArticleId, UserId: authorId, Content: "Untouched", Status: model.CommentStatusApproved, CreatedAt: time.Now()}
+	repo := &fakeCommentRepository{comments: map[uuid.UUID]model.Comment{comment.Id: comment, untouched.Id: untouched}}
+	service := newTestCommentService(repo, approvingModeration{}, &fixedSpamClassifier{})
+
+	require.NoError(t, service.EditComment(ctx, comment.Id, "Second take", authorId, "user"))
+	require.NoError(t, service.EditComment(ctx, comment.Id, "Third take", authorId, "user"))
+
+	edits, err := service.FindCommentEdits(ctx, comment.Id)
+	require.NoError(t, err)
+	require.Len(t, edits, 2)
+	assert.Equal(t, "First take", edits[0].PreviousContent, "oldest edit first")
+	assert.Equal(t, "Second take", edits[0].Content)
+	assert.Equal(t, "Second take", edits[1].PreviousContent)
+	assert.Equal(t, "Third take", edits[1].Content)
+
+	edits, err = service.FindCommentEdits(ctx, untouched.Id)
+	require.NoError(t, err)
+	assert.Empty(t, edits, "a comment never edited has an empty history")
+
+	_, err = service.FindCommentEdits(ctx, uuid.New())
+	var appErr *utils.AppError
+	require.True(t, errors.As(err, &appErr), "got %v", err)
+	assert.Equal(t, 404, appErr.StatusCode)
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"develapar-server/config"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+	FeedFormatJSON = "json"
+)
+
+// maxCachedFeeds bounds the feed cache, every category, tag and author has its own feeds.
+// The least recently served feed is evicted first.
+const maxCachedFeeds = 500
+
+// FeedDocument is a rendered feed together with its conditional GET validators
+type FeedDocument struct {
+	Body         []byte
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+type FeedService interface {
+	// GetFeed returns the feed served at path. Rendered feeds are cached until an
+	// article is published, updated, archived, deleted or retagged. A feed of an
+	// unknown category, tag or author answers as not found.
+	GetFeed(ctx context.Context, path string, filter dto.ArticleFeedFilter, format string) (FeedDocument, error)
+}
+
+// cachedFeed is a rendered feed and the publication stamp it was rendered at
+type cachedFeed struct {
+	Document     FeedDocument `json:"document"`
+	LastModified time.Time    `json:"last_modified"`
+	Count        int          `json:"count"`
+}
+
+type feedService struct {
+	articleRepo      repository.ArticleRepository
+	categoryRepo     repository.CategoryRepository
+	tagRepo          repository.TagRepository
+	userRepo         repository.UserRepository
+	markdownRenderer utils.MarkdownRenderer
+	site             config.SiteConfig
+	errorWrapper     utils.ErrorWrapper
+	cache            utils.Cache
+}
+
+// GetFeed implements FeedService.
+func (f *feedService) GetFeed(ctx context.Context, path string, filter dto.ArticleFeedFilter, format string) (FeedDocument, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return FeedDocument{}, ctx.Err()
+	default:
+	}
+
+	// A cheap stamp query tells whether the cached feed is still current
+	lastModified, count, err := f.articleRepo.GetPublicationStamp(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return FeedDocument{}, ctx.Err()
+		}
+		return FeedDocument{}, fmt.Errorf("failed to check feed freshness: %v", err)
+	}
+
+	cacheKey := format + " " + path
+	if cached, ok := f.cachedFeed(ctx, cacheKey); ok && cached.LastModified.Equal(lastModified) && cached.Count == count {
+		return cached.Document, nil
+	}
+
+	feed, err := f.buildFeed(ctx, path, filter)
+	if err != nil {
+		return FeedDocument{}, err
+	}
+	feed.Updated = lastModified
+
+	document, err := renderFeed(feed, format)
+	if err != nil {
+		return FeedDocument{}, err
+	}
+	document.LastModified = lastModified
+
+	if data, err := json.Marshal(cachedFeed{Document: document, LastModified: lastModified, Count: count}); err == nil {
+		// The in-memory cache does not fail
+		_ = f.cache.Set(ctx, cacheKey, data, 0)
+	}
+
+	return document, nil
+}
+
+// cachedFeed returns the feed cached under key, if any
+func (f *feedService) cachedFeed(ctx context.Context, key string) (cachedFeed, bool) {
+	data, found, err := f.cache.Get(ctx, key)
+	if err != nil || !found {
+		return cachedFeed{}, false
+	}
+	var cached cachedFeed
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return cachedFeed{}, false
+	}
+	return cached, true
+}
+
+// buildFeed loads the articles of a feed and converts them to feed items
+func (f *feedService) buildFeed(ctx context.Context, path string, filter dto.ArticleFeedFilter) (utils.Feed, error) {
+	feed := utils.Feed{
+		Title:       f.site.Title,
+		Description: f.site.Description,
+		Link:        f.site.BaseURL,
+		FeedURL:     f.site.BaseURL + path,
+	}
+
+	switch {
+	case filter.Category != "":
+		if _, err := f.categoryRepo.GetCategoryByName(ctx, filter.Category); err != nil {
+			if ctx.Err() != nil {
+				return utils.Feed{}, ctx.Err()
+			}
+			if errors.Is(err, sql.ErrNoRows) {
+				return utils.Feed{}, f.errorWrapper.NotFoundError(ctx, "Category")
+			}
+			return utils.Feed{}, fmt.Errorf("failed to fetch feed category: %v", err)
+		}
+		feed.Title = fmt.Sprintf("%s - %s", f.site.Title, filter.Category)
+		feed.Description = fmt.Sprintf("Articles in category %s", filter.Category)
+	case filter.Tag != "":
+		if _, err := f.tagRepo.GetTagByName(ctx, filter.Tag); err != nil {
+			if ctx.Err() != nil {
+				return utils.Feed{}, ctx.Err()
+			}
+			if errors.Is(err, sql.ErrNoRows) {
+				return utils.Feed{}, f.errorWrapper.NotFoundError(ctx, "Tag")
+			}
+			return utils.Feed{}, fmt.Errorf("failed to fetch feed tag: %v", err)
+		}
+		feed.Title = fmt.Sprintf("%s - #%s", f.site.Title, filter.Tag)
+		feed.Description = fmt.Sprintf("Articles tagged %s", filter.Tag)
+	case filter.UserId != uuid.Nil:
+		author, err := f.userRepo.GetUserById(ctx, filter.UserId)
+		if err != nil {
+			if ctx.Err() != nil {
+				return utils.Feed{}, ctx.Err()
+			}
+			if errors.Is(err, sql.ErrNoRows) {
+				return utils.Feed{}, f.errorWrapper.NotFoundError(ctx, "Author")
+			}
+			return utils.Feed{}, fmt.Errorf("failed to fetch feed author: %v", err)
+		}
+		feed.Title = fmt.Sprintf("%s - %s", f.site.Title, author.Name)
+		feed.Description = fmt.Sprintf("Articles by %s", author.Name)
+	}
+
+	articles, err := f.articleRepo.GetFeedArticles(ctx, filter, f.site.FeedItemLimit)
+	if err != nil {
+		if ctx.Err() != nil {
+			return utils.Feed{}, ctx.Err()
+		}
+		return utils.Feed{}, fmt.Errorf("failed to fetch feed articles: %v", err)
+	}
+
+	for _, article := range articles {
+		item, err := f.feedItem(article)
+		if err != nil {
+			return utils.Feed{}, err
+		}
+		feed.Items = append(feed.Items, item)
+	}
+
+	return feed, nil
+}
+
+// feedItem converts a public article into a feed entry
+func (f *feedService) feedItem(article model.Article) (utils.FeedItem, error) {
+	// Articles written before rendering was introduced have no stored output yet
+	if article.ContentHash == "" {
+		if err := renderArticleContent(f.markdownRenderer, &article); err != nil {
+			return utils.FeedItem{}, err
+		}
+	}
+
+	item := utils.FeedItem{
+		Id:          article.Id.String(),
+		Title:       article.Title,
+		Link:        f.site.BaseURL + "/articles/" + article.Slug,
+		Summary:     article.Excerpt,
+		ContentHTML: article.ContentHTML,
+		Published:   article.CreatedAt,
+		Updated:     article.UpdatedAt,
+	}
+	if article.PublishAt != nil {
+		item.Published = *article.PublishAt
+	}
+	if article.User != nil {
+		item.AuthorName = article.User.Name
+	}
+	if article.Category != nil {
+		item.Categories = append(item.Categories, article.Category.Name)
+	}
+	for _, tag := range article.Tags {
+		item.Categories = append(item.Categories, tag.Name)
+	}
+	return item, nil
+}
+
+// renderFeed renders a feed in the requested format and computes its strong ETag
+func renderFeed(feed utils.Feed, format string) (FeedDocument, error) {
+	var (
+		body        []byte
+		contentType string
+		err         error
+	)
+
+	switch format {
+	case FeedFormatRSS:
+		body, err = utils.RenderRSS(feed)
+		contentType = utils.RSSContentType
+	case FeedFormatAtom:
+		body, err = utils.RenderAtom(feed)
+		contentType = utils.AtomContentType
+	case FeedFormatJSON:
+		body, err = utils.RenderJSONFeed(feed)
+		contentType = utils.JSONFeedContentType
+	default:
+		return FeedDocument{}, fmt.Errorf("unknown feed format %q", format)
+	}
+	if err != nil {
+		return FeedDocument{}, fmt.Errorf("failed to render %s feed: %v", format, err)
+	}
+
+	sum := sha256.Sum256(body)
+	return FeedDocument{
+		Body:        body,
+		ContentType: contentType,
+		ETag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+	}, nil
+}
+
+func NewFeedService(articleRepo repository.ArticleRepository, categoryRepo repository.CategoryRepository, tagRepo repository.TagRepository, userRepo repository.UserRepository, markdownRenderer utils.MarkdownRenderer, site config.SiteConfig, errorWrapper utils.ErrorWrapper) FeedService {
+	return &feedService{
+		articleRepo:      articleRepo,
+		categoryRepo:     categoryRepo,
+		tagRepo:          tagRepo,
+		userRepo:         userRepo,
+		markdownRenderer: markdownRenderer,
+		site:             site,
+		errorWrapper:     errorWrapper,
+		cache:            utils.NewLRUCache(maxCachedFeeds),
+	}
+}
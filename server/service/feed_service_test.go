@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/config"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFeedArticleRepository serves a fixed list of public articles and publication stamp
+type fakeFeedArticleRepository struct {
+	repository.ArticleRepository
+	articles     []model.Article
+	lastModified time.Time
+	count        int
+	feedQueries  int
+}
+
+func (r *fakeFeedArticleRepository) GetPublicationStamp(ctx context.Context) (time.Time, int, error) {
+	return r.lastModified, r.count, nil
+}
+
+func (r *fakeFeedArticleRepository) GetFeedArticles(ctx context.Context, filter dto.ArticleFeedFilter, limit int) ([]model.Article, error) {
+	r.feedQueries++
+	return r.articles, nil
+}
+
+type fakeCategoryRepository struct {
+	repository.CategoryRepository
+	categories map[string]model.Category
+}
+
+func (r *fakeCategoryRepository) GetCategoryByName(ctx context.Context, name string) (model.Category, error) {
+	category, ok := r.categories[name]
+	if !ok {
+		return model.Category{}, sql.ErrNoRows
+	}
+	return category, nil
+}
+
+type fakeTagRepository struct {
+	repository.TagRepository
+	tags map[string]model.Tags
+}
+
+func (r *fakeTagRepository) GetTagByName(ctx context.Context, name string) (model.Tags, error) {
+	tag, ok := r.tags[name]
+	if !ok {
+		return model.Tags{}, sql.ErrNoRows
+	}
+	return tag, nil
+}
+
+func newTestFeedService(articleRepo *fakeFeedArticleRepository) FeedService {
+	categoryRepo := &fakeCategoryRepository{categories: map[string]model.Category{"go": {Id: uuid.New(), Name: "go"}}}
+	tagRepo := &fakeTagRepository{tags: map[string]model.Tags{"testing": {Id: uuid.New(), Name: "testing"}}}
+	site := config.SiteConfig{Title: "Develapar", BaseURL: "https://example.com", FeedItemLimit: 20}
+	return NewFeedService(articleRepo, categoryRepo, tagRepo, nil, utils.NewMarkdownRenderer(), site, utils.NewErrorWrapper())
+}
+
+func TestFeedService_UnknownFilterIsNotFound(t *testing.T) {
+	service := newTestFeedService(&fakeFeedArticleRepository{lastModified: time.Now()})
+
+	tests := []struct {
+		name    string
+		filter  dto.ArticleFeedFilter
+		wantErr bool
+	}{
+		{name: "known category", filter: dto.ArticleFeedFilter{Category: "go"}},
+		{name: "unknown category", filter: dto.ArticleFeedFilter{Category: "cobol"}, wantErr: true},
+		{name: "known tag", filter: dto.ArticleFeedFilter{Tag: "testing"}},
+		{name: "unknown tag", filter: dto.ArticleFeedFilter{Tag: "nope"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/feeds/" + tt.filter.Category + tt.filter.Tag + "/feed.xml"
+			_, err := service.GetFeed(context.Background(), path, tt.filter, FeedFormatRSS)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var appErr *utils.AppError
+			require.True(t, errors.As(err, &appErr))
+			assert.Equal(t, 404, appErr.StatusCode)
+		})
+	}
+}
+
+func TestFeedService_CachesUntilTheStampChanges(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	repo := &fakeFeedArticleRepository{
+		articles: []model.Article{{
+			Id: uuid.New(), Title: "Hello", Slug: "hello", Content: "Hello **world**",
+			CreatedAt: now, UpdatedAt: now,
+		}},
+		lastModified: now,
+		count:        1,
+	}
+	service := newTestFeedService(repo)
+	ctx := context.Background()
+
+	first, err := service.GetFeed(ctx, "/feed.xml", dto.ArticleFeedFilter{}, FeedFormatRSS)
+	require.NoError(t, err)
+	second, err := service.GetFeed(ctx, "/feed.xml", dto.ArticleFeedFilter{}, FeedFormatRSS)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, repo.feedQueries, "an unchanged stamp serves the cached feed")
+
+	// A tag added to an article changes the count of the stamp only
+	repo.count++
+	_, err = service.GetFeed(ctx, "/feed.xml", dto.ArticleFeedFilter{}, FeedFormatRSS)
+	require.NoError(t, err)
+	assert.Equal(t, 2, repo.feedQueries)
+
+	_, err = service.GetFeed(ctx, "/feed.xml", dto.ArticleFeedFilter{}, FeedFormatAtom)
+	require.NoError(t, err)
+	assert.Equal(t, 3, repo.feedQueries, "every format is cached on its own")
+}
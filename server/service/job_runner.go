@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"develapar-server/utils"
+	"sync"
+	"time"
+)
+
+// BackgroundJob is a unit of periodic work run by the JobRunner
+type BackgroundJob interface {
+	Name() string
+	Interval() time.Duration
+	Run(ctx context.Context) error
+}
+
+// JobRunner runs background jobs on their own tickers until stopped
+type JobRunner interface {
+	Register(job BackgroundJob)
+	Start(ctx context.Context)
+	Stop(ctx context.Context) error
+}
+
+type jobRunner struct {
+	jobs    []BackgroundJob
+	logger  utils.Logger
+	timeout time.Duration
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+}
+
+// Register implements JobRunner. Jobs must be registered before Start.
+func (r *jobRunner) Register(job BackgroundJob) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs = append(r.jobs, job)
+}
+
+// Start implements JobRunner.
+func (r *jobRunner) Start(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+
+	for _, job := range r.jobs {
+		r.wg.Add(1)
+		go r.loop(runCtx, job)
+	}
+
+	r.logger.Info(ctx, "Background jobs started", utils.IntField("jobs", len(r.jobs)))
+}
+
+// Stop implements JobRunner. It cancels all jobs and waits for running ones to
+// return, or until ctx is done.
+func (r *jobRunner) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel := r.cancel
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		r.logger.Info(ctx, "Background jobs stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loop runs a single job on its interval until ctx is cancelled
+func (r *jobRunner) loop(ctx context.Context, job BackgroundJob) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(job.Interval())
+	defer ticker.Stop()
+
+	// Run once right away so pending work is not delayed by a full interval after startup
+	r.runOnce(ctx, job)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.runOnce(ctx, job)
+		}
+	}
+}
+
+// runOnce runs a job with a timeout, recovering from panics so one bad run
+// does not stop the job or the server
+func (r *jobRunner) runOnce(ctx context.Context, job BackgroundJob) {
+	runCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.logger.Error(ctx, "Background job panicked", nil,
+				utils.StringField("job", job.Name()),
+				utils.Field{Key: "panic", Value: rec},
+			)
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(runCtx); err != nil {
+		// Errors caused by shutdown are expected
+		if ctx.Err() != nil {
+			return
+		}
+		r.logger.Error(ctx, "Background job failed", err,
+			utils.StringField("job", job.Name()),
+			utils.DurationField("duration", time.Since(start)),
+		)
+	}
+}
+
+// NewJobRunner creates a job runner; timeout bounds a single run of any job
+func NewJobRunner(logger utils.Logger, timeout time.Duration) JobRunner {
+	return &jobRunner{
+		logger:  logger,
+		timeout: timeout,
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"develapar-server/utils"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingJob counts its runs and blocks each one until its context is done when block is set
+type countingJob struct {
+	interval time.Duration
+	block    bool
+	runs     atomic.Int32
+	started  chan struct{}
+}
+
+func newTestLogger() utils.Logger {
+	return utils.NewJSONLogger(io.Discard, utils.FatalLevel, "test")
+}
+
+func newCountingJob(interval time.Duration, block bool) *countingJob {
+	return &countingJob{interval: interval, block: block, started: make(chan struct{}, 16)}
+}
+
+func (j *countingJob) Name() string {
+	return "counting"
+}
+
+func (j *countingJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *countingJob) Run(ctx context.Context) error {
+	j.runs.Add(1)
+	select {
+	case j.started <- struct{}{}:
+	default:
+	}
+	if j.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestJobRunner_RunsRightAwayAndOnInterval(t *testing.T) {
+	job := newCountingJob(10*time.Millisecond, false)
+	runner := NewJobRunner(newTestLogger(), time.Second)
+	runner.Register(job)
+
+	runner.Start(context.Background())
+	for i := 0; i < 3; i++ {
+		select {
+		case <-job.started:
+		case <-time.After(time.Second):
+			t.Fatalf("job ran %d times, want at least 3", job.runs.Load())
+		}
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, runner.Stop(stopCtx))
+}
+
+func TestJobRunner_StopsWhenContextIsCancelled(t *testing.T) {
+	job := newCountingJob(time.Hour, true)
+	runner := NewJobRunner(newTestLogger(), time.Hour)
+	runner.Register(job)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runner.Start(ctx)
+	select {
+	case <-job.started:
+	case <-time.After(time.Second):
+		t.Fatal("job did not start")
+	}
+
+	// Cancelling the context ends the running job and its loop without a call to Stop
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		runner.(*jobRunner).wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("runner kept running after its context was cancelled")
+	}
+	assert.Equal(t, int32(1), job.runs.Load())
+}
+
+func TestJobRunner_StopCancelsRunningJobs(t *testing.T) {
+	job := newCountingJob(time.Hour, true)
+	runner := NewJobRunner(newTestLogger(), time.Hour)
+	runner.Register(job)
+
+	runner.Start(context.Background())
+	select {
+	case <-job.started:
+	case <-time.After(time.Second):
+		t.Fatal("job did not start")
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, runner.Stop(stopCtx))
+}
+
+func TestJobRunner_StopWithoutStart(t *testing.T) {
+	runner := NewJobRunner(newTestLogger(), time.Second)
+	assert.NoError(t, runner.Stop(context.Background()))
+}
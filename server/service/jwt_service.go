@@ -17,6 +17,12 @@ type JwtService interface {
 	VerifyToken(tokenString string) (jwt.MapClaims, error)
 
 	GenerateRefreshToken() (string, error)
+
+	// GenerateScopedToken issues a token that only grants purpose on subject until it
+	// expires after ttl. Scoped tokens are rejected by VerifyToken.
+	GenerateScopedToken(purpose, subject string, ttl time.Duration) (string, time.Time, error)
+	// VerifyScopedToken checks a token issued for purpose and returns its subject
+	VerifyScopedToken(tokenString, purpose string) (string, error)
 }
 type jwtService struct {
 	config config.SecurityConfig
@@ -74,9 +80,48 @@ func (j *jwtService) VerifyToken(tokenString string) (jwt.MapClaims, error) {
 	if !token.Valid || !ok || claims["iss"] != j.config.Issues {
 		return nil, errors.New("invalid issuer or claims")
 	}
+	// Scoped tokens are signed with the same key but never identify a user
+	if _, scoped := claims["purpose"]; scoped {
+		return nil, errors.New("scoped token cannot authenticate")
+	}
 	return claims, nil
 }
 
+// GenerateScopedToken implements JwtService.
+func (j *jwtService) GenerateScopedToken(purpose, subject string, ttl time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
+	claims := dto.ScopedTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    j.config.Issues,
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		Purpose: purpose,
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(j.config.Key))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// VerifyScopedToken implements JwtService.
+func (j *jwtService) VerifyScopedToken(tokenString, purpose string) (string, error) {
+	var claims dto.ScopedTokenClaims
+	token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(j.config.Key), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(j.config.Issues), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return "", errors.New("failed verify token")
+	}
+	if claims.Purpose != purpose {
+		return "", errors.New("token issued for another purpose")
+	}
+	return claims.Subject, nil
+}
+
 func NewJwtService(cg config.SecurityConfig) JwtService {
 	return &jwtService{config: cg}
 }
@@ -3,6 +3,7 @@ package service
 import (
 	"develapar-server/model"
 	"develapar-server/model/dto"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/mock"
@@ -26,3 +27,13 @@ func (j *JwtServiceMock) GenerateRefreshToken() (string, error) {
 	args := j.Called()
 	return args.Get(0).(string), args.Error(1)
 }
+
+func (j *JwtServiceMock) GenerateScopedToken(purpose, subject string, ttl time.Duration) (string, time.Time, error) {
+	args := j.Called(purpose, subject, ttl)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (j *JwtServiceMock) VerifyScopedToken(token, purpose string) (string, error) {
+	args := j.Called(token, purpose)
+	return args.String(0), args.Error(1)
+}
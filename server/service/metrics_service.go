@@ -21,11 +21,15 @@ type MetricsService interface {
 	RecordMemoryUsage(ctx context.Context, allocBytes, sysBytes uint64)
 	RecordGoroutineCount(ctx context.Context, count int)
 	
+	// Cache metrics
+	RecordCacheLookup(ctx context.Context, cacheName string, hit bool)
+	
 	// Get metrics
 	GetRequestMetrics(ctx context.Context) RequestMetrics
 	GetDatabaseMetrics(ctx context.Context) DatabaseMetrics
 	GetApplicationMetrics(ctx context.Context) ApplicationMetrics
 	GetErrorMetrics(ctx context.Context) ErrorMetrics
+	GetCacheMetrics(ctx context.Context) CacheMetrics
 	GetAllMetrics(ctx context.Context) AllMetrics
 	
 	// Reset metrics
@@ -92,12 +96,23 @@ type ErrorMetrics struct {
 	LastUpdated       time.Time        `json:"last_updated"`
 }
 
+// CacheMetrics represents read cache hit and miss metrics
+type CacheMetrics struct {
+	Hits          int64            `json:"hits"`
+	Misses        int64            `json:"misses"`
+	HitRate       float64          `json:"hit_rate"`
+	HitsByCache   map[string]int64 `json:"hits_by_cache"`
+	MissesByCache map[string]int64 `json:"misses_by_cache"`
+	LastUpdated   time.Time        `json:"last_updated"`
+}
+
 // AllMetrics represents all metrics combined
 type AllMetrics struct {
 	Request     RequestMetrics     `json:"request"`
 	Database    DatabaseMetrics    `json:"database"`
 	Application ApplicationMetrics `json:"application"`
 	Error       ErrorMetrics       `json:"error"`
+	Cache       CacheMetrics       `json:"cache"`
 	Timestamp   time.Time          `json:"timestamp"`
 }
 
@@ -131,6 +146,10 @@ type metricsService struct {
 	totalErrors       int64
 	errorsByType      map[string]int64
 	errorsByOperation map[string]int64
+	
+	// Cache metrics
+	cacheHits         map[string]int64
+	cacheMisses       map[string]int64
 }
 
 // NewMetricsService creates a new metrics service with context support
@@ -147,6 +166,8 @@ func NewMetricsService(logger utils.Logger) MetricsService {
 		queryLatencies:    make([]time.Duration, 0, 1000), // Keep last 1000 queries
 		errorsByType:      make(map[string]int64),
 		errorsByOperation: make(map[string]int64),
+		cacheHits:         make(map[string]int64),
+		cacheMisses:       make(map[string]int64),
 	}
 }
 
@@ -265,6 +286,18 @@ func (ms *metricsService) RecordGoroutineCount(ctx context.Context, count int) {
 	ms.goroutineCount = count
 }
 
+// RecordCacheLookup records a read cache hit or miss with context
+func (ms *metricsService) RecordCacheLookup(ctx context.Context, cacheName string, hit bool) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	
+	if hit {
+		ms.cacheHits[cacheName]++
+	} else {
+		ms.cacheMisses[cacheName]++
+	}
+}
+
 // GetRequestMetrics returns request metrics with context
 func (ms *metricsService) GetRequestMetrics(ctx context.Context) RequestMetrics {
 	ms.mu.RLock()
@@ -345,6 +378,35 @@ func (ms *metricsService) GetErrorMetrics(ctx context.Context) ErrorMetrics {
 	}
 }
 
+// GetCacheMetrics returns read cache metrics with context
+func (ms *metricsService) GetCacheMetrics(ctx context.Context) CacheMetrics {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	
+	var hits, misses int64
+	for _, count := range ms.cacheHits {
+		hits += count
+	}
+	for _, count := range ms.cacheMisses {
+		misses += count
+	}
+	
+	// Calculate hit rate
+	hitRate := float64(0)
+	if hits+misses > 0 {
+		hitRate = float64(hits) / float64(hits+misses) * 100
+	}
+	
+	return CacheMetrics{
+		Hits:          hits,
+		Misses:        misses,
+		HitRate:       hitRate,
+		HitsByCache:   ms.copyStringInt64Map(ms.cacheHits),
+		MissesByCache: ms.copyStringInt64Map(ms.cacheMisses),
+		LastUpdated:   time.Now(),
+	}
+}
+
 // GetAllMetrics returns all metrics combined with context
 func (ms *metricsService) GetAllMetrics(ctx context.Context) AllMetrics {
 	return AllMetrics{
@@ -352,6 +414,7 @@ func (ms *metricsService) GetAllMetrics(ctx context.Context) AllMetrics {
 		Database:    ms.GetDatabaseMetrics(ctx),
 		Application: ms.GetApplicationMetrics(ctx),
 		Error:       ms.GetErrorMetrics(ctx),
+		Cache:       ms.GetCacheMetrics(ctx),
 		Timestamp:   time.Now(),
 	}
 }
@@ -384,6 +447,10 @@ func (ms *metricsService) ResetMetrics(ctx context.Context) {
 	ms.totalErrors = 0
 	ms.errorsByType = make(map[string]int64)
 	ms.errorsByOperation = make(map[string]int64)
+	
+	// Reset cache metrics
+	ms.cacheHits = make(map[string]int64)
+	ms.cacheMisses = make(map[string]int64)
 }
 
 // Helper methods for calculations
@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"develapar-server/model"
 	"develapar-server/model/dto"
 	"develapar-server/repository"
+	"develapar-server/utils"
 	"errors"
 	"regexp"
 	"strings"
@@ -19,7 +21,8 @@ type ProductService interface {
 	GetAllProductCategoriesWithPagination(ctx context.Context, page, limit int) (PaginationResult, error)
 	GetProductCategoryById(ctx context.Context, id uuid.UUID) (dto.ProductCategoryResponse, error)
 	GetProductCategoryBySlug(ctx context.Context, slug string) (dto.ProductCategoryResponse, error)
-	UpdateProductCategory(ctx context.Context, id uuid.UUID, req dto.UpdateProductCategoryRequest) (dto.ProductCategoryResponse, error)
+	// UpdateProductCategory applies the update only if version is still the current one
+	UpdateProductCategory(ctx context.Context, id uuid.UUID, version int, req dto.UpdateProductCategoryRequest) (dto.ProductCategoryResponse, error)
 	DeleteProductCategory(ctx context.Context, id uuid.UUID) error
 
 	// Products
@@ -27,13 +30,15 @@ type ProductService interface {
 	GetAllProductsWithPagination(ctx context.Context, page, limit int) (PaginationResult, error)
 	GetProductById(ctx context.Context, id uuid.UUID) (dto.ProductResponse, error)
 	GetProductsByCategoryWithPagination(ctx context.Context, categoryId uuid.UUID, page, limit int) (PaginationResult, error)
-	UpdateProduct(ctx context.Context, id uuid.UUID, req dto.UpdateProductRequest) (dto.ProductResponse, error)
+	// UpdateProduct applies the update only if version is still the current one
+	UpdateProduct(ctx context.Context, id uuid.UUID, version int, req dto.UpdateProductRequest) (dto.ProductResponse, error)
 	DeleteProduct(ctx context.Context, id uuid.UUID) error
 
 	// Product Affiliate Links
 	CreateProductAffiliateLink(ctx context.Context, productId uuid.UUID, req dto.CreateProductAffiliateLinkRequest) (dto.ProductAffiliateLinkResponse, error)
 	GetAffiliateLinksbyProductId(ctx context.Context, productId uuid.UUID) ([]dto.ProductAffiliateLinkResponse, error)
-	UpdateProductAffiliateLink(ctx context.Context, productId, affiliateId uuid.UUID, req dto.UpdateProductAffiliateLinkRequest) (dto.ProductAffiliateLinkResponse, error)
+	// UpdateProductAffiliateLink applies the update only if version is still the current one
+	UpdateProductAffiliateLink(ctx context.Context, productId, affiliateId uuid.UUID, version int, req dto.UpdateProductAffiliateLinkRequest) (dto.ProductAffiliateLinkResponse, error)
 	DeleteProductAffiliateLink(ctx context.Context, id uuid.UUID) error
 
 	// Article Product Relations
@@ -44,9 +49,11 @@ type ProductService interface {
 }
 
 type productService struct {
-	productRepo repository.ProductRepository
-	validation  ValidationService
-	pagination  PaginationService
+	productRepo   repository.ProductRepository
+	validation    ValidationService
+	pagination    PaginationService
+	slugAllocator SlugAllocator
+	errorWrapper  utils.ErrorWrapper
 }
 
 // Helper functions for conversion
@@ -56,6 +63,7 @@ func (s *productService) modelToProductCategoryResponse(category model.ProductCa
 		Name:        category.Name,
 		Slug:        category.Slug,
 		Description: category.Description,
+		Version:     category.Version,
 		CreatedAt:   category.CreatedAt,
 		UpdatedAt:   category.UpdatedAt,
 	}
@@ -66,6 +74,7 @@ func (s *productService) modelToProductAffiliateLinkResponse(link model.ProductA
 		Id:           link.Id,
 		PlatformName: link.PlatformName,
 		Url:          link.Url,
+		Version:      link.Version,
 		CreatedAt:    link.CreatedAt,
 		UpdatedAt:    link.UpdatedAt,
 	}
@@ -92,6 +101,7 @@ func (s *productService) modelToProductResponse(product model.Product, affiliate
 		ImageUrl:          product.ImageUrl,
 		IsActive:          product.IsActive,
 		AffiliateLinks:    affiliateLinkResponses,
+		Version:           product.Version,
 		CreatedAt:         product.CreatedAt,
 		UpdatedAt:         product.UpdatedAt,
 	}
@@ -112,6 +122,7 @@ func (s *productService) modelToProductListResponse(product model.Product) dto.P
 		Description:       product.Description,
 		ImageUrl:          product.ImageUrl,
 		IsActive:          product.IsActive,
+		Version:           product.Version,
 		CreatedAt:         product.CreatedAt,
 		UpdatedAt:         product.UpdatedAt,
 	}
@@ -124,29 +135,32 @@ func (s *productService) CreateProductCategory(ctx context.Context, req dto.Crea
 		return dto.ProductCategoryResponse{}, errors.New("category name is required")
 	}
 
-	// Generate slug if not provided
-	slug := req.Slug
-	if strings.TrimSpace(slug) == "" {
-		slug = s.generateSlug(req.Name)
-	} else {
-		slug = s.generateSlug(slug)
-	}
-
-	// Validate slug format
-	if !s.isValidSlug(slug) {
-		return dto.ProductCategoryResponse{}, errors.New("invalid slug format")
-	}
-
 	// Create model
 	category := model.ProductCategory{
+		Id:          uuid.Must(uuid.NewV7()),
 		Name:        req.Name,
-		Slug:        slug,
 		Description: req.Description,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
-	createdCategory, err := s.productRepo.CreateProductCategory(ctx, category)
+	// A slug given in the request is used as is, otherwise a free one is derived from the name
+	var createdCategory model.ProductCategory
+	create := func(slug string) error {
+		category.Slug = slug
+		var err error
+		createdCategory, err = s.productRepo.CreateProductCategory(ctx, category)
+		return err
+	}
+	var err error
+	if strings.TrimSpace(req.Slug) != "" {
+		var slug string
+		if slug, err = s.slugAllocator.Claim(ctx, repository.SlugTableProductCategories, req.Slug, category.Id); err == nil {
+			err = create(slug)
+		}
+	} else {
+		err = s.slugAllocator.Save(ctx, repository.SlugTableProductCategories, req.Name, category.Id, create)
+	}
 	if err != nil {
 		return dto.ProductCategoryResponse{}, err
 	}
@@ -218,7 +232,7 @@ func (s *productService) GetProductCategoryBySlug(ctx context.Context, slug stri
 	return s.modelToProductCategoryResponse(category), nil
 }
 
-func (s *productService) UpdateProductCategory(ctx context.Context, id uuid.UUID, req dto.UpdateProductCategoryRequest) (dto.ProductCategoryResponse, error) {
+func (s *productService) UpdateProductCategory(ctx context.Context, id uuid.UUID, version int, req dto.UpdateProductCategoryRequest) (dto.ProductCategoryResponse, error) {
 	if id == uuid.Nil {
 		return dto.ProductCategoryResponse{}, errors.New("invalid category ID")
 	}
@@ -229,29 +243,44 @@ func (s *productService) UpdateProductCategory(ctx context.Context, id uuid.UUID
 		return dto.ProductCategoryResponse{}, err
 	}
 
+	// Refuse updates made from an outdated copy of the category
+	if existingCategory.Version != version {
+		return dto.ProductCategoryResponse{}, s.errorWrapper.PreconditionFailedError(ctx, "Product category")
+	}
+
 	// Update fields if provided
 	if req.Name != nil {
 		existingCategory.Name = *req.Name
 	}
-	if req.Slug != nil {
-		existingCategory.Slug = s.generateSlug(*req.Slug)
-	} else if req.Name != nil {
-		// Auto-generate slug from name if name is updated but slug is not provided
-		existingCategory.Slug = s.generateSlug(*req.Name)
-	}
 	if req.Description != nil {
 		existingCategory.Description = req.Description
 	}
 
-	// Validate slug format
-	if !s.isValidSlug(existingCategory.Slug) {
-		return dto.ProductCategoryResponse{}, errors.New("invalid slug format")
-	}
-
 	existingCategory.UpdatedAt = time.Now()
 
-	updatedCategory, err := s.productRepo.UpdateProductCategory(ctx, existingCategory)
+	var updatedCategory model.ProductCategory
+	update := func(slug string) error {
+		existingCategory.Slug = slug
+		var err error
+		updatedCategory, err = s.productRepo.UpdateProductCategory(ctx, existingCategory)
+		return err
+	}
+	if req.Slug != nil && strings.TrimSpace(*req.Slug) != "" {
+		var slug string
+		if slug, err = s.slugAllocator.Claim(ctx, repository.SlugTableProductCategories, *req.Slug, id); err == nil {
+			err = update(slug)
+		}
+	} else if req.Name != nil {
+		// Auto-generate slug from name if name is updated but slug is not provided
+		err = s.slugAllocator.Save(ctx, repository.SlugTableProductCategories, existingCategory.Name, id, update)
+	} else {
+		err = update(existingCategory.Slug)
+	}
 	if err != nil {
+		// The category was changed by someone else since it was read
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.ProductCategoryResponse{}, s.errorWrapper.PreconditionFailedError(ctx, "Product category")
+		}
 		return dto.ProductCategoryResponse{}, err
 	}
 
@@ -404,7 +433,7 @@ func (s *productService) GetProductsByCategoryWithPagination(ctx context.Context
 	return result, nil
 }
 
-func (s *productService) UpdateProduct(ctx context.Context, id uuid.UUID, req dto.UpdateProductRequest) (dto.ProductResponse, error) {
+func (s *productService) UpdateProduct(ctx context.Context, id uuid.UUID, version int, req dto.UpdateProductRequest) (dto.ProductResponse, error) {
 	if id == uuid.Nil {
 		return dto.ProductResponse{}, errors.New("invalid product ID")
 	}
@@ -415,6 +444,11 @@ func (s *productService) UpdateProduct(ctx context.Context, id uuid.UUID, req dt
 		return dto.ProductResponse{}, err
 	}
 
+	// Refuse updates made from an outdated copy of the product
+	if existingProduct.Version != version {
+		return dto.ProductResponse{}, s.errorWrapper.PreconditionFailedError(ctx, "Product")
+	}
+
 	// Update fields if provided
 	if req.ProductCategoryId != nil {
 		existingProduct.ProductCategoryId = req.ProductCategoryId
@@ -443,6 +477,10 @@ func (s *productService) UpdateProduct(ctx context.Context, id uuid.UUID, req dt
 
 	updatedProduct, err := s.productRepo.UpdateProduct(ctx, existingProduct)
 	if err != nil {
+		// The product was changed by someone else since it was read
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.ProductResponse{}, s.errorWrapper.PreconditionFailedError(ctx, "Product")
+		}
 		return dto.ProductResponse{}, err
 	}
 
@@ -515,7 +553,7 @@ func (s *productService) GetAffiliateLinksbyProductId(ctx context.Context, produ
 	return responses, nil
 }
 
-func (s *productService) UpdateProductAffiliateLink(ctx context.Context, productId, affiliateId uuid.UUID, req dto.UpdateProductAffiliateLinkRequest) (dto.ProductAffiliateLinkResponse, error) {
+func (s *productService) UpdateProductAffiliateLink(ctx context.Context, productId, affiliateId uuid.UUID, version int, req dto.UpdateProductAffiliateLinkRequest) (dto.ProductAffiliateLinkResponse, error) {
 	if productId == uuid.Nil {
 		return dto.ProductAffiliateLinkResponse{}, errors.New("invalid product ID")
 	}
@@ -541,6 +579,11 @@ func (s *productService) UpdateProductAffiliateLink(ctx context.Context, product
 		return dto.ProductAffiliateLinkResponse{}, errors.New("affiliate link not found")
 	}
 
+	// Refuse updates made from an outdated copy of the link
+	if existingLink.Version != version {
+		return dto.ProductAffiliateLinkResponse{}, s.errorWrapper.PreconditionFailedError(ctx, "Affiliate link")
+	}
+
 	// Update fields if provided
 	if req.PlatformName != nil {
 		existingLink.PlatformName = *req.PlatformName
@@ -558,6 +601,10 @@ func (s *productService) UpdateProductAffiliateLink(ctx context.Context, product
 
 	updatedLink, err := s.productRepo.UpdateProductAffiliateLink(ctx, *existingLink)
 	if err != nil {
+		// The link was changed by someone else since it was read
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.ProductAffiliateLinkResponse{}, s.errorWrapper.PreconditionFailedError(ctx, "Affiliate link")
+		}
 		return dto.ProductAffiliateLinkResponse{}, err
 	}
 
@@ -672,37 +719,18 @@ func (s *productService) GetArticlesByProductIdWithPagination(ctx context.Contex
 }
 
 // Helper functions
-func (s *productService) generateSlug(input string) string {
-	// Convert to lowercase
-	slug := strings.ToLower(input)
-
-	// Replace spaces and special characters with hyphens
-	reg := regexp.MustCompile(`[^a-z0-9]+`)
-	slug = reg.ReplaceAllString(slug, "-")
-
-	// Remove leading and trailing hyphens
-	slug = strings.Trim(slug, "-")
-
-	return slug
-}
-
-func (s *productService) isValidSlug(slug string) bool {
-	// Slug should only contain lowercase letters, numbers, and hyphens
-	// Should not start or end with hyphen
-	match, _ := regexp.MatchString(`^[a-z0-9]+(-[a-z0-9]+)*$`, slug)
-	return match
-}
-
 func (s *productService) isValidURL(url string) bool {
 	// Basic URL validation
 	match, _ := regexp.MatchString(`^https?://[^\s/$.?#].[^\s]*$`, url)
 	return match
 }
 
-func NewProductService(productRepo repository.ProductRepository, validation ValidationService, pagination PaginationService) ProductService {
+func NewProductService(productRepo repository.ProductRepository, validation ValidationService, pagination PaginationService, slugAllocator SlugAllocator, errorWrapper utils.ErrorWrapper) ProductService {
 	return &productService{
-		productRepo: productRepo,
-		validation:  validation,
-		pagination:  pagination,
+		productRepo:   productRepo,
+		validation:    validation,
+		pagination:    pagination,
+		slugAllocator: slugAllocator,
+		errorWrapper:  errorWrapper,
 	}
 }
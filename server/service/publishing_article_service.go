@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+
+	"github.com/google/uuid"
+)
+
+// publishingArticleService announces every write to an article, its workflow status or its
+// authors as a cache event, other calls go straight to ArticleService. It is installed
+// whether or not the read cache is enabled, other caches of article data subscribe too.
+type publishingArticleService struct {
+	ArticleService
+	events CacheEventPublisher
+}
+
+// UpdateArticle implements ArticleService.
+func (c *publishingArticleService) UpdateArticle(ctx context.Context, id uuid.UUID, version int, req dto.UpdateArticleRequest, editorID uuid.UUID) (model.Article, error) {
+	article, err := c.ArticleService.UpdateArticle(ctx, id, version, req, editorID)
+	return article, c.published(ctx, id, err)
+}
+
+// DeleteArticle implements ArticleService.
+func (c *publishingArticleService) DeleteArticle(ctx context.Context, id uuid.UUID) error {
+	return c.published(ctx, id, c.ArticleService.DeleteArticle(ctx, id))
+}
+
+// SubmitArticle implements ArticleService.
+func (c *publishingArticleService) SubmitArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (model.Article, error) {
+	article, err := c.ArticleService.SubmitArticle(ctx, id, actorID)
+	return article, c.published(ctx, id, err)
+}
+
+// ApproveArticle implements ArticleService.
+func (c *publishingArticleService) ApproveArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID, note string) (model.Article, error) {
+	article, err := c.ArticleService.ApproveArticle(ctx, id, actorID, note)
+	return article, c.published(ctx, id, err)
+}
+
+// RejectArticle implements ArticleService.
+func (c *publishingArticleService) RejectArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID, reason string) (model.Article, error) {
+	article, err := c.ArticleService.RejectArticle(ctx, id, actorID, reason)
+	return article, c.published(ctx, id, err)
+}
+
+// PublishArticle implements ArticleService.
+func (c *publishingArticleService) PublishArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (model.Article, error) {
+	article, err := c.ArticleService.PublishArticle(ctx, id, actorID)
+	return article, c.published(ctx, id, err)
+}
+
+// ArchiveArticle implements ArticleService.
+func (c *publishingArticleService) ArchiveArticle(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (model.Article, error) {
+	article, err := c.ArticleService.ArchiveArticle(ctx, id, actorID)
+	return article, c.published(ctx, id, err)
+}
+
+// AddAuthor implements ArticleService.
+func (c *publishingArticleService) AddAuthor(ctx context.Context, articleId, userId uuid.UUID, role string) (model.ArticleAuthor, error) {
+	author, err := c.ArticleService.AddAuthor(ctx, articleId, userId, role)
+	return author, c.published(ctx, articleId, err)
+}
+
+// RemoveAuthor implements ArticleService.
+func (c *publishingArticleService) RemoveAuthor(ctx context.Context, articleId, userId uuid.UUID) error {
+	return c.published(ctx, articleId, c.ArticleService.RemoveAuthor(ctx, articleId, userId))
+}
+
+// published announces the write to the article when it succeeded and passes err through
+func (c *publishingArticleService) published(ctx context.Context, id uuid.UUID, err error) error {
+	if err == nil {
+		c.events.Publish(ctx, articleChanged(id))
+	}
+	return err
+}
+
+// NewPublishingArticleService wraps an ArticleService so its writes publish cache events
+func NewPublishingArticleService(next ArticleService, events CacheEventPublisher) ArticleService {
+	return &publishingArticleService{
+		ArticleService: next,
+		events:         events,
+	}
+}
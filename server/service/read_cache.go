@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"develapar-server/utils"
+	"encoding/json"
+	"time"
+)
+
+// readCache serves the reads of a cached service decorator from a utils.Cache and
+// counts its hits and misses under the name of the service
+type readCache struct {
+	name    string
+	cache   utils.Cache
+	metrics MetricsService
+	ttl     time.Duration
+	logger  utils.Logger
+}
+
+func newReadCache(name string, cache utils.Cache, metrics MetricsService, ttl time.Duration, logger utils.Logger) readCache {
+	return readCache{name: name, cache: cache, metrics: metrics, ttl: ttl, logger: logger}
+}
+
+// cachedRead returns the value cached under key, or loads it and caches it with the tags
+// of the loaded value. Values are stored as JSON, the form they are served in, so a cached
+// read answers exactly like a fresh one. Errors are never cached and an unavailable cache
+// only costs the lookup.
+//
+// A write committed while the value loads invalidates before the value is stored, which
+// would leave the old value cached. The cache generation is read before the load: a value
+// loaded across an invalidation is not stored, and one stored right before an invalidation
+// is deleted again.
+func cachedRead[T any](ctx context.Context, rc readCache, key string, load func() (T, error), tags func(T) []string) (T, error) {
+	data, found, err := rc.cache.Get(ctx, key)
+	if err != nil {
+		rc.logger.Warn(ctx, "Cache lookup failed, reading from the database",
+			utils.StringField("cache", rc.name), utils.StringField("key", key), utils.ErrorField(err))
+	}
+	if found {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			rc.metrics.RecordCacheLookup(ctx, rc.name, true)
+			return value, nil
+		}
+		// Entries written by an older shape of the value are replaced below
+	}
+	rc.metrics.RecordCacheLookup(ctx, rc.name, false)
+
+	generation, err := rc.cache.Generation(ctx)
+	if err != nil {
+		rc.logger.Warn(ctx, "Cache generation lookup failed, not caching the entry",
+			utils.StringField("cache", rc.name), utils.StringField("key", key), utils.ErrorField(err))
+		return load()
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	data, err = json.Marshal(value)
+	if err != nil {
+		rc.logger.Warn(ctx, "Failed to encode cache entry",
+			utils.StringField("cache", rc.name), utils.StringField("key", key), utils.ErrorField(err))
+		return value, nil
+	}
+	if !rc.unchangedSince(ctx, generation) {
+		return value, nil
+	}
+	if err := rc.cache.Set(ctx, key, data, rc.ttl, tags(value)...); err != nil {
+		rc.logger.Warn(ctx, "Failed to store cache entry",
+			utils.StringField("cache", rc.name), utils.StringField("key", key), utils.ErrorField(err))
+		return value, nil
+	}
+	if !rc.unchangedSince(ctx, generation) {
+		if err := rc.cache.Delete(ctx, key); err != nil {
+			rc.logger.Warn(ctx, "Failed to delete a cache entry stored across an invalidation",
+				utils.StringField("cache", rc.name), utils.StringField("key", key), utils.ErrorField(err))
+		}
+	}
+	return value, nil
+}
+
+// unchangedSince reports whether nothing was invalidated since generation was read
+func (rc readCache) unchangedSince(ctx context.Context, generation uint64) bool {
+	current, err := rc.cache.Generation(ctx)
+	return err == nil && current == generation
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/utils"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type readCacheFixture struct {
+	cache  utils.Cache
+	events CacheEventBus
+	reads  readCache
+}
+
+func newReadCacheFixture() readCacheFixture {
+	logger := newTestLogger()
+	cache := utils.NewLRUCache(100)
+	events := NewCacheEventBus()
+	events.Subscribe(NewCacheInvalidator(cache, logger))
+	return readCacheFixture{
+		cache:  cache,
+		events: events,
+		reads:  newReadCache("test", cache, NewMetricsService(logger), time.Hour, logger),
+	}
+}
+
+func TestCachedRead_InvalidationDuringLoad(t *testing.T) {
+	ctx := context.Background()
+	fixture := newReadCacheFixture()
+	tag := articleCacheTag(uuid.New())
+
+	loads := 0
+	load := func(invalidate bool) func() (string, error) {
+		return func() (string, error) {
+			loads++
+			if invalidate {
+				// A write commits and invalidates while the old value is on its way
+				fixture.events.Publish(ctx, CacheEvent{Tags: []string{tag}})
+				return "old", nil
+			}
+			return "new", nil
+		}
+	}
+	tags := func(string) []string { return []string{tag} }
+
+	value, err := cachedRead(ctx, fixture.reads, "key", load(true), tags)
+	require.NoError(t, err)
+	assert.Equal(t, "old", value)
+
+	value, err = cachedRead(ctx, fixture.reads, "key", load(false), tags)
+	require.NoError(t, err)
+	assert.Equal(t, "new", value, "the value loaded across the invalidation is not cached")
+
+	value, err = cachedRead(ctx, fixture.reads, "key", load(false), tags)
+	require.NoError(t, err)
+	assert.Equal(t, "new", value)
+	assert.Equal(t, 2, loads)
+}
+
+// fakeCategoryService counts the reads that reach it
+type fakeCategoryService struct {
+	CategoryService
+	lists   int
+	lookups int
+}
+
+func (s *fakeCategoryService) FindAll(ctx context.Context) ([]model.Category, error) {
+	s.lists++
+	return []model.Category{{Id: uuid.New(), Name: "go"}}, nil
+}
+
+func (s *fakeCategoryService) FindById(ctx context.Context, id uuid.UUID) (model.Category, error) {
+	s.lookups++
+	return model.Category{Id: id, Name: "go"}, nil
+}
+
+func (s *fakeCategoryService) CreateCategory(ctx context.Context, payload model.Category) (model.Category, error) {
+	return payload, nil
+}
+
+func (s *fakeCategoryService) UpdateCategory(ctx context.Context, id uuid.UUID, version int, req dto.UpdateCategoryRequest) (model.Category, error) {
+	return model.Category{Id: id}, nil
+}
+
+func (s *fakeCategoryService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func TestCachedCategoryService(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	tests := []struct {
+		name        string
+		write       func(s CategoryService) error
+		wantLists   int
+		wantLookups int
+	}{
+		{name: "repeated reads are cached", write: func(s CategoryService) error { return nil }, wantLists: 1, wantLookups: 1},
+		{name: "create drops the list", write: func(s CategoryService) error {
+			_, err := s.CreateCategory(ctx, model.Category{Name: "rust"})
+			return err
+		}, wantLists: 2, wantLookups: 1},
+		{name: "update drops the list and the category", write: func(s CategoryService) error {
+			_, err := s.UpdateCategory(ctx, id, 1, dto.UpdateCategoryRequest{})
+			return err
+		}, wantLists: 2, wantLookups: 2},
+		{name: "delete drops the list and the category", write: func(s CategoryService) error {
+			return s.DeleteCategory(ctx, id)
+		}, wantLists: 2, wantLookups: 2},
+		{name: "write to another category keeps the lookup", write: func(s CategoryService) error {
+			return s.DeleteCategory(ctx, uuid.New())
+		}, wantLists: 2, wantLookups: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := newReadCacheFixture()
+			next := &fakeCategoryService{}
+			service := NewCachedCategoryService(next, fixture.cache, fixture.events, fixture.reads.metrics, time.Hour, newTestLogger())
+
+			for i := 0; i < 2; i++ {
+				_, err := service.FindAll(ctx)
+				require.NoError(t, err)
+				_, err = service.FindById(ctx, id)
+				require.NoError(t, err)
+				if i == 0 {
+					require.NoError(t, tt.write(service))
+				}
+			}
+			assert.Equal(t, tt.wantLists, next.lists)
+			assert.Equal(t, tt.wantLookups, next.lookups)
+		})
+	}
+}
+
+// fakeTagService counts the reads that reach it
+type fakeTagService struct {
+	TagService
+	lists   int
+	lookups int
+}
+
+func (s *fakeTagService) FindAll(ctx context.Context) ([]model.Tags, error) {
+	s.lists++
+	return []model.Tags{{Id: uuid.New(), Name: "testing"}}, nil
+}
+
+func (s *fakeTagService) FindById(ctx context.Context, id uuid.UUID) (model.Tags, error) {
+	s.lookups++
+	return model.Tags{Id: id, Name: "testing"}, nil
+}
+
+func (s *fakeTagService) CreateTag(ctx context.Context, payload model.Tags) (model.Tags, error) {
+	return payload, nil
+}
+
+func (s *fakeTagService) UpdateTag(ctx context.Context, id uuid.UUID, version int, payload model.Tags) (model.Tags, error) {
+	return payload, nil
+}
+
+func (s *fakeTagService) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func TestCachedTagService(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	tests := []struct {
+		name        string
+		write       func(s TagService) error
+		wantLists   int
+		wantLookups int
+	}{
+		{name: "repeated reads are cached", write: func(s TagService) error { return nil }, wantLists: 1, wantLookups: 1},
+		{name: "create drops the list", write: func(s TagService) error {
+			_, err := s.CreateTag(ctx, model.Tags{Name: "go"})
+			return err
+		}, wantLists: 2, wantLookups: 1},
+		{name: "update drops the list and the tag", write: func(s TagService) error {
+			_, err := s.UpdateTag(ctx, id, 1, model.Tags{Id: id})
+			return err
+		}, wantLists: 2, wantLookups: 2},
+		{name: "delete drops the list and the tag", write: func(s TagService) error {
+			return s.DeleteTag(ctx, id)
+		}, wantLists: 2, wantLookups: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := newReadCacheFixture()
+			next := &fakeTagService{}
+			service := NewCachedTagService(next, fixture.cache, fixture.events, fixture.reads.metrics, time.Hour, newTestLogger())
+
+			for i := 0; i < 2; i++ {
+				_, err := service.FindAll(ctx)
+				require.NoError(t, err)
+				_, err = service.FindById(ctx, id)
+				require.NoError(t, err)
+				if i == 0 {
+					require.NoError(t, tt.write(service))
+				}
+			}
+			assert.Equal(t, tt.wantLists, next.lists)
+			assert.Equal(t, tt.wantLookups, next.lookups)
+		})
+	}
+}
+
+// fakeProductService counts the lookups that reach it
+type fakeProductService struct {
+	ProductService
+	categoryId uuid.UUID
+	lookups    int
+}
+
+func (s *fakeProductService) GetProductById(ctx context.Context, id uuid.UUID) (dto.ProductResponse, error) {
+	s.lookups++
+	return dto.ProductResponse{Id: id, ProductCategoryId: &s.categoryId, Name: "Keyboard"}, nil
+}
+
+func (s *fakeProductService) UpdateProduct(ctx context.Context, id uuid.UUID, version int, req dto.UpdateProductRequest) (dto.ProductResponse, error) {
+	return dto.ProductResponse{Id: id}, nil
+}
+
+func (s *fakeProductService) UpdateProductCategory(ctx context.Context, id uuid.UUID, version int, req dto.UpdateProductCategoryRequest) (dto.ProductCategoryResponse, error) {
+	return dto.ProductCategoryResponse{}, nil
+}
+
+func (s *fakeProductService) DeleteProductAffiliateLink(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func TestCachedProductService(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	categoryId := uuid.New()
+
+	tests := []struct {
+		name        string
+		write       func(s ProductService) error
+		wantLookups int
+	}{
+		{name: "repeated reads are cached", write: func(s ProductService) error { return nil }, wantLookups: 1},
+		{name: "update drops the product", write: func(s ProductService) error {
+			_, err := s.UpdateProduct(ctx, id, 1, dto.UpdateProductRequest{})
+			return err
+		}, wantLookups: 2},
+		{name: "update of another product keeps it", write: func(s ProductService) error {
+			_, err := s.UpdateProduct(ctx, uuid.New(), 1, dto.UpdateProductRequest{})
+			return err
+		}, wantLookups: 1},
+		{name: "rename of its category drops the product", write: func(s ProductService) error {
+			_, err := s.UpdateProductCategory(ctx, categoryId, 1, dto.UpdateProductCategoryRequest{})
+			return err
+		}, wantLookups: 2},
+		{name: "deleted affiliate link drops every product", write: func(s ProductService) error {
+			return s.DeleteProductAffiliateLink(ctx, uuid.New())
+		}, wantLookups: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := newReadCacheFixture()
+			next := &fakeProductService{categoryId: categoryId}
+			service := NewCachedProductService(next, fixture.cache, fixture.events, fixture.reads.metrics, time.Hour, newTestLogger())
+
+			_, err := service.GetProductById(ctx, id)
+			require.NoError(t, err)
+			require.NoError(t, tt.write(service))
+			_, err = service.GetProductById(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLookups, next.lookups)
+		})
+	}
+}
+
+// fakeSlugArticleService serves one article by slug and counts the lookups
+type fakeSlugArticleService struct {
+	ArticleService
+	article model.Article
+	lookups int
+}
+
+func (s *fakeSlugArticleService) FindBySlug(ctx context.Context, slug string) (model.Article, error) {
+	s.lookups++
+	return s.article, nil
+}
+
+func TestCachedArticleService(t *testing.T) {
+	ctx := context.Background()
+	article := model.Article{
+		Id: uuid.New(), CategoryId: uuid.New(), Slug: "hello",
+		Status: model.ArticleStatusPublished, Visibility: model.ArticleVisibilityPasswordProtected,
+		PasswordHash: "$2a$10$hash", ContentHash: "content-hash",
+		Tags: []model.Tags{{Id: uuid.New(), Name: "go"}},
+	}
+
+	tests := []struct {
+		name        string
+		event       CacheEvent
+		wantLookups int
+	}{
+		{name: "unrelated write keeps the article", event: articleChanged(uuid.New()), wantLookups: 1},
+		{name: "write to the article drops it", event: articleChanged(article.Id), wantLookups: 2},
+		{name: "rename of its category drops it", event: categoryChanged(article.CategoryId), wantLookups: 2},
+		{name: "rename of one of its tags drops it", event: tagChanged(article.Tags[0].Id), wantLookups: 2},
+		{name: "rename of another tag keeps it", event: tagChanged(uuid.New()), wantLookups: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := newReadCacheFixture()
+			next := &fakeSlugArticleService{article: article}
+			service := NewCachedArticleService(next, fixture.cache, fixture.reads.metrics, time.Hour, newTestLogger())
+
+			_, err := service.FindBySlug(ctx, article.Slug)
+			require.NoError(t, err)
+			fixture.events.Publish(ctx, tt.event)
+			cached, err := service.FindBySlug(ctx, article.Slug)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLookups, next.lookups)
+
+			if tt.wantLookups == 1 {
+				assert.Equal(t, article.ContentHash, cached.ContentHash, "the content hash survives the cache")
+				assert.Empty(t, cached.PasswordHash, "the password hash is never cached")
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxRelatedArticles is the largest number of related articles that can be requested
+	MaxRelatedArticles = 20
+
+	// relatedCacheTTL bounds how long recency and text scores may be stale. Writes to an
+	// article drop every cached list it is part of right away.
+	relatedCacheTTL = time.Hour
+
+	// MaxCachedRelated bounds the related articles cache, every published article has its
+	// own entry. The least recently used entry is evicted first.
+	MaxCachedRelated = 5000
+)
+
+type RelatedArticleService interface {
+	// FindRelated returns up to limit published articles related to the article with slug
+	FindRelated(ctx context.Context, slug string, limit int) ([]dto.RelatedArticle, error)
+}
+
+type relatedArticleService struct {
+	articleRepo    repository.ArticleRepository
+	articleTagRepo repository.ArticleTagRepository
+	reads          readCache
+	errorWrapper   utils.ErrorWrapper
+}
+
+// FindRelated implements RelatedArticleService.
+func (r *relatedArticleService) FindRelated(ctx context.Context, slug string, limit int) ([]dto.RelatedArticle, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if slug == "" {
+		return nil, r.errorWrapper.ValidationError(ctx, "slug", "slug is required")
+	}
+	if limit < 1 || limit > MaxRelatedArticles {
+		return nil, r.errorWrapper.ValidationError(ctx, "limit", fmt.Sprintf("limit must be between 1 and %d", MaxRelatedArticles))
+	}
+
+	article, err := r.articleRepo.GetArticleBySlug(ctx, slug)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.errorWrapper.NotFoundError(ctx, "Article")
+		}
+		return nil, fmt.Errorf("failed to fetch article by slug: %v", err)
+	}
+
+	// Unpublished, private and password protected articles must not leak through their related articles
+	if article.Status != model.ArticleStatusPublished ||
+		article.Visibility == model.ArticleVisibilityPrivate ||
+		article.Visibility == model.ArticleVisibilityPasswordProtected {
+		return nil, r.errorWrapper.NotFoundError(ctx, "Article")
+	}
+
+	related, err := r.relatedTo(ctx, article.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	return related, nil
+}
+
+// relatedTo returns the full cached list of related articles, computing it on a miss.
+// The maximum is always cached so every requested limit is served from one entry. The
+// entry is tagged with the source and every listed article, their categories and tags,
+// so a write to any of them drops it.
+func (r *relatedArticleService) relatedTo(ctx context.Context, articleId uuid.UUID) ([]dto.RelatedArticle, error) {
+	return cachedRead(ctx, r.reads, "related:"+articleId.String(), func() ([]dto.RelatedArticle, error) {
+		related, err := r.articleTagRepo.GetRelatedArticles(ctx, articleId, MaxRelatedArticles)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("failed to fetch related articles: %v", err)
+		}
+		return related, nil
+	}, func(related []dto.RelatedArticle) []string {
+		tags := []string{articleCacheTag(articleId)}
+		for _, item := range related {
+			tags = append(tags, articleCacheTag(item.Id), categoryCacheTag(item.CategoryId))
+		}
+		return tags
+	})
+}
+
+// NewRelatedArticleService creates the related articles service. Its cache must be
+// subscribed to the cache events so writes to articles drop the lists containing them.
+func NewRelatedArticleService(articleRepo repository.ArticleRepository, articleTagRepo repository.ArticleTagRepository, cache utils.Cache, metrics MetricsService, logger utils.Logger, errorWrapper utils.ErrorWrapper) RelatedArticleService {
+	return &relatedArticleService{
+		articleRepo:    articleRepo,
+		articleTagRepo: articleTagRepo,
+		reads:          newReadCache("related_articles", cache, metrics, relatedCacheTTL, logger),
+		errorWrapper:   errorWrapper,
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSlugArticleRepository finds articles by slug
+type fakeSlugArticleRepository struct {
+	repository.ArticleRepository
+	articles map[string]model.Article
+}
+
+func (r *fakeSlugArticleRepository) GetArticleBySlug(ctx context.Context, slug string) (model.Article, error) {
+	article, ok := r.articles[slug]
+	if !ok {
+		return model.Article{}, sql.ErrNoRows
+	}
+	return article, nil
+}
+
+// fakeRelatedRepository returns fixed related lists and counts the queries
+type fakeRelatedRepository struct {
+	repository.ArticleTagRepository
+	related map[uuid.UUID][]dto.RelatedArticle
+	queries int
+}
+
+func (r *fakeRelatedRepository) GetRelatedArticles(ctx context.Context, articleId uuid.UUID, limit int) ([]dto.RelatedArticle, error) {
+	r.queries++
+	return r.related[articleId], nil
+}
+
+type relatedFixture struct {
+	service RelatedArticleService
+	repo    *fakeRelatedRepository
+	events  CacheEventBus
+	source  model.Article
+	listed  []dto.RelatedArticle
+}
+
+func newRelatedFixture() relatedFixture {
+	source := model.Article{Id: uuid.New(), Slug: "source", Status: model.ArticleStatusPublished, Visibility: model.ArticleVisibilityPublic}
+	listed := []dto.RelatedArticle{
+		{Article: model.Article{Id: uuid.New(), CategoryId: uuid.New(), Slug: "first"}, SharedTags: 2, Score: 0.9},
+		{Article: model.Article{Id: uuid.New(), CategoryId: uuid.New(), Slug: "second"}, SharedTags: 1, Score: 0.5},
+		{Article: model.Article{Id: uuid.New(), CategoryId: uuid.New(), Slug: "third"}, Score: 0.1},
+	}
+
+	articles := &fakeSlugArticleRepository{articles: map[string]model.Article{
+		"source":  source,
+		"draft":   {Id: uuid.New(), Slug: "draft", Status: model.ArticleStatusDraft, Visibility: model.ArticleVisibilityPublic},
+		"private": {Id: uuid.New(), Slug: "private", Status: model.ArticleStatusPublished, Visibility: model.ArticleVisibilityPrivate},
+	}}
+	repo := &fakeRelatedRepository{related: map[uuid.UUID][]dto.RelatedArticle{source.Id: listed}}
+
+	logger := newTestLogger()
+	cache := utils.NewLRUCache(MaxCachedRelated)
+	events := NewCacheEventBus()
+	events.Subscribe(NewCacheInvalidator(cache, logger))
+
+	return relatedFixture{
+		service: NewRelatedArticleService(articles, repo, cache, NewMetricsService(logger), logger, utils.NewErrorWrapper()),
+		repo:    repo,
+		events:  events,
+		source:  source,
+		listed:  listed,
+	}
+}
+
+func TestRelatedArticleService_FindRelated(t *testing.T) {
+	fixture := newRelatedFixture()
+
+	tests := []struct {
+		name       string
+		slug       string
+		limit      int
+		wantStatus int
+		wantSlugs  []string
+	}{
+		{name: "full list", slug: "source", limit: MaxRelatedArticles, wantSlugs: []string{"first", "second", "third"}},
+		{name: "limit truncates", slug: "source", limit: 2, wantSlugs: []string{"first", "second"}},
+		{name: "limit below one", slug: "source", limit: 0, wantStatus: 400},
+		{name: "limit above maximum", slug: "source", limit: MaxRelatedArticles + 1, wantStatus: 400},
+		{name: "unknown article", slug: "missing", limit: 5, wantStatus: 404},
+		{name: "unpublished article", slug: "draft", limit: 5, wantStatus: 404},
+		{name: "private article", slug: "private", limit: 5, wantStatus: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			related, err := fixture.service.FindRelated(context.Background(), tt.slug, tt.limit)
+			if tt.wantStatus != 0 {
+				var appErr *utils.AppError
+				require.True(t, errors.As(err, &appErr))
+				assert.Equal(t, tt.wantStatus, appErr.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+			slugs := make([]string, len(related))
+			for i, item := range related {
+				slugs[i] = item.Slug
+			}
+			assert.Equal(t, tt.wantSlugs, slugs)
+		})
+	}
+	assert.Equal(t, 1, fixture.repo.queries, "every limit is served from one cached list")
+}
+
+func TestRelatedArticleService_Invalidation(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		event       func(f relatedFixture) CacheEvent
+		wantQueries int
+	}{
+		{name: "write to the source article", event: func(f relatedFixture) CacheEvent { return articleChanged(f.source.Id) }, wantQueries: 2},
+		{name: "write to a listed article", event: func(f relatedFixture) CacheEvent { return articleChanged(f.listed[1].Id) }, wantQueries: 2},
+		{name: "rename of a listed category", event: func(f relatedFixture) CacheEvent { return categoryChanged(f.listed[2].CategoryId) }, wantQueries: 2},
+		{name: "write to an unrelated article", event: func(f relatedFixture) CacheEvent { return articleChanged(uuid.New()) }, wantQueries: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := newRelatedFixture()
+			_, err := fixture.service.FindRelated(ctx, "source", 5)
+			require.NoError(t, err)
+
+			fixture.events.Publish(ctx, tt.event(fixture))
+
+			_, err = fixture.service.FindRelated(ctx, "source", 5)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantQueries, fixture.repo.queries)
+		})
+	}
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/model/dto"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type SeriesService interface {
+	FindAll(ctx context.Context) ([]model.Series, error)
+	FindById(ctx context.Context, id uuid.UUID) (model.Series, error)
+	// FindBySlug returns a series with its articles in order. Unless includeUnpublished
+	// is set only published articles are listed.
+	FindBySlug(ctx context.Context, slug string, includeUnpublished bool) (model.Series, error)
+	CreateSeries(ctx context.Context, req dto.CreateSeriesRequest, userId uuid.UUID) (model.Series, error)
+	UpdateSeries(ctx context.Context, id uuid.UUID, req dto.UpdateSeriesRequest) (model.Series, error)
+	DeleteSeries(ctx context.Context, id uuid.UUID) error
+	AddArticle(ctx context.Context, seriesId, articleId uuid.UUID) (model.Series, error)
+	RemoveArticle(ctx context.Context, seriesId, articleId uuid.UUID) (model.Series, error)
+	// ReorderArticles replaces the order of a series at once, articleIds must list
+	// every article of the series exactly once
+	ReorderArticles(ctx context.Context, seriesId uuid.UUID, articleIds []uuid.UUID) (model.Series, error)
+	// FindNavigation returns the series navigation of an article, nil when the article
+	// is not part of a series. A failing lookup is logged and also returns nil, the
+	// navigation is not worth failing the read of the article for.
+	FindNavigation(ctx context.Context, articleId uuid.UUID) (*model.SeriesNavigation, error)
+}
+
+type seriesService struct {
+	repo          repository.SeriesRepository
+	slugAllocator SlugAllocator
+	logger        utils.Logger
+	errorWrapper  utils.ErrorWrapper
+}
+
+// FindAll implements SeriesService.
+func (s *seriesService) FindAll(ctx context.Context) ([]model.Series, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	listSeries, err := s.repo.GetAll(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to fetch series: %v", err)
+	}
+
+	return listSeries, nil
+}
+
+// FindById implements SeriesService.
+func (s *seriesService) FindById(ctx context.Context, id uuid.UUID) (model.Series, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.Series{}, ctx.Err()
+	default:
+	}
+
+	series, err := s.repo.GetSeriesById(ctx, id)
+	if err != nil {
+		return model.Series{}, s.wrapLookupError(ctx, err)
+	}
+
+	return series, nil
+}
+
+// FindBySlug implements SeriesService.
+func (s *seriesService) FindBySlug(ctx context.Context, slug string, includeUnpublished bool) (model.Series, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.Series{}, ctx.Err()
+	default:
+	}
+
+	series, err := s.repo.GetSeriesBySlug(ctx, slug)
+	if err != nil {
+		return model.Series{}, s.wrapLookupError(ctx, err)
+	}
+
+	return s.withArticles(ctx, series, !includeUnpublished)
+}
+
+// CreateSeries implements SeriesService.
+func (s *seriesService) CreateSeries(ctx context.Context, req dto.CreateSeriesRequest, userId uuid.UUID) (model.Series, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.Series{}, ctx.Err()
+	default:
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return model.Series{}, s.errorWrapper.ValidationError(ctx, "title", "series title is required")
+	}
+
+	payload := model.Series{
+		Id:          uuid.Must(uuid.NewV7()),
+		Title:       title,
+		Description: strings.TrimSpace(req.Description),
+		UserId:      userId,
+	}
+	var series model.Series
+	err := s.slugAllocator.Save(ctx, repository.SlugTableSeries, title, payload.Id, func(slug string) error {
+		payload.Slug = slug
+		var err error
+		series, err = s.repo.CreateSeries(ctx, payload)
+		return err
+	})
+	if err != nil {
+		return model.Series{}, s.wrapWriteError(ctx, err, "create series")
+	}
+
+	return series, nil
+}
+
+// UpdateSeries implements SeriesService.
+func (s *seriesService) UpdateSeries(ctx context.Context, id uuid.UUID, req dto.UpdateSeriesRequest) (model.Series, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.Series{}, ctx.Err()
+	default:
+	}
+
+	series, err := s.repo.GetSeriesById(ctx, id)
+	if err != nil {
+		return model.Series{}, s.wrapLookupError(ctx, err)
+	}
+
+	// Update fields if provided
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return model.Series{}, s.errorWrapper.ValidationError(ctx, "title", "series title is required")
+		}
+		series.Title = title
+	}
+	if req.Description != nil {
+		series.Description = strings.TrimSpace(*req.Description)
+	}
+
+	// A renamed series gets a new slug
+	var updated model.Series
+	update := func(slug string) error {
+		series.Slug = slug
+		var err error
+		updated, err = s.repo.UpdateSeries(ctx, series)
+		return err
+	}
+	if req.Title != nil {
+		err = s.slugAllocator.Save(ctx, repository.SlugTableSeries, series.Title, series.Id, update)
+	} else {
+		err = update(series.Slug)
+	}
+	if err != nil {
+		return model.Series{}, s.wrapWriteError(ctx, err, "update series")
+	}
+
+	return s.withArticles(ctx, updated, false)
+}
+
+// DeleteSeries implements SeriesService.
+func (s *seriesService) DeleteSeries(ctx context.Context, id uuid.UUID) error {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := s.repo.DeleteSeries(ctx, id); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to delete series: %v", err)
+	}
+
+	return nil
+}
+
+// AddArticle implements SeriesService.
+func (s *seriesService) AddArticle(ctx context.Context, seriesId, articleId uuid.UUID) (model.Series, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.Series{}, ctx.Err()
+	default:
+	}
+
+	if articleId == uuid.Nil {
+		return model.Series{}, s.errorWrapper.ValidationError(ctx, "article_id", "article ID is required")
+	}
+
+	if err := s.repo.AppendArticle(ctx, seriesId, articleId); err != nil {
+		if ctx.Err() != nil {
+			return model.Series{}, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Series{}, s.errorWrapper.NotFoundError(ctx, "Series")
+		}
+		if repository.IsUniqueViolation(err) {
+			return model.Series{}, s.errorWrapper.ConflictError(ctx, "Series", "article is already part of a series")
+		}
+		if repository.IsForeignKeyViolation(err) {
+			return model.Series{}, s.errorWrapper.NotFoundError(ctx, "Article")
+		}
+		return model.Series{}, fmt.Errorf("failed to add article to series: %v", err)
+	}
+
+	return s.findWithAllArticles(ctx, seriesId)
+}
+
+// RemoveArticle implements SeriesService.
+func (s *seriesService) RemoveArticle(ctx context.Context, seriesId, articleId uuid.UUID) (model.Series, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.Series{}, ctx.Err()
+	default:
+	}
+
+	if err := s.repo.RemoveArticle(ctx, seriesId, articleId); err != nil {
+		if ctx.Err() != nil {
+			return model.Series{}, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Series{}, s.errorWrapper.NotFoundError(ctx, "Series article")
+		}
+		return model.Series{}, fmt.Errorf("failed to remove article from series: %v", err)
+	}
+
+	return s.findWithAllArticles(ctx, seriesId)
+}
+
+// ReorderArticles implements SeriesService.
+func (s *seriesService) ReorderArticles(ctx context.Context, seriesId uuid.UUID, articleIds []uuid.UUID) (model.Series, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return model.Series{}, ctx.Err()
+	default:
+	}
+
+	if len(articleIds) == 0 {
+		return model.Series{}, s.errorWrapper.ValidationError(ctx, "article_ids", "article IDs are required")
+	}
+
+	if err := s.repo.ReorderArticles(ctx, seriesId, articleIds); err != nil {
+		if ctx.Err() != nil {
+			return model.Series{}, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Series{}, s.errorWrapper.NotFoundError(ctx, "Series")
+		}
+		if errors.Is(err, repository.ErrSeriesOrderMismatch) {
+			return model.Series{}, s.errorWrapper.ValidationError(ctx, "article_ids", err.Error())
+		}
+		return model.Series{}, fmt.Errorf("failed to reorder series: %v", err)
+	}
+
+	return s.findWithAllArticles(ctx, seriesId)
+}
+
+// FindNavigation implements SeriesService.
+func (s *seriesService) FindNavigation(ctx context.Context, articleId uuid.UUID) (*model.SeriesNavigation, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	series, err := s.repo.GetSeriesByArticleId(ctx, articleId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warn(ctx, "Failed to fetch article series",
+				utils.StringField("article_id", articleId.String()),
+				utils.ErrorField(err),
+			)
+		}
+		return nil, nil
+	}
+
+	return buildSeriesNavigation(series, articleId), nil
+}
+
+// buildSeriesNavigation locates articleId among the ordered series articles
+func buildSeriesNavigation(series model.Series, articleId uuid.UUID) *model.SeriesNavigation {
+	for i, article := range series.Articles {
+		if article.ArticleId != articleId {
+			continue
+		}
+
+		navigation := &model.SeriesNavigation{
+			Id:    series.Id,
+			Title: series.Title,
+			Slug:  series.Slug,
+			Part:  i + 1,
+			Total: len(series.Articles),
+		}
+		if i > 0 {
+			previous := series.Articles[i-1]
+			navigation.Previous = &previous
+		}
+		if i < len(series.Articles)-1 {
+			next := series.Articles[i+1]
+			navigation.Next = &next
+		}
+		return navigation
+	}
+
+	return nil
+}
+
+// findWithAllArticles loads a series with all of its articles after a membership change
+func (s *seriesService) findWithAllArticles(ctx context.Context, seriesId uuid.UUID) (model.Series, error) {
+	series, err := s.repo.GetSeriesById(ctx, seriesId)
+	if err != nil {
+		return model.Series{}, s.wrapLookupError(ctx, err)
+	}
+	return s.withArticles(ctx, series, false)
+}
+
+func (s *seriesService) withArticles(ctx context.Context, series model.Series, publicOnly bool) (model.Series, error) {
+	articles, err := s.repo.GetSeriesArticles(ctx, series.Id, publicOnly)
+	if err != nil {
+		if ctx.Err() != nil {
+			return model.Series{}, ctx.Err()
+		}
+		return model.Series{}, fmt.Errorf("failed to fetch series articles: %v", err)
+	}
+	series.Articles = articles
+	return series, nil
+}
+
+func (s *seriesService) wrapLookupError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return s.errorWrapper.NotFoundError(ctx, "Series")
+	}
+	return fmt.Errorf("failed to fetch series: %v", err)
+}
+
+func (s *seriesService) wrapWriteError(ctx context.Context, err error, operation string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return s.errorWrapper.NotFoundError(ctx, "Series")
+	}
+	if repository.IsUniqueViolation(err) {
+		return s.errorWrapper.ConflictError(ctx, "Series", "a series with this title already exists")
+	}
+	return fmt.Errorf("failed to %s: %v", operation, err)
+}
+
+func NewSeriesService(repo repository.SeriesRepository, slugAllocator SlugAllocator, logger utils.Logger, errorWrapper utils.ErrorWrapper) SeriesService {
+	return &seriesService{
+		repo:          repo,
+		slugAllocator: slugAllocator,
+		logger:        logger,
+		errorWrapper:  errorWrapper,
+	}
+}
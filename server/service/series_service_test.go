@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSeriesRepository holds one series; appendErr and lookupErr fail the matching calls
+type fakeSeriesRepository struct {
+	repository.SeriesRepository
+	series    model.Series
+	appendErr error
+	lookupErr error
+}
+
+func (r *fakeSeriesRepository) AppendArticle(ctx context.Context, seriesId, articleId uuid.UUID) error {
+	if r.appendErr != nil {
+		return r.appendErr
+	}
+	r.series.Articles = append(r.series.Articles, model.SeriesArticle{Position: len(r.series.Articles) + 1, ArticleId: articleId})
+	return nil
+}
+
+func (r *fakeSeriesRepository) GetSeriesById(ctx context.Context, id uuid.UUID) (model.Series, error) {
+	if id != r.series.Id {
+		return model.Series{}, sql.ErrNoRows
+	}
+	return r.series, nil
+}
+
+func (r *fakeSeriesRepository) GetSeriesArticles(ctx context.Context, seriesId uuid.UUID, publicOnly bool) ([]model.SeriesArticle, error) {
+	return r.series.Articles, nil
+}
+
+func (r *fakeSeriesRepository) GetSeriesByArticleId(ctx context.Context, articleId uuid.UUID) (model.Series, error) {
+	if r.lookupErr != nil {
+		return model.Series{}, r.lookupErr
+	}
+	for _, article := range r.series.Articles {
+		if article.ArticleId == articleId {
+			return r.series, nil
+		}
+	}
+	return model.Series{}, sql.ErrNoRows
+}
+
+func newTestSeriesService(repo *fakeSeriesRepository) SeriesService {
+	return NewSeriesService(repo, nil, newTestLogger(), utils.NewErrorWrapper())
+}
+
+func testSeries(parts int) model.Series {
+	series := model.Series{Id: uuid.New(), Title: "Go in practice", Slug: "go-in-practice"}
+	for i := 1; i <= parts; i++ {
+		series.Articles = append(series.Articles, model.SeriesArticle{Position: i, ArticleId: uuid.New()})
+	}
+	return series
+}
+
+func TestSeriesService_AddArticle(t *testing.T) {
+	tests := []struct {
+		name       string
+		seriesId   func(series model.Series) uuid.UUID
+		articleId  uuid.UUID
+		appendErr  error
+		wantStatus int
+	}{
+		{name: "appends the article", articleId: uuid.New()},
+		{name: "missing article id", articleId: uuid.Nil, wantStatus: 400},
+		{name: "unknown series", articleId: uuid.New(), appendErr: sql.ErrNoRows, wantStatus: 404},
+		{name: "unknown article", articleId: uuid.New(), appendErr: &pq.Error{Code: "23503", Constraint: "series_articles_article_id_fkey"}, wantStatus: 404},
+		{name: "article already in a series", articleId: uuid.New(), appendErr: &pq.Error{Code: "23505"}, wantStatus: 409},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeSeriesRepository{series: testSeries(1), appendErr: tt.appendErr}
+
+			series, err := newTestSeriesService(repo).AddArticle(context.Background(), repo.series.Id, tt.articleId)
+			if tt.wantStatus != 0 {
+				var appErr *utils.AppError
+				require.True(t, errors.As(err, &appErr), "got %v", err)
+				assert.Equal(t, tt.wantStatus, appErr.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, series.Articles, 2)
+			assert.Equal(t, tt.articleId, series.Articles[1].ArticleId)
+		})
+	}
+}
+
+func TestSeriesService_FindNavigation(t *testing.T) {
+	ctx := context.Background()
+	series := testSeries(3)
+
+	t.Run("should place the article between its neighbours", func(t *testing.T) {
+		navigation, err := newTestSeriesService(&fakeSeriesRepository{series: series}).FindNavigation(ctx, series.Articles[1].ArticleId)
+		require.NoError(t, err)
+		require.NotNil(t, navigation)
+		assert.Equal(t, 2, navigation.Part)
+		assert.Equal(t, 3, navigation.Total)
+		assert.Equal(t, series.Articles[0].ArticleId, navigation.Previous.ArticleId)
+		assert.Equal(t, series.Articles[2].ArticleId, navigation.Next.ArticleId)
+	})
+
+	t.Run("should leave out the missing neighbours at the ends", func(t *testing.T) {
+		service := newTestSeriesService(&fakeSeriesRepository{series: series})
+
+		first, err := service.FindNavigation(ctx, series.Articles[0].ArticleId)
+		require.NoError(t, err)
+		assert.Nil(t, first.Previous)
+
+		last, err := service.FindNavigation(ctx, series.Articles[2].ArticleId)
+		require.NoError(t, err)
+		assert.Nil(t, last.Next)
+	})
+
+	t.Run("should return nil for an article in no series", func(t *testing.T) {
+		navigation, err := newTestSeriesService(&fakeSeriesRepository{series: series}).FindNavigation(ctx, uuid.New())
+		require.NoError(t, err)
+		assert.Nil(t, navigation)
+	})
+
+	t.Run("should degrade to no navigation when the lookup fails", func(t *testing.T) {
+		repo := &fakeSeriesRepository{series: series, lookupErr: errors.New("connection refused")}
+		navigation, err := newTestSeriesService(repo).FindNavigation(ctx, series.Articles[0].ArticleId)
+		require.NoError(t, err)
+		assert.Nil(t, navigation)
+	})
+
+	t.Run("should still report a cancelled request", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := newTestSeriesService(&fakeSeriesRepository{series: series}).FindNavigation(cancelled, series.Articles[0].ArticleId)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"develapar-server/config"
+	"develapar-server/model/dto"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+type SitemapService interface {
+	// WriteSitemap streams /sitemap.xml. Once there are more entries than fit in one
+	// sitemap it is written as a sitemap index pointing to the child sitemaps instead.
+	WriteSitemap(ctx context.Context, w io.Writer) error
+	// WriteSitemapPage streams one child sitemap of the index, pages start at 1
+	WriteSitemapPage(ctx context.Context, w io.Writer, page int) error
+	// RobotsTxt returns the robots.txt content pointing crawlers to the sitemap
+	RobotsTxt() string
+}
+
+type sitemapService struct {
+	repo         repository.SitemapRepository
+	site         config.SiteConfig
+	pageSize     int
+	errorWrapper utils.ErrorWrapper
+}
+
+// WriteSitemap implements SitemapService.
+func (s *sitemapService) WriteSitemap(ctx context.Context, w io.Writer) error {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	count, lastMod, err := s.repo.CountEntries(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to count sitemap entries: %v", err)
+	}
+
+	if count <= s.pageSize {
+		return s.writeEntries(ctx, w, 0)
+	}
+
+	index := utils.NewSitemapIndexWriter(w)
+	pages := (count + s.pageSize - 1) / s.pageSize
+	for page := 1; page <= pages; page++ {
+		if err := index.WriteURL(s.pageURL(page), lastMod); err != nil {
+			return err
+		}
+	}
+	return index.Close()
+}
+
+// WriteSitemapPage implements SitemapService.
+func (s *sitemapService) WriteSitemapPage(ctx context.Context, w io.Writer, page int) error {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	count, _, err := s.repo.CountEntries(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to count sitemap entries: %v", err)
+	}
+
+	// Child sitemaps only exist while the sitemap is split into an index
+	pages := (count + s.pageSize - 1) / s.pageSize
+	if count <= s.pageSize || page < 1 || page > pages {
+		return s.errorWrapper.NotFoundError(ctx, "Sitemap")
+	}
+
+	return s.writeEntries(ctx, w, (page-1)*s.pageSize)
+}
+
+// writeEntries streams up to one page of entries starting at offset as a <urlset>
+func (s *sitemapService) writeEntries(ctx context.Context, w io.Writer, offset int) error {
+	sitemap := utils.NewSitemapWriter(w)
+	err := s.repo.EachEntry(ctx, offset, s.pageSize, func(entry dto.SitemapEntry) error {
+		return sitemap.WriteURL(s.entryURL(entry), entry.LastMod)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to write sitemap: %v", err)
+	}
+	return sitemap.Close()
+}
+
+// entryURL builds the public page URL of a sitemap entry
+func (s *sitemapService) entryURL(entry dto.SitemapEntry) string {
+	key := url.PathEscape(entry.Key)
+	switch entry.Kind {
+	case dto.SitemapKindCategory:
+		return s.site.BaseURL + "/categories/" + key
+	case dto.SitemapKindTag:
+		return s.site.BaseURL + "/tags/" + key
+	case dto.SitemapKindProductCategory:
+		return s.site.BaseURL + "/products/categories/" + key
+	default:
+		return s.site.BaseURL + "/articles/" + key
+	}
+}
+
+func (s *sitemapService) pageURL(page int) string {
+	return fmt.Sprintf("%s/sitemaps/sitemap-%d.xml", s.site.BaseURL, page)
+}
+
+// RobotsTxt implements SitemapService.
+func (s *sitemapService) RobotsTxt() string {
+	return "User-agent: *\n" +
+		"Allow: /\n" +
+		"Disallow: /api/\n" +
+		"Disallow: /swagger/\n" +
+		"\n" +
+		"Sitemap: " + s.site.BaseURL + "/sitemap.xml\n"
+}
+
+func NewSitemapService(repo repository.SitemapRepository, site config.SiteConfig, errorWrapper utils.ErrorWrapper) SitemapService {
+	return &sitemapService{
+		repo:         repo,
+		site:         site,
+		pageSize:     utils.MaxSitemapURLs,
+		errorWrapper: errorWrapper,
+	}
+}
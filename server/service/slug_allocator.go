@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// SlugAllocator hands out slugs that are unique within a table, see the repository.SlugTable constants
+type SlugAllocator interface {
+	// Allocate derives a slug from source that is neither reserved nor used by another row.
+	// Taken slugs get the first free numeric suffix, "hello-world-2", "hello-world-3" and so on.
+	Allocate(ctx context.Context, table, source string, ownerId uuid.UUID) (string, error)
+	// Save allocates a slug from source and stores the row through save. When a concurrent
+	// write takes the slug first, save fails on the unique constraint and runs again with a
+	// slug carrying a short random suffix.
+	Save(ctx context.Context, table, source string, ownerId uuid.UUID, save func(slug string) error) error
+	// Claim checks a slug chosen by a user. Unlike allocated slugs it is never changed,
+	// a reserved or taken slug is an error.
+	Claim(ctx context.Context, table, slug string, ownerId uuid.UUID) (string, error)
+}
+
+const (
+	// maxNumericSlugSuffix is the last numeric suffix tried before falling back to a random one
+	maxNumericSlugSuffix = 100
+	// maxSlugSaveAttempts bounds the retries of Save on concurrent slug collisions
+	maxSlugSaveAttempts = 5
+)
+
+type slugAllocator struct {
+	repo         repository.SlugRepository
+	errorWrapper utils.ErrorWrapper
+}
+
+// Allocate implements SlugAllocator.
+func (s *slugAllocator) Allocate(ctx context.Context, table, source string, ownerId uuid.UUID) (string, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	default:
+	}
+
+	base := utils.GenerateSlug(source)
+	taken, err := s.takenSlugs(ctx, table, base, ownerId)
+	if err != nil {
+		return "", err
+	}
+
+	if !utils.IsReservedSlug(base) && !taken[base] {
+		return base, nil
+	}
+	for n := 2; n <= maxNumericSlugSuffix; n++ {
+		candidate := utils.SlugWithSuffix(base, strconv.Itoa(n))
+		if !taken[candidate] {
+			return candidate, nil
+		}
+	}
+
+	return randomSlugSuffix(base), nil
+}
+
+// Save implements SlugAllocator.
+func (s *slugAllocator) Save(ctx context.Context, table, source string, ownerId uuid.UUID, save func(slug string) error) error {
+	slug, err := s.Allocate(ctx, table, source, ownerId)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = save(slug)
+		if attempt == maxSlugSaveAttempts || !repository.IsSlugViolation(err, table) {
+			return err
+		}
+
+		// Another row took the slug between the check and the write, a random suffix
+		// keeps concurrent writers from racing for the same numeric suffix again
+		slug = randomSlugSuffix(utils.GenerateSlug(source))
+	}
+}
+
+// Claim implements SlugAllocator.
+func (s *slugAllocator) Claim(ctx context.Context, table, slug string, ownerId uuid.UUID) (string, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	default:
+	}
+
+	normalized := utils.GenerateSlug(strings.TrimSpace(slug))
+	if normalized == "" {
+		return "", s.errorWrapper.ValidationError(ctx, "slug", "Slug must contain letters or digits")
+	}
+	if utils.IsReservedSlug(normalized) {
+		return "", s.errorWrapper.ValidationError(ctx, "slug", fmt.Sprintf("Slug %q is reserved", normalized))
+	}
+
+	taken, err := s.takenSlugs(ctx, table, normalized, ownerId)
+	if err != nil {
+		return "", err
+	}
+	if taken[normalized] {
+		return "", s.errorWrapper.ConflictError(ctx, "Slug", fmt.Sprintf("Slug %q is already in use", normalized))
+	}
+
+	return normalized, nil
+}
+
+// takenSlugs returns the slugs starting with base that belong to other rows of table
+func (s *slugAllocator) takenSlugs(ctx context.Context, table, base string, ownerId uuid.UUID) (map[string]bool, error) {
+	slugs, err := s.repo.GetTakenSlugs(ctx, table, base, ownerId)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to check slug availability: %v", err)
+	}
+
+	taken := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		taken[slug] = true
+	}
+	return taken, nil
+}
+
+// randomSlugSuffix appends a short random hex suffix to base
+func randomSlugSuffix(base string) string {
+	return utils.SlugWithSuffix(base, strings.ReplaceAll(uuid.NewString(), "-", "")[:6])
+}
+
+func NewSlugAllocator(repo repository.SlugRepository, errorWrapper utils.ErrorWrapper) SlugAllocator {
+	return &slugAllocator{
+		repo:         repo,
+		errorWrapper: errorWrapper,
+	}
+}
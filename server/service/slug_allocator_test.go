@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSlugAllocator(slugs map[string]map[uuid.UUID]string, history map[string]uuid.UUID) SlugAllocator {
+	return NewSlugAllocator(&fakeSlugRepository{slugs: slugs, history: history}, utils.NewErrorWrapper())
+}
+
+// randomSuffixSlug matches a slug carrying the short random suffix of a retried save
+var randomSuffixSlug = regexp.MustCompile(`^hello-world-[0-9a-f]{6}$`)
+
+func TestSlugAllocator_Allocate(t *testing.T) {
+	owner, other, renamed := uuid.New(), uuid.New(), uuid.New()
+	crowded := map[uuid.UUID]string{other: "hello-world"}
+	for n := 2; n <= maxNumericSlugSuffix; n++ {
+		crowded[uuid.New()] = "hello-world-" + strconv.Itoa(n)
+	}
+
+	tests := []struct {
+		name    string
+		table   string
+		source  string
+		owner   uuid.UUID
+		slugs   map[uuid.UUID]string
+		history map[string]uuid.UUID
+		want    string
+	}{
+		{name: "free slug", table: repository.SlugTableArticles, source: "Hello World", owner: owner, want: "hello-world"},
+		{name: "taken slug gets a suffix", table: repository.SlugTableArticles, source: "Hello World", owner: owner,
+			slugs: map[uuid.UUID]string{other: "hello-world"}, want: "hello-world-2"},
+		{name: "first free suffix", table: repository.SlugTableCategories, source: "Hello World", owner: owner,
+			slugs: map[uuid.UUID]string{other: "hello-world", uuid.New(): "hello-world-2", uuid.New(): "hello-world-4"}, want: "hello-world-3"},
+		{name: "own slug is not a collision", table: repository.SlugTableArticles, source: "Hello World", owner: owner,
+			slugs: map[uuid.UUID]string{owner: "hello-world"}, want: "hello-world"},
+		{name: "old slug of another article is reserved", table: repository.SlugTableArticles, source: "Hello World", owner: owner,
+			history: map[string]uuid.UUID{"hello-world": renamed}, want: "hello-world-2"},
+		{name: "old slug of the article itself can be taken back", table: repository.SlugTableArticles, source: "Hello World", owner: renamed,
+			history: map[string]uuid.UUID{"hello-world": renamed}, want: "hello-world"},
+		{name: "reserved route segment gets a suffix", table: repository.SlugTableArticles, source: "RSS", owner: owner, want: "rss-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocator := newTestSlugAllocator(map[string]map[uuid.UUID]string{tt.table: tt.slugs}, tt.history)
+
+			slug, err := allocator.Allocate(context.Background(), tt.table, tt.source, tt.owner)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, slug)
+		})
+	}
+
+	t.Run("random suffix once the numeric ones are used up", func(t *testing.T) {
+		allocator := newTestSlugAllocator(map[string]map[uuid.UUID]string{repository.SlugTableArticles: crowded}, nil)
+
+		slug, err := allocator.Allocate(context.Background(), repository.SlugTableArticles, "Hello World", owner)
+		require.NoError(t, err)
+		assert.Regexp(t, randomSuffixSlug, slug)
+	})
+}
+
+func TestSlugAllocator_Save(t *testing.T) {
+	slugViolation := &pq.Error{Code: "23505", Constraint: repository.SlugTableArticles + "_slug_key"}
+
+	t.Run("retries with a random suffix after a unique violation", func(t *testing.T) {
+		allocator := newTestSlugAllocator(nil, nil)
+		var tried []string
+		err := allocator.Save(context.Background(), repository.SlugTableArticles, "Hello World", uuid.New(), func(slug string) error {
+			tried = append(tried, slug)
+			if len(tried) == 1 {
+				return slugViolation
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, tried, 2)
+		assert.Equal(t, "hello-world", tried[0])
+		assert.Regexp(t, randomSuffixSlug, tried[1])
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		allocator := newTestSlugAllocator(nil, nil)
+		attempts := 0
+		err := allocator.Save(context.Background(), repository.SlugTableArticles, "Hello World", uuid.New(), func(slug string) error {
+			attempts++
+			return slugViolation
+		})
+		assert.True(t, repository.IsSlugViolation(err, repository.SlugTableArticles))
+		assert.Equal(t, maxSlugSaveAttempts, attempts)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		allocator := newTestSlugAllocator(nil, nil)
+		tests := []error{
+			errors.New("connection refused"),
+			&pq.Error{Code: "23505", Constraint: "articles_pkey"},
+			&pq.Error{Code: "23505", Constraint: repository.SlugTableCategories + "_slug_key"},
+		}
+		for _, saveErr := range tests {
+			attempts := 0
+			err := allocator.Save(context.Background(), repository.SlugTableArticles, "Hello World", uuid.New(), func(slug string) error {
+				attempts++
+				return saveErr
+			})
+			assert.Equal(t, saveErr, err)
+			assert.Equal(t, 1, attempts)
+		}
+	})
+}
+
+func TestSlugAllocator_Claim(t *testing.T) {
+	owner, other, renamed := uuid.New(), uuid.New(), uuid.New()
+	allocator := newTestSlugAllocator(
+		map[string]map[uuid.UUID]string{repository.SlugTableArticles: {owner: "mine", other: "taken"}},
+		map[string]uuid.UUID{"old-title": renamed},
+	)
+
+	tests := []struct {
+		name       string
+		slug       string
+		owner      uuid.UUID
+		want       string
+		wantStatus int
+	}{
+		{name: "free slug is normalized", slug: "  My Slug ", owner: owner, want: "my-slug"},
+		{name: "own slug", slug: "mine", owner: owner, want: "mine"},
+		{name: "prefix of a taken slug is free", slug: "take", owner: owner, want: "take"},
+		{name: "slug of another article", slug: "taken", owner: owner, wantStatus: 409},
+		{name: "old slug of another article", slug: "old-title", owner: owner, wantStatus: 409},
+		{name: "old slug of the article itself", slug: "old-title", owner: renamed, want: "old-title"},
+		{name: "reserved route segment", slug: "feed", owner: owner, wantStatus: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slug, err := allocator.Claim(context.Background(), repository.SlugTableArticles, tt.slug, tt.owner)
+			if tt.wantStatus != 0 {
+				var appErr *utils.AppError
+				require.True(t, errors.As(err, &appErr), "got %v", err)
+				assert.Equal(t, tt.wantStatus, appErr.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, slug)
+		})
+	}
+}
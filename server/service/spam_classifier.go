@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"develapar-server/config"
+	"develapar-server/model"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Signals a spam score is computed from, stored with the comment for auditing
+const (
+	SpamSignalBayes     = "bayes"
+	SpamSignalLinks     = "links"
+	SpamSignalDuplicate = "duplicate"
+	SpamSignalVelocity  = "velocity"
+	SpamSignalBlocklist = "blocklist"
+)
+
+// SpamVerdict is the spam score of a comment between 0 and 1 with the signals it was
+// combined from. Signals is nil when the comment was not scored.
+type SpamVerdict struct {
+	Score   float64
+	Signals map[string]float64
+	// Spam is set when the score reaches the spam threshold, Hold when it reaches the
+	// threshold for holding the comment in the moderation queue
+	Spam bool
+	Hold bool
+}
+
+// SpamClassifier scores new comments and learns from the decisions of moderators
+type SpamClassifier interface {
+	// Classify scores a comment before it is stored. Signals that cannot be computed are
+	// left out, so a failing check never blocks a comment.
+	Classify(ctx context.Context, comment model.Comment) SpamVerdict
+	// RecordPosting counts a stored comment towards the posting velocity of its author
+	RecordPosting(ctx context.Context, comment model.Comment)
+	// Learn trains the classifier with a moderator decision
+	Learn(content string, spam bool)
+	// Forget undoes a decision learned before that a moderator changed
+	Forget(content string, spam bool)
+	// Train learns the most recent moderator decisions stored and returns how many
+	Train(ctx context.Context) (int, error)
+}
+
+// PostingCounter counts events per key in a time window, the rate limit store is one
+type PostingCounter interface {
+	Increment(ctx context.Context, key string, window time.Duration) (int, error)
+	Get(ctx context.Context, key string) (int, error)
+}
+
+// spamLinkPattern matches the links of a comment, with or without a scheme
+var spamLinkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"'()]+`)
+
+// spamClassifier combines a naive Bayes classifier trained from moderator decisions with
+// heuristics. Each signal is a probability that the comment is spam, they are combined
+// as independent evidence: the score is 1 - (1 - s1)(1 - s2)...
+type spamClassifier struct {
+	repo    repository.CommentModerationRepository
+	counter PostingCounter
+	bayes   *utils.NaiveBayes
+	config  config.SpamConfig
+	logger  utils.Logger
+}
+
+// Classify implements SpamClassifier.
+func (s *spamClassifier) Classify(ctx context.Context, comment model.Comment) SpamVerdict {
+	signals := make(map[string]float64)
+
+	// A Bayes probability of 0.5 means the classifier cannot tell, only the certainty
+	// beyond that counts as evidence
+	if s.bayes.Trained() {
+		signals[SpamSignalBayes] = math.Max(0, 2*s.bayes.SpamProbability(comment.Content)-1)
+	}
+
+	links := spamLinkPattern.FindAllString(comment.Content, -1)
+	if excess := len(links) - s.config.MaxLinks; excess > 0 {
+		signals[SpamSignalLinks] = 1 - math.Pow(0.5, float64(excess))
+	}
+
+	if s.isBlocked(comment.Content, links) {
+		signals[SpamSignalBlocklist] = 1
+	}
+
+	// Short replies like "Thanks!" are repeated innocently
+	if len([]rune(strings.TrimSpace(comment.Content))) >= s.config.DuplicateMinLength {
+		duplicates, err := s.repo.CountDuplicates(ctx, comment.Content, comment.UserId, comment.ArticleId, time.Now().Add(-s.config.DuplicateWindow))
+		if err != nil {
+			s.logger.Error(ctx, "Failed to count duplicate comments", err)
+		} else if duplicates > 0 {
+			signals[SpamSignalDuplicate] = 1 - math.Pow(0.5, float64(duplicates))
+		}
+	}
+
+	// The comment being scored is not counted yet, it is recorded once it is stored
+	posted, err := s.counter.Get(ctx, postingCounterKey(comment))
+	if err != nil {
+		s.logger.Error(ctx, "Failed to count comments per user", err,
+			utils.StringField("user_id", comment.UserId.String()))
+	} else if excess := posted + 1 - s.config.VelocityLimit; excess > 0 {
+		signals[SpamSignalVelocity] = 1 - math.Pow(0.5, float64(excess))
+	}
+
+	notSpam := 1.0
+	for _, signal := range signals {
+		notSpam *= 1 - signal
+	}
+	score := 1 - notSpam
+
+	return SpamVerdict{
+		Score:   score,
+		Signals: signals,
+		Spam:    score >= s.config.SpamThreshold,
+		Hold:    score >= s.config.HoldThreshold,
+	}
+}
+
+// RecordPosting implements SpamClassifier.
+func (s *spamClassifier) RecordPosting(ctx context.Context, comment model.Comment) {
+	if _, err := s.counter.Increment(ctx, postingCounterKey(comment), s.config.VelocityWindow); err != nil {
+		s.logger.Error(ctx, "Failed to count comments per user", err,
+			utils.StringField("user_id", comment.UserId.String()))
+	}
+}
+
+func postingCounterKey(comment model.Comment) string {
+	return "comment:user:" + comment.UserId.String()
+}
+
+// isBlocked reports whether the content has a blocked word or links to a blocked domain
+// or one of its subdomains. Blocked words with a space are matched as phrases.
+func (s *spamClassifier) isBlocked(content string, links []string) bool {
+	lower := strings.ToLower(content)
+	tokens := make(map[string]bool)
+	for _, token := range utils.SpamTokens(content) {
+		tokens[token] = true
+	}
+	for _, word := range s.config.BlockedWords {
+		if tokens[word] || (strings.Contains(word, " ") && strings.Contains(lower, word)) {
+			return true
+		}
+	}
+
+	for _, link := range links {
+		if !strings.Contains(link, "://") {
+			link = "http://" + link
+		}
+		parsed, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(parsed.Hostname())
+		for _, domain := range s.config.BlockedDomains {
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Learn implements SpamClassifier.
+func (s *spamClassifier) Learn(content string, spam bool) {
+	s.bayes.Learn(content, spam)
+}
+
+// Forget implements SpamClassifier.
+func (s *spamClassifier) Forget(content string, spam bool) {
+	s.bayes.Forget(content, spam)
+}
+
+// Train implements SpamClassifier.
+func (s *spamClassifier) Train(ctx context.Context) (int, error) {
+	if s.config.TrainingLimit == 0 {
+		return 0, nil
+	}
+
+	decisions, err := s.repo.GetSpamDecisions(ctx, s.config.TrainingLimit)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, fmt.Errorf("failed to load spam decisions: %v", err)
+	}
+	for _, comment := range decisions {
+		s.bayes.Learn(comment.Content, comment.Status == model.CommentStatusSpam)
+	}
+
+	return len(decisions), nil
+}
+
+// NewSpamClassifier creates the default spam classifier, untrained until Train is called.
+// counter tracks how many comments each user posts within the velocity window.
+func NewSpamClassifier(repo repository.CommentModerationRepository, counter PostingCounter, spamConfig config.SpamConfig, logger utils.Logger) SpamClassifier {
+	return &spamClassifier{
+		repo:    repo,
+		counter: counter,
+		bayes:   utils.NewNaiveBayes(),
+		config:  spamConfig,
+		logger:  logger,
+	}
+}
+
+// disabledSpamClassifier leaves every comment unscored
+type disabledSpamClassifier struct{}
+
+// Classify implements SpamClassifier.
+func (disabledSpamClassifier) Classify(context.Context, model.Comment) SpamVerdict {
+	return SpamVerdict{}
+}
+
+// RecordPosting implements SpamClassifier.
+func (disabledSpamClassifier) RecordPosting(context.Context, model.Comment) {}
+
+// Learn implements SpamClassifier.
+func (disabledSpamClassifier) Learn(string, bool) {}
+
+// Forget implements SpamClassifier.
+func (disabledSpamClassifier) Forget(string, bool) {}
+
+// Train implements SpamClassifier.
+func (disabledSpamClassifier) Train(context.Context) (int, error) { return 0, nil }
+
+// NewDisabledSpamClassifier creates a classifier for when the spam filter is turned off
+func NewDisabledSpamClassifier() SpamClassifier {
+	return disabledSpamClassifier{}
+}
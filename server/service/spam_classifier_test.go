@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"develapar-server/config"
+	"develapar-server/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePostingCounter counts in memory without a window
+type fakePostingCounter struct {
+	counts map[string]int
+}
+
+func (c *fakePostingCounter) Increment(ctx context.Context, key string, window time.Duration) (int, error) {
+	c.counts[key]++
+	return c.counts[key], nil
+}
+
+func (c *fakePostingCounter) Get(ctx context.Context, key string) (int, error) {
+	return c.counts[key], nil
+}
+
+func newTestSpamClassifier(repo *fakeCommentModerationRepository, counter *fakePostingCounter) *spamClassifier {
+	spamConfig := config.DefaultSpamConfig()
+	spamConfig.BlockedWords = []string{"casino", "cheap pills"}
+	spamConfig.BlockedDomains = []string{"spam.example"}
+	return NewSpamClassifier(repo, counter, spamConfig, newTestLogger()).(*spamClassifier)
+}
+
+func TestSpamClassifier_Signals(t *testing.T) {
+	userId := uuid.New()
+	longContent := "I really enjoyed reading this article about Go"
+
+	tests := []struct {
+		name       string
+		content    string
+		duplicates int
+		posted     int
+		want       map[string]float64
+		// wantDuplicateQuery tells whether the duplicates were counted
+		wantDuplicateQuery bool
+	}{
+		{name: "clean comment", content: longContent, want: map[string]float64{}, wantDuplicateQuery: true},
+		{name: "links up to the limit", content: "see https://a.example and www.b.example", want: map[string]float64{}, wantDuplicateQuery: true},
+		{name: "one link over the limit", content: "https://a.example https://b.example https://c.example", want: map[string]float64{SpamSignalLinks: 0.5}, wantDuplicateQuery: true},
+		{name: "two links over the limit", content: "https://a.example https://b.example https://c.example https://d.example", want: map[string]float64{SpamSignalLinks: 0.75}, wantDuplicateQuery: true},
+		{name: "blocked word", content: "Best casino", want: map[string]float64{SpamSignalBlocklist: 1}},
+		{name: "blocked phrase", content: "Get Cheap Pills", want: map[string]float64{SpamSignalBlocklist: 1}},
+		{name: "word containing a blocked word", content: "occasino", want: map[string]float64{}},
+		{name: "link to a blocked subdomain", content: "www.offers.spam.example", want: map[string]float64{SpamSignalBlocklist: 1}, wantDuplicateQuery: true},
+		{name: "one duplicate", content: longContent, duplicates: 1, want: map[string]float64{SpamSignalDuplicate: 0.5}, wantDuplicateQuery: true},
+		{name: "two duplicates", content: longContent, duplicates: 2, want: map[string]float64{SpamSignalDuplicate: 0.75}, wantDuplicateQuery: true},
+		{name: "short content is never a duplicate", content: "Thanks!", duplicates: 3, want: map[string]float64{}},
+		{name: "posting at the velocity limit", content: longContent, posted: 4, want: map[string]float64{}, wantDuplicateQuery: true},
+		{name: "posting over the velocity limit", content: longContent, posted: 5, want: map[string]float64{SpamSignalVelocity: 0.5}, wantDuplicateQuery: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeCommentModerationRepository{duplicates: tt.duplicates}
+			counter := &fakePostingCounter{counts: map[string]int{"comment:user:" + userId.String(): tt.posted}}
+			classifier := newTestSpamClassifier(repo, counter)
+
+			verdict := classifier.Classify(context.Background(), model.Comment{UserId: userId, ArticleId: uuid.New(), Content: tt.content})
+			require.Len(t, verdict.Signals, len(tt.want))
+			for signal, want := range tt.want {
+				assert.InDelta(t, want, verdict.Signals[signal], 1e-9, signal)
+			}
+			assert.Equal(t, tt.wantDuplicateQuery, repo.duplicateQueries > 0)
+			assert.Equal(t, tt.posted, counter.counts["comment:user:"+userId.String()], "scoring does not count the posting")
+		})
+	}
+}
+
+func TestSpamClassifier_Bayes(t *testing.T) {
+	classifier := newTestSpamClassifier(&fakeCommentModerationRepository{}, &fakePostingCounter{counts: map[string]int{}})
+	ctx := context.Background()
+
+	verdict := classifier.Classify(ctx, model.Comment{Content: "win free money now"})
+	assert.NotContains(t, verdict.Signals, SpamSignalBayes, "an untrained classifier gives no evidence")
+
+	for i := 0; i < 5; i++ {
+		classifier.Learn("win free money now", true)
+		classifier.Learn("great article about testing in go", false)
+	}
+	spam := classifier.Classify(ctx, model.Comment{Content: "free money"})
+	ham := classifier.Classify(ctx, model.Comment{Content: "testing in go"})
+	assert.Greater(t, spam.Signals[SpamSignalBayes], 0.5)
+	assert.Zero(t, ham.Signals[SpamSignalBayes], "ham probability is no evidence of spam")
+
+	for i := 0; i < 5; i++ {
+		classifier.Forget("win free money now", true)
+	}
+	forgotten := classifier.Classify(ctx, model.Comment{Content: "free money"})
+	assert.NotContains(t, forgotten.Signals, SpamSignalBayes, "forgetting every spam decision untrains the classifier")
+}
+
+func TestSpamClassifier_Thresholds(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name       string
+		content    string
+		duplicates int
+		posted     int
+		wantScore  float64
+		wantHold   bool
+		wantSpam   bool
+	}{
+		{name: "below the hold threshold", content: "I really enjoyed reading this article about Go", wantScore: 0},
+		{name: "one signal reaches the hold threshold", content: "https://a.example https://b.example https://c.example", wantScore: 0.5, wantHold: true},
+		{name: "signals combine as independent evidence", content: "https://a.example https://b.example https://c.example", duplicates: 1, wantScore: 0.75, wantHold: true},
+		{name: "three signals stay below the spam threshold", content: "https://a.example https://b.example https://c.example", duplicates: 1, posted: 5, wantScore: 0.875, wantHold: true},
+		{name: "combined signals reach the spam threshold", content: "https://a.example https://b.example https://c.example https://d.example", duplicates: 2, posted: 5, wantScore: 1 - 0.25*0.25*0.5, wantHold: true, wantSpam: true},
+		{name: "blocklist alone is spam", content: "Best casino", wantScore: 1, wantHold: true, wantSpam: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeCommentModerationRepository{duplicates: tt.duplicates}
+			counter := &fakePostingCounter{counts: map[string]int{"comment:user:" + userId.String(): tt.posted}}
+			classifier := newTestSpamClassifier(repo, counter)
+
+			verdict := classifier.Classify(context.Background(), model.Comment{UserId: userId, ArticleId: uuid.New(), Content: tt.content})
+			assert.InDelta(t, tt.wantScore, verdict.Score, 1e-9)
+			assert.Equal(t, tt.wantHold, verdict.Hold)
+			assert.Equal(t, tt.wantSpam, verdict.Spam)
+		})
+	}
+}
+
+func TestSpamClassifier_RecordPosting(t *testing.T) {
+	userId := uuid.New()
+	counter := &fakePostingCounter{counts: map[string]int{}}
+	classifier := newTestSpamClassifier(&fakeCommentModerationRepository{}, counter)
+
+	classifier.RecordPosting(context.Background(), model.Comment{UserId: userId})
+	classifier.RecordPosting(context.Background(), model.Comment{UserId: userId})
+	assert.Equal(t, 2, counter.counts["comment:user:"+userId.String()])
+}
@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"develapar-server/model"
 	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -14,13 +17,15 @@ type TagService interface {
 	CreateTag(ctx context.Context, payload model.Tags) (model.Tags, error)
 	FindAll(ctx context.Context) ([]model.Tags, error)
 	FindById(ctx context.Context, id uuid.UUID) (model.Tags, error)
-	UpdateTag(ctx context.Context, id uuid.UUID, payload model.Tags) (model.Tags, error)
+	// UpdateTag applies the update only if version is still the current one
+	UpdateTag(ctx context.Context, id uuid.UUID, version int, payload model.Tags) (model.Tags, error)
 	DeleteTag(ctx context.Context, id uuid.UUID) error
 }
 
 type tagService struct {
 	repo              repository.TagRepository
 	validationService ValidationService
+	errorWrapper      utils.ErrorWrapper
 }
 
 // CreateTag implements TagService.
@@ -110,7 +115,7 @@ func (t *tagService) FindById(ctx context.Context, id uuid.UUID) (model.Tags, er
 }
 
 // UpdateTag implements TagService.
-func (t *tagService) UpdateTag(ctx context.Context, id uuid.UUID, payload model.Tags) (model.Tags, error) {
+func (t *tagService) UpdateTag(ctx context.Context, id uuid.UUID, version int, payload model.Tags) (model.Tags, error) {
 	// Check context cancellation
 	select {
 	case <-ctx.Done():
@@ -131,6 +136,25 @@ func (t *tagService) UpdateTag(ctx context.Context, id uuid.UUID, payload model.
 	// Normalize tag name
 	payload.Name = strings.ToLower(strings.TrimSpace(payload.Name))
 	payload.Id = id
+	payload.Version = version
+
+	// Get existing tag with context
+	existing, err := t.repo.GetTagById(ctx, id)
+	if err != nil {
+		// Check if context was cancelled during repository operation
+		if ctx.Err() != nil {
+			return model.Tags{}, ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Tags{}, t.errorWrapper.NotFoundError(ctx, "Tag")
+		}
+		return model.Tags{}, fmt.Errorf("failed to fetch tag for update: %v", err)
+	}
+
+	// Refuse updates made from an outdated copy of the tag
+	if existing.Version != version {
+		return model.Tags{}, t.errorWrapper.PreconditionFailedError(ctx, "Tag")
+	}
 
 	// Check context cancellation before update
 	select {
@@ -146,6 +170,10 @@ func (t *tagService) UpdateTag(ctx context.Context, id uuid.UUID, payload model.
 		if ctx.Err() != nil {
 			return model.Tags{}, ctx.Err()
 		}
+		// The tag was changed by someone else since it was read
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Tags{}, t.errorWrapper.PreconditionFailedError(ctx, "Tag")
+		}
 		return model.Tags{}, fmt.Errorf("failed to update tag: %v", err)
 	}
 
@@ -179,9 +207,10 @@ func (t *tagService) DeleteTag(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func NewTagService(repository repository.TagRepository, validationService ValidationService) TagService {
+func NewTagService(repository repository.TagRepository, validationService ValidationService, errorWrapper utils.ErrorWrapper) TagService {
 	return &tagService{
 		repo:              repository,
 		validationService: validationService,
+		errorWrapper:      errorWrapper,
 	}
 }
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVersionedTagRepository updates tags by id only while their version is unchanged
+type fakeVersionedTagRepository struct {
+	repository.TagRepository
+	tags map[uuid.UUID]model.Tags
+}
+
+func (r *fakeVersionedTagRepository) GetTagById(ctx context.Context, id uuid.UUID) (model.Tags, error) {
+	tag, ok := r.tags[id]
+	if !ok {
+		return model.Tags{}, sql.ErrNoRows
+	}
+	return tag, nil
+}
+
+func (r *fakeVersionedTagRepository) UpdateTag(ctx context.Context, payload model.Tags) (model.Tags, error) {
+	tag, ok := r.tags[payload.Id]
+	if !ok || tag.Version != payload.Version {
+		return model.Tags{}, sql.ErrNoRows
+	}
+	payload.Version++
+	r.tags[payload.Id] = payload
+	return payload, nil
+}
+
+func TestTagService_UpdateTag(t *testing.T) {
+	tag := model.Tags{Id: uuid.New(), Name: "golang", Version: 3}
+
+	tests := []struct {
+		name       string
+		id         uuid.UUID
+		version    int
+		wantStatus int
+	}{
+		{name: "current version", id: tag.Id, version: 3},
+		{name: "outdated version", id: tag.Id, version: 2, wantStatus: 412},
+		{name: "missing tag", id: uuid.New(), version: 3, wantStatus: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeVersionedTagRepository{tags: map[uuid.UUID]model.Tags{tag.Id: tag}}
+			errorWrapper := utils.NewErrorWrapper()
+			service := NewTagService(repo, NewValidationService(errorWrapper), errorWrapper)
+
+			updated, err := service.UpdateTag(context.Background(), tt.id, tt.version, model.Tags{Name: " Go "})
+			if tt.wantStatus != 0 {
+				var appErr *utils.AppError
+				require.True(t, errors.As(err, &appErr), "got %v", err)
+				assert.Equal(t, tt.wantStatus, appErr.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "go", updated.Name)
+			assert.Equal(t, 4, updated.Version)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"develapar-server/model"
+	"develapar-server/utils"
+	"time"
+)
+
+// trashPurgeJob periodically deletes the trash older than the retention period for good
+type trashPurgeJob struct {
+	service  TrashService
+	logger   utils.Logger
+	interval time.Duration
+}
+
+// Name implements BackgroundJob.
+func (j *trashPurgeJob) Name() string {
+	return "trash_purge"
+}
+
+// Interval implements BackgroundJob.
+func (j *trashPurgeJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run implements BackgroundJob.
+func (j *trashPurgeJob) Run(ctx context.Context) error {
+	purged, err := j.service.PurgeExpired(ctx)
+	if err != nil {
+		return err
+	}
+
+	if purged[model.TrashTypeArticle]+purged[model.TrashTypeComment]+purged[model.TrashTypeProduct] > 0 {
+		j.logger.Info(ctx, "Purged expired trash",
+			utils.Int64Field(model.TrashTypeArticle, purged[model.TrashTypeArticle]),
+			utils.Int64Field(model.TrashTypeComment, purged[model.TrashTypeComment]),
+			utils.Int64Field(model.TrashTypeProduct, purged[model.TrashTypeProduct]),
+		)
+	}
+
+	return nil
+}
+
+// NewTrashPurgeJob creates the background job that purges expired trash
+func NewTrashPurgeJob(service TrashService, logger utils.Logger, interval time.Duration) BackgroundJob {
+	return &trashPurgeJob{
+		service:  service,
+		logger:   logger,
+		interval: interval,
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashService interface {
+	// FindTrash lists the soft deleted articles, comments and products with the time they
+	// will be purged. An empty itemType lists every type.
+	FindTrash(ctx context.Context, itemType string, page, limit int) (PaginationResult, error)
+	Restore(ctx context.Context, itemType string, id uuid.UUID) error
+	// PurgeExpired permanently deletes the items trashed longer than the retention period
+	PurgeExpired(ctx context.Context) (map[string]int64, error)
+}
+
+type trashService struct {
+	repo              repository.TrashRepository
+	paginationService PaginationService
+	cacheEvents       CacheEventPublisher
+	retention         time.Duration
+	errorWrapper      utils.ErrorWrapper
+	now               func() time.Time
+}
+
+// FindTrash implements TrashService.
+func (s *trashService) FindTrash(ctx context.Context, itemType string, page, limit int) (PaginationResult, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return PaginationResult{}, ctx.Err()
+	default:
+	}
+
+	itemTypes, err := s.itemTypes(ctx, itemType)
+	if err != nil {
+		return PaginationResult{}, err
+	}
+
+	// Parse and validate pagination query
+	query, paginationErr := s.paginationService.ParseQuery(ctx, page, limit, "deleted_at", "desc")
+	if paginationErr != nil {
+		return PaginationResult{}, fmt.Errorf("pagination validation failed: %v", paginationErr)
+	}
+
+	items, total, err := s.repo.GetTrash(ctx, itemTypes, query.Offset, query.Limit)
+	if err != nil {
+		if ctx.Err() != nil {
+			return PaginationResult{}, ctx.Err()
+		}
+		return PaginationResult{}, fmt.Errorf("failed to fetch trash: %v", err)
+	}
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(s.retention)
+	}
+
+	result, paginationErr := s.paginationService.Paginate(ctx, items, total, query)
+	if paginationErr != nil {
+		return PaginationResult{}, fmt.Errorf("failed to create pagination result: %v", paginationErr)
+	}
+
+	return result, nil
+}
+
+// Restore implements TrashService.
+func (s *trashService) Restore(ctx context.Context, itemType string, id uuid.UUID) error {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if _, err := s.itemTypes(ctx, itemType); err != nil {
+		return err
+	}
+
+	articleId, err := s.repo.Restore(ctx, itemType, id)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return s.errorWrapper.NotFoundError(ctx, "Trash item")
+		}
+		return fmt.Errorf("failed to restore %s: %v", itemType, err)
+	}
+
+	s.cacheEvents.Publish(ctx, restoredEvent(itemType, id, articleId))
+	return nil
+}
+
+// PurgeExpired implements TrashService.
+func (s *trashService) PurgeExpired(ctx context.Context) (map[string]int64, error) {
+	// Check context cancellation
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	purged, err := s.repo.Purge(ctx, s.now().Add(-s.retention))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to purge trash: %v", err)
+	}
+
+	return purged, nil
+}
+
+// restoredEvent names the cached reads a restored item shows up in again, the same ones
+// its delete dropped: the article it belongs to with the category and tag lists, or the product
+func restoredEvent(itemType string, id, articleId uuid.UUID) CacheEvent {
+	if itemType == model.TrashTypeProduct {
+		return productChanged(id)
+	}
+	event := articleChanged(articleId)
+	event.Keys = []string{categoryListCacheKey, tagListCacheKey}
+	return event
+}
+
+// itemTypes validates an item type filter, an empty one selects every type
+func (s *trashService) itemTypes(ctx context.Context, itemType string) ([]string, error) {
+	if itemType == "" {
+		return repository.TrashTypes, nil
+	}
+	for _, known := range repository.TrashTypes {
+		if itemType == known {
+			return []string{itemType}, nil
+		}
+	}
+	return nil, s.errorWrapper.ValidationError(ctx, "type", "type must be article, comment or product")
+}
+
+func NewTrashService(repo repository.TrashRepository, paginationService PaginationService, cacheEvents CacheEventPublisher, retentionDays int, errorWrapper utils.ErrorWrapper) TrashService {
+	return &trashService{
+		repo:              repo,
+		paginationService: paginationService,
+		cacheEvents:       cacheEvents,
+		retention:         time.Duration(retentionDays) * 24 * time.Hour,
+		errorWrapper:      errorWrapper,
+		now:               time.Now,
+	}
+}
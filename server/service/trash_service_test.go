@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"develapar-server/model"
+	"develapar-server/repository"
+	"develapar-server/utils"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTrashRepository holds the trashed items by id and records the purge cutoff
+type fakeTrashRepository struct {
+	repository.TrashRepository
+	items    map[uuid.UUID]model.TrashItem
+	articles map[uuid.UUID]uuid.UUID
+	cutoff   time.Time
+	purgeErr error
+}
+
+func (r *fakeTrashRepository) GetTrash(ctx context.Context, itemTypes []string, offset, limit int) ([]model.TrashItem, int, error) {
+	items := []model.TrashItem{}
+	for _, item := range r.items {
+		for _, itemType := range itemTypes {
+			if item.Type == itemType {
+				items = append(items, item)
+			}
+		}
+	}
+	return items, len(items), nil
+}
+
+func (r *fakeTrashRepository) Restore(ctx context.Context, itemType string, id uuid.UUID) (uuid.UUID, error) {
+	item, ok := r.items[id]
+	if !ok || item.Type != itemType {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	delete(r.items, id)
+	return r.articles[id], nil
+}
+
+// recordingPublisher keeps the published cache events
+type recordingPublisher struct {
+	events []CacheEvent
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event CacheEvent) {
+	p.events = append(p.events, event)
+}
+
+func (r *fakeTrashRepository) Purge(ctx context.Context, cutoff time.Time) (map[string]int64, error) {
+	if r.purgeErr != nil {
+		return nil, r.purgeErr
+	}
+	r.cutoff = cutoff
+	purged := map[string]int64{}
+	for id, item := range r.items {
+		if item.DeletedAt.Before(cutoff) {
+			purged[item.Type]++
+			delete(r.items, id)
+		}
+	}
+	return purged, nil
+}
+
+func newTestTrashService(repo *fakeTrashRepository, now time.Time) TrashService {
+	return newTestTrashServiceWithEvents(repo, &recordingPublisher{}, now)
+}
+
+func newTestTrashServiceWithEvents(repo *fakeTrashRepository, events CacheEventPublisher, now time.Time) TrashService {
+	errorWrapper := utils.NewErrorWrapper()
+	service := NewTrashService(repo, NewPaginationService(NewValidationService(errorWrapper), errorWrapper), events, 30, errorWrapper)
+	service.(*trashService).now = func() time.Time { return now }
+	return service
+}
+
+func TestTrashService_PurgeExpired(t *testing.T) {
+	now := time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC)
+	expired := model.TrashItem{Type: model.TrashTypeArticle, Id: uuid.New(), DeletedAt: now.Add(-30*24*time.Hour - time.Minute)}
+	kept := model.TrashItem{Type: model.TrashTypeComment, Id: uuid.New(), DeletedAt: now.Add(-30*24*time.Hour + time.Minute)}
+
+	t.Run("should purge only the items trashed before the retention period", func(t *testing.T) {
+		repo := &fakeTrashRepository{items: map[uuid.UUID]model.TrashItem{expired.Id: expired, kept.Id: kept}}
+
+		purged, err := newTestTrashService(repo, now).PurgeExpired(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(-30*24*time.Hour), repo.cutoff)
+		assert.Equal(t, map[string]int64{model.TrashTypeArticle: 1}, purged)
+		assert.Contains(t, repo.items, kept.Id)
+	})
+
+	t.Run("should report a failing purge", func(t *testing.T) {
+		repo := &fakeTrashRepository{purgeErr: errors.New("connection refused")}
+
+		_, err := newTestTrashService(repo, now).PurgeExpired(context.Background())
+		assert.ErrorContains(t, err, "failed to purge trash")
+	})
+}
+
+func TestTrashService_FindTrashPurgeAt(t *testing.T) {
+	now := time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC)
+	item := model.TrashItem{Type: model.TrashTypeProduct, Id: uuid.New(), DeletedAt: now.Add(-24 * time.Hour)}
+	repo := &fakeTrashRepository{items: map[uuid.UUID]model.TrashItem{item.Id: item}}
+
+	result, err := newTestTrashService(repo, now).FindTrash(context.Background(), "", 1, 10)
+	require.NoError(t, err)
+	items := result.Data.([]model.TrashItem)
+	require.Len(t, items, 1)
+	assert.Equal(t, item.DeletedAt.Add(30*24*time.Hour), items[0].PurgeAt)
+}
+
+func TestTrashService_Restore(t *testing.T) {
+	comment := model.TrashItem{Type: model.TrashTypeComment, Id: uuid.New(), DeletedAt: time.Now()}
+	article := model.TrashItem{Type: model.TrashTypeArticle, Id: uuid.New(), DeletedAt: time.Now()}
+	product := model.TrashItem{Type: model.TrashTypeProduct, Id: uuid.New(), DeletedAt: time.Now()}
+	commentedArticle := uuid.New()
+
+	tests := []struct {
+		name       string
+		itemType   string
+		id         uuid.UUID
+		wantStatus int
+		wantEvent  CacheEvent
+	}{
+		{name: "restores a comment", itemType: model.TrashTypeComment, id: comment.Id,
+			wantEvent: CacheEvent{Keys: []string{categoryListCacheKey, tagListCacheKey}, Tags: []string{articleCacheTag(commentedArticle)}}},
+		{name: "restores an article", itemType: model.TrashTypeArticle, id: article.Id,
+			wantEvent: CacheEvent{Keys: []string{categoryListCacheKey, tagListCacheKey}, Tags: []string{articleCacheTag(article.Id)}}},
+		{name: "restores a product", itemType: model.TrashTypeProduct, id: product.Id, wantEvent: productChanged(product.Id)},
+		{name: "unknown type", itemType: "user", id: comment.Id, wantStatus: 400},
+		{name: "item not in the trash", itemType: model.TrashTypeComment, id: uuid.New(), wantStatus: 404},
+		{name: "item of another type", itemType: model.TrashTypeArticle, id: comment.Id, wantStatus: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTrashRepository{
+				items:    map[uuid.UUID]model.TrashItem{comment.Id: comment, article.Id: article, product.Id: product},
+				articles: map[uuid.UUID]uuid.UUID{comment.Id: commentedArticle, article.Id: article.Id},
+			}
+			events := &recordingPublisher{}
+
+			err := newTestTrashServiceWithEvents(repo, events, time.Now()).Restore(context.Background(), tt.itemType, tt.id)
+			if tt.wantStatus != 0 {
+				var appErr *utils.AppError
+				require.True(t, errors.As(err, &appErr), "got %v", err)
+				assert.Equal(t, tt.wantStatus, appErr.StatusCode)
+				assert.Empty(t, events.events, "a failed restore publishes nothing")
+				return
+			}
+			require.NoError(t, err)
+			assert.NotContains(t, repo.items, tt.id)
+			assert.Equal(t, []CacheEvent{tt.wantEvent}, events.events)
+		})
+	}
+}
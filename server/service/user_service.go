@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"develapar-server/model"
 	"develapar-server/model/dto"
 	"develapar-server/repository"
 	"develapar-server/utils"
+	"errors"
 	"fmt"
 	"log"
 	"net/url"
@@ -21,7 +23,8 @@ type UserService interface {
 	FindAllUserWithPagination(ctx context.Context, page, limit int) (PaginationResult, error)
 	Login(ctx context.Context, payload dto.LoginDto) (dto.LoginResponseDto, error)
 	RefreshToken(ctx context.Context, refreshToken string) (dto.LoginResponseDto, error)
-	UpdateUser(ctx context.Context, requestingUserID uuid.UUID, requestingUserRole string, targetUserID uuid.UUID, req dto.UpdateUserRequest) (model.User, error)
+	// UpdateUser applies the update only if version is still the current one
+	UpdateUser(ctx context.Context, requestingUserID uuid.UUID, requestingUserRole string, targetUserID uuid.UUID, version int, req dto.UpdateUserRequest) (model.User, error)
 	DeleteUser(ctx context.Context, requestingUserID uuid.UUID, requestingUserRole string, targetUserID uuid.UUID) error
 }
 
@@ -31,6 +34,7 @@ type userService struct {
 	passwordHasher    utils.PasswordHasher
 	paginationService PaginationService
 	validationService ValidationService
+	errorWrapper      utils.ErrorWrapper
 }
 
 // Login implements UserService.
@@ -306,7 +310,7 @@ func (u *userService) RefreshToken(ctx context.Context, refreshToken string) (dt
 }
 
 // UpdateUser implements UserService.
-func (u *userService) UpdateUser(ctx context.Context, requestingUserID uuid.UUID, requestingUserRole string, targetUserID uuid.UUID, req dto.UpdateUserRequest) (model.User, error) {
+func (u *userService) UpdateUser(ctx context.Context, requestingUserID uuid.UUID, requestingUserRole string, targetUserID uuid.UUID, version int, req dto.UpdateUserRequest) (model.User, error) {
 	// Check context cancellation
 	select {
 	case <-ctx.Done():
@@ -340,9 +344,17 @@ func (u *userService) UpdateUser(ctx context.Context, requestingUserID uuid.UUID
 		if ctx.Err() != nil {
 			return model.User{}, ctx.Err()
 		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, u.errorWrapper.NotFoundError(ctx, "User")
+		}
 		return model.User{}, fmt.Errorf("failed to fetch user for update: %v", err)
 	}
 
+	// Refuse updates made from an outdated copy of the user
+	if user.Version != version {
+		return model.User{}, u.errorWrapper.PreconditionFailedError(ctx, "User")
+	}
+
 	// Update fields if provided
 	if req.Name != nil {
 		user.Name = *req.Name
@@ -373,6 +385,10 @@ func (u *userService) UpdateUser(ctx context.Context, requestingUserID uuid.UUID
 		if ctx.Err() != nil {
 			return model.User{}, ctx.Err()
 		}
+		// The user was changed by someone else since it was read
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, u.errorWrapper.PreconditionFailedError(ctx, "User")
+		}
 		return model.User{}, fmt.Errorf("failed to update user: %v", err)
 	}
 
@@ -437,12 +453,13 @@ func (u *userService) DeleteUser(ctx context.Context, requestingUserID uuid.UUID
 	return nil
 }
 
-func NewUserservice(repository repository.UserRepository, jS JwtService, ph utils.PasswordHasher, paginationService PaginationService, validationService ValidationService) UserService {
+func NewUserservice(repository repository.UserRepository, jS JwtService, ph utils.PasswordHasher, paginationService PaginationService, validationService ValidationService, errorWrapper utils.ErrorWrapper) UserService {
 	return &userService{
 		repo:              repository,
 		jwtService:        jS,
 		passwordHasher:    ph,
 		paginationService: paginationService,
 		validationService: validationService,
+		errorWrapper:      errorWrapper,
 	}
 }
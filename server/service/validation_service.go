@@ -175,7 +175,7 @@ func (vs *validationService) ValidateUser(ctx context.Context, user model.User)
 			RequestID: requestID,
 		})
 	} else {
-		validRoles := []string{"admin", "user", "moderator"}
+		validRoles := []string{"admin", "editor", "user", "moderator"}
 		isValidRole := false
 		for _, role := range validRoles {
 			if strings.ToLower(user.Role) == role {
@@ -186,7 +186,7 @@ func (vs *validationService) ValidateUser(ctx context.Context, user model.User)
 		if !isValidRole {
 			fieldErrors = append(fieldErrors, FieldError{
 				Field:     "role",
-				Message:   "Role must be one of: admin, user, moderator",
+				Message:   "Role must be one of: admin, editor, user, moderator",
 				Value:     user.Role,
 				RequestID: requestID,
 			})
@@ -514,22 +514,30 @@ func (vs *validationService) ValidateArticle(ctx context.Context, article model.
 		}
 	}
 
-	// Validate user (must have valid user ID)
-	if article.User.Id == uuid.Nil {
+	// Validate user (must have valid user ID), either as plain ID or populated relation
+	userId := article.UserId
+	if userId == uuid.Nil && article.User != nil {
+		userId = article.User.Id
+	}
+	if userId == uuid.Nil {
 		fieldErrors = append(fieldErrors, FieldError{
 			Field:     "user_id",
 			Message:   "Valid user ID is required",
-			Value:     fmt.Sprintf("%d", article.User.Id),
+			Value:     userId.String(),
 			RequestID: requestID,
 		})
 	}
 
-	// Validate category (must have valid category ID)
-	if article.Category.Id == uuid.Nil {
+	// Validate category (must have valid category ID), either as plain ID or populated relation
+	categoryId := article.CategoryId
+	if categoryId == uuid.Nil && article.Category != nil {
+		categoryId = article.Category.Id
+	}
+	if categoryId == uuid.Nil {
 		fieldErrors = append(fieldErrors, FieldError{
 			Field:     "category_id",
 			Message:   "Valid category ID is required",
-			Value:     fmt.Sprintf("%d", article.Category.Id),
+			Value:     categoryId.String(),
 			RequestID: requestID,
 		})
 	}
@@ -630,21 +638,21 @@ func (vs *validationService) ValidateComment(ctx context.Context, comment model.
 	}
 
 	// Validate user reference (must have valid user ID)
-	if comment.User.Id == uuid.Nil {
+	if comment.UserId == uuid.Nil {
 		fieldErrors = append(fieldErrors, FieldError{
 			Field:     "user_id",
 			Message:   "Valid user ID is required",
-			Value:     fmt.Sprintf("%d", comment.User.Id),
+			Value:     comment.UserId.String(),
 			RequestID: requestID,
 		})
 	}
 
 	// Validate article reference (must have valid article ID)
-	if comment.Article.Id == uuid.Nil {
+	if comment.ArticleId == uuid.Nil {
 		fieldErrors = append(fieldErrors, FieldError{
 			Field:     "article_id",
 			Message:   "Valid article ID is required",
-			Value:     fmt.Sprintf("%d", comment.Article.Id),
+			Value:     comment.ArticleId.String(),
 			RequestID: requestID,
 		})
 	}
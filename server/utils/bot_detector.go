@@ -0,0 +1,29 @@
+package utils
+
+import "strings"
+
+// botUserAgentMarkers are lowercase fragments found in the User-Agent of crawlers,
+// link previewers, monitoring services and HTTP libraries
+var botUserAgentMarkers = []string{
+	"bot", "crawler", "spider", "slurp", "crawl",
+	"facebookexternalhit", "embedly", "quora link preview", "whatsapp", "telegram",
+	"bingpreview", "headlesschrome", "phantomjs", "lighthouse", "pingdom", "uptimerobot",
+	"curl/", "wget/", "python-requests", "python-urllib", "go-http-client", "java/",
+	"okhttp", "axios/", "node-fetch", "postmanruntime", "insomnia", "httpclient",
+}
+
+// IsBotUserAgent reports whether a User-Agent belongs to a known bot or non-browser client.
+// Requests without a User-Agent are treated as bots as well.
+func IsBotUserAgent(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+
+	for _, marker := range botUserAgentMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
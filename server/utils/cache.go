@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Cache stores encoded read results under a key for a limited time. An entry can be
+// tagged with the records it was built from, so a write to one record drops every
+// entry that contains it without knowing their keys.
+type Cache interface {
+	// Get returns the value stored under key, found is false when it is missing or expired
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	// Set stores value under key for ttl, a ttl of zero keeps it until it is evicted
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+	Delete(ctx context.Context, keys ...string) error
+	// InvalidateTags deletes every entry stored with one of the tags
+	InvalidateTags(ctx context.Context, tags ...string) error
+	// Generation returns a counter that moves before every Delete and InvalidateTags. A
+	// reader storing a value it loaded compares it to the counter read before the load,
+	// a change means the value may predate a write that was invalidated meanwhile.
+	Generation(ctx context.Context) (uint64, error)
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	tags      []string
+	expiresAt time.Time
+}
+
+// lruCache keeps the most recently used entries in process memory
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	// order holds the entries from most to least recently used
+	order *list.List
+	// tagged maps each tag to the keys stored with it
+	tagged map[string]map[string]struct{}
+	// generation counts the deletes and invalidations
+	generation uint64
+	now        func() time.Time
+}
+
+// NewLRUCache creates an in-memory cache holding at most capacity entries. When it is
+// full the least recently used entry is evicted, expired entries are dropped on access.
+func NewLRUCache(capacity int) Cache {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &lruCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		tagged:   make(map[string]map[string]struct{}),
+		now:      time.Now,
+	}
+}
+
+// Get implements Cache.
+func (c *lruCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		c.remove(element)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+// Set implements Cache.
+func (c *lruCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	entry := &lruEntry{key: key, value: value, tags: tags}
+	if ttl > 0 {
+		entry.expiresAt = c.now().Add(ttl)
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for _, tag := range tags {
+		keys, ok := c.tagged[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.tagged[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// Delete implements Cache.
+func (c *lruCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+	return nil
+}
+
+// InvalidateTags implements Cache.
+func (c *lruCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	for _, tag := range tags {
+		for key := range c.tagged[tag] {
+			if element, ok := c.entries[key]; ok {
+				c.remove(element)
+			}
+		}
+		delete(c.tagged, tag)
+	}
+	return nil
+}
+
+// Generation implements Cache.
+func (c *lruCache) Generation(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation, nil
+}
+
+// remove drops an entry and its tag references, the caller holds the lock
+func (c *lruCache) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*lruEntry)
+	delete(c.entries, entry.key)
+	for _, tag := range entry.tags {
+		keys := c.tagged[tag]
+		delete(keys, entry.key)
+		if len(keys) == 0 {
+			delete(c.tagged, tag)
+		}
+	}
+}
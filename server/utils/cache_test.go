@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache_GetSet(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(10)
+
+	_, found, err := cache.Get(ctx, "article:slug:hello")
+	require.NoError(t, err)
+	assert.False(t, found, "should miss before the entry is stored")
+
+	require.NoError(t, cache.Set(ctx, "article:slug:hello", []byte("v1"), time.Minute))
+	value, found, err := cache.Get(ctx, "article:slug:hello")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("v1"), value)
+
+	require.NoError(t, cache.Set(ctx, "article:slug:hello", []byte("v2"), time.Minute))
+	value, _, _ = cache.Get(ctx, "article:slug:hello")
+	assert.Equal(t, []byte("v2"), value, "should overwrite an existing entry")
+}
+
+func TestLRUCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(10).(*lruCache)
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	require.NoError(t, cache.Set(ctx, "short", []byte("a"), time.Minute))
+	require.NoError(t, cache.Set(ctx, "forever", []byte("b"), 0))
+
+	now = now.Add(59 * time.Second)
+	_, found, _ := cache.Get(ctx, "short")
+	assert.True(t, found, "should keep the entry until the ttl passes")
+
+	now = now.Add(time.Second)
+	_, found, _ = cache.Get(ctx, "short")
+	assert.False(t, found, "should drop the entry once the ttl passed")
+
+	now = now.Add(24 * time.Hour)
+	_, found, _ = cache.Get(ctx, "forever")
+	assert.True(t, found, "should keep entries without ttl")
+}
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(2)
+
+	require.NoError(t, cache.Set(ctx, "a", []byte("a"), time.Minute))
+	require.NoError(t, cache.Set(ctx, "b", []byte("b"), time.Minute))
+
+	// Reading a makes b the least recently used entry
+	_, found, _ := cache.Get(ctx, "a")
+	require.True(t, found)
+
+	require.NoError(t, cache.Set(ctx, "c", []byte("c"), time.Minute))
+
+	_, found, _ = cache.Get(ctx, "b")
+	assert.False(t, found, "should evict the least recently used entry")
+	_, found, _ = cache.Get(ctx, "a")
+	assert.True(t, found)
+	_, found, _ = cache.Get(ctx, "c")
+	assert.True(t, found)
+}
+
+func TestLRUCache_DeleteAndInvalidateTags(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(10)
+
+	require.NoError(t, cache.Set(ctx, "article:slug:a", []byte("a"), time.Minute, "article:1", "category:1"))
+	require.NoError(t, cache.Set(ctx, "article:slug:b", []byte("b"), time.Minute, "article:2", "category:1"))
+	require.NoError(t, cache.Set(ctx, "article:slug:c", []byte("c"), time.Minute, "article:3", "category:2"))
+	require.NoError(t, cache.Set(ctx, "categories:all", []byte("all"), time.Minute))
+
+	require.NoError(t, cache.Delete(ctx, "categories:all", "missing"))
+	_, found, _ := cache.Get(ctx, "categories:all")
+	assert.False(t, found, "should delete by key")
+
+	require.NoError(t, cache.InvalidateTags(ctx, "category:1"))
+	_, found, _ = cache.Get(ctx, "article:slug:a")
+	assert.False(t, found, "should drop every entry with the tag")
+	_, found, _ = cache.Get(ctx, "article:slug:b")
+	assert.False(t, found, "should drop every entry with the tag")
+	_, found, _ = cache.Get(ctx, "article:slug:c")
+	assert.True(t, found, "should keep entries without the tag")
+
+	// Overwriting an entry replaces its tags
+	require.NoError(t, cache.Set(ctx, "article:slug:c", []byte("c2"), time.Minute, "article:3"))
+	require.NoError(t, cache.InvalidateTags(ctx, "category:2"))
+	_, found, _ = cache.Get(ctx, "article:slug:c")
+	assert.True(t, found, "should forget the tags of the replaced entry")
+}
+
+func TestLRUCache_Generation(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(10)
+
+	start, err := cache.Generation(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Set(ctx, "article:slug:a", []byte("a"), time.Minute, "article:1"))
+	generation, _ := cache.Generation(ctx)
+	assert.Equal(t, start, generation, "should not move on a store")
+
+	require.NoError(t, cache.Delete(ctx, "article:slug:a"))
+	generation, _ = cache.Generation(ctx)
+	assert.Equal(t, start+1, generation, "should move on a delete")
+
+	require.NoError(t, cache.InvalidateTags(ctx, "article:1"))
+	generation, _ = cache.Generation(ctx)
+	assert.Equal(t, start+2, generation, "should move on an invalidation")
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IsNotModified evaluates the conditional GET headers of a request against the current
+// validators of a resource. If-None-Match takes precedence over If-Modified-Since, as
+// required by RFC 9110. An empty etag or zero lastModified skips that validator.
+func IsNotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if etag == "" {
+			return false
+		}
+		return ETagMatches(ifNoneMatch, etag)
+	}
+
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		if err != nil {
+			return false
+		}
+		// HTTP dates have second precision
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// ETagMatches reports whether a list of entity tags from an If-None-Match header
+// contains etag, using weak comparison
+func ETagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// VersionETag is the strong entity tag of a resource at a given row version. Clients send
+// it back in If-Match to update the resource only if nobody changed it in the meantime.
+func VersionETag(version int) string {
+	return `"v` + strconv.Itoa(version) + `"`
+}
+
+// IfMatchVersion reads the row version an If-Match header was made from. Only strong tags
+// built by VersionETag are accepted, optionally followed by "-" and a body hash as sent on
+// cached reads: weak tags never match under If-Match (RFC 9110) and a wildcard would skip
+// the version check altogether.
+func IfMatchVersion(header string) (int, bool) {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if !strings.HasPrefix(candidate, `"v`) || !strings.HasSuffix(candidate, `"`) || len(candidate) < 4 {
+			continue
+		}
+		value := candidate[2 : len(candidate)-1]
+		if dash := strings.IndexByte(value, '-'); dash >= 0 {
+			value = value[:dash]
+		}
+		version, err := strconv.Atoi(value)
+		if err != nil || version < 1 {
+			continue
+		}
+		return version, true
+	}
+	return 0, false
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsNotModified(t *testing.T) {
+	lastModified := time.Date(2024, 3, 1, 10, 0, 0, 500, time.UTC)
+	etag := `"abc123"`
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    bool
+	}{
+		{name: "no conditional headers", method: http.MethodGet, want: false},
+		{name: "matching etag", method: http.MethodGet, headers: map[string]string{"If-None-Match": `"abc123"`}, want: true},
+		{name: "matching weak etag", method: http.MethodGet, headers: map[string]string{"If-None-Match": `W/"abc123"`}, want: true},
+		{name: "etag in list", method: http.MethodGet, headers: map[string]string{"If-None-Match": `"other", "abc123"`}, want: true},
+		{name: "wildcard", method: http.MethodGet, headers: map[string]string{"If-None-Match": "*"}, want: true},
+		{name: "different etag", method: http.MethodGet, headers: map[string]string{"If-None-Match": `"other"`}, want: false},
+		{
+			name:    "etag takes precedence over date",
+			method:  http.MethodGet,
+			headers: map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			want:    false,
+		},
+		{name: "not modified since", method: http.MethodGet, headers: map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)}, want: true},
+		{name: "modified since", method: http.MethodGet, headers: map[string]string{"If-Modified-Since": lastModified.Add(-time.Minute).Format(http.TimeFormat)}, want: false},
+		{name: "invalid date", method: http.MethodGet, headers: map[string]string{"If-Modified-Since": "yesterday"}, want: false},
+		{name: "head request", method: http.MethodHead, headers: map[string]string{"If-None-Match": `"abc123"`}, want: true},
+		{name: "post request", method: http.MethodPost, headers: map[string]string{"If-None-Match": `"abc123"`}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/feed.xml", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			assert.Equal(t, tt.want, IsNotModified(req, etag, lastModified))
+		})
+	}
+}
+
+func TestVersionETag(t *testing.T) {
+	assert.Equal(t, `"v1"`, VersionETag(1))
+	assert.Equal(t, `"v42"`, VersionETag(42))
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   int
+		wantOK bool
+	}{
+		{name: "version tag", header: `"v3"`, want: 3, wantOK: true},
+		{name: "round trip", header: VersionETag(17), want: 17, wantOK: true},
+		{name: "tag in list", header: `"other", "v5"`, want: 5, wantOK: true},
+		{name: "version tag with body hash", header: `"v7-3f2a9c"`, want: 7, wantOK: true},
+		{name: "hash without version", header: `"v-3f2a9c"`, wantOK: false},
+		{name: "empty", header: "", wantOK: false},
+		{name: "wildcard", header: "*", wantOK: false},
+		{name: "weak tag", header: `W/"v3"`, wantOK: false},
+		{name: "unquoted", header: "v3", wantOK: false},
+		{name: "not a version", header: `"abc123"`, wantOK: false},
+		{name: "zero version", header: `"v0"`, wantOK: false},
+		{name: "empty version", header: `"v"`, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := IfMatchVersion(tt.header)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
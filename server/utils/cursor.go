@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor is returned by DecodeCursor for a cursor it did not encode
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor turns the position of a page into an opaque, URL safe cursor string
+func EncodeCursor(position interface{}) (string, error) {
+	raw, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeCursor reads a cursor made by EncodeCursor into position
+func DecodeCursor(cursor string, position interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, position); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
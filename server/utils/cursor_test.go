@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	Id        string    `json:"id"`
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	position := testCursor{
+		CreatedAt: time.Date(2024, 3, 1, 10, 0, 0, 123456000, time.UTC),
+		Id:        "0190a1b2-0000-7000-8000-000000000001",
+	}
+
+	cursor, err := EncodeCursor(position)
+	require.NoError(t, err)
+	assert.NotContains(t, cursor, "=", "cursor is unpadded")
+	assert.NotContains(t, cursor, "/")
+	assert.NotContains(t, cursor, "+")
+
+	var decoded testCursor
+	require.NoError(t, DecodeCursor(cursor, &decoded))
+	assert.True(t, position.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, position.Id, decoded.Id)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "not a cursor!"},
+		{name: "not json", cursor: "bm90IGpzb24"},
+		{name: "wrong shape", cursor: "WzEsMiwzXQ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded testCursor
+			assert.ErrorIs(t, DecodeCursor(tt.cursor, &decoded), ErrInvalidCursor)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -19,6 +20,8 @@ const (
 	ErrCancelled     = "REQUEST_CANCELLED"
 	ErrConflict      = "CONFLICT_ERROR"
 	ErrBadRequest    = "BAD_REQUEST"
+	ErrPreconditionFailed   = "PRECONDITION_FAILED"
+	ErrPreconditionRequired = "PRECONDITION_REQUIRED"
 )
 
 // AppError represents a custom application error with context information
@@ -60,6 +63,8 @@ type ErrorWrapper interface {
 	CancellationError(ctx context.Context, operation string) *AppError
 	ConflictError(ctx context.Context, resource string, message string) *AppError
 	BadRequestError(ctx context.Context, message string) *AppError
+	PreconditionFailedError(ctx context.Context, resource string) *AppError
+	PreconditionRequiredError(ctx context.Context, resource string) *AppError
 }
 
 // errorWrapper implements ErrorWrapper interface
@@ -302,6 +307,46 @@ func (ew *errorWrapper) BadRequestError(ctx context.Context, message string) *Ap
 	}
 }
 
+// PreconditionFailedError creates a precondition failed error with context, raised when
+// the version a client updates from is no longer the current one
+func (ew *errorWrapper) PreconditionFailedError(ctx context.Context, resource string) *AppError {
+	requestID, userID := ew.extractContextInfo(ctx)
+	
+	message := "Resource has been modified since it was read"
+	if resource != "" {
+		message = fmt.Sprintf("%s has been modified since it was read, fetch it again and retry", resource)
+	}
+	
+	return &AppError{
+		Code:       ErrPreconditionFailed,
+		Message:    message,
+		StatusCode: 412,
+		RequestID:  requestID,
+		UserID:     userID,
+		Timestamp:  time.Now(),
+	}
+}
+
+// PreconditionRequiredError creates a precondition required error with context, raised when
+// an update does not say which version of the resource it was made from
+func (ew *errorWrapper) PreconditionRequiredError(ctx context.Context, resource string) *AppError {
+	requestID, userID := ew.extractContextInfo(ctx)
+	
+	message := "If-Match header is required"
+	if resource != "" {
+		message = fmt.Sprintf("If-Match header with the ETag of the %s is required", strings.ToLower(resource))
+	}
+	
+	return &AppError{
+		Code:       ErrPreconditionRequired,
+		Message:    message,
+		StatusCode: 428,
+		RequestID:  requestID,
+		UserID:     userID,
+		Timestamp:  time.Now(),
+	}
+}
+
 // NewErrorWrapper creates a new error wrapper
 func NewErrorWrapper() ErrorWrapper {
 	return &errorWrapper{}
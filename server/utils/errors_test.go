@@ -199,6 +199,30 @@ func TestErrorWrapper(t *testing.T) {
 		assert.Equal(t, "req-123", appErr.RequestID)
 	})
 
+	t.Run("PreconditionFailedError", func(t *testing.T) {
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "request_id", "req-123")
+
+		appErr := wrapper.PreconditionFailedError(ctx, "Article")
+
+		assert.Equal(t, ErrPreconditionFailed, appErr.Code)
+		assert.Equal(t, "Article has been modified since it was read, fetch it again and retry", appErr.Message)
+		assert.Equal(t, 412, appErr.StatusCode)
+		assert.Equal(t, "req-123", appErr.RequestID)
+	})
+
+	t.Run("PreconditionRequiredError", func(t *testing.T) {
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "request_id", "req-123")
+
+		appErr := wrapper.PreconditionRequiredError(ctx, "Article")
+
+		assert.Equal(t, ErrPreconditionRequired, appErr.Code)
+		assert.Equal(t, "If-Match header with the ETag of the article is required", appErr.Message)
+		assert.Equal(t, 428, appErr.StatusCode)
+		assert.Equal(t, "req-123", appErr.RequestID)
+	})
+
 	t.Run("ExtractContextInfo_EmptyContext", func(t *testing.T) {
 		appErr := wrapper.ValidationError(context.Background(), "field", "message")
 
@@ -0,0 +1,259 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+)
+
+// Feed is a format independent syndication feed, rendered by RenderRSS, RenderAtom and RenderJSONFeed
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // Home page of the feed
+	FeedURL     string // URL the feed itself is served from
+	Updated     time.Time
+	Items       []FeedItem
+}
+
+// FeedItem is a single entry of a Feed
+type FeedItem struct {
+	Id          string
+	Title       string
+	Link        string
+	Summary     string
+	ContentHTML string
+	AuthorName  string
+	Categories  []string
+	Published   time.Time
+	Updated     time.Time
+}
+
+const (
+	RSSContentType      = "application/rss+xml; charset=utf-8"
+	AtomContentType     = "application/atom+xml; charset=utf-8"
+	JSONFeedContentType = "application/feed+json; charset=utf-8"
+)
+
+type xmlCDATA struct {
+	Text string `xml:",cdata"`
+}
+
+type rssDocument struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	DCNS      string     `xml:"xmlns:dc,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	GUID        rssGUID   `xml:"guid"`
+	Description string    `xml:"description,omitempty"`
+	Content     *xmlCDATA `xml:"content:encoded,omitempty"`
+	Creator     string    `xml:"dc:creator,omitempty"`
+	Categories  []string  `xml:"category"`
+	PubDate     string    `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RenderRSS renders the feed as RSS 2.0
+func RenderRSS(feed Feed) ([]byte, error) {
+	doc := rssDocument{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		DCNS:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       feed.Title,
+			Link:        feed.Link,
+			Description: feed.Description,
+			AtomLink:    atomLink{Href: feed.FeedURL, Rel: "self", Type: "application/rss+xml"},
+			Items:       []rssItem{},
+		},
+	}
+	if !feed.Updated.IsZero() {
+		doc.Channel.LastBuildDate = feed.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, item := range feed.Items {
+		rss := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{IsPermaLink: false, Value: item.Id},
+			Description: item.Summary,
+			Creator:     item.AuthorName,
+			Categories:  item.Categories,
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+		}
+		if item.ContentHTML != "" {
+			rss.Content = &xmlCDATA{Text: item.ContentHTML}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, rss)
+	}
+
+	return marshalXML(doc)
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Id       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Id         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Links      []atomLink     `xml:"link"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// RenderAtom renders the feed as Atom 1.0
+func RenderAtom(feed Feed) ([]byte, error) {
+	doc := atomFeed{
+		Xmlns:    "http://www.w3.org/2005/Atom",
+		Id:       feed.FeedURL,
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		Updated:  feed.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+			{Href: feed.FeedURL, Rel: "self", Type: "application/atom+xml"},
+		},
+		Entries: []atomEntry{},
+	}
+
+	for _, item := range feed.Items {
+		entry := atomEntry{
+			Id:        "urn:uuid:" + item.Id,
+			Title:     item.Title,
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Links:     []atomLink{{Href: item.Link, Rel: "alternate", Type: "text/html"}},
+		}
+		if item.AuthorName != "" {
+			entry.Author = &atomPerson{Name: item.AuthorName}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		if item.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Body: item.Summary}
+		}
+		if item.ContentHTML != "" {
+			entry.Content = &atomText{Type: "html", Body: item.ContentHTML}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return marshalXML(doc)
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	FeedURL     string         `json:"feed_url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	Id            string           `json:"id"`
+	URL           string           `json:"url,omitempty"`
+	Title         string           `json:"title,omitempty"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished string           `json:"date_published,omitempty"`
+	DateModified  string           `json:"date_modified,omitempty"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// RenderJSONFeed renders the feed as JSON Feed 1.1
+func RenderJSONFeed(feed Feed) ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		FeedURL:     feed.FeedURL,
+		Description: feed.Description,
+		Items:       []jsonFeedItem{},
+	}
+
+	for _, item := range feed.Items {
+		entry := jsonFeedItem{
+			Id:            item.Id,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentHTML:   item.ContentHTML,
+			Summary:       item.Summary,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+			DateModified:  item.Updated.UTC().Format(time.RFC3339),
+			Tags:          item.Categories,
+		}
+		if item.AuthorName != "" {
+			entry.Authors = []jsonFeedAuthor{{Name: item.AuthorName}}
+		}
+		doc.Items = append(doc.Items, entry)
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleFeed() Feed {
+	published := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	return Feed{
+		Title:       "Develapar",
+		Description: "Latest articles",
+		Link:        "https://example.com",
+		FeedURL:     "https://example.com/feed.xml",
+		Updated:     published.Add(time.Hour),
+		Items: []FeedItem{
+			{
+				Id:          "0190a1b2-0000-7000-8000-000000000001",
+				Title:       "Tips & Tricks <Go>",
+				Link:        "https://example.com/articles/tips-tricks-go",
+				Summary:     "Short summary",
+				ContentHTML: "<p>Hello <strong>world</strong></p>",
+				AuthorName:  "Budi",
+				Categories:  []string{"Programming", "go"},
+				Published:   published,
+				Updated:     published.Add(time.Hour),
+			},
+		},
+	}
+}
+
+func TestRenderRSS(t *testing.T) {
+	body, err := RenderRSS(sampleFeed())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(body), xml.Header))
+
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				Title      string   `xml:"title"`
+				Link       string   `xml:"link"`
+				GUID       string   `xml:"guid"`
+				Content    string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+				Categories []string `xml:"category"`
+				PubDate    string   `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal(body, &doc))
+
+	assert.Equal(t, "2.0", doc.Version)
+	assert.Equal(t, "Develapar", doc.Channel.Title)
+	require.Len(t, doc.Channel.Items, 1)
+	item := doc.Channel.Items[0]
+	assert.Equal(t, "Tips & Tricks <Go>", item.Title)
+	assert.Equal(t, "0190a1b2-0000-7000-8000-000000000001", item.GUID)
+	assert.Equal(t, "<p>Hello <strong>world</strong></p>", item.Content)
+	assert.Equal(t, []string{"Programming", "go"}, item.Categories)
+	assert.Equal(t, "Fri, 01 Mar 2024 10:00:00 +0000", item.PubDate)
+}
+
+func TestRenderAtom(t *testing.T) {
+	body, err := RenderAtom(sampleFeed())
+	require.NoError(t, err)
+
+	var doc struct {
+		Id      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			Id      string `xml:"id"`
+			Title   string `xml:"title"`
+			Author  string `xml:"author>name"`
+			Content struct {
+				Type string `xml:"type,attr"`
+				Body string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(body, &doc))
+
+	assert.Equal(t, "https://example.com/feed.xml", doc.Id)
+	assert.Equal(t, "2024-03-01T11:00:00Z", doc.Updated)
+	require.Len(t, doc.Entries, 1)
+	assert.Equal(t, "urn:uuid:0190a1b2-0000-7000-8000-000000000001", doc.Entries[0].Id)
+	assert.Equal(t, "Budi", doc.Entries[0].Author)
+	assert.Equal(t, "html", doc.Entries[0].Content.Type)
+	assert.Equal(t, "<p>Hello <strong>world</strong></p>", doc.Entries[0].Content.Body)
+}
+
+func TestRenderJSONFeed(t *testing.T) {
+	body, err := RenderJSONFeed(sampleFeed())
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &doc))
+
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", doc["version"])
+	assert.Equal(t, "https://example.com/feed.xml", doc["feed_url"])
+	items := doc["items"].([]interface{})
+	require.Len(t, items, 1)
+	item := items[0].(map[string]interface{})
+	assert.Equal(t, "Tips & Tricks <Go>", item["title"])
+	assert.Equal(t, "2024-03-01T10:00:00Z", item["date_published"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "Budi"}}, item["authors"])
+}
+
+func TestRenderFeeds_EmptyFeed(t *testing.T) {
+	feed := Feed{Title: "Empty", Link: "https://example.com", FeedURL: "https://example.com/feed.json"}
+
+	body, err := RenderJSONFeed(feed)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"items": []`)
+
+	_, err = RenderRSS(feed)
+	require.NoError(t, err)
+	_, err = RenderAtom(feed)
+	require.NoError(t, err)
+}
@@ -0,0 +1,119 @@
+package utils
+
+import "strings"
+
+// Diff operations returned by DiffLines
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// maxDiffCells bounds the LCS table DiffLines builds. Past it the changed
+// region is reported as a whole replacement instead of a minimal diff.
+const maxDiffCells = 4_000_000
+
+// DiffLine is a single line of a line-level diff. OldLine and NewLine are
+// 1-based line numbers and zero when the line does not exist on that side.
+type DiffLine struct {
+	Op      string
+	OldLine int
+	NewLine int
+	Text    string
+}
+
+// DiffLines computes a line-level diff between oldText and newText using the
+// longest common subsequence of their lines. Common leading and trailing lines
+// are stripped first so typical edits stay cheap on long articles. When the
+// remaining region is too large to compare line by line it is reported as a
+// delete of every old line followed by an insert of every new one.
+func DiffLines(oldText, newText string) []DiffLine {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+
+	// Strip common prefix
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+
+	// Strip common suffix
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	a := oldLines[prefix : len(oldLines)-suffix]
+	b := newLines[prefix : len(newLines)-suffix]
+
+	result := make([]DiffLine, 0, len(oldLines)+len(newLines)-2*suffix-prefix)
+	for k := 0; k < prefix; k++ {
+		result = append(result, DiffLine{Op: DiffEqual, OldLine: k + 1, NewLine: k + 1, Text: oldLines[k]})
+	}
+
+	if len(a) > 0 && len(b) > 0 && len(a) > maxDiffCells/len(b) {
+		for i := range a {
+			result = append(result, DiffLine{Op: DiffDelete, OldLine: prefix + i + 1, Text: a[i]})
+		}
+		for j := range b {
+			result = append(result, DiffLine{Op: DiffInsert, NewLine: prefix + j + 1, Text: b[j]})
+		}
+		return appendCommonSuffix(result, oldLines, newLines, suffix)
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			result = append(result, DiffLine{Op: DiffEqual, OldLine: prefix + i + 1, NewLine: prefix + j + 1, Text: a[i]})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			result = append(result, DiffLine{Op: DiffInsert, NewLine: prefix + j + 1, Text: b[j]})
+			j++
+		default:
+			result = append(result, DiffLine{Op: DiffDelete, OldLine: prefix + i + 1, Text: a[i]})
+			i++
+		}
+	}
+
+	return appendCommonSuffix(result, oldLines, newLines, suffix)
+}
+
+// appendCommonSuffix adds the suffix lines DiffLines stripped as equal lines
+func appendCommonSuffix(result []DiffLine, oldLines, newLines []string, suffix int) []DiffLine {
+	for k := 0; k < suffix; k++ {
+		oldIdx := len(oldLines) - suffix + k
+		newIdx := len(newLines) - suffix + k
+		result = append(result, DiffLine{Op: DiffEqual, OldLine: oldIdx + 1, NewLine: newIdx + 1, Text: oldLines[oldIdx]})
+	}
+	return result
+}
+
+// splitLines splits text on newlines, normalising CRLF and ignoring a single trailing newline
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    []DiffLine
+	}{
+		{
+			name:    "should return only equal lines for identical text",
+			oldText: "a\nb",
+			newText: "a\nb",
+			want: []DiffLine{
+				{Op: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Op: DiffEqual, OldLine: 2, NewLine: 2, Text: "b"},
+			},
+		},
+		{
+			name:    "should detect inserted line",
+			oldText: "a\nc",
+			newText: "a\nb\nc",
+			want: []DiffLine{
+				{Op: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Op: DiffInsert, NewLine: 2, Text: "b"},
+				{Op: DiffEqual, OldLine: 2, NewLine: 3, Text: "c"},
+			},
+		},
+		{
+			name:    "should detect deleted line",
+			oldText: "a\nb\nc",
+			newText: "a\nc",
+			want: []DiffLine{
+				{Op: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Op: DiffDelete, OldLine: 2, Text: "b"},
+				{Op: DiffEqual, OldLine: 3, NewLine: 2, Text: "c"},
+			},
+		},
+		{
+			name:    "should show changed line as delete then insert",
+			oldText: "a\nb\nc",
+			newText: "a\nx\nc",
+			want: []DiffLine{
+				{Op: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Op: DiffDelete, OldLine: 2, Text: "b"},
+				{Op: DiffInsert, NewLine: 2, Text: "x"},
+				{Op: DiffEqual, OldLine: 3, NewLine: 3, Text: "c"},
+			},
+		},
+		{
+			name:    "should handle empty old text",
+			oldText: "",
+			newText: "a",
+			want: []DiffLine{
+				{Op: DiffInsert, NewLine: 1, Text: "a"},
+			},
+		},
+		{
+			name:    "should ignore CRLF and trailing newline differences",
+			oldText: "a\r\nb\r\n",
+			newText: "a\nb",
+			want: []DiffLine{
+				{Op: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Op: DiffEqual, OldLine: 2, NewLine: 2, Text: "b"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DiffLines(tt.oldText, tt.newText))
+		})
+	}
+}
+
+func TestDiffLines_LargeInputFallsBackToReplace(t *testing.T) {
+	// Build two texts whose changed regions exceed maxDiffCells
+	size := 2100
+	oldLines := make([]string, size)
+	newLines := make([]string, size)
+	for i := 0; i < size; i++ {
+		oldLines[i] = fmt.Sprintf("old %d", i)
+		newLines[i] = fmt.Sprintf("new %d", i)
+	}
+	oldText := "head\n" + strings.Join(oldLines, "\n") + "\ntail"
+	newText := "head\n" + strings.Join(newLines, "\n") + "\ntail"
+
+	diff := DiffLines(oldText, newText)
+	require.Len(t, diff, 2+2*size)
+
+	assert.Equal(t, DiffLine{Op: DiffEqual, OldLine: 1, NewLine: 1, Text: "head"}, diff[0])
+	for i := 0; i < size; i++ {
+		assert.Equal(t, DiffLine{Op: DiffDelete, OldLine: i + 2, Text: oldLines[i]}, diff[1+i])
+		assert.Equal(t, DiffLine{Op: DiffInsert, NewLine: i + 2, Text: newLines[i]}, diff[1+size+i])
+	}
+	assert.Equal(t, DiffLine{Op: DiffEqual, OldLine: size + 2, NewLine: size + 2, Text: "tail"}, diff[len(diff)-1])
+}
@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"develapar-server/model"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+const (
+	// DefaultExcerptLength is the maximum number of characters in a generated excerpt
+	DefaultExcerptLength = 200
+	// DefaultWordsPerMinute is the reading speed used for the reading time estimate
+	DefaultWordsPerMinute = 200
+)
+
+// RenderedMarkdown is the output of rendering an article body
+type RenderedMarkdown struct {
+	HTML               string
+	TOC                []model.TOCEntry
+	Excerpt            string
+	ReadingTimeMinutes int
+}
+
+// MarkdownRenderer interface for turning Markdown into sanitized HTML
+type MarkdownRenderer interface {
+	Render(source string) (RenderedMarkdown, error)
+}
+
+type markdownRenderer struct {
+	markdown       goldmark.Markdown
+	policy         *bluemonday.Policy
+	excerptLength  int
+	wordsPerMinute int
+}
+
+// NewMarkdownRenderer creates a new instance of MarkdownRenderer.
+// Raw HTML in the source is dropped by the Markdown parser and the rendered
+// output is passed through an allowlist sanitizer as a second line of defence.
+func NewMarkdownRenderer() MarkdownRenderer {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[a-zA-Z0-9_+-]+$`)).OnElements("code")
+
+	return &markdownRenderer{
+		markdown:       goldmark.New(goldmark.WithExtensions(extension.GFM)),
+		policy:         policy,
+		excerptLength:  DefaultExcerptLength,
+		wordsPerMinute: DefaultWordsPerMinute,
+	}
+}
+
+// ContentHash returns a stable fingerprint of article content, used to skip
+// re-rendering when the content did not change
+func ContentHash(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *markdownRenderer) Render(source string) (RenderedMarkdown, error) {
+	src := []byte(source)
+	doc := r.markdown.Parser().Parse(text.NewReader(src))
+
+	var (
+		toc       = []model.TOCEntry{}
+		anchors   = make(map[string]int)
+		plainText strings.Builder
+		excerpt   strings.Builder
+	)
+
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *ast.Heading:
+			title := nodeText(node, src)
+			anchor := uniqueAnchor(anchors, title)
+			node.SetAttributeString("id", []byte(anchor))
+			toc = append(toc, model.TOCEntry{Level: node.Level, Text: title, Anchor: anchor})
+			plainText.WriteString(title + " ")
+			return ast.WalkSkipChildren, nil
+		case *ast.Paragraph:
+			paragraph := nodeText(node, src)
+			plainText.WriteString(paragraph + " ")
+			if excerpt.Len() < r.excerptLength {
+				excerpt.WriteString(paragraph + " ")
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.CodeBlock, *ast.FencedCodeBlock:
+			lines := node.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				segment := lines.At(i)
+				plainText.Write(segment.Value(src))
+			}
+			plainText.WriteString(" ")
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return RenderedMarkdown{}, fmt.Errorf("failed to walk markdown: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.markdown.Renderer().Render(&buf, src, doc); err != nil {
+		return RenderedMarkdown{}, fmt.Errorf("failed to render markdown: %v", err)
+	}
+
+	return RenderedMarkdown{
+		HTML:               r.policy.Sanitize(buf.String()),
+		TOC:                toc,
+		Excerpt:            truncateWords(strings.TrimSpace(excerpt.String()), r.excerptLength),
+		ReadingTimeMinutes: readingTime(plainText.String(), r.wordsPerMinute),
+	}, nil
+}
+
+// nodeText collects the visible text of a node and its children
+func nodeText(n ast.Node, source []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := child.(type) {
+		case *ast.Text:
+			sb.Write(node.Segment.Value(source))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				sb.WriteString(" ")
+			}
+		case *ast.String:
+			sb.Write(node.Value)
+		case *ast.AutoLink:
+			sb.Write(node.Label(source))
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// uniqueAnchor turns a heading into a slug, adding a numeric suffix when the
+// same heading text appears more than once
+func uniqueAnchor(seen map[string]int, title string) string {
+	anchor := slugify(title)
+	if anchor == "" {
+		anchor = "section"
+	}
+
+	count := seen[anchor]
+	seen[anchor] = count + 1
+	if count == 0 {
+		return anchor
+	}
+
+	suffixed := fmt.Sprintf("%s-%d", anchor, count)
+	// A heading may literally be called "intro-1", keep going until the anchor is free
+	for seen[suffixed] > 0 {
+		count++
+		suffixed = fmt.Sprintf("%s-%d", anchor, count)
+	}
+	seen[anchor] = count + 1
+	seen[suffixed] = 1
+	return suffixed
+}
+
+// truncateWords cuts s to at most limit characters on a word boundary
+func truncateWords(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+
+	runes := []rune(s)
+	cut := string(runes[:limit])
+	if idx := strings.LastIndex(cut, " "); idx > 0 {
+		cut = cut[:idx]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// readingTime estimates whole minutes needed to read s, at least one minute for any text
+func readingTime(s string, wordsPerMinute int) int {
+	words := len(strings.Fields(s))
+	if words == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(words) / float64(wordsPerMinute)))
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"develapar-server/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownRenderer_RendersHTML(t *testing.T) {
+	renderer := NewMarkdownRenderer()
+
+	rendered, err := renderer.Render("Hello **world**\n\n```go\nfmt.Println(1)\n```")
+	require.NoError(t, err)
+
+	assert.Contains(t, rendered.HTML, "<strong>world</strong>")
+	assert.Contains(t, rendered.HTML, `<code class="language-go">`)
+}
+
+func TestMarkdownRenderer_SanitizesHTML(t *testing.T) {
+	renderer := NewMarkdownRenderer()
+
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{name: "script tag", source: "<script>alert(1)</script>\n\ntext"},
+		{name: "inline event handler", source: `<img src="x" onerror="alert(1)">`},
+		{name: "javascript link", source: "[click](javascript:alert(1))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := renderer.Render(tt.source)
+			require.NoError(t, err)
+
+			html := strings.ToLower(rendered.HTML)
+			assert.NotContains(t, html, "<script")
+			assert.NotContains(t, html, "onerror")
+			assert.NotContains(t, html, "javascript:")
+		})
+	}
+}
+
+func TestMarkdownRenderer_HeadingAnchorsAndTOC(t *testing.T) {
+	renderer := NewMarkdownRenderer()
+
+	source := "# Getting Started\n\nintro\n\n## Install `go`\n\n## Getting Started\n\ntext"
+	rendered, err := renderer.Render(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, []model.TOCEntry{
+		{Level: 1, Text: "Getting Started", Anchor: "getting-started"},
+		{Level: 2, Text: "Install go", Anchor: "install-go"},
+		{Level: 2, Text: "Getting Started", Anchor: "getting-started-1"},
+	}, rendered.TOC)
+	assert.Contains(t, rendered.HTML, `<h1 id="getting-started">`)
+	assert.Contains(t, rendered.HTML, `<h2 id="getting-started-1">`)
+}
+
+func TestMarkdownRenderer_HeadingAnchorsWithoutASCII(t *testing.T) {
+	renderer := NewMarkdownRenderer()
+
+	// Headings without an ASCII reading fall back to "section", never to the "untitled" article slug
+	rendered, err := renderer.Render("# 入門\n\ntext\n\n## 概要\n\n## Untitled")
+	require.NoError(t, err)
+
+	assert.Equal(t, []model.TOCEntry{
+		{Level: 1, Text: "入門", Anchor: "section"},
+		{Level: 2, Text: "概要", Anchor: "section-1"},
+		{Level: 2, Text: "Untitled", Anchor: "untitled"},
+	}, rendered.TOC)
+	assert.Contains(t, rendered.HTML, `<h1 id="section">`)
+}
+
+func TestMarkdownRenderer_Excerpt(t *testing.T) {
+	renderer := NewMarkdownRenderer()
+
+	rendered, err := renderer.Render("# Title\n\nFirst *paragraph* with [a link](https://example.com).")
+	require.NoError(t, err)
+	assert.Equal(t, "First paragraph with a link.", rendered.Excerpt)
+
+	long := strings.Repeat("word ", 100)
+	rendered, err = renderer.Render(long)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len([]rune(rendered.Excerpt)), DefaultExcerptLength+1)
+	assert.True(t, strings.HasSuffix(rendered.Excerpt, "word…"))
+}
+
+func TestMarkdownRenderer_ReadingTime(t *testing.T) {
+	renderer := NewMarkdownRenderer()
+
+	tests := []struct {
+		name   string
+		source string
+		want   int
+	}{
+		{name: "empty content", source: "", want: 0},
+		{name: "short content", source: "just a few words", want: 1},
+		{name: "exactly one minute", source: strings.Repeat("word ", DefaultWordsPerMinute), want: 1},
+		{name: "rounds up", source: strings.Repeat("word ", DefaultWordsPerMinute+1), want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := renderer.Render(tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rendered.ReadingTimeMinutes)
+		})
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	assert.Equal(t, ContentHash("same"), ContentHash("same"))
+	assert.NotEqual(t, ContentHash("one"), ContentHash("two"))
+	assert.Len(t, ContentHash(""), 64)
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// NaiveBayes is a spam/ham text classifier that learns incrementally. Every text counts
+// each of its tokens once, so repeating a word does not outweigh the rest of the text.
+// It is safe for concurrent use.
+type NaiveBayes struct {
+	mu         sync.RWMutex
+	spamDocs   int
+	hamDocs    int
+	spamTokens map[string]int
+	hamTokens  map[string]int
+	// spamTotal and hamTotal are the token counts of each class, vocabulary the distinct tokens
+	spamTotal  int
+	hamTotal   int
+	vocabulary map[string]int
+}
+
+// NewNaiveBayes creates an untrained classifier
+func NewNaiveBayes() *NaiveBayes {
+	return &NaiveBayes{
+		spamTokens: make(map[string]int),
+		hamTokens:  make(map[string]int),
+		vocabulary: make(map[string]int),
+	}
+}
+
+// Learn trains the classifier with a text known to be spam or ham
+func (nb *NaiveBayes) Learn(text string, spam bool) {
+	nb.mu.Lock()
+	defer nb.mu.Unlock()
+	nb.update(SpamTokens(text), spam, 1)
+}
+
+// Forget undoes an earlier Learn of the same text and class, for a decision that was
+// changed afterwards. Forgetting a text that was never learned skews the counts.
+func (nb *NaiveBayes) Forget(text string, spam bool) {
+	nb.mu.Lock()
+	defer nb.mu.Unlock()
+	nb.update(SpamTokens(text), spam, -1)
+}
+
+func (nb *NaiveBayes) update(tokens []string, spam bool, delta int) {
+	docs, counts, total := &nb.hamDocs, nb.hamTokens, &nb.hamTotal
+	if spam {
+		docs, counts, total = &nb.spamDocs, nb.spamTokens, &nb.spamTotal
+	}
+	if *docs+delta < 0 {
+		return
+	}
+	*docs += delta
+
+	for _, token := range tokens {
+		if counts[token]+delta < 0 {
+			continue
+		}
+		counts[token] += delta
+		*total += delta
+		if counts[token] == 0 {
+			delete(counts, token)
+		}
+
+		nb.vocabulary[token] += delta
+		if nb.vocabulary[token] <= 0 {
+			delete(nb.vocabulary, token)
+		}
+	}
+}
+
+// Trained reports whether the classifier has seen at least one spam and one ham text
+func (nb *NaiveBayes) Trained() bool {
+	nb.mu.RLock()
+	defer nb.mu.RUnlock()
+	return nb.spamDocs > 0 && nb.hamDocs > 0
+}
+
+// SpamProbability returns the probability between 0 and 1 that text is spam. It is 0.5
+// until the classifier is Trained.
+func (nb *NaiveBayes) SpamProbability(text string) float64 {
+	tokens := SpamTokens(text)
+
+	nb.mu.RLock()
+	defer nb.mu.RUnlock()
+
+	if nb.spamDocs == 0 || nb.hamDocs == 0 {
+		return 0.5
+	}
+
+	// Log probabilities with Laplace smoothing, tokens never seen in training are skipped
+	docs := float64(nb.spamDocs + nb.hamDocs)
+	spamScore := math.Log(float64(nb.spamDocs) / docs)
+	hamScore := math.Log(float64(nb.hamDocs) / docs)
+	vocabulary := float64(len(nb.vocabulary))
+	for _, token := range tokens {
+		if _, known := nb.vocabulary[token]; !known {
+			continue
+		}
+		spamScore += math.Log(float64(nb.spamTokens[token]+1) / (float64(nb.spamTotal) + vocabulary))
+		hamScore += math.Log(float64(nb.hamTokens[token]+1) / (float64(nb.hamTotal) + vocabulary))
+	}
+
+	return 1 / (1 + math.Exp(hamScore-spamScore))
+}
+
+// SpamTokens splits text into the distinct lowercase words and numbers the classifier
+// learns from, ignoring single characters
+func SpamTokens(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) < 2 || seen[word] {
+			continue
+		}
+		seen[word] = true
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func trainedNaiveBayes() *NaiveBayes {
+	nb := NewNaiveBayes()
+	nb.Learn("Cheap pills, buy now and win a free casino bonus", true)
+	nb.Learn("Win money fast at the best online casino, free bonus", true)
+	nb.Learn("Buy cheap followers now, limited offer", true)
+	nb.Learn("Great article, the section on goroutines helped me a lot", false)
+	nb.Learn("I think the benchmark in the second example is missing a warmup", false)
+	nb.Learn("Thanks for the explanation of context cancellation", false)
+	return nb
+}
+
+func TestNaiveBayes_Untrained(t *testing.T) {
+	nb := NewNaiveBayes()
+	assert.False(t, nb.Trained())
+	assert.Equal(t, 0.5, nb.SpamProbability("free casino bonus"))
+
+	nb.Learn("free casino bonus", true)
+	assert.False(t, nb.Trained(), "ham has not been seen yet")
+	assert.Equal(t, 0.5, nb.SpamProbability("free casino bonus"))
+}
+
+func TestNaiveBayes_SpamProbability(t *testing.T) {
+	nb := trainedNaiveBayes()
+	assert.True(t, nb.Trained())
+
+	assert.Greater(t, nb.SpamProbability("FREE casino bonus, buy now!"), 0.9)
+	assert.Less(t, nb.SpamProbability("The goroutines example helped, thanks"), 0.1)
+
+	unknown := nb.SpamProbability("lorem ipsum dolor")
+	assert.InDelta(t, 0.5, unknown, 0.01, "unknown tokens only leave the equal class priors")
+}
+
+func TestNaiveBayes_RepeatedWordsCountOnce(t *testing.T) {
+	nb := trainedNaiveBayes()
+	assert.Equal(t, nb.SpamProbability("casino thanks"), nb.SpamProbability("casino casino casino thanks"))
+}
+
+func TestNaiveBayes_Forget(t *testing.T) {
+	nb := trainedNaiveBayes()
+	before := nb.SpamProbability("crypto giveaway")
+
+	nb.Learn("crypto giveaway, send coins", true)
+	assert.Greater(t, nb.SpamProbability("crypto giveaway"), before)
+
+	nb.Forget("crypto giveaway, send coins", true)
+	assert.InDelta(t, before, nb.SpamProbability("crypto giveaway"), 1e-9)
+}
+
+func TestSpamTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "empty", text: "", want: []string{}},
+		{name: "lowercase and punctuation", text: "Buy NOW!!! Free-bonus.", want: []string{"buy", "now", "free", "bonus"}},
+		{name: "duplicates and single characters", text: "a spam spam b Spam", want: []string{"spam"}},
+		{name: "links", text: "visit https://cheap.example.com/x", want: []string{"visit", "https", "cheap", "example", "com"}},
+		{name: "unicode", text: "Artikel yang bagus sekali", want: []string{"artikel", "yang", "bagus", "sekali"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SpamTokens(tt.text))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisCache shares cached entries between every instance of the API. The keys stored
+// with a tag are kept in a Redis set named after the tag.
+type redisCache struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisCache creates a cache stored in Redis, every key it writes starts with prefix
+func NewRedisCache(client redis.UniversalClient, prefix string) Cache {
+	return &redisCache{client: client, prefix: prefix}
+}
+
+func (c *redisCache) entryKey(key string) string {
+	return c.prefix + key
+}
+
+func (c *redisCache) tagKey(tag string) string {
+	return c.prefix + "tag:" + tag
+}
+
+// generationKey is the counter of the deletes and invalidations of every instance
+func (c *redisCache) generationKey() string {
+	return c.prefix + "meta:generation"
+}
+
+// Get implements Cache.
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.entryKey(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set implements Cache.
+// The tag sets live as long as their longest lived entry: NX gives a new set the ttl of
+// its first entry and GT only ever extends it.
+func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	entryKey := c.entryKey(key)
+
+	pipe := c.client.TxPipeline()
+	pipe.Set(ctx, entryKey, value, ttl)
+	for _, tag := range tags {
+		tagKey := c.tagKey(tag)
+		pipe.SAdd(ctx, tagKey, entryKey)
+		if ttl > 0 {
+			pipe.ExpireNX(ctx, tagKey, ttl)
+			pipe.ExpireGT(ctx, tagKey, ttl)
+		} else {
+			pipe.Persist(ctx, tagKey)
+		}
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Delete implements Cache.
+func (c *redisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	entryKeys := make([]string, len(keys))
+	for i, key := range keys {
+		entryKeys[i] = c.entryKey(key)
+	}
+	if err := c.client.Incr(ctx, c.generationKey()).Err(); err != nil {
+		return err
+	}
+	return c.client.Del(ctx, entryKeys...).Err()
+}
+
+// InvalidateTags implements Cache.
+func (c *redisCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	if err := c.client.Incr(ctx, c.generationKey()).Err(); err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		tagKey := c.tagKey(tag)
+		entryKeys, err := c.client.SMembers(ctx, tagKey).Result()
+		if err != nil {
+			return err
+		}
+		if err := c.client.Del(ctx, append(entryKeys, tagKey)...).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Generation implements Cache.
+func (c *redisCache) Generation(ctx context.Context) (uint64, error) {
+	generation, err := c.client.Get(ctx, c.generationKey()).Uint64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return generation, err
+}
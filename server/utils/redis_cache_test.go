@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRedisCache(t *testing.T) (Cache, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisCache(client, "test:"), server
+}
+
+func TestRedisCache_GetSet(t *testing.T) {
+	ctx := context.Background()
+	cache, server := setupRedisCache(t)
+
+	_, found, err := cache.Get(ctx, "article:slug:hello")
+	require.NoError(t, err)
+	assert.False(t, found, "should miss before the entry is stored")
+
+	require.NoError(t, cache.Set(ctx, "article:slug:hello", []byte("v1"), time.Minute))
+	value, found, err := cache.Get(ctx, "article:slug:hello")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("v1"), value)
+	assert.True(t, server.Exists("test:article:slug:hello"), "should prefix the stored key")
+}
+
+func TestRedisCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	cache, server := setupRedisCache(t)
+
+	require.NoError(t, cache.Set(ctx, "short", []byte("a"), time.Minute, "article:1"))
+
+	server.FastForward(59 * time.Second)
+	_, found, _ := cache.Get(ctx, "short")
+	assert.True(t, found, "should keep the entry until the ttl passes")
+
+	server.FastForward(time.Second)
+	_, found, _ = cache.Get(ctx, "short")
+	assert.False(t, found, "should drop the entry once the ttl passed")
+	assert.False(t, server.Exists("test:tag:article:1"), "should expire the tag set with its entries")
+}
+
+func TestRedisCache_TagSetLivesAsLongAsItsEntries(t *testing.T) {
+	ctx := context.Background()
+	cache, server := setupRedisCache(t)
+
+	require.NoError(t, cache.Set(ctx, "long", []byte("a"), time.Hour, "category:1"))
+	require.NoError(t, cache.Set(ctx, "short", []byte("b"), time.Minute, "category:1"))
+
+	assert.Equal(t, time.Hour, server.TTL("test:tag:category:1"), "should not shorten the tag set")
+}
+
+func TestRedisCache_DeleteAndInvalidateTags(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := setupRedisCache(t)
+
+	require.NoError(t, cache.Set(ctx, "article:slug:a", []byte("a"), time.Minute, "article:1", "category:1"))
+	require.NoError(t, cache.Set(ctx, "article:slug:b", []byte("b"), time.Minute, "article:2", "category:1"))
+	require.NoError(t, cache.Set(ctx, "article:slug:c", []byte("c"), time.Minute, "article:3", "category:2"))
+	require.NoError(t, cache.Set(ctx, "categories:all", []byte("all"), time.Minute))
+
+	require.NoError(t, cache.Delete(ctx, "categories:all", "missing"))
+	_, found, _ := cache.Get(ctx, "categories:all")
+	assert.False(t, found, "should delete by key")
+
+	require.NoError(t, cache.InvalidateTags(ctx, "category:1", "unknown"))
+	_, found, _ = cache.Get(ctx, "article:slug:a")
+	assert.False(t, found, "should drop every entry with the tag")
+	_, found, _ = cache.Get(ctx, "article:slug:b")
+	assert.False(t, found, "should drop every entry with the tag")
+	_, found, _ = cache.Get(ctx, "article:slug:c")
+	assert.True(t, found, "should keep entries without the tag")
+}
+
+func TestRedisCache_ServerUnavailable(t *testing.T) {
+	ctx := context.Background()
+	cache, server := setupRedisCache(t)
+	server.Close()
+
+	_, found, err := cache.Get(ctx, "article:slug:hello")
+	assert.Error(t, err)
+	assert.False(t, found)
+	assert.Error(t, cache.Set(ctx, "article:slug:hello", []byte("v1"), time.Minute))
+}
+
+func TestRedisCache_Generation(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := setupRedisCache(t)
+
+	start, err := cache.Generation(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Set(ctx, "article:slug:a", []byte("a"), time.Minute, "article:1"))
+	generation, _ := cache.Generation(ctx)
+	assert.Equal(t, start, generation, "should not move on a store")
+
+	require.NoError(t, cache.Delete(ctx, "article:slug:a"))
+	generation, _ = cache.Generation(ctx)
+	assert.Equal(t, start+1, generation, "should move on a delete")
+
+	require.NoError(t, cache.InvalidateTags(ctx, "article:1"))
+	generation, _ = cache.Generation(ctx)
+	assert.Equal(t, start+2, generation, "should move on an invalidation")
+}
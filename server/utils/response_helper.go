@@ -89,6 +89,21 @@ func (rh *ResponseHelper) SendCreated(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusCreated, response)
 }
 
+// SendMovedPermanently sends a 301 redirect to location. Clients that do not follow
+// redirects get the new location in the body as well.
+func (rh *ResponseHelper) SendMovedPermanently(c *gin.Context, location string, data interface{}) {
+	ctx := c.Request.Context()
+	response := dto.SuccessResponse(ctx, data)
+	
+	// Add request ID to response headers if available
+	if response.Meta != nil && response.Meta.RequestID != "" {
+		c.Header("X-Request-ID", response.Meta.RequestID)
+	}
+	
+	c.Header("Location", location)
+	c.JSON(http.StatusMovedPermanently, response)
+}
+
 // SendNoContent sends a standardized no content response with context
 func (rh *ResponseHelper) SendNoContent(c *gin.Context) {
 	ctx := c.Request.Context()
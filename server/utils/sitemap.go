@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io"
+	"time"
+)
+
+// MaxSitemapURLs is the maximum number of URLs a single sitemap may contain
+// according to the sitemaps.org protocol
+const MaxSitemapURLs = 50000
+
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// ErrSitemapFull is returned when more than MaxSitemapURLs entries are written to one sitemap
+var ErrSitemapFull = errors.New("sitemap already contains the maximum number of URLs")
+
+// SitemapWriter streams a sitemap <urlset> or <sitemapindex> document entry by entry,
+// so large sitemaps never have to be held in memory
+type SitemapWriter struct {
+	w       *bufio.Writer
+	root    string
+	count   int
+	started bool
+	closed  bool
+}
+
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// NewSitemapWriter creates a writer for a <urlset> sitemap
+func NewSitemapWriter(w io.Writer) *SitemapWriter {
+	return &SitemapWriter{w: bufio.NewWriter(w), root: "urlset"}
+}
+
+// NewSitemapIndexWriter creates a writer for a <sitemapindex> listing child sitemaps
+func NewSitemapIndexWriter(w io.Writer) *SitemapWriter {
+	return &SitemapWriter{w: bufio.NewWriter(w), root: "sitemapindex"}
+}
+
+// WriteURL adds a <url> to a sitemap, or a <sitemap> to a sitemap index.
+// A zero lastMod omits the <lastmod> element.
+func (s *SitemapWriter) WriteURL(loc string, lastMod time.Time) error {
+	if s.closed {
+		return errors.New("sitemap writer is closed")
+	}
+	if s.count >= MaxSitemapURLs {
+		return ErrSitemapFull
+	}
+	if err := s.start(); err != nil {
+		return err
+	}
+
+	entry := sitemapEntry{Loc: loc}
+	if !lastMod.IsZero() {
+		entry.LastMod = lastMod.UTC().Format(time.RFC3339)
+	}
+
+	element := "url"
+	if s.root == "sitemapindex" {
+		element = "sitemap"
+	}
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name
+		sitemapEntry
+	}{XMLName: xml.Name{Local: element}, sitemapEntry: entry})
+	if err != nil {
+		return err
+	}
+
+	s.count++
+	if _, err := s.w.Write(body); err != nil {
+		return err
+	}
+	_, err = s.w.WriteString("\n")
+	return err
+}
+
+// Count returns the number of entries written so far
+func (s *SitemapWriter) Count() int {
+	return s.count
+}
+
+// Close writes the closing root element and flushes the buffered output.
+// It does not close the underlying writer.
+func (s *SitemapWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	if err := s.start(); err != nil {
+		return err
+	}
+	s.closed = true
+
+	if _, err := s.w.WriteString("</" + s.root + ">\n"); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+func (s *SitemapWriter) start() error {
+	if s.started {
+		return nil
+	}
+	s.started = true
+	_, err := s.w.WriteString(xml.Header + `<` + s.root + ` xmlns="` + sitemapNamespace + `">` + "\n")
+	return err
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSitemapWriter_URLSet(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewSitemapWriter(&buf)
+
+	lastMod := time.Date(2024, 3, 1, 10, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	require.NoError(t, writer.WriteURL("https://example.com/articles/a&b", lastMod))
+	require.NoError(t, writer.WriteURL("https://example.com/tags/go", time.Time{}))
+	require.NoError(t, writer.Close())
+	assert.Equal(t, 2, writer.Count())
+
+	var doc struct {
+		XMLName xml.Name
+		URLs    []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"url"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Equal(t, "urlset", doc.XMLName.Local)
+	assert.Equal(t, sitemapNamespace, doc.XMLName.Space)
+	require.Len(t, doc.URLs, 2)
+	assert.Equal(t, "https://example.com/articles/a&b", doc.URLs[0].Loc)
+	assert.Equal(t, "2024-03-01T03:00:00Z", doc.URLs[0].LastMod)
+	assert.Empty(t, doc.URLs[1].LastMod)
+}
+
+func TestSitemapWriter_Index(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewSitemapIndexWriter(&buf)
+
+	require.NoError(t, writer.WriteURL("https://example.com/sitemaps/sitemap-1.xml", time.Time{}))
+	require.NoError(t, writer.Close())
+
+	var doc struct {
+		XMLName  xml.Name
+		Sitemaps []struct {
+			Loc string `xml:"loc"`
+		} `xml:"sitemap"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Equal(t, "sitemapindex", doc.XMLName.Local)
+	require.Len(t, doc.Sitemaps, 1)
+	assert.Equal(t, "https://example.com/sitemaps/sitemap-1.xml", doc.Sitemaps[0].Loc)
+}
+
+func TestSitemapWriter_EmptyIsValid(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewSitemapWriter(&buf)
+	require.NoError(t, writer.Close())
+
+	var doc struct {
+		XMLName xml.Name
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "urlset", doc.XMLName.Local)
+}
+
+func TestSitemapWriter_Limit(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewSitemapWriter(&buf)
+	writer.count = MaxSitemapURLs
+
+	assert.ErrorIs(t, writer.WriteURL("https://example.com/one-too-many", time.Time{}), ErrSitemapFull)
+}
@@ -4,19 +4,94 @@ import (
 	"regexp"
 	"strings"
 	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
+// MaxSlugLength is the longest slug generated, suffixes included
+const MaxSlugLength = 100
+
+// reservedSlugs are path segments of fixed routes next to slug routes, a slug with
+// one of these values could never be reached
+var reservedSlugs = map[string]bool{
+	"admin":    true,
+	"api":      true,
+	"atom":     true,
+	"author":   true,
+	"category": true,
+	"edit":     true,
+	"feed":     true,
+	"json":     true,
+	"manage":   true,
+	"new":      true,
+	"related":  true,
+	"rss":      true,
+	"search":   true,
+	"sitemap":  true,
+	"tag":      true,
+	"trending": true,
+}
+
+// transliterations spells letters without a decomposition to ASCII in Latin letters.
+// Letters with diacritics such as é or ñ are handled by Unicode decomposition.
+var transliterations = map[rune]string{
+	// Latin
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'þ': "th", 'ł': "l", 'ı': "i",
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i",
+	'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s",
+	'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+var slugSeparators = regexp.MustCompile(`-+`)
+
+// Transliterate spells text in lowercase ASCII where a reading is known. Diacritics are
+// dropped, Cyrillic and Greek are romanized and other characters are kept unchanged.
+func Transliterate(text string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		if unicode.Is(unicode.Mn, r) {
+			// Combining mark split off a letter by the decomposition
+			continue
+		}
+		if latin, ok := transliterations[r]; ok {
+			b.WriteString(latin)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 func GenerateSlug(title string) string {
 	if title == "" {
 		return ""
 	}
 
-	// Convert to lowercase
-	slug := strings.ToLower(title)
-	
-	// Replace spaces and special characters with hyphens
+	slug := slugify(title)
+
+	// If slug is empty after processing, return a default
+	if slug == "" {
+		return "untitled"
+	}
+
+	return slug
+}
+
+// slugify turns text into a slug, returning "" when nothing in it has an ASCII reading
+func slugify(title string) string {
+	// Convert to lowercase ASCII where possible
+	slug := Transliterate(title)
+
+	// Replace spaces and special characters with hyphens, letters without
+	// an ASCII reading are left out
 	slug = strings.Map(func(r rune) rune {
-		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsNumber(r)) {
 			return r
 		}
 		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
@@ -24,18 +99,33 @@ func GenerateSlug(title string) string {
 		}
 		return -1
 	}, slug)
-	
+
 	// Remove multiple consecutive hyphens
-	re := regexp.MustCompile(`-+`)
-	slug = re.ReplaceAllString(slug, "-")
-	
+	slug = slugSeparators.ReplaceAllString(slug, "-")
+
 	// Remove leading and trailing hyphens
-	slug = strings.Trim(slug, "-")
-	
-	// If slug is empty after processing, return a default
-	if slug == "" {
-		return "untitled"
+	return truncateSlug(strings.Trim(slug, "-"), MaxSlugLength)
+}
+
+// SlugWithSuffix appends suffix to slug, shortening slug so the result stays within MaxSlugLength
+func SlugWithSuffix(slug, suffix string) string {
+	return truncateSlug(slug, MaxSlugLength-len(suffix)-1) + "-" + suffix
+}
+
+// IsReservedSlug reports whether slug is taken by a fixed route
+func IsReservedSlug(slug string) bool {
+	return reservedSlugs[slug]
+}
+
+// truncateSlug shortens slug to at most max bytes, preferably at a hyphen
+func truncateSlug(slug string, max int) string {
+	if len(slug) <= max {
+		return slug
 	}
-	
-	return slug
+
+	slug = slug[:max]
+	if cut := strings.LastIndex(slug, "-"); cut > max/2 {
+		slug = slug[:cut]
+	}
+	return strings.Trim(slug, "-")
 }
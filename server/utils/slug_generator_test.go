@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -71,3 +72,62 @@ func TestGenerateSlug(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSlug_Transliteration(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{name: "should drop diacritics", title: "Café Crème à la Façon", want: "cafe-creme-a-la-facon"},
+		{name: "should spell special latin letters", title: "Straße Øresund Łódź", want: "strasse-oresund-lodz"},
+		{name: "should romanize cyrillic", title: "Привет мир", want: "privet-mir"},
+		{name: "should romanize greek", title: "Καλημέρα κόσμε", want: "kalimera-kosme"},
+		{name: "should keep ascii parts of mixed titles", title: "Go 入門 Guide", want: "go-guide"},
+		{name: "should fall back for scripts without reading", title: "入門", want: "untitled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GenerateSlug(tt.title); got != tt.want {
+				t.Errorf("GenerateSlug() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSlug_MaxLength(t *testing.T) {
+	title := strings.Repeat("lorem ipsum ", 20)
+	got := GenerateSlug(title)
+	if len(got) > MaxSlugLength {
+		t.Fatalf("GenerateSlug() length = %d, want at most %d", len(got), MaxSlugLength)
+	}
+	if strings.HasSuffix(got, "-") || !strings.HasSuffix(got, "ipsum") && !strings.HasSuffix(got, "lorem") {
+		t.Errorf("GenerateSlug() = %v, want a cut at a word boundary", got)
+	}
+}
+
+func TestSlugWithSuffix(t *testing.T) {
+	if got := SlugWithSuffix("hello-world", "2"); got != "hello-world-2" {
+		t.Errorf("SlugWithSuffix() = %v, want hello-world-2", got)
+	}
+
+	long := GenerateSlug(strings.Repeat("lorem ipsum ", 20))
+	got := SlugWithSuffix(long, "a1b2c3")
+	if len(got) > MaxSlugLength {
+		t.Errorf("SlugWithSuffix() length = %d, want at most %d", len(got), MaxSlugLength)
+	}
+	if !strings.HasSuffix(got, "-a1b2c3") {
+		t.Errorf("SlugWithSuffix() = %v, want suffix -a1b2c3", got)
+	}
+}
+
+func TestIsReservedSlug(t *testing.T) {
+	for _, slug := range []string{"search", "feed", "trending"} {
+		if !IsReservedSlug(slug) {
+			t.Errorf("IsReservedSlug(%q) = false, want true", slug)
+		}
+	}
+	if IsReservedSlug("search-tips") {
+		t.Errorf("IsReservedSlug(%q) = true, want false", "search-tips")
+	}
+}